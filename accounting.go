@@ -0,0 +1,54 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+// AccountingOption selects the presentation of FormatAccounting.
+type AccountingOption uint8
+
+const (
+	// AccountingGrouping separates thousands in the integer digits with
+	// commas.
+	AccountingGrouping AccountingOption = 1 << iota
+	// AccountingUnsignedZero writes values that are zero after rounding
+	// without parentheses, so -0.001 is written "0.00" rather than
+	// "(0.00)".
+	AccountingUnsignedZero
+)
+
+// FormatAccounting returns the decimal value in plain notation with
+// negative values enclosed in parentheses, as in accounting reports:
+// -1234.5 is written "(1,234.50)" with places 2 and AccountingGrouping. The
+// result has places fraction digits, rounding ties to even; if places is
+// negative, the digits of d are kept as they are. Infinities are written
+// "Infinity" and "(Infinity)", and NaNs as by String.
+func (d Dec32) FormatAccounting(places int, opts AccountingOption) string {
+	if d.IsNaN() {
+		return d.String()
+	}
+	var digits []byte
+	neg := d.Sign() < 0
+	if d.IsInf() {
+		digits = []byte("Infinity")
+	} else {
+		x := newBigDec32(d)
+		if places >= 0 {
+			x.rescale(-places)
+		}
+		if x.isZero() && opts&AccountingUnsignedZero != 0 {
+			neg = false
+		}
+		digits = appendPlainDigits(nil, []byte(x.coeff.String()), x.exp)
+		if opts&AccountingGrouping != 0 {
+			digits = appendGrouped(nil, digits, ',')
+		}
+	}
+	if !neg {
+		return string(digits)
+	}
+	buf := make([]byte, 0, len(digits)+2)
+	buf = append(buf, '(')
+	buf = append(buf, digits...)
+	return string(append(buf, ')'))
+}
@@ -0,0 +1,36 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import "testing"
+
+func TestFormatAccounting(t *testing.T) {
+	testCases := []struct {
+		d      Dec32
+		places int
+		opts   AccountingOption
+		ref    string
+	}{
+		{mustEncode(t, -12345, -1), 2, AccountingGrouping, "(1,234.50)"},
+		{mustEncode(t, 12345, -1), 2, AccountingGrouping, "1,234.50"},
+		{mustEncode(t, -12345, -1), 2, 0, "(1234.50)"},
+		{mustEncode(t, -1234567, 2), 0, AccountingGrouping, "(123,456,700)"},
+		{mustEncode(t, -125, -3), 2, 0, "(0.12)"},
+		{mustEncode(t, 12345, -3), -1, 0, "12.345"},
+		{mustEncode(t, -1, -3), 2, 0, "(0.00)"},
+		{mustEncode(t, -1, -3), 2, AccountingUnsignedZero, "0.00"},
+		{mustEncode(t, 0, -2) | signMask, -1, AccountingUnsignedZero, "0.00"},
+		{mustEncode(t, -5, -3), 2, AccountingUnsignedZero, "0.00"},
+		{mustEncode(t, -15, -3), 2, AccountingUnsignedZero, "(0.02)"},
+		{inf32 | signMask, 2, AccountingGrouping, "(Infinity)"},
+		{inf32, 2, 0, "Infinity"},
+		{nan32, 2, 0, "NaN"},
+	}
+	for i, testCase := range testCases {
+		if s := testCase.d.FormatAccounting(testCase.places, testCase.opts); s != testCase.ref {
+			t.Errorf("testCase #%d: %v: expect %q, got %q", i, testCase.d, testCase.ref, s)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// The AMQP 1.0 type constructors of decimal32, decimal64 and decimal128
+// values.
+const (
+	amqpDecimal32  = 0x74
+	amqpDecimal64  = 0x84
+	amqpDecimal128 = 0x94
+)
+
+var (
+	errAMQPShort    = errors.New("decimal: truncated AMQP decimal32")
+	errAMQPType     = errors.New("decimal: AMQP value is not a decimal32")
+	errAMQPShort64  = errors.New("decimal: truncated AMQP decimal64")
+	errAMQPType64   = errors.New("decimal: AMQP value is not a decimal64")
+	errAMQPShort128 = errors.New("decimal: truncated AMQP decimal128")
+	errAMQPType128  = errors.New("decimal: AMQP value is not a decimal128")
+)
+
+// AppendAMQP appends d to buf as an AMQP 1.0 decimal32 value, the type
+// constructor 0x74 followed by the interchange encoding in network byte
+// order, and returns the extended buffer.
+func (d Dec32) AppendAMQP(buf []byte) []byte {
+	buf = append(buf, amqpDecimal32)
+	return binary.BigEndian.AppendUint32(buf, uint32(d))
+}
+
+// DecodeAMQP decodes the AMQP 1.0 decimal32 value at the start of b, as
+// written by AppendAMQP, and returns it with the number of bytes read. It
+// returns an error if b is too short or holds a value of another type. The
+// encoding is returned as sent, including non-canonical encodings and NaN
+// payloads.
+func DecodeAMQP(b []byte) (Dec32, int, error) {
+	if len(b) < 1 {
+		return nan32, 0, errAMQPShort
+	}
+	if b[0] != amqpDecimal32 {
+		return nan32, 0, errAMQPType
+	}
+	if len(b) < 5 {
+		return nan32, 0, errAMQPShort
+	}
+	return Dec32(binary.BigEndian.Uint32(b[1:])), 5, nil
+}
+
+// AppendAMQP appends d to buf as an AMQP 1.0 decimal64 value, the type
+// constructor 0x84 followed by the interchange encoding in network byte
+// order, and returns the extended buffer.
+func (d Dec64) AppendAMQP(buf []byte) []byte {
+	buf = append(buf, amqpDecimal64)
+	return binary.BigEndian.AppendUint64(buf, uint64(d))
+}
+
+// DecodeAMQP64 decodes the AMQP 1.0 decimal64 value at the start of b, as
+// DecodeAMQP does for decimal32 values.
+func DecodeAMQP64(b []byte) (Dec64, int, error) {
+	if len(b) < 1 {
+		return nan64, 0, errAMQPShort64
+	}
+	if b[0] != amqpDecimal64 {
+		return nan64, 0, errAMQPType64
+	}
+	if len(b) < 9 {
+		return nan64, 0, errAMQPShort64
+	}
+	return Dec64(binary.BigEndian.Uint64(b[1:])), 9, nil
+}
+
+// AppendAMQP appends d to buf as an AMQP 1.0 decimal128 value, the type
+// constructor 0x94 followed by the interchange encoding in network byte
+// order, and returns the extended buffer.
+func (d Dec128) AppendAMQP(buf []byte) []byte {
+	buf = append(buf, amqpDecimal128)
+	buf = binary.BigEndian.AppendUint64(buf, d.hi)
+	return binary.BigEndian.AppendUint64(buf, d.lo)
+}
+
+// DecodeAMQP128 decodes the AMQP 1.0 decimal128 value at the start of b, as
+// DecodeAMQP does for decimal32 values.
+func DecodeAMQP128(b []byte) (Dec128, int, error) {
+	if len(b) < 1 {
+		return nan128, 0, errAMQPShort128
+	}
+	if b[0] != amqpDecimal128 {
+		return nan128, 0, errAMQPType128
+	}
+	if len(b) < 17 {
+		return nan128, 0, errAMQPShort128
+	}
+	return Dec128{binary.BigEndian.Uint64(b[1:]), binary.BigEndian.Uint64(b[9:])}, 17, nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAMQP(t *testing.T) {
+	testCases := []struct {
+		d   Dec32
+		ref []byte
+	}{
+		{mustEncode(t, 9999999, 0), []byte{0x74, 0x6c, 0xb8, 0x96, 0x7f}},
+		{mustEncode(t, -125, -2), []byte{0x74, 0xb1, 0x80, 0x00, 0x7d}},
+		{inf32, []byte{0x74, 0x78, 0, 0, 0}},
+		{Dec32(0x6cbfffff), []byte{0x74, 0x6c, 0xbf, 0xff, 0xff}},
+	}
+	for i, testCase := range testCases {
+		b := testCase.d.AppendAMQP([]byte{0x40})
+		if !bytes.Equal(b[1:], testCase.ref) {
+			t.Errorf("testCase #%d: %v: expect %x, got %x", i, testCase.d, testCase.ref, b[1:])
+		}
+		d, n, err := DecodeAMQP(append(b[1:], 0x40))
+		if err != nil || n != 5 || d != testCase.d {
+			t.Errorf("testCase #%d: expect %v, got %v %d %v", i, testCase.d, d, n, err)
+		}
+	}
+	for _, b := range [][]byte{nil, {0x74, 0x32, 0x80}, {0x84, 0, 0, 0, 0, 0, 0, 0, 0}} {
+		if _, _, err := DecodeAMQP(b); err == nil {
+			t.Errorf("%x: expect error", b)
+		}
+	}
+}
+
+func TestAMQPWide(t *testing.T) {
+	d64 := MustEncodeDec64(1, 0)
+	b := d64.AppendAMQP(nil)
+	if ref := []byte{0x84, 0x31, 0xc0, 0, 0, 0, 0, 0, 1}; !bytes.Equal(b, ref) {
+		t.Errorf("expect %x, got %x", ref, b)
+	}
+	if d, n, err := DecodeAMQP64(append(b, 0x40)); err != nil || n != 9 || d != d64 {
+		t.Errorf("expect %x, got %x %d %v", uint64(d64), uint64(d), n, err)
+	}
+	d128 := Dec128FromBits(0x3040000000000000, 1)
+	b = d128.AppendAMQP(nil)
+	if ref := []byte{0x94, 0x30, 0x40, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}; !bytes.Equal(b, ref) {
+		t.Errorf("expect %x, got %x", ref, b)
+	}
+	if d, n, err := DecodeAMQP128(append(b, 0x40)); err != nil || n != 17 || d != d128 {
+		t.Errorf("expect %x, got %x %d %v", d128, d, n, err)
+	}
+	for _, b := range [][]byte{nil, {0x84, 0x31}, {0x74, 0, 0, 0, 0, 0, 0, 0, 0}} {
+		if _, _, err := DecodeAMQP64(b); err == nil {
+			t.Errorf("%x: expect error", b)
+		}
+	}
+	for _, b := range [][]byte{nil, {0x94, 0x30}, {0x84, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}} {
+		if _, _, err := DecodeAMQP128(b); err == nil {
+			t.Errorf("%x: expect error", b)
+		}
+	}
+}
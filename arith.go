@@ -0,0 +1,214 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import "math/big"
+
+// Add returns the sum x+y, correctly rounded to the nearest decimal32, ties
+// to even. An exact sum takes the smaller of the operand exponents, so
+// 1.20 + 3 is 4.20, and an exact zero sum is +0 unless both operands are
+// negative. Sums too large to represent become infinities of their sign, and
+// the sum of infinities of opposite sign is NaN, as is any sum with a NaN.
+func (x Dec32) Add(y Dec32) Dec32 {
+	var c Context
+	return c.Add(x, y)
+}
+
+// Sub returns the difference x-y, rounded as for Add, which it is with the
+// sign of y reversed.
+func (x Dec32) Sub(y Dec32) Dec32 {
+	var c Context
+	return c.Sub(x, y)
+}
+
+// Mul returns the product x*y, correctly rounded to the nearest decimal32,
+// ties to even. An exact product takes the sum of the operand exponents,
+// where the range allows, so 1.20 * 3 is 3.60. Products too large to
+// represent become infinities and those too small lose digits to the
+// subnormal range or become zero. The product of a zero and an infinity is
+// NaN, as is any product with a NaN.
+func (x Dec32) Mul(y Dec32) Dec32 {
+	var c Context
+	return c.Mul(x, y)
+}
+
+// Div returns the quotient x/y, correctly rounded to the nearest decimal32,
+// ties to even. An exact quotient takes the exponent closest to the
+// difference of the operand exponents, so 3.60 / 3 is 1.20 and 1 / 4 is
+// 0.25. Dividing a nonzero value by zero gives an infinity with the sign of
+// the quotient, and a finite value by an infinity a zero with the smallest
+// exponent. 0/0, Inf/Inf and quotients with a NaN are NaN.
+func (x Dec32) Div(y Dec32) Dec32 {
+	var c Context
+	return c.Div(x, y)
+}
+
+// Recip returns the reciprocal 1/d, correctly rounded to the nearest
+// decimal32, ties to even. An exact reciprocal takes the exponent closest to
+// the negated exponent of d, so 1/0.25 is 4 and 1/2.0 is 0.5. The reciprocal
+// of a zero is the infinity of the same sign, as division by zero gives, and
+// that of an infinity is a zero of the same sign with the smallest exponent.
+// Results too small to represent become zero. NaNs give a quiet NaN.
+func (d Dec32) Recip() Dec32 {
+	var c Context
+	return c.Recip(d)
+}
+
+// Hypot returns sqrt(x*x + y*y), computed exactly and rounded once to the
+// nearest decimal32, ties to even, so that it cannot overflow or underflow in
+// intermediate steps. An exact result takes the smaller of the operand
+// exponents where possible, so Hypot(0.3, 0.4) is 0.5. As with math.Hypot,
+// the result is +Inf if either operand is infinite, even if the other is
+// NaN, and otherwise NaN if either is NaN.
+func (x Dec32) Hypot(y Dec32) Dec32 {
+	var c Context
+	return c.Hypot(x, y)
+}
+
+// Sqrt returns the square root of d, the IEEE 754 squareRoot operation,
+// computed exactly and rounded once to the nearest decimal32, ties to even.
+// An exact root takes the exponent closest to half that of d, so Sqrt(1.44)
+// is 1.2 and Sqrt(100) is 10, and -0 is its own root. The root of +Inf is
+// +Inf, and negative values other than -0 give NaN.
+func (d Dec32) Sqrt() Dec32 {
+	var c Context
+	return c.Sqrt(d)
+}
+
+// Pow10 returns 10^n exactly, with coefficient 1 and exponent n where the
+// exponent range allows, so Pow10(-2) is 0.01 and equals the quantum of
+// cents. Above 1E+90 the coefficient takes trailing zeros, up to 1E+96;
+// Pow10(n) is +Inf for n > 96 and 0 for n < -101.
+func Pow10(n int) Dec32 {
+	n = min(max(n, minExp-2), maxExp+7)
+	x := newBigDecInt64(1)
+	x.exp = n
+	var c Context
+	return c.round(x)
+}
+
+// Remquo returns the IEEE remainder of x/y, x - n*y where n is the integer
+// nearest to x/y, ties to even, together with the seven low-order decimal
+// digits of n, carrying the sign of x/y, as math.Remquo returns low-order
+// bits. Because 4 and 8 divide 10^7, quo also gives n modulo 4 or 8, as
+// needed to reduce an angle by multiples of a quarter or eighth turn.
+//
+// The remainder is always exact, with the smaller exponent of x and y, and
+// its magnitude is at most |y|/2. A zero remainder has the sign of x. The
+// remainder is NaN, and quo 0, if x is infinite, y is zero or either is NaN;
+// if y is infinite and x finite, the remainder is x.
+func (x Dec32) Remquo(y Dec32) (rem Dec32, quo int) {
+	switch {
+	case x.IsNaN() || y.IsNaN() || x.IsInf() || y.Zero():
+		return nan32, 0
+	case y.IsInf():
+		return x, 0
+	}
+	a, b := newBigDec32(x), newBigDec32(y)
+	// Align both coefficients to the smaller exponent.
+	exp := min(a.exp, b.exp)
+	a.coeff.Mul(&a.coeff, bigPow10(a.exp-exp))
+	b.coeff.Mul(&b.coeff, bigPow10(b.exp-exp))
+	var n, r, r2 big.Int
+	n.QuoRem(&a.coeff, &b.coeff, &r)
+	if c := r2.Lsh(&r, 1).Cmp(&b.coeff); c > 0 || c == 0 && n.Bit(0) == 1 {
+		n.Add(&n, big.NewInt(1))
+		r.Sub(&r, &b.coeff)
+	}
+	if a.neg {
+		r.Neg(&r)
+	}
+	rem, _ = new(bigDec).setSigned(&r, exp, a.neg).dec32()
+	quo = int(n.Rem(&n, bigPow10(7)).Int64())
+	if a.neg != b.neg {
+		quo = -quo
+	}
+	return rem, quo
+}
+
+// Rem returns the IEEE remainder of x/y, x - n*y where n is the integer
+// nearest to x/y, ties to even, as math.Remainder does, so that Rem(10, 3)
+// is 1 and Rem(11, 3) is -1. It is the remainder of Remquo, with the same
+// special cases.
+func (x Dec32) Rem(y Dec32) Dec32 {
+	r, _ := x.Remquo(y)
+	return r
+}
+
+// Mod returns the remainder of x/y with the quotient truncated towards zero,
+// x - n*y for the integer n = trunc(x/y), as math.Mod does, so that the
+// result has the sign of x and is less than |y| in magnitude: Mod(-7, 3) is
+// -1. An amount split into equal installments of y leaves Mod(x, y) over.
+// Like Rem, the remainder is exact, with the smaller exponent of x and y,
+// and has the same special cases.
+func (x Dec32) Mod(y Dec32) Dec32 {
+	switch {
+	case x.IsNaN() || y.IsNaN() || x.IsInf() || y.Zero():
+		return nan32
+	case y.IsInf():
+		return x
+	}
+	a, b := newBigDec32(x), newBigDec32(y)
+	exp := min(a.exp, b.exp)
+	a.coeff.Mul(&a.coeff, bigPow10(a.exp-exp))
+	b.coeff.Mul(&b.coeff, bigPow10(b.exp-exp))
+	r := &bigDec{neg: a.neg, exp: exp}
+	r.coeff.Rem(&a.coeff, &b.coeff)
+	m, _ := r.dec32()
+	return m
+}
+
+// Modf returns the integer part and the fractional part of d, both with the
+// sign of d, so that their sum is d exactly: 123.45 splits into 123 and 0.45,
+// and -0.5 into -0 and -0.5. The integer part has exponent 0 unless d has a
+// larger one, in which case the integer part is d itself; the fractional part
+// keeps the exponent of d, or 0 if that is positive, so 3.00 splits into 3
+// and 0.00. As with math.Modf, an infinity splits into itself and NaN, and a
+// NaN into two quiet NaNs.
+func (d Dec32) Modf() (ipart, frac Dec32) {
+	switch {
+	case d.IsNaN():
+		return nan32, nan32
+	case d.IsInf():
+		return d, nan32
+	}
+	neg, coeff, exp := d.unpack()
+	switch {
+	case exp >= 0:
+		return d, pack32(neg, 0, 0)
+	case -exp >= len(pow10Uint64) || uint64(coeff) < pow10Uint64[-exp]:
+		return pack32(neg, 0, 0), d
+	}
+	p := uint32(pow10Uint64[-exp])
+	return pack32(neg, coeff/p, 0), pack32(neg, coeff%p, exp)
+}
+
+// IsMultipleOf reports whether d is an integer multiple of m, computed
+// exactly, so that 1.15 is a multiple of 0.05 although the binary floats
+// nearest them are not. This is the multipleOf test of JSON Schema. It is
+// false if m is zero, or if either value is infinite or NaN; zero is a
+// multiple of every other finite m. Signs are ignored.
+func (d Dec32) IsMultipleOf(m Dec32) bool {
+	if d.IsNaN() || d.IsInf() || m.IsNaN() || m.IsInf() || m.Zero() {
+		return false
+	}
+	a, b := newBigDec32(d), newBigDec32(m)
+	exp := min(a.exp, b.exp)
+	a.coeff.Mul(&a.coeff, bigPow10(a.exp-exp))
+	b.coeff.Mul(&b.coeff, bigPow10(b.exp-exp))
+	return a.coeff.Rem(&a.coeff, &b.coeff).Sign() == 0
+}
+
+// FMA returns the fused multiply-add x*y+z, computed exactly and rounded
+// once to the nearest decimal32, ties to even, as IEEE 754 fusedMultiplyAdd
+// specifies. The exact product keeps all of its up to fourteen digits, so
+// FMA(9999999, 9999999, -99999980000000) is exactly 1, where Mul and then Add
+// would give 0. An exact result follows the exponent rules of Add for the
+// product and z. The product of a zero and an infinity is NaN even if z is a
+// NaN, and otherwise the special cases are those of Mul and Add.
+func (x Dec32) FMA(y, z Dec32) Dec32 {
+	var c Context
+	return c.FMA(x, y, z)
+}
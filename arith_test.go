@@ -0,0 +1,363 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import "testing"
+
+func TestRecip(t *testing.T) {
+	testCases := []struct {
+		d, ref Dec32
+	}{
+		{mustEncode(t, 25, -2), mustEncode(t, 4, 0)},
+		{mustEncode(t, 20, -1), mustEncode(t, 5, -1)},
+		{mustEncode(t, 4, 0), mustEncode(t, 25, -2)},
+		{mustEncode(t, 3, 0), mustEncode(t, 3333333, -7)},
+		{mustEncode(t, -3, 0), mustEncode(t, -3333333, -7)},
+		{mustEncode(t, 6, 0), mustEncode(t, 1666667, -7)},
+		{mustEncode(t, 8, 3), mustEncode(t, 125, -6)},
+		{mustEncode(t, 9999999, 0), mustEncode(t, 1000000, -13)},
+		// 1/9.999999E+96 is 1.0000001E-97, which has only five digits
+		// in the subnormal range.
+		{mustEncode(t, 9999999, 90), mustEncode(t, 10000, -101)},
+		{mustEncode(t, 1, -96), mustEncode(t, 1000000, 90)},
+		{mustEncode(t, 1, -101), inf32},
+		{mustEncode(t, 1, -95), mustEncode(t, 100000, 90)},
+		{mustEncode(t, 0, 3), inf32},
+		{mustEncode(t, 0, 3) | signMask, inf32 | signMask},
+		{inf32, mustEncode(t, 0, -101)},
+		{inf32 | signMask, mustEncode(t, 0, -101) | signMask},
+		{Dec32(snanMask), nan32},
+	}
+	for i, testCase := range testCases {
+		if r := testCase.d.Recip(); r != testCase.ref {
+			t.Errorf("testCase #%d: 1/%v: expect %v, got %v", i, testCase.d, testCase.ref, r)
+		}
+	}
+}
+
+func TestHypot(t *testing.T) {
+	testCases := []struct {
+		x, y, ref Dec32
+	}{
+		{mustEncode(t, 3, 0), mustEncode(t, 4, 0), mustEncode(t, 5, 0)},
+		{mustEncode(t, -3, -1), mustEncode(t, 4, -1), mustEncode(t, 5, -1)},
+		{mustEncode(t, 30, -2), mustEncode(t, 4, -1), mustEncode(t, 50, -2)},
+		{mustEncode(t, 1, 0), mustEncode(t, 1, 0), mustEncode(t, 1414214, -6)},
+		{mustEncode(t, 1, 0), mustEncode(t, 2, 0), mustEncode(t, 2236068, -6)},
+		// The squares are far beyond the decimal32 range.
+		{mustEncode(t, 3, 90), mustEncode(t, 4, 90), mustEncode(t, 5, 90)},
+		{mustEncode(t, 3, -101), mustEncode(t, 4, -101), mustEncode(t, 5, -101)},
+		{mustEncode(t, 9999999, 90), mustEncode(t, 9999999, 90), inf32},
+		{mustEncode(t, 1, -101), mustEncode(t, 1, -101), mustEncode(t, 1, -101)},
+		{mustEncode(t, 5, 0), mustEncode(t, 0, 3), mustEncode(t, 5, 0)},
+		{mustEncode(t, 0, -2), mustEncode(t, 0, 0) | signMask, mustEncode(t, 0, -2)},
+		{nan32, inf32 | signMask, inf32},
+		{mustEncode(t, 1, 0), nan32, nan32},
+	}
+	for i, testCase := range testCases {
+		if r := testCase.x.Hypot(testCase.y); r != testCase.ref {
+			t.Errorf("testCase #%d: expect %v, got %v", i, testCase.ref, r)
+		}
+	}
+}
+
+func TestRemquo(t *testing.T) {
+	testCases := []struct {
+		x, y, rem Dec32
+		quo       int
+	}{
+		{mustEncode(t, 7, 0), mustEncode(t, 3, 0), mustEncode(t, 1, 0), 2},
+		{mustEncode(t, 8, 0), mustEncode(t, 3, 0), mustEncode(t, -1, 0), 3},
+		{mustEncode(t, -8, 0), mustEncode(t, 3, 0), mustEncode(t, 1, 0), -3},
+		{mustEncode(t, 8, 0), mustEncode(t, -3, 0), mustEncode(t, -1, 0), -3},
+		// Ties go to the even quotient.
+		{mustEncode(t, 5, 0), mustEncode(t, 2, 0), mustEncode(t, 1, 0), 2},
+		{mustEncode(t, 7, 0), mustEncode(t, 2, 0), mustEncode(t, -1, 0), 4},
+		{mustEncode(t, 10, -1), mustEncode(t, 3, -1), mustEncode(t, 1, -1), 3},
+		{mustEncode(t, 1, 0), mustEncode(t, 25, -2), mustEncode(t, 0, -2), 4},
+		{mustEncode(t, -1, 0), mustEncode(t, 25, -2), mustEncode(t, 0, -2) | signMask, -4},
+		{mustEncode(t, 1, -6), mustEncode(t, 3, 0), mustEncode(t, 1, -6), 0},
+		// The quotient 10^96 keeps only its low-order digits.
+		{mustEncode(t, 1, 90), mustEncode(t, 1, -6), mustEncode(t, 0, -6), 0},
+		{mustEncode(t, 1234567, 90), mustEncode(t, 7, -101), mustEncode(t, -3, -101), 8571429},
+		{mustEncode(t, 5, 0), inf32, mustEncode(t, 5, 0), 0},
+		{inf32, mustEncode(t, 5, 0), nan32, 0},
+		{mustEncode(t, 5, 0), mustEncode(t, 0, 0), nan32, 0},
+		{nan32, mustEncode(t, 5, 0), nan32, 0},
+	}
+	for i, testCase := range testCases {
+		rem, quo := testCase.x.Remquo(testCase.y)
+		if rem != testCase.rem || quo != testCase.quo {
+			t.Errorf("testCase #%d: %v rem %v: expect %v %d, got %v %d", i, testCase.x, testCase.y, testCase.rem, testCase.quo, rem, quo)
+		}
+	}
+}
+
+func TestModf(t *testing.T) {
+	testCases := []struct {
+		d, ipart, frac Dec32
+	}{
+		{mustEncode(t, 12345, -2), mustEncode(t, 123, 0), mustEncode(t, 45, -2)},
+		{mustEncode(t, -12345, -2), mustEncode(t, -123, 0), mustEncode(t, -45, -2)},
+		{mustEncode(t, 300, -2), mustEncode(t, 3, 0), mustEncode(t, 0, -2)},
+		{mustEncode(t, -5, -1), mustEncode(t, 0, 0) | signMask, mustEncode(t, -5, -1)},
+		{mustEncode(t, 9999999, -7), mustEncode(t, 0, 0), mustEncode(t, 9999999, -7)},
+		{mustEncode(t, 1, -101), mustEncode(t, 0, 0), mustEncode(t, 1, -101)},
+		{mustEncode(t, 15, 2), mustEncode(t, 15, 2), mustEncode(t, 0, 0)},
+		{mustEncode(t, 7, 0), mustEncode(t, 7, 0), mustEncode(t, 0, 0)},
+		{mustEncode(t, 0, -3) | signMask, mustEncode(t, 0, 0) | signMask, mustEncode(t, 0, -3) | signMask},
+		{inf32 | signMask, inf32 | signMask, nan32},
+		{Dec32(snanMask), nan32, nan32},
+	}
+	for i, testCase := range testCases {
+		ipart, frac := testCase.d.Modf()
+		if ipart != testCase.ipart || frac != testCase.frac {
+			t.Errorf("testCase #%d: Modf(%v): expect %v %v, got %v %v", i, testCase.d, testCase.ipart, testCase.frac, ipart, frac)
+		}
+	}
+}
+
+func TestIsMultipleOf(t *testing.T) {
+	testCases := []struct {
+		d, m Dec32
+		ok   bool
+	}{
+		{mustEncode(t, 115, -2), mustEncode(t, 5, -2), true},
+		{mustEncode(t, 116, -2), mustEncode(t, 5, -2), false},
+		{mustEncode(t, -115, -2), mustEncode(t, 5, -2), true},
+		{mustEncode(t, 115, -2), mustEncode(t, -5, -2), true},
+		{mustEncode(t, 3, 0), mustEncode(t, 1, -2), true},
+		{mustEncode(t, 3, 0), mustEncode(t, 15, -1), true},
+		{mustEncode(t, 3, 0), mustEncode(t, 7, -1), false},
+		{mustEncode(t, 1, 90), mustEncode(t, 3, -101), false},
+		{mustEncode(t, 3, 90), mustEncode(t, 3, -101), true},
+		{mustEncode(t, 1, -7), mustEncode(t, 1, -6), false},
+		{mustEncode(t, 0, 5), mustEncode(t, 7, -3), true},
+		{mustEncode(t, 5, 0), mustEncode(t, 0, 0), false},
+		{inf32, mustEncode(t, 1, 0), false},
+		{mustEncode(t, 1, 0), inf32, false},
+		{nan32, mustEncode(t, 1, 0), false},
+	}
+	for i, testCase := range testCases {
+		if ok := testCase.d.IsMultipleOf(testCase.m); ok != testCase.ok {
+			t.Errorf("testCase #%d: %v multiple of %v: expect %v, got %v", i, testCase.d, testCase.m, testCase.ok, ok)
+		}
+	}
+}
+
+func TestAddSub(t *testing.T) {
+	testCases := []struct {
+		x, y, sum, diff Dec32
+	}{
+		{mustEncode(t, 120, -2), mustEncode(t, 3, 0), mustEncode(t, 420, -2), mustEncode(t, -180, -2)},
+		{mustEncode(t, 1, 0), mustEncode(t, 1, -7), mustEncode(t, 1000000, -6), mustEncode(t, 9999999, -7)},
+		{mustEncode(t, 1, 0), mustEncode(t, 5, -7), mustEncode(t, 1000000, -6), mustEncode(t, 9999995, -7)},
+		{mustEncode(t, 1, 0), mustEncode(t, 15, -7), mustEncode(t, 1000002, -6), mustEncode(t, 9999985, -7)},
+		{mustEncode(t, 9999999, 0), mustEncode(t, 1, 0), mustEncode(t, 1000000, 1), mustEncode(t, 9999998, 0)},
+		{mustEncode(t, 2, 0), mustEncode(t, 2, 0), mustEncode(t, 4, 0), mustEncode(t, 0, 0)},
+		{mustEncode(t, -2, 0), mustEncode(t, 2, 0), mustEncode(t, 0, 0), mustEncode(t, -4, 0)},
+		{pack32(true, 0, 0), pack32(true, 0, -1), pack32(true, 0, -1), mustEncode(t, 0, -1)},
+		{mustEncode(t, 9999999, 90), mustEncode(t, 1, 90), inf32, mustEncode(t, 9999998, 90)},
+		{mustEncode(t, -9999999, 90), mustEncode(t, 1, 90), mustEncode(t, -9999998, 90), inf32 | signMask},
+		{mustEncode(t, 1, -101), mustEncode(t, 1, -101), mustEncode(t, 2, -101), mustEncode(t, 0, -101)},
+		{mustEncode(t, 1, 0), inf32, inf32, inf32 | signMask},
+		{inf32, inf32, inf32, nan32},
+		{inf32 | signMask, inf32, nan32, inf32 | signMask},
+		{nan32, mustEncode(t, 1, 0), nan32, nan32},
+		{mustEncode(t, 1, 0), Dec32(snanMask) | signMask, nan32 | signMask, nan32 | signMask},
+	}
+	for i, testCase := range testCases {
+		if r := testCase.x.Add(testCase.y); r != testCase.sum {
+			t.Errorf("testCase #%d: %v + %v: expect %v, got %v", i, testCase.x, testCase.y, testCase.sum, r)
+		}
+		if r := testCase.x.Sub(testCase.y); r != testCase.diff {
+			t.Errorf("testCase #%d: %v - %v: expect %v, got %v", i, testCase.x, testCase.y, testCase.diff, r)
+		}
+	}
+}
+
+func TestMul(t *testing.T) {
+	testCases := []struct {
+		x, y, ref Dec32
+	}{
+		{mustEncode(t, 120, -2), mustEncode(t, 3, 0), mustEncode(t, 360, -2)},
+		{mustEncode(t, -12, -1), mustEncode(t, 12, -1), mustEncode(t, -144, -2)},
+		{mustEncode(t, 1234567, 0), mustEncode(t, 1234567, 0), mustEncode(t, 1524156, 6)},
+		{mustEncode(t, 5, 45), mustEncode(t, 2, 50), mustEncode(t, 1000000, 90)},
+		{mustEncode(t, 1, 50), mustEncode(t, 1, 47), inf32},
+		{mustEncode(t, -1, 50), mustEncode(t, 1, 47), inf32 | signMask},
+		{mustEncode(t, 1234, -50), mustEncode(t, 1, -53), mustEncode(t, 12, -101)},
+		{mustEncode(t, 1, -60), mustEncode(t, 1, -60), mustEncode(t, 0, -101)},
+		{mustEncode(t, 0, 3), mustEncode(t, -5, -1), pack32(true, 0, 2)},
+		{mustEncode(t, -2, 0), inf32, inf32 | signMask},
+		{mustEncode(t, 0, 0), inf32, nan32},
+		{inf32 | signMask, inf32 | signMask, inf32},
+		{nan32, mustEncode(t, 0, 0), nan32},
+	}
+	for i, testCase := range testCases {
+		if r := testCase.x.Mul(testCase.y); r != testCase.ref {
+			t.Errorf("testCase #%d: %v * %v: expect %v, got %v", i, testCase.x, testCase.y, testCase.ref, r)
+		}
+	}
+}
+
+func TestDiv(t *testing.T) {
+	testCases := []struct {
+		x, y, ref Dec32
+	}{
+		{mustEncode(t, 360, -2), mustEncode(t, 3, 0), mustEncode(t, 120, -2)},
+		{mustEncode(t, 1, 0), mustEncode(t, 4, 0), mustEncode(t, 25, -2)},
+		{mustEncode(t, 2, 0), mustEncode(t, 3, 0), mustEncode(t, 6666667, -7)},
+		{mustEncode(t, -1, 0), mustEncode(t, 3, 0), mustEncode(t, -3333333, -7)},
+		{mustEncode(t, 100, 0), mustEncode(t, 4, 0), mustEncode(t, 25, 0)},
+		{mustEncode(t, 1, 2), mustEncode(t, 1, 0), mustEncode(t, 1, 2)},
+		{mustEncode(t, 0, 0), mustEncode(t, -5, -2), pack32(true, 0, 2)},
+		{mustEncode(t, 1, 90), mustEncode(t, 1, -10), inf32},
+		{mustEncode(t, 1, -90), mustEncode(t, 3, 7), mustEncode(t, 3333, -101)},
+		{mustEncode(t, 1, -90), mustEncode(t, 1, 20), mustEncode(t, 0, -101)},
+		{mustEncode(t, 1, 0), mustEncode(t, 0, 0), inf32},
+		{mustEncode(t, 1, 0), pack32(true, 0, 0), inf32 | signMask},
+		{mustEncode(t, 0, 0), mustEncode(t, 0, 0), nan32},
+		{mustEncode(t, -1, 0), inf32, pack32(true, 0, minExp)},
+		{inf32, mustEncode(t, -2, 0), inf32 | signMask},
+		{inf32, mustEncode(t, 0, 0), inf32},
+		{inf32, inf32, nan32},
+		{mustEncode(t, 1, 0), nan32, nan32},
+	}
+	for i, testCase := range testCases {
+		if r := testCase.x.Div(testCase.y); r != testCase.ref {
+			t.Errorf("testCase #%d: %v / %v: expect %v, got %v", i, testCase.x, testCase.y, testCase.ref, r)
+		}
+	}
+}
+
+func TestFMA(t *testing.T) {
+	testCases := []struct {
+		x, y, z, ref Dec32
+	}{
+		{mustEncode(t, 120, -2), mustEncode(t, 3, 0), mustEncode(t, 1, -3), mustEncode(t, 3601, -3)},
+		{mustEncode(t, 9999999, 0), mustEncode(t, 9999999, 0), mustEncode(t, -9999998, 7), mustEncode(t, 1, 0)},
+		{mustEncode(t, 1234567, 0), mustEncode(t, 1234567, 0), mustEncode(t, 1, 0), mustEncode(t, 1524156, 6)},
+		{mustEncode(t, 15, -1), mustEncode(t, 2, 0), mustEncode(t, 1, -8), mustEncode(t, 3000000, -6)},
+		{mustEncode(t, 1, 50), mustEncode(t, 1, 47), mustEncode(t, -1, 90), MaxDec32},
+		{mustEncode(t, 1, -60), mustEncode(t, 1, -60), mustEncode(t, 0, 0), mustEncode(t, 0, -101)},
+		{mustEncode(t, -2, 0), mustEncode(t, 3, 0), mustEncode(t, 6, 0), mustEncode(t, 0, 0)},
+		{mustEncode(t, 0, 0), mustEncode(t, 5, 0), pack32(true, 0, 0), mustEncode(t, 0, 0)},
+		{inf32, mustEncode(t, -2, 0), mustEncode(t, 5, 0), inf32 | signMask},
+		{inf32, mustEncode(t, 2, 0), inf32 | signMask, nan32},
+		{mustEncode(t, 2, 0), mustEncode(t, 3, 0), inf32 | signMask, inf32 | signMask},
+		{mustEncode(t, 0, 0), inf32, nan32 | signMask, nan32 | signMask},
+		{mustEncode(t, 2, 0), nan32, mustEncode(t, 1, 0), nan32},
+	}
+	for i, testCase := range testCases {
+		if r := testCase.x.FMA(testCase.y, testCase.z); r != testCase.ref {
+			t.Errorf("testCase #%d: %v * %v + %v: expect %v, got %v", i, testCase.x, testCase.y, testCase.z, testCase.ref, r)
+		}
+	}
+}
+
+func TestContextFMA(t *testing.T) {
+	c := Context{Mode: ToNegativeInf}
+	if r := c.FMA(mustEncode(t, 2, 0), mustEncode(t, 3, 0), mustEncode(t, -6, 0)); r != pack32(true, 0, 0) || c.Flags != 0 {
+		t.Errorf("expect -0 exactly, got %v %v", r, c.Flags)
+	}
+	c = Context{}
+	if r := c.FMA(mustEncode(t, 0, 0), inf32, nan32); !r.IsNaN() || c.Flags != Invalid {
+		t.Errorf("expect NaN and Invalid, got %v %v", r, c.Flags)
+	}
+	c = Context{}
+	if r := c.FMA(mustEncode(t, 15, -1), mustEncode(t, 2, 0), mustEncode(t, 1, -8)); r != mustEncode(t, 3000000, -6) || c.Flags != Inexact {
+		t.Errorf("expect 3.000000 and Inexact, got %v %v", r, c.Flags)
+	}
+}
+
+func TestRemMod(t *testing.T) {
+	testCases := []struct {
+		x, y, rem, mod Dec32
+	}{
+		{mustEncode(t, 10, 0), mustEncode(t, 3, 0), mustEncode(t, 1, 0), mustEncode(t, 1, 0)},
+		{mustEncode(t, -7, 0), mustEncode(t, 3, 0), mustEncode(t, -1, 0), mustEncode(t, -1, 0)},
+		{mustEncode(t, 7, 0), mustEncode(t, -3, 0), mustEncode(t, 1, 0), mustEncode(t, 1, 0)},
+		{mustEncode(t, 75, -1), mustEncode(t, 2, 0), mustEncode(t, -5, -1), mustEncode(t, 15, -1)},
+		{mustEncode(t, 123, -2), mustEncode(t, 5, -2), mustEncode(t, -2, -2), mustEncode(t, 3, -2)},
+		{mustEncode(t, -6, 0), mustEncode(t, 3, 0), pack32(true, 0, 0), pack32(true, 0, 0)},
+		{mustEncode(t, 1, 10), mustEncode(t, 7, 0), mustEncode(t, -3, 0), mustEncode(t, 4, 0)},
+		{mustEncode(t, 1, -1), SmallestSubnormalDec32, mustEncode(t, 0, -101), mustEncode(t, 0, -101)},
+		{mustEncode(t, 5, 0), inf32, mustEncode(t, 5, 0), mustEncode(t, 5, 0)},
+		{inf32, mustEncode(t, 5, 0), nan32, nan32},
+		{mustEncode(t, 5, 0), mustEncode(t, 0, 0), nan32, nan32},
+	}
+	for i, testCase := range testCases {
+		if r := testCase.x.Rem(testCase.y); r != testCase.rem {
+			t.Errorf("testCase #%d: Rem(%v, %v): expect %v, got %v", i, testCase.x, testCase.y, testCase.rem, r)
+		}
+		if r := testCase.x.Mod(testCase.y); r != testCase.mod {
+			t.Errorf("testCase #%d: Mod(%v, %v): expect %v, got %v", i, testCase.x, testCase.y, testCase.mod, r)
+		}
+	}
+}
+
+func TestSqrt(t *testing.T) {
+	testCases := []struct {
+		d, ref Dec32
+	}{
+		{mustEncode(t, 144, -2), mustEncode(t, 12, -1)},
+		{mustEncode(t, 100, 0), mustEncode(t, 10, 0)},
+		{mustEncode(t, 1, 1), mustEncode(t, 3162278, -6)},
+		{mustEncode(t, 2, 0), mustEncode(t, 1414214, -6)},
+		{mustEncode(t, 4, -1), mustEncode(t, 6324555, -7)},
+		{mustEncode(t, 25, -4), mustEncode(t, 5, -2)},
+		{mustEncode(t, 9999999, 90), mustEncode(t, 3162278, 42)},
+		{mustEncode(t, 1, -101), mustEncode(t, 3162278, -57)},
+		{mustEncode(t, 0, -3), mustEncode(t, 0, -2)},
+		{mustEncode(t, 0, 5) | signMask, mustEncode(t, 0, 2) | signMask},
+		{inf32, inf32},
+		{mustEncode(t, -1, 0), nan32},
+		{inf32 | signMask, nan32},
+		{nan32 | 4, nan32 | 4},
+	}
+	for i, testCase := range testCases {
+		if r := testCase.d.Sqrt(); r != testCase.ref {
+			t.Errorf("testCase #%d: Sqrt(%v): expect %v, got %v", i, testCase.d, testCase.ref, r)
+		}
+	}
+}
+
+func TestContextSqrt(t *testing.T) {
+	c := Context{Precision: 3, Mode: ToZero}
+	if r := c.Sqrt(mustEncode(t, 2, 0)); r != mustEncode(t, 141, -2) || c.Flags != Inexact {
+		t.Errorf("expect 1.41 and Inexact, got %v %v", r, c.Flags)
+	}
+	c = Context{}
+	if r := c.Sqrt(mustEncode(t, 49, 0)); r != mustEncode(t, 7, 0) || c.Flags != 0 {
+		t.Errorf("expect 7 exactly, got %v %v", r, c.Flags)
+	}
+	if r := c.Sqrt(mustEncode(t, -49, 0)); !r.IsNaN() || c.Flags != Invalid {
+		t.Errorf("expect NaN and Invalid, got %v %v", r, c.Flags)
+	}
+}
+
+func TestPow10(t *testing.T) {
+	testCases := []struct {
+		n   int
+		ref Dec32
+	}{
+		{0, mustEncode(t, 1, 0)},
+		{-2, mustEncode(t, 1, -2)},
+		{7, mustEncode(t, 1, 7)},
+		{90, mustEncode(t, 1, 90)},
+		{96, mustEncode(t, 1000000, 90)},
+		{97, inf32},
+		{1 << 40, inf32},
+		{-101, mustEncode(t, 1, -101)},
+		{-102, mustEncode(t, 0, -101)},
+		{-1 << 40, mustEncode(t, 0, -101)},
+	}
+	for i, testCase := range testCases {
+		if r := Pow10(testCase.n); r != testCase.ref {
+			t.Errorf("testCase #%d: Pow10(%d): expect %v, got %v", i, testCase.n, testCase.ref, r)
+		}
+	}
+}
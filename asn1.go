@@ -0,0 +1,107 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"errors"
+	"strconv"
+)
+
+// First contents octets of ASN.1 REAL values, from X.690 section 8.5.
+const (
+	asn1RealNR3    = 0x03
+	asn1RealPosInf = 0x40
+	asn1RealNegInf = 0x41
+	asn1RealNaN    = 0x42
+	asn1RealNegZ   = 0x43
+)
+
+var (
+	errASN1RealFormat = errors.New("decimal: malformed ASN.1 REAL")
+	errASN1RealBinary = errors.New("decimal: ASN.1 REAL in binary encoding")
+	errASN1RealRange  = errors.New("decimal: ASN.1 REAL out of decimal32 range")
+)
+
+// AppendASN1Real appends the contents octets of the ASN.1 REAL encoding of d
+// to buf, in the base-10 NR3 form that DER requires, and returns the
+// extended buffer. The tag and length are left to the caller, such as an
+// asn1.RawValue with tag 9. DER encodes values, not cohort members, so
+// trailing zeros of the coefficient are removed: 1.50 is encoded "15.E-1".
+// Positive zero has no contents octets, and negative zero, the infinities
+// and NaN are encoded as the special real values.
+func (d Dec32) AppendASN1Real(buf []byte) []byte {
+	switch {
+	case d.IsNaN():
+		return append(buf, asn1RealNaN)
+	case d.IsInf() && d.Sign() < 0:
+		return append(buf, asn1RealNegInf)
+	case d.IsInf():
+		return append(buf, asn1RealPosInf)
+	}
+	neg, coeff, exp := d.unpack()
+	switch {
+	case coeff == 0 && neg:
+		return append(buf, asn1RealNegZ)
+	case coeff == 0:
+		return buf
+	}
+	for coeff%10 == 0 {
+		coeff /= 10
+		exp++
+	}
+	buf = append(buf, asn1RealNR3)
+	if neg {
+		buf = append(buf, '-')
+	}
+	buf = strconv.AppendUint(buf, uint64(coeff), 10)
+	buf = append(buf, '.', 'E')
+	if exp == 0 {
+		return append(buf, '+', '0')
+	}
+	return strconv.AppendInt(buf, int64(exp), 10)
+}
+
+// DecodeASN1Real decodes the contents octets of an ASN.1 REAL value in the
+// base-10 encoding, in any of the ISO 6093 forms NR1, NR2 and NR3, as read
+// by ParseNR, or one of the special real values, rounding it to the nearest
+// decimal32, ties to even. It returns an error for the binary
+// encoding, for malformed values, and for values too large in magnitude for
+// decimal32.
+func DecodeASN1Real(b []byte) (Dec32, error) {
+	if len(b) == 0 {
+		return pack32(false, 0, 0), nil
+	}
+	switch b[0] {
+	case asn1RealPosInf, asn1RealNegInf, asn1RealNaN, asn1RealNegZ:
+		if len(b) != 1 {
+			return nan32, errASN1RealFormat
+		}
+		switch b[0] {
+		case asn1RealPosInf:
+			return inf32, nil
+		case asn1RealNegInf:
+			return inf32 | signMask, nil
+		case asn1RealNaN:
+			return nan32, nil
+		}
+		return pack32(true, 0, 0), nil
+	}
+	if b[0]&0x80 != 0 {
+		return nan32, errASN1RealBinary
+	}
+	form := NRForm(b[0])
+	if form < NR1 || form > NR3 {
+		return nan32, errASN1RealFormat
+	}
+	x, ok := parseNR(string(b[1:]), form)
+	if !ok {
+		return nan32, errASN1RealFormat
+	}
+	d, _ := x.dec32()
+	if d.IsInf() {
+		return d, errASN1RealRange
+	}
+	return d, nil
+}
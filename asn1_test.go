@@ -0,0 +1,72 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import "testing"
+
+func TestASN1Real(t *testing.T) {
+	testCases := []struct {
+		d   Dec32
+		ref string
+	}{
+		{mustEncode(t, 150, -2), "\x0315.E-1"},
+		{mustEncode(t, -1, 2), "\x03-1.E2"},
+		{mustEncode(t, 1200, 0), "\x0312.E2"},
+		{mustEncode(t, 7, 0), "\x037.E+0"},
+		{mustEncode(t, 9999999, -101), "\x039999999.E-101"},
+		{mustEncode(t, 0, 5), ""},
+		{mustEncode(t, 0, 0) | signMask, "\x43"},
+		{inf32, "\x40"},
+		{inf32 | signMask, "\x41"},
+		{nan32, "\x42"},
+	}
+	for i, testCase := range testCases {
+		b := testCase.d.AppendASN1Real(nil)
+		if string(b) != testCase.ref {
+			t.Errorf("testCase #%d: %v: expect %q, got %q", i, testCase.d, testCase.ref, b)
+		}
+		d, err := DecodeASN1Real(b)
+		switch {
+		case err != nil:
+			t.Errorf("testCase #%d: %v", i, err)
+		case d.IsNaN() != testCase.d.IsNaN() || d.Sign() != testCase.d.Sign():
+			t.Errorf("testCase #%d: expect %v, got %v", i, testCase.d, d)
+		case !d.IsNaN() && cmp32(d, testCase.d) != 0:
+			t.Errorf("testCase #%d: expect %v, got %v", i, testCase.d, d)
+		}
+	}
+}
+
+func TestDecodeASN1Real(t *testing.T) {
+	testCases := []struct {
+		b   string
+		ref string
+		ok  bool
+	}{
+		{"\x01  -42", "-42", true},
+		{"\x01+42", "42", true},
+		{"\x02 1,50", "1.50", true},
+		{"\x02.5", "0.5", true},
+		{"\x03 12,5E-3", "0.0125", true},
+		{"\x03-0.E+0", "-0", true},
+		{"\x0312345678.E0", "1.234568E+7", true},
+		{"\x031.E97", "Infinity", false},
+		{"\x011.5", "", false},
+		{"\x02 15", "", false},
+		{"\x021.5E3", "", false},
+		{"\x0315E3", "", false},
+		{"\x031.5", "", false},
+		{"\x03Inf.E0", "", false},
+		{"\x04 1", "", false},
+		{"\x80\x00\x01", "", false},
+		{"\x40\x00", "", false},
+	}
+	for i, testCase := range testCases {
+		d, err := DecodeASN1Real([]byte(testCase.b))
+		if (err == nil) != testCase.ok || testCase.ref != "" && d.String() != testCase.ref {
+			t.Errorf("testCase #%d: %q: expect %s %v, got %v %v", i, testCase.b, testCase.ref, testCase.ok, d, err)
+		}
+	}
+}
@@ -0,0 +1,437 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"math"
+	"math/big"
+)
+
+// bigDec is an exact intermediate value, coeff * 10^exp, used by operations
+// whose result may need more digits than a decimal format holds before it is
+// rounded.
+type bigDec struct {
+	neg   bool
+	coeff big.Int // magnitude, never negative
+	exp   int
+}
+
+var bigTen = big.NewInt(10)
+
+// pow10Cache holds powers of ten small enough to be commonly needed when
+// aligning or rounding decimal32 coefficients.
+var pow10Cache = func() []*big.Int {
+	cache := make([]*big.Int, 40)
+	p := big.NewInt(1)
+	for i := range cache {
+		cache[i] = new(big.Int).Set(p)
+		p.Mul(p, bigTen)
+	}
+	return cache
+}()
+
+// bigPow10 returns 10^n. The result must not be modified.
+func bigPow10(n int) *big.Int {
+	if n < len(pow10Cache) {
+		return pow10Cache[n]
+	}
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
+
+// bigDigits returns the number of decimal digits in the non-negative x.
+// Zero has one digit.
+func bigDigits(x *big.Int) int {
+	if x.Sign() == 0 {
+		return 1
+	}
+	// 1233/4096 is slightly more than log10(2), so n is an upper bound that
+	// is at most one too large.
+	n := (x.BitLen()*1233)>>12 + 1
+	if x.CmpAbs(bigPow10(n-1)) < 0 {
+		n--
+	}
+	return n
+}
+
+// newBigDec32 returns the exact value of a finite decimal32.
+func newBigDec32(d Dec32) *bigDec {
+	neg, coeff, exp := d.unpack()
+	x := &bigDec{neg: neg, exp: exp}
+	x.coeff.SetUint64(uint64(coeff))
+	return x
+}
+
+// newBigDec64 returns the exact value of a finite decimal64.
+func newBigDec64(d Dec64) *bigDec {
+	neg, coeff, exp := d.unpack()
+	x := &bigDec{neg: neg, exp: exp}
+	x.coeff.SetUint64(coeff)
+	return x
+}
+
+// newBigDecInt64 returns n as a bigDec with exponent 0.
+func newBigDecInt64(n int64) *bigDec {
+	x := &bigDec{neg: n < 0}
+	x.coeff.Abs(x.coeff.SetInt64(n))
+	return x
+}
+
+// newBigDecFloat64 returns the exact value of the finite f as a bigDec. A
+// binary fraction m * 2^-k is exactly m * 5^k * 10^-k.
+func newBigDecFloat64(f float64) *bigDec {
+	x := &bigDec{neg: math.Signbit(f)}
+	frac, exp := math.Frexp(math.Abs(f))
+	m := uint64(frac * (1 << 53))
+	exp -= 53
+	for m != 0 && m&1 == 0 {
+		m >>= 1
+		exp++
+	}
+	x.coeff.SetUint64(m)
+	if exp >= 0 {
+		x.coeff.Lsh(&x.coeff, uint(exp))
+		return x
+	}
+	var five big.Int
+	x.coeff.Mul(&x.coeff, five.Exp(big.NewInt(5), big.NewInt(int64(-exp)), nil))
+	x.exp = exp
+	return x
+}
+
+// cmp compares the values of x and y, returning -1, 0 or +1. Zeros of
+// either sign compare equal.
+func (x *bigDec) cmp(y *bigDec) int {
+	return new(bigDec).sub(x, y).signed().Sign()
+}
+
+// isZero returns whether x is a zero of either sign.
+func (x *bigDec) isZero() bool {
+	return x.coeff.Sign() == 0
+}
+
+// signed returns the coefficient of x with its sign applied.
+func (x *bigDec) signed() *big.Int {
+	c := new(big.Int).Set(&x.coeff)
+	if x.neg {
+		c.Neg(c)
+	}
+	return c
+}
+
+// setSigned sets z to c * 10^exp. A zero c keeps the sign given by neg.
+func (z *bigDec) setSigned(c *big.Int, exp int, neg bool) *bigDec {
+	z.coeff.Abs(c)
+	z.exp = exp
+	if c.Sign() != 0 {
+		neg = c.Sign() < 0
+	}
+	z.neg = neg
+	return z
+}
+
+// add sets z to the exact sum x+y and returns z. The exponent of the result is
+// the smaller of the operand exponents. An exact zero sum is positive unless
+// both operands are negative.
+func (z *bigDec) add(x, y *bigDec) *bigDec {
+	exp := x.exp
+	if y.exp < exp {
+		exp = y.exp
+	}
+	a, b := x.signed(), y.signed()
+	a.Mul(a, bigPow10(x.exp-exp))
+	b.Mul(b, bigPow10(y.exp-exp))
+	return z.setSigned(a.Add(a, b), exp, x.neg && y.neg)
+}
+
+// sub sets z to the exact difference x-y and returns z.
+func (z *bigDec) sub(x, y *bigDec) *bigDec {
+	ny := &bigDec{neg: !y.neg, exp: y.exp}
+	ny.coeff.Set(&y.coeff)
+	return z.add(x, ny)
+}
+
+// mul sets z to the exact product x*y and returns z.
+func (z *bigDec) mul(x, y *bigDec) *bigDec {
+	z.coeff.Mul(&x.coeff, &y.coeff)
+	z.exp = x.exp + y.exp
+	z.neg = x.neg != y.neg
+	return z
+}
+
+// quo sets z to the quotient x/y, which must have a nonzero divisor, and
+// returns z. If the quotient is exact, z holds it with the exponent closest to
+// x.exp - y.exp. Otherwise z holds at least prec+1 significant digits of the
+// quotient followed by a nonzero sticky digit, so that rounding z to prec or
+// fewer digits is correctly rounded.
+func (z *bigDec) quo(x, y *bigDec, prec int) *bigDec {
+	neg := x.neg != y.neg
+	ideal := x.exp - y.exp
+	shift := prec + 1 + bigDigits(&y.coeff) - bigDigits(&x.coeff)
+	if shift < 0 {
+		shift = 0
+	}
+	var n, r big.Int
+	n.Mul(&x.coeff, bigPow10(shift))
+	z.coeff.QuoRem(&n, &y.coeff, &r)
+	z.exp = ideal - shift
+	z.neg = neg
+	if r.Sign() == 0 {
+		z.reduce(ideal)
+		return z
+	}
+	z.coeff.Mul(&z.coeff, bigTen)
+	z.coeff.Add(&z.coeff, big.NewInt(1))
+	z.exp--
+	return z
+}
+
+// sqrt sets z to the square root of x, which must not be negative unless it
+// is a zero, and returns z. If the root is exact, z holds it with the exponent
+// closest to floor(x.exp/2). Otherwise z holds at least prec+1 significant
+// digits of the root followed by a nonzero sticky digit, as for quo. The root
+// of a zero keeps its sign.
+func (z *bigDec) sqrt(x *bigDec, prec int) *bigDec {
+	ideal := x.exp >> 1
+	if x.isZero() {
+		z.coeff.SetInt64(0)
+		z.exp, z.neg = ideal, x.neg
+		return z
+	}
+	// Scale the coefficient to an even exponent and enough digits that its
+	// integer root has at least prec+1.
+	shift := max(2*(prec+1)-bigDigits(&x.coeff), 0)
+	if (x.exp-shift)&1 != 0 {
+		shift++
+	}
+	var c, sq big.Int
+	c.Mul(&x.coeff, bigPow10(shift))
+	z.exp, z.neg = (x.exp-shift)/2, false
+	z.coeff.Sqrt(&c)
+	if sq.Mul(&z.coeff, &z.coeff).Cmp(&c) == 0 {
+		z.reduce(ideal)
+		return z
+	}
+	z.coeff.Mul(&z.coeff, bigTen)
+	z.coeff.Add(&z.coeff, big.NewInt(1))
+	z.exp--
+	return z
+}
+
+// reduce removes trailing zeros from the coefficient of x while its exponent
+// is below ideal, so that exact results carry the preferred exponent.
+func (x *bigDec) reduce(ideal int) {
+	if x.isZero() {
+		if x.exp < ideal {
+			x.exp = ideal
+		}
+		return
+	}
+	var q, r big.Int
+	for x.exp < ideal {
+		q.QuoRem(&x.coeff, bigTen, &r)
+		if r.Sign() != 0 {
+			return
+		}
+		x.coeff.Set(&q)
+		x.exp++
+	}
+}
+
+// scaledInt sets c to the magnitude of x * 10^scale, and reports whether that
+// value is an integer.
+func (x *bigDec) scaledInt(c *big.Int, scale int) bool {
+	e := x.exp + scale
+	if e >= 0 {
+		c.Mul(&x.coeff, bigPow10(e))
+		return true
+	}
+	var r big.Int
+	c.QuoRem(&x.coeff, bigPow10(-e), &r)
+	return r.Sign() == 0
+}
+
+// scaledInt64 returns x * 10^scale as an int64, and whether that value is an
+// integer within the int64 range.
+func (x *bigDec) scaledInt64(scale int) (int64, bool) {
+	var c big.Int
+	if !x.scaledInt(&c, scale) {
+		return 0, false
+	}
+	if x.neg {
+		c.Neg(&c)
+	}
+	if !c.IsInt64() {
+		return 0, false
+	}
+	return c.Int64(), true
+}
+
+// shr drops the low n decimal digits of the coefficient of x, rounding half to
+// even, and reports whether any of the discarded digits were nonzero.
+func (x *bigDec) shr(n int) (inexact bool) {
+	return x.shrMode(n, ToNearestEven)
+}
+
+// shrMode is like shr but rounds with the given mode.
+func (x *bigDec) shrMode(n int, mode RoundingMode) (inexact bool) {
+	if n <= 0 {
+		return false
+	}
+	if n > bigDigits(&x.coeff) {
+		// The coefficient is less than half of 10^n; avoid computing a
+		// power of ten that may be enormous.
+		inexact = x.coeff.Sign() != 0
+		x.coeff.SetInt64(0)
+		x.exp += n
+		if inexact && mode.roundUp(x.neg, &x.coeff, -1) {
+			x.coeff.SetInt64(1)
+		}
+		return inexact
+	}
+	var r big.Int
+	m := bigPow10(n)
+	x.coeff.QuoRem(&x.coeff, m, &r)
+	x.exp += n
+	if r.Sign() == 0 {
+		return false
+	}
+	r.Lsh(&r, 1)
+	if mode.roundUp(x.neg, &x.coeff, r.Cmp(m)) {
+		x.coeff.Add(&x.coeff, big.NewInt(1))
+	}
+	return true
+}
+
+// rescale sets the exponent of x to exp, rounding ties to even or appending
+// zeros to the coefficient as required, and reports whether any nonzero digits
+// were discarded.
+func (x *bigDec) rescale(exp int) (inexact bool) {
+	return x.rescaleMode(exp, ToNearestEven)
+}
+
+// rescaleMode is like rescale but rounds with the given mode.
+func (x *bigDec) rescaleMode(exp int, mode RoundingMode) (inexact bool) {
+	if x.exp < exp {
+		return x.shrMode(exp-x.exp, mode)
+	}
+	x.coeff.Mul(&x.coeff, bigPow10(x.exp-exp))
+	x.exp = exp
+	return false
+}
+
+// roundDigits rounds x in place to at most n significant digits with the given
+// mode, and reports whether any nonzero digits were discarded.
+func (x *bigDec) roundDigits(n int, mode RoundingMode) (inexact bool) {
+	inexact = x.shrMode(bigDigits(&x.coeff)-n, mode)
+	if bigDigits(&x.coeff) > n {
+		// Rounding carried into an extra digit, which must be a zero.
+		x.shr(1)
+	}
+	return inexact
+}
+
+// dec32 rounds x to the nearest decimal32 value, ties to even, and reports
+// whether the result is exact. x is not modified.
+func (x *bigDec) dec32() (Dec32, bool) {
+	r := &bigDec{neg: x.neg, exp: x.exp}
+	r.coeff.Set(&x.coeff)
+	inexact, overflow := r.round32()
+	if overflow {
+		return inf32 | signOf(r.neg), false
+	}
+	return pack32(r.neg, uint32(r.coeff.Uint64()), r.exp), !inexact
+}
+
+// dec64 rounds x to the nearest decimal64 value, ties to even, and reports
+// whether the result is exact. x is not modified.
+func (x *bigDec) dec64() (Dec64, bool) {
+	r := &bigDec{neg: x.neg, exp: x.exp}
+	r.coeff.Set(&x.coeff)
+	inexact, overflow := r.roundFormat(format64, format64.digits, ToNearestEven)
+	if overflow {
+		return inf64 | signOf64(r.neg), false
+	}
+	return pack64(r.neg, r.coeff.Uint64(), r.exp), !inexact
+}
+
+// round32 rounds x in place to the nearest value in the decimal32 value set,
+// ties to even, reporting whether the result is inexact and whether it
+// overflowed. Results with an exponent below the decimal32 range are rounded
+// to the minimum exponent. Results too large to represent overflow, leaving x
+// unspecified; the exponent of a zero is clamped into range.
+func (x *bigDec) round32() (inexact, overflow bool) {
+	return x.roundPrec(7, ToNearestEven)
+}
+
+// roundPrec is like round32, but rounds x to at most prec significant
+// digits, which must be between 1 and 7, with the given mode.
+func (x *bigDec) roundPrec(prec int, mode RoundingMode) (inexact, overflow bool) {
+	return x.roundFormat(format32, prec, mode)
+}
+
+// A decFormat gives the limits of a decimal interchange format.
+type decFormat struct {
+	digits         int // coefficient digits
+	minExp, maxExp int
+}
+
+var (
+	format32 = decFormat{7, minExp, maxExp}
+	format64 = decFormat{16, minExp64, maxExp64}
+)
+
+// roundFormat is like roundPrec for the value set of the format f, rounding
+// x to at most prec significant digits, between 1 and f.digits.
+func (x *bigDec) roundFormat(f decFormat, prec int, mode RoundingMode) (inexact, overflow bool) {
+	if x.isZero() {
+		if x.exp < f.minExp {
+			x.exp = f.minExp
+		} else if x.exp > f.maxExp {
+			x.exp = f.maxExp
+		}
+		return false, false
+	}
+	drop := bigDigits(&x.coeff) - prec
+	if drop < 0 {
+		drop = 0
+	}
+	if x.exp+drop < f.minExp {
+		drop = f.minExp - x.exp
+	}
+	inexact = x.shrMode(drop, mode)
+	if bigDigits(&x.coeff) > prec {
+		// Rounding carried into an extra digit, which must be a zero.
+		x.shr(1)
+	}
+	if x.isZero() {
+		x.exp = f.minExp
+		return inexact, false
+	}
+	if x.exp > f.maxExp {
+		pad := x.exp - f.maxExp
+		if bigDigits(&x.coeff)+pad > f.digits {
+			return true, true
+		}
+		x.coeff.Mul(&x.coeff, bigPow10(pad))
+		x.exp = f.maxExp
+	}
+	return inexact, false
+}
+
+// signOf returns the sign bit of a decimal32 value that is negative if neg.
+func signOf(neg bool) Dec32 {
+	if neg {
+		return signMask
+	}
+	return 0
+}
+
+// signOf64 returns the sign bit of a decimal64 value that is negative if neg.
+func signOf64(neg bool) Dec64 {
+	if neg {
+		return signMask64
+	}
+	return 0
+}
@@ -0,0 +1,40 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+// bpsExp is the power of ten of one basis point.
+const bpsExp = -4
+
+// FromBasisPoints returns the fraction expressed by bps basis points, such
+// that 25 basis points is 0.0025, by shifting the exponent, and whether the
+// result is exact.
+func FromBasisPoints(bps Dec32) (Dec32, bool) {
+	return bps.scaleb(bpsExp)
+}
+
+// ToBasisPoints returns the fraction d in basis points, such that 0.0025 is
+// 25 basis points, by shifting the exponent, and whether the result is exact.
+func (d Dec32) ToBasisPoints() (Dec32, bool) {
+	return d.scaleb(-bpsExp)
+}
+
+// ApplyBps returns the amount of bps basis points of d, such as the fee on a
+// payment, and whether the result is exact. The product is computed exactly
+// and rounded once, ties to even, so 0.25 basis points of 1234.56 is
+// 0.03086400. Multiplying an infinity by zero gives NaN.
+func (d Dec32) ApplyBps(bps Dec32) (Dec32, bool) {
+	switch {
+	case d.IsNaN() || bps.IsNaN():
+		return nan32, false
+	case d.IsInf() || bps.IsInf():
+		if d.Zero() || bps.Zero() {
+			return nan32, false
+		}
+		return inf32 | signOf(d.Sign() != bps.Sign()), true
+	}
+	x := new(bigDec).mul(newBigDec32(d), newBigDec32(bps))
+	x.exp += bpsExp
+	return x.dec32()
+}
@@ -0,0 +1,56 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import "testing"
+
+func TestBasisPoints(t *testing.T) {
+	testCases := []struct {
+		bps, frac Dec32
+	}{
+		{mustEncode(t, 25, 0), mustEncode(t, 25, -4)},
+		{mustEncode(t, 25, -1), mustEncode(t, 25, -5)},
+		{mustEncode(t, -10000, 0), mustEncode(t, -10000, -4)},
+		{inf32, inf32},
+	}
+	for i, testCase := range testCases {
+		if d, ok := FromBasisPoints(testCase.bps); d != testCase.frac || !ok {
+			t.Errorf("testCase #%d: expect %v, got %v %v", i, testCase.frac, d, ok)
+		}
+		if d, ok := testCase.frac.ToBasisPoints(); d != testCase.bps || !ok {
+			t.Errorf("testCase #%d: expect %v, got %v %v", i, testCase.bps, d, ok)
+		}
+	}
+	if d, ok := FromBasisPoints(mustEncode(t, 1, -98)); ok || d != mustEncode(t, 0, -101) {
+		t.Errorf("expect inexact underflow, got %v %v", d, ok)
+	}
+	if d, ok := mustEncode(t, 1234567, 90).ToBasisPoints(); ok || d != inf32 {
+		t.Errorf("expect overflow, got %v %v", d, ok)
+	}
+}
+
+func TestApplyBps(t *testing.T) {
+	testCases := []struct {
+		d, bps Dec32
+		ref    Dec32
+		exact  bool
+	}{
+		{mustEncode(t, 123456, -2), mustEncode(t, 25, -2), mustEncode(t, 3086400, -8), true},
+		{mustEncode(t, 10000, -2), mustEncode(t, 30, 0), mustEncode(t, 300000, -6), true},
+		{mustEncode(t, -5000, 0), mustEncode(t, 15, 0), mustEncode(t, -75000, -4), true},
+		// 9999999 * 9999999e-4 rounds to seven digits.
+		{mustEncode(t, 9999999, 0), mustEncode(t, 9999999, 0), mustEncode(t, 9999998, 3), false},
+		{inf32, mustEncode(t, 1, 0), inf32, true},
+		{inf32, mustEncode(t, -1, 0), inf32 | signMask, true},
+		{inf32, mustEncode(t, 0, 0), nan32, false},
+		{mustEncode(t, 1, 0), nan32, nan32, false},
+	}
+	for i, testCase := range testCases {
+		d, exact := testCase.d.ApplyBps(testCase.bps)
+		if d != testCase.ref || exact != testCase.exact {
+			t.Errorf("testCase #%d: expect %v %v, got %v %v", i, testCase.ref, testCase.exact, d, exact)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+var (
+	errCanonicalLength = errors.New("decimal: canonical encoding is not 4 bytes")
+	errEncodingLength  = errors.New("decimal: decimal32 encoding is not 4 bytes")
+	errNotCanonical    = errors.New("decimal: encoding is not canonical")
+)
+
+const (
+	// nanPayloadMask selects the trailing significand of a NaN, its
+	// payload, which is canonical up to maxPayload.
+	nanPayloadMask = 0x000fffff
+	maxPayload     = 999999
+)
+
+// canonicalValue returns the representative of the numeric value of d that
+// CanonicalBytes encodes.
+func (d Dec32) canonicalValue() Dec32 {
+	switch {
+	case d.IsNaN():
+		return nan32
+	case d.IsInf():
+		return inf32 | signOf(d.Sign() < 0)
+	case d.Zero():
+		return pack32(false, 0, 0)
+	}
+	return d.reduce()
+}
+
+// CanonicalBytes returns an encoding of d that is the same for all decimals
+// with the same numeric value, for content hashing and digital signatures:
+// the 4-byte big-endian interchange encoding of d with trailing zeros removed
+// from its coefficient as far as the exponent range allows. All zeros, of
+// either sign and any exponent, are encoded as 0E+0, and all NaNs, quiet or
+// signaling, as a quiet NaN without payload, so that 1.50 and 1.5 encode
+// alike, as do -0 and 0.00. Non-canonical encodings are encoded as the
+// values they denote.
+func (d Dec32) CanonicalBytes() []byte {
+	return binary.BigEndian.AppendUint32(make([]byte, 0, 4), uint32(d.canonicalValue()))
+}
+
+// DecodeCanonical decodes a value encoded by CanonicalBytes, returning an
+// error if b is not exactly the canonical encoding of the value it denotes,
+// so that a signed value has only one accepted encoding.
+func DecodeCanonical(b []byte) (Dec32, error) {
+	if len(b) != 4 {
+		return nan32, errCanonicalLength
+	}
+	d := Dec32(binary.BigEndian.Uint32(b))
+	if d.canonicalValue() != d {
+		return nan32, errNotCanonical
+	}
+	return d, nil
+}
+
+// IsCanonical returns whether d is a canonical encoding, as IEEE 754-2008
+// defines them: not a finite value with a coefficient above 9,999,999, an
+// infinity with any bits set beyond its sign and combination field, or a
+// NaN with bits set between its signaling bit and its payload or a payload
+// above 999,999. Operations produce only canonical encodings, but other
+// implementations may send non-canonical ones.
+func (d Dec32) IsCanonical() bool {
+	return d.canonicalEncoding() == d
+}
+
+// Canonical returns the canonical encoding of the value that d encodes: a
+// coefficient above 9,999,999 becomes a zero with the same sign and
+// exponent, an infinity loses its trailing bits, and a NaN loses the bits
+// after its signaling bit other than its payload, or its payload too if that
+// is above 999,999. Canonical encodings are returned unchanged, so
+// d.Canonical() == d exactly when d.IsCanonical().
+func (d Dec32) Canonical() Dec32 {
+	return d.canonicalEncoding()
+}
+
+// canonicalEncoding returns the canonical encoding of the value encoded by d,
+// as IEEE 754-2008 treats non-canonical encodings: a coefficient above
+// 9,999,999 denotes zero, an infinity ignores all bits but its sign, and a
+// NaN ignores the bits between its signaling bit and its payload, and a
+// payload above 999,999, taking them as zero. Canonical encodings are
+// returned unchanged.
+func (d Dec32) canonicalEncoding() Dec32 {
+	switch {
+	case d.IsNaN():
+		c := d & (signMask | snanMask | nanPayloadMask)
+		if c&nanPayloadMask > maxPayload {
+			c &^= nanPayloadMask
+		}
+		return c
+	case d.IsInf():
+		return d & (signMask | inf32)
+	}
+	return d.NormalizeZero()
+}
+
+// DecodeStrict decodes the 4-byte big-endian decimal32 interchange encoding
+// b, returning an error if it is not canonical, such as a coefficient above
+// 9,999,999, an infinity with trailing bits set or a NaN with a payload out
+// of range, so that malformed input fails fast.
+func DecodeStrict(b []byte) (Dec32, error) {
+	if len(b) != 4 {
+		return nan32, errEncodingLength
+	}
+	d := Dec32(binary.BigEndian.Uint32(b))
+	if d.canonicalEncoding() != d {
+		return nan32, errNotCanonical
+	}
+	return d, nil
+}
+
+// DecodeLenient decodes the 4-byte big-endian decimal32 interchange
+// encoding b, repairing a non-canonical encoding to the canonical encoding
+// of the value the standard takes it to denote: an oversize coefficient
+// becomes a zero of the same sign and exponent, and the ignored bits of
+// infinities and NaNs are cleared. It returns an error only if b is not 4
+// bytes long.
+func DecodeLenient(b []byte) (Dec32, error) {
+	if len(b) != 4 {
+		return nan32, errEncodingLength
+	}
+	return Dec32(binary.BigEndian.Uint32(b)).canonicalEncoding(), nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCanonicalBytes(t *testing.T) {
+	testCases := []struct {
+		ds  []Dec32
+		ref []byte
+	}{
+		{[]Dec32{mustEncode(t, 15, -1), mustEncode(t, 150, -2), mustEncode(t, 1500000, -6)}, []byte{0x32, 0x00, 0x00, 0x0f}},
+		{[]Dec32{mustEncode(t, 0, 0), mustEncode(t, 0, -2) | signMask, mustEncode(t, 0, 90), Dec32(0x6cbfffff)}, []byte{0x32, 0x80, 0x00, 0x00}},
+		{[]Dec32{mustEncode(t, 1000000, 90)}, []byte{0x5f, 0x8f, 0x42, 0x40}},
+		{[]Dec32{mustEncode(t, -12, 0), mustEncode(t, -1200, -2)}, []byte{0xb2, 0x80, 0x00, 0x0c}},
+		{[]Dec32{inf32, inf32 | 0x12345}, []byte{0x78, 0, 0, 0}},
+		{[]Dec32{nan32, nan32 | signMask, Dec32(snanMask | 7)}, []byte{0x7c, 0, 0, 0}},
+	}
+	for i, testCase := range testCases {
+		for _, d := range testCase.ds {
+			b := d.CanonicalBytes()
+			if !bytes.Equal(b, testCase.ref) {
+				t.Errorf("testCase #%d: %v: expect %x, got %x", i, d, testCase.ref, b)
+			}
+			c, err := DecodeCanonical(b)
+			if err != nil {
+				t.Errorf("testCase #%d: %v", i, err)
+			} else if !d.IsNaN() && cmp32(c, d) != 0 {
+				t.Errorf("testCase #%d: expect %v, got %v", i, d, c)
+			}
+		}
+	}
+	for _, b := range [][]byte{{0x32, 0x00, 0x00, 0x96}, {0xb2, 0x80, 0, 0}, {0x6c, 0xbf, 0xff, 0xff}, {0x7e, 0, 0, 0}, {0x78, 0, 0, 1}, {0x32, 0x80, 0}} {
+		if d, err := DecodeCanonical(b); err == nil {
+			t.Errorf("%x: expect error, got %v", b, d)
+		}
+	}
+}
+
+func TestDecodeStrictLenient(t *testing.T) {
+	testCases := []struct {
+		b         []byte
+		lenient   Dec32
+		canonical bool
+	}{
+		{[]byte{0x32, 0x00, 0x00, 0x0f}, mustEncode(t, 15, -1), true},
+		{[]byte{0xb2, 0x80, 0x00, 0x00}, pack32(true, 0, 0), true},
+		{[]byte{0x6c, 0xb8, 0x96, 0x7f}, mustEncode(t, 9999999, 0), true},
+		// A coefficient of 10,000,000 or more denotes zero.
+		{[]byte{0x6c, 0xb8, 0x96, 0x80}, mustEncode(t, 0, 0), false},
+		{[]byte{0xec, 0xbf, 0xff, 0xff}, pack32(true, 0, 0), false},
+		{[]byte{0x78, 0, 0, 0}, inf32, true},
+		{[]byte{0xfa, 0x01, 0x02, 0x03}, inf32 | signMask, false},
+		{[]byte{0x7c, 0x0f, 0x42, 0x3f}, nan32 | maxPayload, true},
+		{[]byte{0x7e, 0, 0, 1}, Dec32(snanMask | 1), true},
+		{[]byte{0x7c, 0x0f, 0x42, 0x40}, nan32, false},
+		{[]byte{0xfe, 0x10, 0, 5}, Dec32(snanMask|5) | signMask, false},
+	}
+	for i, testCase := range testCases {
+		d, err := DecodeLenient(testCase.b)
+		if err != nil || d != testCase.lenient {
+			t.Errorf("testCase #%d: expect lenient %x, got %x %v", i, uint32(testCase.lenient), uint32(d), err)
+		}
+		d, err = DecodeStrict(testCase.b)
+		if testCase.canonical && (err != nil || d != testCase.lenient) {
+			t.Errorf("testCase #%d: expect strict %x, got %x %v", i, uint32(testCase.lenient), uint32(d), err)
+		}
+		if !testCase.canonical && err == nil {
+			t.Errorf("testCase #%d: expect error, got %x", i, uint32(d))
+		}
+	}
+	for _, b := range [][]byte{nil, {0x32, 0x80, 0}, {0x32, 0x80, 0, 0, 0}} {
+		if _, err := DecodeStrict(b); err == nil {
+			t.Errorf("%x: expect strict length error", b)
+		}
+		if _, err := DecodeLenient(b); err == nil {
+			t.Errorf("%x: expect lenient length error", b)
+		}
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	testCases := []struct {
+		d, ref Dec32
+	}{
+		{mustEncode(t, 15, -1), mustEncode(t, 15, -1)},
+		{mustEncode(t, -9999999, 90), mustEncode(t, -9999999, 90)},
+		{pack32(true, 0, 5), pack32(true, 0, 5)},
+		{Dec32(0x6cb89680), mustEncode(t, 0, 0)},
+		{Dec32(0xefffffff), pack32(true, 0, 26)},
+		{inf32 | 0x01020304, inf32},
+		{inf32 | signMask | 0x00000001, inf32 | signMask},
+		{nan32 | 42, nan32 | 42},
+		{nan32 | 0x01f00000 | 42, nan32 | 42},
+		{nan32 | 1000000, nan32},
+		{Dec32(snanMask) | signMask | 0x00100007, Dec32(snanMask) | signMask | 7},
+	}
+	for i, testCase := range testCases {
+		c := testCase.d.Canonical()
+		if c != testCase.ref {
+			t.Errorf("testCase #%d: %x: expect %x, got %x", i, uint32(testCase.d), uint32(testCase.ref), uint32(c))
+		}
+		if canonical := testCase.d == testCase.ref; testCase.d.IsCanonical() != canonical {
+			t.Errorf("testCase #%d: %x: expect IsCanonical %v", i, uint32(testCase.d), canonical)
+		}
+		if !c.IsCanonical() {
+			t.Errorf("testCase #%d: expect %x canonical", i, uint32(c))
+		}
+		if testCase.d.CmpTotal(c) != 0 || !testCase.d.IsNaN() && !testCase.d.Equal(c) {
+			t.Errorf("testCase #%d: expect %x to compare equal to %x", i, uint32(testCase.d), uint32(c))
+		}
+	}
+}
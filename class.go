@@ -0,0 +1,84 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import "strconv"
+
+// Abs returns d with its sign bit cleared. Like the other sign operations,
+// it changes only the sign bit, so it applies to NaNs too, is always exact
+// and raises no exceptions.
+func (d Dec32) Abs() Dec32 {
+	return d &^ signMask
+}
+
+// Neg returns d with its sign bit reversed, so that the negation of 0 is -0.
+func (d Dec32) Neg() Dec32 {
+	return d ^ signMask
+}
+
+// CopySign returns d with the sign bit of sign.
+func (d Dec32) CopySign(sign Dec32) Dec32 {
+	return d&^signMask | sign&signMask
+}
+
+// A Class is one of the ten classes into which IEEE 754-2008 divides the
+// values of a decimal format.
+type Class uint8
+
+// The classes, in the order of IEEE 754-2008.
+const (
+	ClassSignalingNaN Class = iota
+	ClassQuietNaN
+	ClassNegativeInfinity
+	ClassNegativeNormal
+	ClassNegativeSubnormal
+	ClassNegativeZero
+	ClassPositiveZero
+	ClassPositiveSubnormal
+	ClassPositiveNormal
+	ClassPositiveInfinity
+)
+
+var classNames = [...]string{
+	"sNaN", "NaN",
+	"-Infinity", "-Normal", "-Subnormal", "-Zero",
+	"+Zero", "+Subnormal", "+Normal", "+Infinity",
+}
+
+// String returns the name of the class in the decNumber specification, such
+// as "+Normal" or "sNaN".
+func (c Class) String() string {
+	if int(c) < len(classNames) {
+		return classNames[c]
+	}
+	return "Class(" + strconv.Itoa(int(c)) + ")"
+}
+
+// Class returns the class of d. Subnormal values are those finite nonzero
+// values below 1E-95 in magnitude, whose adjusted exponent is below -95.
+// Non-canonical coefficients are zeros.
+func (d Dec32) Class() Class {
+	neg := d.Sign() < 0
+	var c Class
+	switch {
+	case d.IsSignalingNaN():
+		return ClassSignalingNaN
+	case d.IsNaN():
+		return ClassQuietNaN
+	case d.IsInf():
+		c = ClassPositiveInfinity
+	case d.Zero():
+		c = ClassPositiveZero
+	case d.AdjustedExponent() < minExp+6:
+		c = ClassPositiveSubnormal
+	default:
+		c = ClassPositiveNormal
+	}
+	if neg {
+		// The negative classes mirror the positive ones about the zeros.
+		c = ClassNegativeZero + ClassPositiveZero - c
+	}
+	return c
+}
@@ -0,0 +1,65 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import "testing"
+
+func TestSignOps(t *testing.T) {
+	snan, _ := SignalingNaN(12)
+	testCases := []struct {
+		d, sign       Dec32
+		abs, neg, cps Dec32
+	}{
+		{mustEncode(t, -125, -2), mustEncode(t, 1, 0),
+			mustEncode(t, 125, -2), mustEncode(t, 125, -2), mustEncode(t, 125, -2)},
+		{mustEncode(t, 0, 3), mustEncode(t, -1, 0),
+			mustEncode(t, 0, 3), mustEncode(t, 0, 3) | signMask, mustEncode(t, 0, 3) | signMask},
+		{inf32, nan32 | signMask, inf32, inf32 | signMask, inf32 | signMask},
+		{snan | signMask, inf32, snan, snan, snan},
+	}
+	for i, testCase := range testCases {
+		if r := testCase.d.Abs(); r != testCase.abs {
+			t.Errorf("testCase #%d: Abs: expect %08x, got %08x", i, uint32(testCase.abs), uint32(r))
+		}
+		if r := testCase.d.Neg(); r != testCase.neg {
+			t.Errorf("testCase #%d: Neg: expect %08x, got %08x", i, uint32(testCase.neg), uint32(r))
+		}
+		if r := testCase.d.CopySign(testCase.sign); r != testCase.cps {
+			t.Errorf("testCase #%d: CopySign: expect %08x, got %08x", i, uint32(testCase.cps), uint32(r))
+		}
+	}
+}
+
+func TestClass(t *testing.T) {
+	snan, _ := SignalingNaN(0)
+	testCases := []struct {
+		d   Dec32
+		ref Class
+		s   string
+	}{
+		{snan | signMask, ClassSignalingNaN, "sNaN"},
+		{nan32 | signMask, ClassQuietNaN, "NaN"},
+		{inf32 | signMask, ClassNegativeInfinity, "-Infinity"},
+		{mustEncode(t, -1, -95), ClassNegativeNormal, "-Normal"},
+		{mustEncode(t, -9, -97), ClassNegativeSubnormal, "-Subnormal"},
+		{mustEncode(t, 0, -4) | signMask, ClassNegativeZero, "-Zero"},
+		{mustEncode(t, 0, 0), ClassPositiveZero, "+Zero"},
+		{0x6cbfffff, ClassPositiveZero, "+Zero"},
+		{SmallestSubnormalDec32, ClassPositiveSubnormal, "+Subnormal"},
+		{mustEncode(t, 999999, -101), ClassPositiveSubnormal, "+Subnormal"},
+		{MinPositiveDec32, ClassPositiveNormal, "+Normal"},
+		{MaxDec32, ClassPositiveNormal, "+Normal"},
+		{inf32, ClassPositiveInfinity, "+Infinity"},
+	}
+	for i, testCase := range testCases {
+		c := testCase.d.Class()
+		if c != testCase.ref || c.String() != testCase.s {
+			t.Errorf("testCase #%d: expect %v, got %v", i, testCase.ref, c)
+		}
+	}
+	if s := Class(10).String(); s != "Class(10)" {
+		t.Errorf("expect Class(10), got %q", s)
+	}
+}
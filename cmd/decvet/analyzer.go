@@ -0,0 +1,105 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// decimalPath is the import path of the decimal package.
+const decimalPath = "github.com/cmars/ieee754-dec"
+
+// Analyzer reports lossy conversions between decimals and binary floats.
+var Analyzer = &analysis.Analyzer{
+	Name: "decvet",
+	Doc:  "report lossy conversions between decimal values and binary floating point",
+	Run:  run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			if call, ok := n.(*ast.CallExpr); ok {
+				checkCall(pass, call)
+			}
+			return true
+		})
+	}
+	return nil, nil
+}
+
+// checkCall reports call if it is a lossy conversion.
+func checkCall(pass *analysis.Pass, call *ast.CallExpr) {
+	if tv, ok := pass.TypesInfo.Types[call.Fun]; ok && tv.IsType() {
+		if isDecimal(tv.Type) && len(call.Args) == 1 && isFloat(pass, call.Args[0]) {
+			pass.Reportf(call.Pos(), "conversion of a float to %s sets its encoding bits, not its value",
+				typeName(tv.Type))
+		}
+		return
+	}
+	var id *ast.Ident
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		id = fun
+	case *ast.SelectorExpr:
+		id = fun.Sel
+	default:
+		return
+	}
+	fn, ok := pass.TypesInfo.Uses[id].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != decimalPath {
+		return
+	}
+	sig := fn.Type().(*types.Signature)
+	switch name := fn.Name(); {
+	case sig.Recv() != nil && (name == "Float32" || name == "Float64"):
+		if isDecimal(sig.Recv().Type()) {
+			pass.Reportf(call.Pos(), "%s.%s rounds the decimal to binary floating point",
+				typeName(sig.Recv().Type()), name)
+		}
+	case sig.Recv() == nil && strings.HasPrefix(name, "FromFloat") && len(call.Args) == 1:
+		arg := call.Args[0]
+		if pass.TypesInfo.Types[arg].Value == nil {
+			return
+		}
+		if lit, ok := ast.Unparen(arg).(*ast.BasicLit); ok && lit.Kind == token.FLOAT {
+			pass.Reportf(call.Pos(), "decimal.%s(%s) rounds from the nearest binary float; use MustParseDec32(%q)",
+				name, lit.Value, lit.Value)
+			return
+		}
+		pass.Reportf(call.Pos(), "decimal.%s of a constant rounds from the nearest binary float; parse its decimal string instead", name)
+	}
+}
+
+// isDecimal reports whether t is one of the decimal types.
+func isDecimal(t types.Type) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == decimalPath && strings.HasPrefix(obj.Name(), "Dec")
+}
+
+// isFloat reports whether x is a floating-point value. A float literal
+// converted to an integer type takes that type, so literals are recognized
+// by their syntax.
+func isFloat(pass *analysis.Pass, x ast.Expr) bool {
+	if lit, ok := ast.Unparen(x).(*ast.BasicLit); ok && lit.Kind == token.FLOAT {
+		return true
+	}
+	basic, ok := pass.TypesInfo.TypeOf(x).Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsFloat != 0
+}
+
+// typeName returns the qualified name of the decimal type t.
+func typeName(t types.Type) string {
+	return "decimal." + types.Unalias(t).(*types.Named).Obj().Name()
+}
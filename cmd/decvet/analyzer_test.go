@@ -0,0 +1,118 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// testImporter type-checks the packages of testdata/src, falling back to the
+// standard library.
+type testImporter struct {
+	t    *testing.T
+	fset *token.FileSet
+	pkgs map[string]*types.Package
+}
+
+func (imp *testImporter) Import(path string) (*types.Package, error) {
+	if pkg, ok := imp.pkgs[path]; ok {
+		return pkg, nil
+	}
+	if _, err := os.Stat(filepath.Join("testdata", "src", path)); err != nil {
+		return importer.Default().Import(path)
+	}
+	pkg, _, _ := imp.check(path)
+	return pkg, nil
+}
+
+// check parses and type-checks the package path of testdata/src.
+func (imp *testImporter) check(path string) (*types.Package, []*ast.File, *types.Info) {
+	dir := filepath.Join("testdata", "src", path)
+	names, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil || len(names) == 0 {
+		imp.t.Fatalf("%s: no files: %v", dir, err)
+	}
+	var files []*ast.File
+	for _, name := range names {
+		f, err := parser.ParseFile(imp.fset, name, nil, parser.ParseComments)
+		if err != nil {
+			imp.t.Fatal(err)
+		}
+		files = append(files, f)
+	}
+	info := &types.Info{
+		Types: make(map[ast.Expr]types.TypeAndValue),
+		Defs:  make(map[*ast.Ident]types.Object),
+		Uses:  make(map[*ast.Ident]types.Object),
+	}
+	conf := types.Config{Importer: imp}
+	pkg, err := conf.Check(path, imp.fset, files, info)
+	if err != nil {
+		imp.t.Fatal(err)
+	}
+	imp.pkgs[path] = pkg
+	return pkg, files, info
+}
+
+var wantRE = regexp.MustCompile("// want `([^`]*)`")
+
+// TestAnalyzer checks that the diagnostics on package a are those its want
+// comments expect, in the manner of analysistest.
+func TestAnalyzer(t *testing.T) {
+	imp := &testImporter{t: t, fset: token.NewFileSet(), pkgs: make(map[string]*types.Package)}
+	pkg, files, info := imp.check("a")
+
+	type key struct {
+		file string
+		line int
+	}
+	want := make(map[key]*regexp.Regexp)
+	for _, f := range files {
+		for _, cg := range f.Comments {
+			for _, c := range cg.List {
+				if m := wantRE.FindStringSubmatch(c.Text); m != nil {
+					pos := imp.fset.Position(c.Pos())
+					want[key{pos.Filename, pos.Line}] = regexp.MustCompile(m[1])
+				}
+			}
+		}
+	}
+
+	pass := &analysis.Pass{
+		Analyzer:  Analyzer,
+		Fset:      imp.fset,
+		Files:     files,
+		Pkg:       pkg,
+		TypesInfo: info,
+		Report: func(d analysis.Diagnostic) {
+			pos := imp.fset.Position(d.Pos)
+			k := key{pos.Filename, pos.Line}
+			re, ok := want[k]
+			switch {
+			case !ok:
+				t.Errorf("%s: unexpected diagnostic: %s", pos, d.Message)
+			case !re.MatchString(d.Message):
+				t.Errorf("%s: diagnostic %q does not match %q", pos, d.Message, re)
+			}
+			delete(want, k)
+		},
+	}
+	if _, err := Analyzer.Run(pass); err != nil {
+		t.Fatal(err)
+	}
+	for k, re := range want {
+		t.Errorf("%s:%d: no diagnostic matching %q", k.file, k.line, re)
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Command decvet reports uses of binary floating point that can silently
+// lose the exactness of decimal values:
+//
+//   - calls of the Float32 and Float64 methods of the decimal types, which
+//     round to the nearest binary float;
+//   - calls of the FromFloat functions with a constant argument, such as
+//     FromFloat64(0.1), which round from the binary float nearest the
+//     constant rather than its decimal value, where MustParseDec32("0.1")
+//     is exact;
+//   - conversions of floats to a decimal type, such as Dec32(f) or
+//     Dec32(2.0), which set the bits of the encoding rather than the value.
+//
+// Run it on its own or as a vet tool:
+//
+//	go install github.com/cmars/ieee754-dec/cmd/decvet
+//	go vet -vettool=$(which decvet) ./...
+package main
+
+import "golang.org/x/tools/go/analysis/singlechecker"
+
+func main() {
+	singlechecker.Main(Analyzer)
+}
@@ -0,0 +1,23 @@
+package a
+
+import decimal "github.com/cmars/ieee754-dec"
+
+const rate = 0.05
+
+func f(d decimal.Dec32, x float64) {
+	_ = d.Float32()   // want `decimal.Dec32.Float32 rounds the decimal to binary floating point`
+	_ = d.Float64()   // want `decimal.Dec32.Float64 rounds the decimal to binary floating point`
+	_ = (d).Float64() // want `decimal.Dec32.Float64 rounds`
+	g := d.Float64    // method values are not calls
+	_ = g
+
+	_, _ = decimal.FromFloat64(0.1)   // want `decimal.FromFloat64\(0.1\) rounds from the nearest binary float; use MustParseDec32\("0.1"\)`
+	_, _ = decimal.FromFloat64(rate)  // want `decimal.FromFloat64 of a constant rounds`
+	_, _ = decimal.FromFloat64(x)     // the value is already a binary float
+	_ = decimal.MustParseDec32("0.1") // exact
+
+	_ = decimal.Dec32(x)   // want `conversion of a float to decimal.Dec32 sets its encoding bits, not its value`
+	_ = decimal.Dec32(2.0) // want `conversion of a float to decimal.Dec32`
+	_ = decimal.Dec32(0x32800000)
+	_ = d.String()
+}
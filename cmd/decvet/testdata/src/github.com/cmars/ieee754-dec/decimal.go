@@ -0,0 +1,11 @@
+// Package decimal declares the parts of the decimal package that decvet
+// checks.
+package decimal
+
+type Dec32 uint32
+
+func (d Dec32) Float32() float32          { return 0 }
+func (d Dec32) Float64() float64          { return 0 }
+func (d Dec32) String() string            { return "" }
+func FromFloat64(f float64) (Dec32, bool) { return 0, false }
+func MustParseDec32(s string) Dec32       { return 0 }
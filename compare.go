@@ -0,0 +1,223 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import "math"
+
+// pow10Uint64 holds the powers of ten representable in a uint64.
+var pow10Uint64 = func() []uint64 {
+	p := make([]uint64, 20)
+	p[0] = 1
+	for i := 1; i < len(p); i++ {
+		p[i] = p[i-1] * 10
+	}
+	return p
+}()
+
+// cmp32 compares the numeric values of x and y, neither of which may be NaN,
+// returning -1, 0 or +1. Zeros of either sign compare equal, as do members of
+// the same cohort, such as 1.0 and 1.00.
+func cmp32(x, y Dec32) int {
+	xs, ys := x.cmpSign(), y.cmpSign()
+	if xs != ys {
+		if xs < ys {
+			return -1
+		}
+		return 1
+	}
+	if xs == 0 {
+		return 0
+	}
+	// Same sign: compare magnitudes, then orient by sign.
+	return xs * cmpMag32(x, y)
+}
+
+// cmpSign returns -1, 0 or +1 according to the sign of the value of d, with
+// zeros of either sign mapping to 0.
+func (d Dec32) cmpSign() int {
+	if d.Zero() {
+		return 0
+	}
+	return d.Sign()
+}
+
+// cmpMag32 compares the magnitudes of the nonzero, non-NaN values x and y.
+func cmpMag32(x, y Dec32) int {
+	xi, yi := x.IsInf(), y.IsInf()
+	switch {
+	case xi && yi:
+		return 0
+	case xi:
+		return 1
+	case yi:
+		return -1
+	}
+	xa, ya := x.AdjustedExponent(), y.AdjustedExponent()
+	if xa != ya {
+		if xa < ya {
+			return -1
+		}
+		return 1
+	}
+	// Equal adjusted exponents: the exponents differ by less than the
+	// precision, so the aligned coefficients fit easily in a uint64.
+	_, xc, xe := x.unpack()
+	_, yc, ye := y.unpack()
+	a, b := uint64(xc), uint64(yc)
+	if xe > ye {
+		a *= pow10Uint64[xe-ye]
+	} else {
+		b *= pow10Uint64[ye-xe]
+	}
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// Cmp compares the numeric values of x and y, returning -1, 0 or +1
+// according to whether x is less than, equal to or greater than y. Values
+// are compared exactly, so members of the same cohort, such as 1.0 and 1.00,
+// compare equal, as do zeros of either sign. As with cmp.Compare for floats,
+// a NaN is less than any number and equal to a NaN, so that Cmp orders
+// slices with the NaNs first; use TotalOrder to order values by their
+// encodings too.
+func (x Dec32) Cmp(y Dec32) int {
+	switch {
+	case x.IsNaN() && y.IsNaN():
+		return 0
+	case x.IsNaN():
+		return -1
+	case y.IsNaN():
+		return 1
+	}
+	return cmp32(x, y)
+}
+
+// Equal reports whether x and y have the same numeric value, as the IEEE
+// 754 compareQuietEqual operation does: 1.0 equals 1.00, -0 equals 0, and a
+// NaN equals nothing, not even itself.
+func (x Dec32) Equal(y Dec32) bool {
+	return !x.IsNaN() && !y.IsNaN() && cmp32(x, y) == 0
+}
+
+// TotalOrder reports whether x is ordered before or equal to y in the total
+// order of IEEE 754, the totalOrder predicate. It agrees with Cmp for values
+// that are numerically different, and orders the rest as follows:
+//
+//	-NaN < -sNaN < -Inf < negative numbers < -0 < +0 < positive numbers < +Inf < +sNaN < +NaN
+//
+// Members of a cohort are ordered by exponent, which increases with the
+// value for positive numbers, so 1.00 < 1.0 < 1 and -1 < -1.0 < -1.00; NaNs
+// of the same sign and kind are ordered by payload, larger payloads further
+// from zero. Non-canonical encodings are ordered as their canonical
+// equivalents.
+func (x Dec32) TotalOrder(y Dec32) bool {
+	return x.CmpTotal(y) <= 0
+}
+
+// CmpTotal compares x and y in the total order of TotalOrder, returning -1,
+// 0 or +1, for use as the comparison function of slices.SortFunc. It returns
+// 0 only if x and y have the same canonical encoding.
+func (x Dec32) CmpTotal(y Dec32) int {
+	x, y = x.canonicalEncoding(), y.canonicalEncoding()
+	xneg, yneg := x.Sign() < 0, y.Sign() < 0
+	switch {
+	case xneg && !yneg:
+		return -1
+	case !xneg && yneg:
+		return 1
+	}
+	c := cmpTotalMag(x&^signMask, y&^signMask)
+	if xneg {
+		return -c
+	}
+	return c
+}
+
+// cmpTotalMag compares the canonical, non-negative x and y in the total
+// order.
+func cmpTotalMag(x, y Dec32) int {
+	xr, yr := x.totalRank(), y.totalRank()
+	switch {
+	case xr != yr:
+		return cmpInt(xr, yr)
+	case x.IsNaN():
+		return cmpInt(int(x&nanPayloadMask), int(y&nanPayloadMask))
+	case x.IsInf():
+		return 0
+	}
+	if c := cmp32(x, y); c != 0 {
+		return c
+	}
+	_, _, xe := x.unpack()
+	_, _, ye := y.unpack()
+	return cmpInt(xe, ye)
+}
+
+// totalRank orders the kinds of value in the total order by magnitude: the
+// numbers, infinity, signaling and then quiet NaNs.
+func (d Dec32) totalRank() int {
+	switch {
+	case d&snanMask == snanMask:
+		return 2
+	case d.IsNaN():
+		return 3
+	case d.IsInf():
+		return 1
+	}
+	return 0
+}
+
+// cmpInt compares the integers a and b, returning -1, 0 or +1.
+func cmpInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// CmpInt64 compares d with the integer n exactly, returning -1, 0 or +1
+// according to whether d is less than, equal to or greater than n. As with
+// cmp.Compare for floats, a NaN is less than any number.
+func (d Dec32) CmpInt64(n int64) int {
+	switch {
+	case d.IsNaN():
+		return -1
+	case d.IsInf():
+		return d.Sign()
+	}
+	return newBigDec32(d).cmp(newBigDecInt64(n))
+}
+
+// CmpFloat64 compares d with the exact binary value of f, returning -1, 0 or
+// +1 according to whether d is less than, equal to or greater than f, so 0.1
+// compares greater than float64(0.1), whose value is slightly less than one
+// tenth. As with cmp.Compare for floats, a NaN is less than any number and
+// equal to a NaN.
+func (d Dec32) CmpFloat64(f float64) int {
+	switch {
+	case d.IsNaN() && math.IsNaN(f):
+		return 0
+	case d.IsNaN():
+		return -1
+	case math.IsNaN(f):
+		return 1
+	case math.IsInf(f, 0):
+		if d.IsInf() && d.Sign() == int(math.Copysign(1, f)) {
+			return 0
+		}
+		return -int(math.Copysign(1, f))
+	case d.IsInf():
+		return d.Sign()
+	}
+	return newBigDec32(d).cmp(newBigDecFloat64(f))
+}
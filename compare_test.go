@@ -0,0 +1,157 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCmpInt64(t *testing.T) {
+	testCases := []struct {
+		d   Dec32
+		n   int64
+		ref int
+	}{
+		{mustEncode(t, 100, 0), 100, 0},
+		{mustEncode(t, 1, 2), 100, 0},
+		{mustEncode(t, 10000, -2), 100, 0},
+		{mustEncode(t, 10001, -2), 100, 1},
+		{mustEncode(t, 9999, -2), 100, -1},
+		{mustEncode(t, -5, -1), 0, -1},
+		{mustEncode(t, 0, 0) | signMask, 0, 0},
+		{mustEncode(t, 9223372, 12), math.MaxInt64, -1},
+		{mustEncode(t, 9223373, 12), math.MaxInt64, 1},
+		{mustEncode(t, -9223372, 12), math.MinInt64, 1},
+		{mustEncode(t, 1, 90), math.MaxInt64, 1},
+		{inf32, math.MaxInt64, 1},
+		{inf32 | signMask, math.MinInt64, -1},
+		{nan32, math.MinInt64, -1},
+	}
+	for i, testCase := range testCases {
+		if c := testCase.d.CmpInt64(testCase.n); c != testCase.ref {
+			t.Errorf("testCase #%d: %v cmp %d: expect %d, got %d", i, testCase.d, testCase.n, testCase.ref, c)
+		}
+	}
+}
+
+func TestCmpFloat64(t *testing.T) {
+	testCases := []struct {
+		d   Dec32
+		f   float64
+		ref int
+	}{
+		{mustEncode(t, 15, -1), 1.5, 0},
+		{mustEncode(t, 25, -2), 0.25, 0},
+		// float64(0.1) is 0.1000000000000000055511151231257827...
+		{mustEncode(t, 1, -1), 0.1, -1},
+		// float64(0.3) is 0.2999999999999999888977697537484345...
+		{mustEncode(t, 3, -1), 0.3, 1},
+		{mustEncode(t, -1, 0), -1, 0},
+		{mustEncode(t, 0, 0), math.Copysign(0, -1), 0},
+		{mustEncode(t, 1, -101), 5e-324, 1},
+		{mustEncode(t, 1, -101), 0, 1},
+		// float64(1e90) is 9.99999999999999966484...E+89.
+		{mustEncode(t, 1, 90), 1e90, 1},
+		{mustEncode(t, 9999999, 90), math.MaxFloat64, -1},
+		{mustEncode(t, 9999999, 90), math.Inf(1), -1},
+		{inf32, math.Inf(1), 0},
+		{inf32, math.Inf(-1), 1},
+		{inf32 | signMask, math.Inf(-1), 0},
+		{inf32 | signMask, -math.MaxFloat64, -1},
+		{nan32, math.Inf(-1), -1},
+		{mustEncode(t, 1, 0), math.NaN(), 1},
+		{nan32, math.NaN(), 0},
+	}
+	for i, testCase := range testCases {
+		if c := testCase.d.CmpFloat64(testCase.f); c != testCase.ref {
+			t.Errorf("testCase #%d: %v cmp %g: expect %d, got %d", i, testCase.d, testCase.f, testCase.ref, c)
+		}
+	}
+}
+
+func TestCmp(t *testing.T) {
+	testCases := []struct {
+		x, y  Dec32
+		ref   int
+		equal bool
+	}{
+		{mustEncode(t, 10, 0), mustEncode(t, 1, 1), 0, true},
+		{mustEncode(t, 10, -1), mustEncode(t, 100, -2), 0, true},
+		{mustEncode(t, 0, 5), pack32(true, 0, -3), 0, true},
+		{mustEncode(t, 12, -1), mustEncode(t, 119, -2), 1, false},
+		{mustEncode(t, -12, -1), mustEncode(t, -119, -2), -1, false},
+		{mustEncode(t, -1, 0), mustEncode(t, 0, 0), -1, false},
+		{mustEncode(t, 9999999, 90), inf32, -1, false},
+		{inf32 | signMask, mustEncode(t, -9999999, 90), -1, false},
+		{inf32, inf32, 0, true},
+		{nan32, mustEncode(t, -9999999, 90), -1, false},
+		{inf32 | signMask, nan32, 1, false},
+		{nan32, Dec32(snanMask) | signMask, 0, false},
+		// A non-canonical coefficient is zero.
+		{Dec32(0x6cbfffff), mustEncode(t, 0, 0), 0, true},
+	}
+	for i, testCase := range testCases {
+		if c := testCase.x.Cmp(testCase.y); c != testCase.ref {
+			t.Errorf("testCase #%d: %v cmp %v: expect %d, got %d", i, testCase.x, testCase.y, testCase.ref, c)
+		}
+		if c := testCase.y.Cmp(testCase.x); c != -testCase.ref {
+			t.Errorf("testCase #%d: %v cmp %v: expect %d, got %d", i, testCase.y, testCase.x, -testCase.ref, c)
+		}
+		if eq := testCase.x.Equal(testCase.y); eq != testCase.equal {
+			t.Errorf("testCase #%d: %v equal %v: expect %v, got %v", i, testCase.x, testCase.y, testCase.equal, eq)
+		}
+	}
+}
+
+func TestTotalOrder(t *testing.T) {
+	// Values in increasing total order.
+	ordered := []Dec32{
+		nan32 | signMask | 5,
+		nan32 | signMask,
+		Dec32(snanMask) | signMask | 7,
+		Dec32(snanMask) | signMask,
+		inf32 | signMask,
+		mustEncode(t, -9999999, 90),
+		mustEncode(t, -1, 0),
+		mustEncode(t, -10, -1),
+		mustEncode(t, -100, -2),
+		mustEncode(t, -1, -101),
+		pack32(true, 0, 90),
+		pack32(true, 0, 0),
+		pack32(true, 0, -101),
+		mustEncode(t, 0, -101),
+		mustEncode(t, 0, 0),
+		mustEncode(t, 0, 90),
+		mustEncode(t, 1, -101),
+		mustEncode(t, 100, -2),
+		mustEncode(t, 10, -1),
+		mustEncode(t, 1, 0),
+		mustEncode(t, 9999999, 90),
+		inf32,
+		Dec32(snanMask),
+		Dec32(snanMask) | 7,
+		nan32,
+		nan32 | 5,
+	}
+	for i, x := range ordered {
+		for j, y := range ordered {
+			ref := cmpInt(i, j)
+			if c := x.CmpTotal(y); c != ref {
+				t.Errorf("#%d %v cmp total #%d %v: expect %d, got %d", i, x, j, y, ref, c)
+			}
+			if o := x.TotalOrder(y); o != (i <= j) {
+				t.Errorf("#%d %v total order #%d %v: expect %v, got %v", i, x, j, y, i <= j, o)
+			}
+		}
+	}
+	// Non-canonical encodings are ordered as their canonical equivalents.
+	if c := (inf32 | 0x1234).CmpTotal(inf32); c != 0 {
+		t.Errorf("expect non-canonical infinity equal to Inf, got %d", c)
+	}
+	if c := Dec32(0x6cbfffff).CmpTotal(pack32(false, 0, 0)); c != 0 {
+		t.Errorf("expect non-canonical zero equal to its canonical zero, got %d", c)
+	}
+}
@@ -0,0 +1,463 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// A Context holds the parameters of rounding for decimal operations. Its
+// methods compute the exact result of an operation and round it once to the
+// precision and with the rounding mode of the context, like the
+// java.math.MathContext argument of BigDecimal operations. The zero value
+// rounds to the full seven digits of decimal32, ties to even, as the
+// operations on Dec32 values do.
+type Context struct {
+	// Precision is the number of significant digits results are rounded
+	// to, between 1 and 7; 0 selects 7. A lower working precision
+	// emulates systems that carry fewer digits: with precision 5,
+	// 2/3 is 0.66667. Results keep the decimal32 exponent range.
+	Precision int
+
+	// Mode is the rounding mode of results.
+	Mode RoundingMode
+
+	// Saturate clamps results that overflow to the largest finite value of
+	// the working precision with their sign, 9.999999E+96 at full
+	// precision, in every rounding mode, for protocols that cannot
+	// represent infinities, raising Clamped along with Overflow where the
+	// rounding mode would give an infinity. Results too small in
+	// magnitude already round to zero or the smallest subnormal.
+	// Infinities that arise exactly, from infinite operands or division
+	// by zero, are kept.
+	Saturate bool
+
+	// Flags accumulates the exceptions raised by the operations of c.
+	// They are never cleared by c, so a computation can be checked once
+	// at its end; set Flags to 0 to start afresh.
+	Flags Flags
+}
+
+// precision returns the working precision of c.
+func (c *Context) precision() int {
+	if c.Precision <= 0 || c.Precision > 7 {
+		return 7
+	}
+	return c.Precision
+}
+
+// round rounds x to the precision of c with its rounding mode, raising the
+// flags of the rounding in c.
+func (c *Context) round(x *bigDec) Dec32 {
+	d, f := c.roundFlags(x)
+	c.Flags |= f
+	return d
+}
+
+// roundFlags is like round, but returns the flags raised instead.
+func (c *Context) roundFlags(x *bigDec) (Dec32, Flags) {
+	prec := c.precision()
+	// IEEE 754 detects the tininess of decimal results before rounding.
+	zero, exp := x.isZero(), x.exp
+	tiny := !zero && x.exp+bigDigits(&x.coeff)-1 < minExp+6
+	inexact, overflow := x.roundPrec(prec, c.Mode)
+	if overflow {
+		return c.overflow(x.neg, prec)
+	}
+	var f Flags
+	switch {
+	case inexact && tiny:
+		f = Underflow | Inexact
+	case inexact:
+		f = Inexact
+	}
+	// The exponent is clamped if that of a zero is brought into range, if
+	// the coefficient is padded to lower it to maxExp, or if a subnormal
+	// result rounds to zero.
+	if zero && x.exp != exp || !zero && (x.exp < exp || x.isZero()) {
+		f |= Clamped
+	}
+	return pack32(x.neg, uint32(x.coeff.Uint64()), x.exp), f
+}
+
+// nan returns a quiet NaN as the result of an operation on ds, raising
+// Invalid in c unless one of them is a quiet NaN. As IEEE 754 recommends,
+// the result keeps the sign and payload of a NaN operand, if any, so that
+// diagnostic payloads propagate through arithmetic: that of the first
+// signaling NaN, or failing one the first quiet NaN, as in the General
+// Decimal Arithmetic specification.
+func (c *Context) nan(ds ...Dec32) Dec32 {
+	c.Flags |= nanFlags(ds...)
+	for _, d := range ds {
+		if d.IsSignalingNaN() {
+			return d.quiet()
+		}
+	}
+	for _, d := range ds {
+		if d.IsNaN() {
+			return d.quiet()
+		}
+	}
+	return nan32
+}
+
+// divByZero returns the infinity with the given sign that results from
+// dividing a nonzero value by zero, raising DivisionByZero in c.
+func (c *Context) divByZero(neg bool) Dec32 {
+	c.Flags |= DivisionByZero
+	return inf32 | signOf(neg)
+}
+
+// overflow returns the result of an overflow with the given sign and the
+// flags it raises: an infinity, or the largest finite value of the working
+// precision for modes that round towards zero in that direction, as IEEE 754
+// specifies, or when saturating, which raises Clamped as well.
+func (c *Context) overflow(neg bool, prec int) (Dec32, Flags) {
+	f := Overflow | Inexact
+	if c.Mode.overflowsToInf(neg) {
+		if !c.Saturate {
+			return inf32 | signOf(neg), f
+		}
+		f |= Clamped
+	}
+	coeff := (pow10Uint64[prec] - 1) * pow10Uint64[7-prec]
+	return pack32(neg, uint32(coeff), maxExp), f
+}
+
+// Round returns d rounded to the precision of c with its rounding mode. The
+// exponent of an exact result is that of d, so with precision 3, 1.20 is
+// unchanged and 1.234 becomes 1.23. Special values are returned unchanged,
+// except that NaNs become quiet.
+func (c *Context) Round(d Dec32) Dec32 {
+	checkSpecial(d)
+	switch {
+	case d.IsNaN():
+		return c.nan(d)
+	case d.IsInf():
+		return d
+	}
+	return c.round(newBigDec32(d))
+}
+
+// ParseDec32 is like the ParseDec32 function, but rounds the value of s with
+// c, so that with ToNearestAway "2.5000005" parses as 2.500001. It returns
+// strconv.ErrRange whenever the value is too large to represent, together
+// with the infinity or, in modes that round towards zero or when saturating,
+// the largest finite value that c gives it. The flags of the rounding are
+// raised in c.
+func (c *Context) ParseDec32(s string) (Dec32, error) {
+	x, form, ok := parseDecimal(s)
+	if !ok {
+		return nan32, &strconv.NumError{Func: "ParseDec32", Num: s, Err: strconv.ErrSyntax}
+	}
+	switch form {
+	case parsedInf:
+		return inf32 | signOf(x.neg), nil
+	case parsedNaN:
+		return nan32 | signOf(x.neg), nil
+	case parsedSNaN:
+		return Dec32(snanMask) | signOf(x.neg), nil
+	}
+	d, f := c.roundFlags(x)
+	c.Flags |= f
+	if f&Overflow != 0 {
+		return d, &strconv.NumError{Func: "ParseDec32", Num: s, Err: strconv.ErrRange}
+	}
+	return d, nil
+}
+
+// FromScaledInt64 is like the FromScaledInt64 function, but rounds the value
+// with c when it cannot be represented exactly.
+func (c *Context) FromScaledInt64(v int64, scale int32) (Dec32, bool) {
+	x := newBigDecInt64(v)
+	x.exp = -int(scale)
+	d, f := c.roundFlags(x)
+	c.Flags |= f
+	return d, f&Inexact == 0
+}
+
+// FromFloat64 is like the FromFloat64 function, but rounds f with c.
+func (c *Context) FromFloat64(f float64) (Dec32, bool) {
+	switch {
+	case math.IsNaN(f):
+		return nan32, false
+	case math.IsInf(f, 0):
+		return inf32 | signOf(f < 0), true
+	case f == 0:
+		return pack32(math.Signbit(f), 0, 0), true
+	}
+	x := newBigDecFloat64(f)
+	long := bigDigits(&x.coeff) > c.precision()
+	d, fl := c.roundFlags(x)
+	c.Flags |= fl
+	if long && !d.IsInf() {
+		d = d.reduce()
+	}
+	return d, fl&Inexact == 0
+}
+
+// Quantize returns d rounded with c to the exponent of q, as for
+// Dec32.Quantize. The result needs at most the precision of c in digits, or
+// it is NaN and Invalid is raised.
+func (c *Context) Quantize(d, q Dec32) Dec32 {
+	checkSpecial(d, q)
+	switch {
+	case d.IsNaN() || q.IsNaN():
+		return c.nan(d, q)
+	case d.IsInf() && q.IsInf():
+		return d
+	case d.IsInf() || q.IsInf():
+		return c.nan(d, q)
+	}
+	_, _, exp := q.unpack()
+	return c.Rescale(d, exp)
+}
+
+// Rescale returns d rounded with c to the exponent exp, as for
+// Dec32.Rescale.
+func (c *Context) Rescale(d Dec32, exp int) Dec32 {
+	checkSpecial(d)
+	switch {
+	case d.IsNaN():
+		return c.nan(d)
+	case d.IsInf() || exp < minExp || exp > maxExp:
+		c.Flags |= Invalid
+		return nan32
+	}
+	x := newBigDec32(d)
+	inexact := x.rescaleMode(exp, c.Mode)
+	if bigDigits(&x.coeff) > c.precision() {
+		c.Flags |= Invalid
+		return nan32
+	}
+	if inexact {
+		c.Flags |= Inexact
+	}
+	return pack32(x.neg, uint32(x.coeff.Uint64()), x.exp)
+}
+
+// Recip returns the reciprocal 1/d rounded with c, as for Dec32.Recip.
+func (c *Context) Recip(d Dec32) Dec32 {
+	checkSpecial(d)
+	switch {
+	case d.IsNaN():
+		return c.nan(d)
+	case d.IsInf():
+		return pack32(d.Sign() < 0, 0, minExp)
+	case d.Zero():
+		return c.divByZero(d.Sign() < 0)
+	}
+	return c.round(new(bigDec).quo(newBigDecInt64(1), newBigDec32(d), c.precision()))
+}
+
+// Hypot returns sqrt(x*x + y*y) rounded with c, as for Dec32.Hypot.
+func (c *Context) Hypot(x, y Dec32) Dec32 {
+	checkSpecial(x, y)
+	switch {
+	case x.IsInf() || y.IsInf():
+		return inf32
+	case x.IsNaN() || y.IsNaN():
+		return c.nan(x, y)
+	}
+	a, b := newBigDec32(x), newBigDec32(y)
+	a.mul(a, a)
+	b.mul(b, b)
+	s := new(bigDec).add(a, b)
+	return c.round(s.sqrt(s, c.precision()))
+}
+
+// Sqrt returns the square root of d rounded with c, as for Dec32.Sqrt,
+// raising Invalid for a negative operand.
+func (c *Context) Sqrt(d Dec32) Dec32 {
+	checkSpecial(d)
+	switch {
+	case d.IsNaN():
+		return c.nan(d)
+	case d.Zero():
+		x := newBigDec32(d)
+		return c.round(x.sqrt(x, c.precision()))
+	case d.Sign() < 0:
+		c.Flags |= Invalid
+		return nan32
+	case d.IsInf():
+		return inf32
+	}
+	x := newBigDec32(d)
+	return c.round(x.sqrt(x, c.precision()))
+}
+
+// Add returns x+y rounded with c, as for Dec32.Add.
+func (c *Context) Add(x, y Dec32) Dec32 {
+	return c.add(x, y, false)
+}
+
+// Sub returns x-y rounded with c, as for Dec32.Sub.
+func (c *Context) Sub(x, y Dec32) Dec32 {
+	return c.add(x, y, true)
+}
+
+// add returns x+y, or x-y if negate is set, rounded with c.
+func (c *Context) add(x, y Dec32, negate bool) Dec32 {
+	checkSpecial(x, y)
+	if negate && !y.IsNaN() {
+		y ^= signMask
+	}
+	switch {
+	case x.IsNaN() || y.IsNaN():
+		return c.nan(x, y)
+	case x.IsInf() && y.IsInf():
+		if x.Sign() != y.Sign() {
+			return c.nan(x, y)
+		}
+		return x
+	case x.IsInf():
+		return x
+	case y.IsInf():
+		return y
+	}
+	a, b := newBigDec32(x), newBigDec32(y)
+	s := new(bigDec).add(a, b)
+	if s.isZero() && a.neg != b.neg {
+		// An exact zero sum of operands of opposite sign is positive,
+		// except when rounding towards negative infinity.
+		s.neg = c.Mode == ToNegativeInf
+	}
+	return c.round(s)
+}
+
+// Mul returns x*y rounded with c, as for Dec32.Mul.
+func (c *Context) Mul(x, y Dec32) Dec32 {
+	checkSpecial(x, y)
+	neg := x.Sign() < 0 != (y.Sign() < 0)
+	switch {
+	case x.IsNaN() || y.IsNaN():
+		return c.nan(x, y)
+	case x.IsInf() || y.IsInf():
+		if x.Zero() || y.Zero() {
+			return c.nan(x, y)
+		}
+		return inf32 | signOf(neg)
+	}
+	return c.round(new(bigDec).mul(newBigDec32(x), newBigDec32(y)))
+}
+
+// Div returns x/y rounded with c, as for Dec32.Div.
+func (c *Context) Div(x, y Dec32) Dec32 {
+	checkSpecial(x, y)
+	neg := x.Sign() < 0 != (y.Sign() < 0)
+	switch {
+	case x.IsNaN() || y.IsNaN():
+		return c.nan(x, y)
+	case x.IsInf() && y.IsInf():
+		return c.nan(x, y)
+	case x.IsInf():
+		return inf32 | signOf(neg)
+	case y.IsInf():
+		return pack32(neg, 0, minExp)
+	case y.Zero():
+		if x.Zero() {
+			return c.nan(x, y)
+		}
+		return c.divByZero(neg)
+	}
+	return c.round(new(bigDec).quo(newBigDec32(x), newBigDec32(y), c.precision()))
+}
+
+// Min returns the lesser of x and y, as for Dec32.Min, raising Invalid in c
+// if either is a signaling NaN.
+func (c *Context) Min(x, y Dec32) Dec32 {
+	return c.minMax(x, y, false, false)
+}
+
+// Max returns the greater of x and y, as for Dec32.Max.
+func (c *Context) Max(x, y Dec32) Dec32 {
+	return c.minMax(x, y, true, false)
+}
+
+// MinMag returns the lesser in magnitude of x and y, as for Dec32.MinMag.
+func (c *Context) MinMag(x, y Dec32) Dec32 {
+	return c.minMax(x, y, false, true)
+}
+
+// MaxMag returns the greater in magnitude of x and y, as for Dec32.MaxMag.
+func (c *Context) MaxMag(x, y Dec32) Dec32 {
+	return c.minMax(x, y, true, true)
+}
+
+// FMA returns x*y+z rounded with c, as for Dec32.FMA.
+func (c *Context) FMA(x, y, z Dec32) Dec32 {
+	checkSpecial(x, y, z)
+	neg := x.Sign() < 0 != (y.Sign() < 0)
+	switch {
+	case x.IsNaN() || y.IsNaN():
+		return c.nan(x, y, z)
+	case (x.IsInf() || y.IsInf()) && (x.Zero() || y.Zero()):
+		// The product is invalid, whatever z is.
+		c.Flags |= Invalid
+		return c.nan(z)
+	case z.IsNaN():
+		return c.nan(z)
+	case x.IsInf() || y.IsInf():
+		if z.IsInf() && z.Sign() < 0 != neg {
+			return c.nan(x, y, z)
+		}
+		return inf32 | signOf(neg)
+	case z.IsInf():
+		return z
+	}
+	p, a := new(bigDec).mul(newBigDec32(x), newBigDec32(y)), newBigDec32(z)
+	s := new(bigDec).add(p, a)
+	if s.isZero() && p.neg != a.neg {
+		// As for add, an exact zero sum of opposite signs is positive
+		// except when rounding towards negative infinity.
+		s.neg = c.Mode == ToNegativeInf
+	}
+	return c.round(s)
+}
+
+// LogB returns the adjusted exponent of d, as for Dec32.LogB, raising
+// DivisionByZero in c if d is zero.
+func (c *Context) LogB(d Dec32) Dec32 {
+	checkSpecial(d)
+	switch {
+	case d.IsNaN():
+		return c.nan(d)
+	case d.IsInf():
+		return inf32
+	case d.Zero():
+		return c.divByZero(true)
+	}
+	e := d.AdjustedExponent()
+	if e < 0 {
+		return pack32(true, uint32(-e), 0)
+	}
+	return pack32(false, uint32(e), 0)
+}
+
+// ScaleB returns d multiplied by 10^n and rounded with c, as for
+// Dec32.ScaleB.
+func (c *Context) ScaleB(d Dec32, n int) Dec32 {
+	checkSpecial(d)
+	switch {
+	case d.IsNaN():
+		return c.nan(d)
+	case d.IsInf():
+		return d
+	}
+	x := newBigDec32(d)
+	x.exp += max(min(n, scaleBLimit), -scaleBLimit)
+	return c.round(x)
+}
+
+// FromBigRat returns r rounded with c, as for the FromBigRat function.
+func (c *Context) FromBigRat(r *big.Rat) Dec32 {
+	x := &bigDec{neg: r.Sign() < 0}
+	x.coeff.Abs(r.Num())
+	y := new(bigDec)
+	y.coeff.Set(r.Denom())
+	return c.round(new(bigDec).quo(x, y, c.precision()))
+}
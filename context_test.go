@@ -0,0 +1,155 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestContextRound(t *testing.T) {
+	testCases := []struct {
+		c      Context
+		d, ref Dec32
+	}{
+		{Context{}, mustEncode(t, 1234567, -3), mustEncode(t, 1234567, -3)},
+		{Context{Precision: 3}, mustEncode(t, 1234, -3), mustEncode(t, 123, -2)},
+		{Context{Precision: 3}, mustEncode(t, 120, -2), mustEncode(t, 120, -2)},
+		{Context{Precision: 3}, mustEncode(t, 1235, -3), mustEncode(t, 124, -2)},
+		{Context{Precision: 3}, mustEncode(t, 1225, -3), mustEncode(t, 122, -2)},
+		{Context{Precision: 3, Mode: ToNearestAway}, mustEncode(t, 1225, -3), mustEncode(t, 123, -2)},
+		{Context{Precision: 3, Mode: ToZero}, mustEncode(t, -1229, -3), mustEncode(t, -122, -2)},
+		{Context{Precision: 3, Mode: ToNegativeInf}, mustEncode(t, -1221, -3), mustEncode(t, -123, -2)},
+		{Context{Precision: 1}, mustEncode(t, 96, 0), mustEncode(t, 1, 2)},
+		{Context{Precision: 8}, mustEncode(t, 1234567, 0), mustEncode(t, 1234567, 0)},
+		{Context{Precision: 5}, mustEncode(t, 1234567, -101), mustEncode(t, 12346, -99)},
+		// Rounding up at the top of the range overflows.
+		{Context{Precision: 3}, mustEncode(t, 9999500, 90), inf32},
+		{Context{Precision: 3, Mode: ToZero}, mustEncode(t, 9999500, 90), mustEncode(t, 9990000, 90)},
+		{Context{Precision: 3, Mode: ToPositiveInf}, mustEncode(t, -9999500, 90), mustEncode(t, -9990000, 90)},
+		{Context{Precision: 3, Mode: ToPositiveInf}, mustEncode(t, 9999500, 90), inf32},
+		{Context{Precision: 3, Mode: ToNegativeInf}, mustEncode(t, 9999500, 90), mustEncode(t, 9990000, 90)},
+		{Context{Saturate: true}, mustEncode(t, 9999999, 90), mustEncode(t, 9999999, 90)},
+		{Context{Precision: 3, Saturate: true}, mustEncode(t, 9999500, 90), mustEncode(t, 9990000, 90)},
+		{Context{Precision: 3, Mode: ToNearestAway, Saturate: true}, mustEncode(t, -9999500, 90), mustEncode(t, -9990000, 90)},
+		{Context{Saturate: true}, inf32, inf32},
+		{Context{Precision: 3}, inf32 | signMask, inf32 | signMask},
+		{Context{Precision: 3}, Dec32(snanMask), nan32},
+	}
+	for i, testCase := range testCases {
+		if r := testCase.c.Round(testCase.d); r != testCase.ref {
+			t.Errorf("testCase #%d: %+v: round %v: expect %v, got %v", i, testCase.c, testCase.d, testCase.ref, r)
+		}
+	}
+}
+
+func TestContextPrecision(t *testing.T) {
+	c := Context{Precision: 5}
+	if r := c.Recip(mustEncode(t, 3, 0)); r != mustEncode(t, 33333, -5) {
+		t.Errorf("1/3: expect 0.33333, got %v", r)
+	}
+	if r := c.Recip(mustEncode(t, 4, 0)); r != mustEncode(t, 25, -2) {
+		t.Errorf("1/4: expect 0.25, got %v", r)
+	}
+	c.Mode = ToPositiveInf
+	if r := c.Recip(mustEncode(t, 3, 0)); r != mustEncode(t, 33334, -5) {
+		t.Errorf("1/3: expect 0.33334, got %v", r)
+	}
+	if r := c.Hypot(mustEncode(t, 1, 0), mustEncode(t, 1, 0)); r != mustEncode(t, 14143, -4) {
+		t.Errorf("hypot(1, 1): expect 1.4143, got %v", r)
+	}
+	if r := c.Hypot(mustEncode(t, 3, 0), mustEncode(t, 4, 0)); r != mustEncode(t, 5, 0) {
+		t.Errorf("hypot(3, 4): expect 5, got %v", r)
+	}
+}
+
+func TestContextSaturate(t *testing.T) {
+	c := Context{Saturate: true}
+	big := mustEncode(t, 9000000, 90)
+	if r := c.Hypot(big, big); r != mustEncode(t, 9999999, 90) {
+		t.Errorf("expect saturation, got %v", r)
+	}
+	if r := c.Recip(mustEncode(t, 1, -101)); r != mustEncode(t, 9999999, 90) {
+		t.Errorf("expect saturation, got %v", r)
+	}
+	if r := c.Recip(mustEncode(t, 0, 0)); r != inf32 {
+		t.Errorf("expect division by zero to give Inf, got %v", r)
+	}
+	var plain Context
+	if r := plain.Hypot(big, big); r != inf32 {
+		t.Errorf("expect Inf, got %v", r)
+	}
+}
+
+func TestContextArith(t *testing.T) {
+	one, three := mustEncode(t, 1, 0), mustEncode(t, 3, 0)
+	testCases := []struct {
+		c    Context
+		op   func(c *Context, x, y Dec32) Dec32
+		x, y Dec32
+		ref  Dec32
+	}{
+		{Context{Precision: 5}, (*Context).Div, mustEncode(t, 2, 0), three, mustEncode(t, 66667, -5)},
+		{Context{Precision: 5, Mode: ToZero}, (*Context).Div, mustEncode(t, 2, 0), three, mustEncode(t, 66666, -5)},
+		{Context{Mode: ToPositiveInf}, (*Context).Div, one, three, mustEncode(t, 3333334, -7)},
+		{Context{Mode: ToNegativeInf}, (*Context).Div, mustEncode(t, -1, 0), three, mustEncode(t, -3333334, -7)},
+		{Context{Precision: 3}, (*Context).Add, mustEncode(t, 1234, -2), mustEncode(t, 1, -3), mustEncode(t, 123, -1)},
+		{Context{Precision: 3, Mode: ToNearestAway}, (*Context).Mul, mustEncode(t, 15, 0), mustEncode(t, 15, -1), mustEncode(t, 225, -1)},
+		{Context{Precision: 2, Mode: ToNearestAway}, (*Context).Mul, mustEncode(t, 15, 0), mustEncode(t, 15, -1), mustEncode(t, 23, 0)},
+		{Context{Precision: 2}, (*Context).Mul, mustEncode(t, 15, 0), mustEncode(t, 15, -1), mustEncode(t, 22, 0)},
+		{Context{Mode: ToPositiveInf}, (*Context).Sub, one, mustEncode(t, 1, -10), mustEncode(t, 1000000, -6)},
+		{Context{Mode: ToNegativeInf}, (*Context).Sub, one, mustEncode(t, 1, -10), mustEncode(t, 9999999, -7)},
+		// An exact zero sum is negative only when rounding down.
+		{Context{Mode: ToNegativeInf}, (*Context).Sub, one, one, pack32(true, 0, 0)},
+		{Context{Mode: ToNegativeInf}, (*Context).Add, pack32(true, 0, 0), mustEncode(t, 0, 0), pack32(true, 0, 0)},
+		{Context{Mode: ToZero}, (*Context).Sub, one, one, mustEncode(t, 0, 0)},
+		{Context{Mode: ToZero}, (*Context).Mul, mustEncode(t, 1, 50), mustEncode(t, 1, 47), mustEncode(t, 9999999, 90)},
+		{Context{Saturate: true}, (*Context).Add, mustEncode(t, 9999999, 90), mustEncode(t, 1, 90), mustEncode(t, 9999999, 90)},
+		{Context{Saturate: true}, (*Context).Div, one, mustEncode(t, 0, 0), inf32},
+	}
+	for i, testCase := range testCases {
+		if r := testCase.op(&testCase.c, testCase.x, testCase.y); r != testCase.ref {
+			t.Errorf("testCase #%d: %+v: %v, %v: expect %v, got %v", i, testCase.c, testCase.x, testCase.y, testCase.ref, r)
+		}
+	}
+}
+
+func TestContextParseDec32(t *testing.T) {
+	testCases := []struct {
+		c   Context
+		s   string
+		ref Dec32
+		err error
+	}{
+		{Context{}, "2.5000005", mustEncode(t, 2500000, -6), nil},
+		{Context{Mode: ToNearestAway}, "2.5000005", mustEncode(t, 2500001, -6), nil},
+		{Context{Mode: ToNegativeInf}, "-1.00000001", mustEncode(t, -1000001, -6), nil},
+		{Context{Precision: 3}, "1.20", mustEncode(t, 120, -2), nil},
+		{Context{Precision: 3}, "1.2345", mustEncode(t, 123, -2), nil},
+		{Context{Mode: ToZero}, "1E+97", mustEncode(t, 9999999, 90), strconv.ErrRange},
+		{Context{}, "-1E+97", inf32 | signMask, strconv.ErrRange},
+		{Context{Saturate: true}, "1E+97", mustEncode(t, 9999999, 90), strconv.ErrRange},
+		{Context{Mode: ToPositiveInf}, "1E-200", mustEncode(t, 1, -101), nil},
+		{Context{}, "-Infinity", inf32 | signMask, nil},
+		{Context{}, "1..2", nan32, strconv.ErrSyntax},
+	}
+	for i, testCase := range testCases {
+		d, err := testCase.c.ParseDec32(testCase.s)
+		if d != testCase.ref || !errors.Is(err, testCase.err) || (err == nil) != (testCase.err == nil) {
+			t.Errorf("testCase #%d: %+v: %q: expect %v %v, got %v %v", i, testCase.c, testCase.s, testCase.ref, testCase.err, d, err)
+		}
+	}
+}
+
+func TestContextFromScaledInt64(t *testing.T) {
+	c := Context{Mode: ToNearestAway}
+	if d, ok := c.FromScaledInt64(123456785, 8); d != mustEncode(t, 1234568, -6) || ok {
+		t.Errorf("expect inexact 1.234568, got %v %v", d, ok)
+	}
+	if d, ok := c.FromScaledInt64(1500000, 6); d != mustEncode(t, 1500000, -6) || !ok {
+		t.Errorf("expect exact 1.500000, got %v %v", d, ok)
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import "math/big"
+
+// The trailing significand fields that hold the payloads of wider NaNs, and
+// the largest canonical decimal64 payload.
+const (
+	nanPayloadMask64  = 1<<50 - 1
+	nanPayloadMask128 = 1<<46 - 1 // of the high 64 bits
+	maxPayload64      = 999999999999999
+)
+
+// ToDec64 returns d converted exactly to a decimal64, with the same sign,
+// coefficient and exponent. NaNs convert to quiet NaNs with the same sign and
+// payload.
+func (d Dec32) ToDec64() Dec64 {
+	sign := Dec64(d&signMask) << 32
+	switch {
+	case d.IsNaN():
+		return nan64 | sign | Dec64(d.NaNPayload())
+	case d.IsInf():
+		return inf64 | sign
+	}
+	neg, coeff, exp := d.unpack()
+	return pack64(neg, uint64(coeff), exp)
+}
+
+// ToDec128 returns d converted exactly to a decimal128, as for ToDec64.
+func (d Dec32) ToDec128() Dec128 {
+	sign := uint64(d&signMask) << 32
+	switch {
+	case d.IsNaN():
+		return Dec128{nan128.hi | sign, uint64(d.NaNPayload())}
+	case d.IsInf():
+		return Dec128{inf128.hi | sign, 0}
+	}
+	neg, coeff, exp := d.unpack()
+	return pack128(neg, new(big.Int).SetUint64(uint64(coeff)), exp)
+}
+
+// ToDec128 returns d converted exactly to a decimal128, with the same sign,
+// coefficient and exponent. NaNs convert to quiet NaNs with the same sign and
+// payload.
+func (d Dec64) ToDec128() Dec128 {
+	sign := uint64(d & signMask64)
+	switch {
+	case d.IsNaN():
+		return Dec128{nan128.hi | sign, d.nanPayload()}
+	case d.IsInf():
+		return Dec128{inf128.hi | sign, 0}
+	}
+	neg, coeff, exp := d.unpack()
+	return pack128(neg, new(big.Int).SetUint64(coeff), exp)
+}
+
+// ToDec32 returns d correctly rounded to a decimal32 with the given mode,
+// reporting whether the result is exact. Values too large in magnitude to
+// represent overflow to infinities, or to the largest finite value for modes
+// that round towards zero, and are reported inexact. NaNs convert to quiet
+// NaNs with the same sign, and with the same payload if it fits in a
+// decimal32 NaN. Use Context.FromDec64 to be told of overflow and underflow.
+func (d Dec64) ToDec32(mode RoundingMode) (Dec32, bool) {
+	c := Context{Mode: mode}
+	r := c.FromDec64(d)
+	return r, c.Flags&Inexact == 0
+}
+
+// ToDec32 returns d correctly rounded to a decimal32 with the given mode, as
+// for Dec64.ToDec32.
+func (d Dec128) ToDec32(mode RoundingMode) (Dec32, bool) {
+	c := Context{Mode: mode}
+	r := c.FromDec128(d)
+	return r, c.Flags&Inexact == 0
+}
+
+// ToDec64 returns d correctly rounded to a decimal64 with the given mode,
+// reporting whether the result is exact, with the overflow and NaN rules of
+// Dec64.ToDec32.
+func (d Dec128) ToDec64(mode RoundingMode) (Dec64, bool) {
+	neg := d.Sign() < 0
+	switch {
+	case d.IsNaN():
+		r := nan64 | Dec64(d.hi&signMask128)
+		if payload, ok := d.nanPayload(); ok && payload <= maxPayload64 {
+			r |= Dec64(payload)
+		}
+		return r, true
+	case d.IsInf():
+		return inf64 | Dec64(d.hi&signMask128), true
+	}
+	x := newBigDec128(d)
+	inexact, overflow := x.roundFormat(format64, format64.digits, mode)
+	switch {
+	case overflow && mode.overflowsToInf(neg):
+		return inf64 | Dec64(d.hi&signMask128), false
+	case overflow:
+		return pack64(neg, maxCoeff64, maxExp64), false
+	}
+	return pack64(neg, x.coeff.Uint64(), x.exp), !inexact
+}
+
+// FromDec64 returns d rounded with c to a decimal32, as for Dec64.ToDec32,
+// raising Inexact, Overflow and Underflow as rounding does, and Invalid for
+// a signaling NaN.
+func (c *Context) FromDec64(d Dec64) Dec32 {
+	switch {
+	case d.IsNaN():
+		return c.narrowNaN(d.Sign() < 0, d&snanMask64 == snanMask64, d.nanPayload())
+	case d.IsInf():
+		return inf32 | signOf(d.Sign() < 0)
+	}
+	return c.round(newBigDec64(d))
+}
+
+// FromDec128 returns d rounded with c to a decimal32, as for FromDec64.
+func (c *Context) FromDec128(d Dec128) Dec32 {
+	switch {
+	case d.IsNaN():
+		payload, _ := d.nanPayload()
+		return c.narrowNaN(d.Sign() < 0, d.hi&snanMask128 == snanMask128, payload)
+	case d.IsInf():
+		return inf32 | signOf(d.Sign() < 0)
+	}
+	return c.round(newBigDec128(d))
+}
+
+// narrowNaN returns the quiet decimal32 NaN that a wider NaN with the given
+// sign and payload converts to, raising Invalid in c if it is signaling.
+// Payloads too large for decimal32 are dropped.
+func (c *Context) narrowNaN(neg, signaling bool, payload uint64) Dec32 {
+	if signaling {
+		c.Flags |= Invalid
+	}
+	r := nan32 | signOf(neg)
+	if payload <= maxPayload {
+		r |= Dec32(payload)
+	}
+	return r
+}
+
+// nanPayload returns the payload of the NaN d, or 0 if it is non-canonical.
+func (d Dec64) nanPayload() uint64 {
+	if payload := uint64(d & nanPayloadMask64); payload <= maxPayload64 {
+		return payload
+	}
+	return 0
+}
+
+// nanPayload returns the payload of the NaN d if it fits in a uint64 and
+// reports whether it does.
+func (d Dec128) nanPayload() (uint64, bool) {
+	if d.hi&nanPayloadMask128 != 0 {
+		return 0, false
+	}
+	return d.lo, true
+}
+
+// newBigDec128 returns the exact value of a finite decimal128.
+func newBigDec128(d Dec128) *bigDec {
+	neg, coeff, exp := d.unpack()
+	x := &bigDec{neg: neg, exp: exp}
+	x.coeff.Set(coeff)
+	return x
+}
@@ -0,0 +1,123 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestWiden(t *testing.T) {
+	nan12, _ := QuietNaN(12)
+	snan12, _ := SignalingNaN(12)
+	testCases := []struct {
+		d     Dec32
+		ref64 Dec64
+		ref   Dec128
+	}{
+		{mustEncode(t, 125, -2), MustEncodeDec64(125, -2), MustEncodeDec128(big.NewInt(125), -2)},
+		{mustEncode(t, -9999999, 90), MustEncodeDec64(-9999999, 90), MustEncodeDec128(big.NewInt(-9999999), 90)},
+		{pack32(true, 0, minExp), pack64(true, 0, minExp), pack128(true, new(big.Int), minExp)},
+		{inf32 | signMask, inf64 | signMask64, Dec128{inf128.hi | signMask128, 0}},
+		{nan12 | signMask, nan64 | signMask64 | 12, Dec128{nan128.hi | signMask128, 12}},
+		{snan12, nan64 | 12, Dec128{nan128.hi, 12}},
+	}
+	for i, testCase := range testCases {
+		if r := testCase.d.ToDec64(); r != testCase.ref64 {
+			t.Errorf("testCase #%d: Dec32 to Dec64: expect %016x, got %016x", i, uint64(testCase.ref64), uint64(r))
+		}
+		if r := testCase.d.ToDec128(); r != testCase.ref {
+			t.Errorf("testCase #%d: Dec32 to Dec128: expect %x, got %x", i, testCase.ref, r)
+		}
+		if r := testCase.ref64.ToDec128(); r != testCase.ref {
+			t.Errorf("testCase #%d: Dec64 to Dec128: expect %x, got %x", i, testCase.ref, r)
+		}
+	}
+}
+
+func TestDec64ToDec32(t *testing.T) {
+	testCases := []struct {
+		d     Dec64
+		mode  RoundingMode
+		ref   Dec32
+		exact bool
+	}{
+		{MustEncodeDec64(12, -2), ToNearestEven, mustEncode(t, 12, -2), true},
+		{MustEncodeDec64(1234567890123456, -10), ToNearestEven, mustEncode(t, 1234568, -1), false},
+		{MustEncodeDec64(1234567890123456, -10), ToZero, mustEncode(t, 1234567, -1), false},
+		{MustEncodeDec64(1, 96), ToNearestEven, mustEncode(t, 1000000, 90), true},
+		{MustEncodeDec64(1, 100), ToNearestEven, inf32, false},
+		{MustEncodeDec64(-1, 369), ToZero, MaxDec32 | signMask, false},
+		{MustEncodeDec64(15, -102), ToNearestEven, mustEncode(t, 2, -101), false},
+		{MustEncodeDec64(1, -398), ToNearestEven, mustEncode(t, 0, -101), false},
+		{MustEncodeDec64(0, -300), ToNearestEven, mustEncode(t, 0, -101), true},
+		{inf64 | signMask64, ToNearestEven, inf32 | signMask, true},
+		{nan64 | 999999, ToNearestEven, nan32 | 999999, true},
+		{nan64 | signMask64 | 1000000, ToNearestEven, nan32 | signMask, true},
+	}
+	for i, testCase := range testCases {
+		r, exact := testCase.d.ToDec32(testCase.mode)
+		if r != testCase.ref || exact != testCase.exact {
+			t.Errorf("testCase #%d: expect %v %v, got %v %v", i, testCase.ref, testCase.exact, r, exact)
+		}
+		r, exact = testCase.d.ToDec128().ToDec32(testCase.mode)
+		if r != testCase.ref || exact != testCase.exact {
+			t.Errorf("testCase #%d: via Dec128: expect %v %v, got %v %v", i, testCase.ref, testCase.exact, r, exact)
+		}
+	}
+}
+
+func TestDec128ToDec64(t *testing.T) {
+	big17, _ := new(big.Int).SetString("12345678901234567", 10)
+	big34, _ := new(big.Int).SetString("9999999999999999999999999999999999", 10)
+	testCases := []struct {
+		d     Dec128
+		mode  RoundingMode
+		ref   Dec64
+		exact bool
+	}{
+		{MustEncodeDec128(big.NewInt(-125), -2), ToNearestEven, MustEncodeDec64(-125, -2), true},
+		{MustEncodeDec128(big17, 0), ToNearestEven, MustEncodeDec64(1234567890123457, 1), false},
+		{MustEncodeDec128(big17, 0), ToNegativeInf, MustEncodeDec64(1234567890123456, 1), false},
+		{MustEncodeDec128(big34, 0), ToNearestEven, MustEncodeDec64(1000000000000000, 19), false},
+		{MustEncodeDec128(big.NewInt(1), 6111), ToNearestEven, inf64, false},
+		{MustEncodeDec128(big.NewInt(1), 6111), ToNegativeInf, pack64(false, maxCoeff64, maxExp64), false},
+		{MustEncodeDec128(big.NewInt(-1), -6176), ToNearestEven, pack64(true, 0, minExp64), false},
+		{Dec128{nan128.hi | signMask128, 5}, ToNearestEven, nan64 | signMask64 | 5, true},
+	}
+	for i, testCase := range testCases {
+		r, exact := testCase.d.ToDec64(testCase.mode)
+		if r != testCase.ref || exact != testCase.exact {
+			t.Errorf("testCase #%d: expect %016x %v, got %016x %v", i, uint64(testCase.ref), testCase.exact, uint64(r), exact)
+		}
+	}
+}
+
+func TestContextFromDec64(t *testing.T) {
+	testCases := []struct {
+		d     Dec64
+		flags Flags
+	}{
+		{MustEncodeDec64(12, -2), 0},
+		{MustEncodeDec64(12345678, 0), Inexact},
+		{MustEncodeDec64(1, 100), Overflow | Inexact},
+		{MustEncodeDec64(15, -102), Underflow | Inexact},
+		{Dec64(snanMask64), Invalid},
+	}
+	for i, testCase := range testCases {
+		var c Context
+		if c.FromDec64(testCase.d); c.Flags != testCase.flags {
+			t.Errorf("testCase #%d: expect %v, got %v", i, testCase.flags, c.Flags)
+		}
+		c = Context{}
+		if c.FromDec128(testCase.d.ToDec128()); c.Flags != testCase.flags && testCase.flags != Invalid {
+			t.Errorf("testCase #%d: via Dec128: expect %v, got %v", i, testCase.flags, c.Flags)
+		}
+	}
+	var c Context
+	if r := c.FromDec128(Dec128{hi: snanMask128}); !r.IsNaN() || r.IsSignalingNaN() || c.Flags != Invalid {
+		t.Errorf("expect quiet NaN and Invalid, got %v %v", r, c.Flags)
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import "bytes"
+
+// iso4217Minor lists the active ISO 4217 currency codes by the number of
+// minor units, that is fraction digits, in which their amounts are stated.
+var iso4217Minor = [...]string{
+	0: "BIF CLP DJF GNF ISK JPY KMF KRW PYG RWF UGX UYI VND VUV XAF XOF XPF",
+	2: "AED AFN ALL AMD ANG AOA ARS AUD AWG AZN BAM BBD BDT BGN BMD BND BOB BOV " +
+		"BRL BSD BTN BWP BYN BZD CAD CDF CHE CHF CHW CNY COP COU CRC CUP CVE CZK " +
+		"DKK DOP DZD EGP ERN ETB EUR FJD FKP GBP GEL GHS GIP GMD GTQ GYD HKD HNL " +
+		"HTG HUF IDR ILS INR IRR JMD KES KGS KHR KPW KYD KZT LAK LBP LKR LRD LSL " +
+		"MAD MDL MGA MKD MMK MNT MOP MRU MUR MVR MWK MXN MXV MYR MZN NAD NGN NIO " +
+		"NOK NPR NZD PAB PEN PGK PHP PKR PLN QAR RON RSD RUB SAR SBD SCR SDG SEK " +
+		"SGD SHP SLE SOS SRD SSP STN SVC SYP SZL THB TJS TMT TOP TRY TTD TWD TZS " +
+		"UAH USD USN UZS VED VES WST XCD XCG YER ZAR ZMW ZWG",
+	3: "BHD IQD JOD KWD LYD OMR TND",
+	4: "CLF UYW",
+}
+
+// currencySymbols holds the symbols of widely used currencies, for
+// CurrencySymbol formatting.
+var currencySymbols = map[string]string{
+	"EUR": "€",
+	"GBP": "£",
+	"ILS": "₪",
+	"INR": "₹",
+	"JPY": "¥",
+	"KRW": "₩",
+	"NGN": "₦",
+	"PHP": "₱",
+	"RUB": "₽",
+	"THB": "฿",
+	"TRY": "₺",
+	"UAH": "₴",
+	"USD": "$",
+	"VND": "₫",
+}
+
+// CurrencyMinorUnits returns the number of fraction digits in which amounts
+// of the ISO 4217 currency code are stated, such as 2 for "USD" and 0 for
+// "JPY", and whether code is a known currency.
+func CurrencyMinorUnits(code string) (int, bool) {
+	if len(code) != 3 {
+		return 0, false
+	}
+	for minor, codes := range iso4217Minor {
+		for i := 0; i+3 <= len(codes); i += 4 {
+			if codes[i:i+3] == code {
+				return minor, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// CurrencyOption selects the presentation of FormatCurrency.
+type CurrencyOption uint8
+
+const (
+	// CurrencySymbol writes the currency symbol, such as "$", in place of
+	// the currency code for currencies with a widely used symbol.
+	CurrencySymbol CurrencyOption = 1 << iota
+	// CurrencyGrouping separates thousands in the integer digits with
+	// commas.
+	CurrencyGrouping
+)
+
+// FormatCurrency returns the amount d of the ISO 4217 currency code, rounded
+// ties to even to the minor units of the currency and written in plain
+// notation after the currency code, such as "USD 1234.50". With
+// CurrencySymbol, the symbol is written instead and without a space:
+// "¥1235". Negative amounts are written with the sign first, such as
+// "-$0.25". It returns false if code is not a known currency or d is not
+// finite.
+func FormatCurrency(d Dec32, code string, opts CurrencyOption) (string, bool) {
+	minor, ok := CurrencyMinorUnits(code)
+	if !ok || d.IsInf() || d.IsNaN() {
+		return "", false
+	}
+	x := newBigDec32(d)
+	x.rescale(-minor)
+	var buf []byte
+	if x.neg && !x.isZero() {
+		buf = append(buf, '-')
+	}
+	if sym, ok := currencySymbols[code]; ok && opts&CurrencySymbol != 0 {
+		buf = append(buf, sym...)
+	} else {
+		buf = append(buf, code...)
+		buf = append(buf, ' ')
+	}
+	plain := appendPlainDigits(nil, []byte(x.coeff.String()), x.exp)
+	if opts&CurrencyGrouping != 0 {
+		buf = appendGrouped(buf, plain, ',')
+	} else {
+		buf = append(buf, plain...)
+	}
+	return string(buf), true
+}
+
+// Convert converts amount at the exchange rate rate, rounding the exact
+// product once with the given mode to targetMinorUnits fraction digits, the
+// minor units of the destination currency as CurrencyMinorUnits reports
+// them. It also returns the residual, the exact product less the converted
+// amount, so that reconciliation can account for the conversion dust: 100.00
+// at 1.08375 converts to 108.38 to the nearest cent with a residual of
+// -0.0050000. The residual has the exponent of the product, and is rounded to
+// the nearest decimal32, ties to even, should it need more than seven digits.
+//
+// Both results are NaN if either operand is NaN or infinite, or if the
+// converted amount needs more than seven digits or -targetMinorUnits is
+// outside the decimal32 exponent range.
+func Convert(amount, rate Dec32, targetMinorUnits int, mode RoundingMode) (converted, residual Dec32) {
+	exp := -targetMinorUnits
+	if amount.IsNaN() || amount.IsInf() || rate.IsNaN() || rate.IsInf() ||
+		exp < minExp || exp > maxExp {
+		return nan32, nan32
+	}
+	p := new(bigDec).mul(newBigDec32(amount), newBigDec32(rate))
+	x := new(bigDec).setSigned(p.signed(), p.exp, p.neg)
+	x.rescaleMode(exp, mode)
+	if bigDigits(&x.coeff) > 7 {
+		return nan32, nan32
+	}
+	converted = pack32(x.neg, uint32(x.coeff.Uint64()), x.exp)
+	r := new(bigDec).sub(p, x)
+	if r.isZero() {
+		r.neg = p.neg
+	}
+	residual, _ = r.dec32()
+	return converted, residual
+}
+
+// FromMinorUnits returns the amount of units minor units of a currency with
+// scale of them to the major unit, as CurrencyMinorUnits reports, so that
+// 1999 cents with scale 2 is 19.99 and 500 yen with scale 0 is 500. The
+// result keeps the exponent -scale where it fits. It is FromScaledInt64, and
+// reports false likewise if the amount needs more than seven digits.
+func FromMinorUnits(units int64, scale uint8) (Dec32, bool) {
+	return FromScaledInt64(units, int32(scale))
+}
+
+// MinorUnits returns the amount d in minor units of a currency of the given
+// scale, so that 19.99 is 1999 with scale 2. It reports false if d is not
+// finite, has more than scale fraction digits or is out of the int64 range;
+// round it with RoundToScale first to settle fractions of a minor unit.
+func (d Dec32) MinorUnits(scale uint8) (int64, bool) {
+	return d.ToScaledInt64(int32(scale))
+}
+
+// RoundToScale returns d quantized with the given mode to scale fraction
+// digits, such as 19.995 to 20.00 with scale 2 rounding ToNearestAway, and
+// reports whether the result is exact. It is Rescale with the exponent
+// -scale, and the result is NaN where that needs more than seven digits.
+func (d Dec32) RoundToScale(scale uint8, mode RoundingMode) (Dec32, bool) {
+	return d.Rescale(-int(scale), mode)
+}
+
+// appendGrouped appends the unsigned plain number s to buf, separating
+// thousands in its integer digits with sep.
+func appendGrouped(buf, s []byte, sep byte) []byte {
+	n := len(s)
+	if i := bytes.IndexByte(s, '.'); i >= 0 {
+		n = i
+	}
+	for i := 0; i < n; i++ {
+		if i > 0 && (n-i)%3 == 0 {
+			buf = append(buf, sep)
+		}
+		buf = append(buf, s[i])
+	}
+	return append(buf, s[n:]...)
+}
@@ -0,0 +1,154 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import "testing"
+
+func TestCurrencyMinorUnits(t *testing.T) {
+	testCases := []struct {
+		code  string
+		minor int
+		ok    bool
+	}{
+		{"USD", 2, true},
+		{"AED", 2, true},
+		{"ZWG", 2, true},
+		{"JPY", 0, true},
+		{"BIF", 0, true},
+		{"KWD", 3, true},
+		{"CLF", 4, true},
+		{"usd", 0, false},
+		{"US", 0, false},
+		{"XXX", 0, false},
+		{"", 0, false},
+	}
+	for i, testCase := range testCases {
+		minor, ok := CurrencyMinorUnits(testCase.code)
+		if minor != testCase.minor || ok != testCase.ok {
+			t.Errorf("testCase #%d: expect %d %v, got %d %v", i, testCase.minor, testCase.ok, minor, ok)
+		}
+	}
+}
+
+func TestFormatCurrency(t *testing.T) {
+	testCases := []struct {
+		d    Dec32
+		code string
+		opts CurrencyOption
+		ref  string
+		ok   bool
+	}{
+		{mustEncode(t, 12345, -1), "USD", CurrencyGrouping, "USD 1,234.50", true},
+		{mustEncode(t, 12345, -1), "USD", 0, "USD 1234.50", true},
+		{mustEncode(t, 12345, -1), "JPY", CurrencySymbol | CurrencyGrouping, "¥1,234", true},
+		{mustEncode(t, 12345, -1), "JPY", CurrencySymbol, "¥1234", true},
+		{mustEncode(t, 12355, -1), "JPY", CurrencySymbol, "¥1236", true},
+		{mustEncode(t, 12345, -1), "KWD", 0, "KWD 1234.500", true},
+		{mustEncode(t, 1234567, 2), "EUR", CurrencySymbol | CurrencyGrouping, "€123,456,700.00", true},
+		{mustEncode(t, 100, 0), "EUR", CurrencyGrouping, "EUR 100.00", true},
+		{mustEncode(t, -25, -2), "USD", CurrencySymbol, "-$0.25", true},
+		// No symbol is known for CHF.
+		{mustEncode(t, 5, 0), "CHF", CurrencySymbol, "CHF 5.00", true},
+		// Amounts that round to zero lose their sign.
+		{mustEncode(t, -4, -3), "USD", 0, "USD 0.00", true},
+		{mustEncode(t, 1, 0), "XYZ", 0, "", false},
+		{inf32, "USD", 0, "", false},
+		{nan32, "USD", 0, "", false},
+	}
+	for i, testCase := range testCases {
+		s, ok := FormatCurrency(testCase.d, testCase.code, testCase.opts)
+		if s != testCase.ref || ok != testCase.ok {
+			t.Errorf("testCase #%d: expect %q %v, got %q %v", i, testCase.ref, testCase.ok, s, ok)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	testCases := []struct {
+		amount, rate        Dec32
+		minor               int
+		mode                RoundingMode
+		converted, residual Dec32
+	}{
+		{mustEncode(t, 10000, -2), mustEncode(t, 108375, -5), 2, ToNearestEven, mustEncode(t, 10838, -2), mustEncode(t, -50000, -7)},
+		{mustEncode(t, 10000, -2), mustEncode(t, 108375, -5), 2, ToZero, mustEncode(t, 10837, -2), mustEncode(t, 50000, -7)},
+		{mustEncode(t, 123456, -2), mustEncode(t, 1234567, -6), 2, ToNearestEven, mustEncode(t, 152415, -2), mustEncode(t, -296448, -8)},
+		// Yen have no minor units.
+		{mustEncode(t, 2550, -2), mustEncode(t, 1574321, -4), 0, ToNearestEven, mustEncode(t, 4015, 0), mustEncode(t, -481450, -6)},
+		{mustEncode(t, -500, -2), mustEncode(t, 9, -1), 2, ToNegativeInf, mustEncode(t, -450, -2), pack32(true, 0, -3)},
+		// A residual of more than seven digits is rounded.
+		{mustEncode(t, 1234567, -6), mustEncode(t, 1234567, -6), 2, ToNearestEven, mustEncode(t, 152, -2), mustEncode(t, 4155677, -9)},
+		{mustEncode(t, 9999999, 0), mustEncode(t, 2, 0), 2, ToNearestEven, nan32, nan32},
+		{mustEncode(t, 1, 0), mustEncode(t, 1, 0), 102, ToNearestEven, nan32, nan32},
+		{inf32, mustEncode(t, 1, 0), 2, ToNearestEven, nan32, nan32},
+		{mustEncode(t, 1, 0), nan32, 2, ToNearestEven, nan32, nan32},
+	}
+	for i, testCase := range testCases {
+		c, r := Convert(testCase.amount, testCase.rate, testCase.minor, testCase.mode)
+		if c != testCase.converted || r != testCase.residual {
+			t.Errorf("testCase #%d: expect %v %v, got %v %v", i, testCase.converted, testCase.residual, c, r)
+		}
+	}
+}
+
+func TestMinorUnits(t *testing.T) {
+	testCases := []struct {
+		units int64
+		scale uint8
+		d     Dec32
+		exact bool
+	}{
+		{1999, 2, mustEncode(t, 1999, -2), true},
+		{-500, 0, mustEncode(t, -500, 0), true},
+		{0, 3, mustEncode(t, 0, -3), true},
+		{12345, 4, mustEncode(t, 12345, -4), true},
+		{123456789, 2, mustEncode(t, 1234568, 0), false},
+	}
+	for i, testCase := range testCases {
+		d, exact := FromMinorUnits(testCase.units, testCase.scale)
+		if d != testCase.d || exact != testCase.exact {
+			t.Errorf("testCase #%d: FromMinorUnits(%d, %d): expect %v %v, got %v %v", i,
+				testCase.units, testCase.scale, testCase.d, testCase.exact, d, exact)
+		}
+		if !exact {
+			continue
+		}
+		if units, ok := d.MinorUnits(testCase.scale); !ok || units != testCase.units {
+			t.Errorf("testCase #%d: MinorUnits(%v, %d): expect %d, got %d %v", i, d, testCase.scale, testCase.units, units, ok)
+		}
+	}
+	for _, d := range []Dec32{mustEncode(t, 19995, -3), inf32, nan32} {
+		if units, ok := d.MinorUnits(2); ok {
+			t.Errorf("MinorUnits(%v, 2): expect failure, got %d", d, units)
+		}
+	}
+	if units, ok := mustEncode(t, 15, 1).MinorUnits(2); !ok || units != 15000 {
+		t.Errorf("MinorUnits(1.5E+2, 2): expect 15000, got %d %v", units, ok)
+	}
+}
+
+func TestRoundToScale(t *testing.T) {
+	testCases := []struct {
+		d     Dec32
+		scale uint8
+		mode  RoundingMode
+		ref   Dec32
+		exact bool
+	}{
+		{mustEncode(t, 19995, -3), 2, ToNearestAway, mustEncode(t, 2000, -2), false},
+		{mustEncode(t, 19995, -3), 2, ToNearestEven, mustEncode(t, 2000, -2), false},
+		{mustEncode(t, 19985, -3), 2, ToNearestEven, mustEncode(t, 1998, -2), false},
+		{mustEncode(t, -19985, -3), 2, ToZero, mustEncode(t, -1998, -2), false},
+		{mustEncode(t, 2, 0), 2, ToNearestEven, mustEncode(t, 200, -2), true},
+		{mustEncode(t, 1234567, 0), 2, ToNearestEven, nan32, false},
+	}
+	for i, testCase := range testCases {
+		r, exact := testCase.d.RoundToScale(testCase.scale, testCase.mode)
+		if r != testCase.ref || exact != testCase.exact {
+			t.Errorf("testCase #%d: RoundToScale(%v, %d): expect %v %v, got %v %v", i,
+				testCase.d, testCase.scale, testCase.ref, testCase.exact, r, exact)
+		}
+	}
+}
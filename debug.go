@@ -0,0 +1,82 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import "strconv"
+
+// Dump returns a breakdown of the fields of the decimal32 encoding of d, for
+// debugging corrupt or unexpected values, such as
+//
+//	Dec32(0x6cbfffff): sign=+ form=large exp=0 coeff=10485759 non-canonical
+//
+// Special values show their kind, and NaNs their payload. Infinities with
+// trailing bits set, and NaNs with reserved bits set or a payload above
+// 999,999, are marked non-canonical too.
+func (d Dec32) Dump() string {
+	buf := []byte("Dec32(0x")
+	buf = strconv.AppendUint(buf, uint64(d), 16)
+	buf = append(buf, "): sign="...)
+	if d.Sign() < 0 {
+		buf = append(buf, '-')
+	} else {
+		buf = append(buf, '+')
+	}
+	switch {
+	case d.IsInf():
+		buf = append(buf, " form=Infinity"...)
+	case d.IsNaN():
+		if d&snanMask == snanMask {
+			buf = append(buf, " form=sNaN payload="...)
+		} else {
+			buf = append(buf, " form=NaN payload="...)
+		}
+		buf = strconv.AppendUint(buf, uint64(d&nanPayloadMask), 10)
+	}
+	if d.IsInf() || d.IsNaN() {
+		if d.canonicalEncoding() != d {
+			buf = append(buf, " non-canonical"...)
+		}
+		return string(buf)
+	}
+	coeff, exp, _ := d.Decode()
+	if d&largeMask == largeMask {
+		buf = append(buf, " form=large exp="...)
+	} else {
+		buf = append(buf, " form=small exp="...)
+	}
+	buf = strconv.AppendInt(buf, int64(exp), 10)
+	buf = append(buf, " coeff="...)
+	if coeff < 0 {
+		coeff = -coeff
+	}
+	buf = strconv.AppendInt(buf, int64(coeff), 10)
+	if coeff > maxCoeff {
+		buf = append(buf, " non-canonical"...)
+	}
+	return string(buf)
+}
+
+// checkCanonical panics with the Dump of d if strict debugging is enabled by
+// the decdebug build tag and d has a non-canonical coefficient.
+func checkCanonical(d Dec32, coeff int32) {
+	if debugStrict && (coeff > maxCoeff || coeff < -maxCoeff) {
+		panic("decimal: non-canonical encoding " + d.Dump())
+	}
+}
+
+// checkSpecial panics with the Dump of the first of ds that is an infinity or
+// NaN with a non-canonical encoding, if strict debugging is enabled by the
+// decdebug build tag. Operations call it on their operands, since special
+// values bypass the check of unpack.
+func checkSpecial(ds ...Dec32) {
+	if !debugStrict {
+		return
+	}
+	for _, d := range ds {
+		if (d.IsInf() || d.IsNaN()) && d.canonicalEncoding() != d {
+			panic("decimal: non-canonical encoding " + d.Dump())
+		}
+	}
+}
@@ -0,0 +1,10 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !decdebug
+
+package decimal
+
+// debugStrict is set by the decdebug build tag; see debug_strict.go.
+const debugStrict = false
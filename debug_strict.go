@@ -0,0 +1,15 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build decdebug
+
+package decimal
+
+// debugStrict enables panics on non-canonical encodings. Build with
+//
+//	go test -tags decdebug ./...
+//
+// to surface corrupt values as soon as an operation reads them, instead of
+// silently treating their coefficients as zero.
+const debugStrict = true
@@ -0,0 +1,61 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build decdebug
+
+package decimal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStrictNonCanonical(t *testing.T) {
+	defer func() {
+		r := recover()
+		if s, _ := r.(string); !strings.Contains(s, "coeff=10485759 non-canonical") {
+			t.Errorf("unexpected panic %v", r)
+		}
+	}()
+	_ = Dec32(0x6cbfffff).String()
+	t.Errorf("expected panic")
+}
+
+func TestStrictSpecialOperands(t *testing.T) {
+	one := mustEncode(t, 1, 0)
+	testCases := []struct {
+		op  func(c *Context) Dec32
+		ref string
+	}{
+		{func(c *Context) Dec32 { return c.Add(Dec32(0x7c100000), one) }, "form=NaN payload=0 non-canonical"},
+		{func(c *Context) Dec32 { return c.Sub(one, nan32|(maxPayload+1)) }, "form=NaN payload=1000000 non-canonical"},
+		{func(c *Context) Dec32 { return c.Mul(inf32|1, one) }, "form=Infinity non-canonical"},
+		{func(c *Context) Dec32 { return c.FMA(one, one, Dec32(0xfe100007)) }, "form=sNaN payload=7 non-canonical"},
+		{func(c *Context) Dec32 { return c.Max(one, inf32|0x00100000) }, "form=Infinity non-canonical"},
+		{func(c *Context) Dec32 { return c.Round(Dec32(0x6cbfffff)) }, "coeff=10485759 non-canonical"},
+	}
+	for i, testCase := range testCases {
+		func() {
+			defer func() {
+				r := recover()
+				if s, _ := r.(string); !strings.Contains(s, testCase.ref) {
+					t.Errorf("testCase #%d: expect panic with %q, got %v", i, testCase.ref, r)
+				}
+			}()
+			var c Context
+			d := testCase.op(&c)
+			t.Errorf("testCase #%d: expected panic, got %v", i, d)
+		}()
+	}
+}
+
+func TestStrictCanonicalSpecials(t *testing.T) {
+	var c Context
+	if d := c.Add(nan32|maxPayload, mustEncode(t, 1, 0)); d != nan32|maxPayload {
+		t.Errorf("expect NaN999999, got %v", d)
+	}
+	if d := c.Mul(inf32|signMask, mustEncode(t, 2, 0)); d != inf32|signMask {
+		t.Errorf("expect -Inf, got %v", d)
+	}
+}
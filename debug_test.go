@@ -0,0 +1,31 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import "testing"
+
+func TestDump(t *testing.T) {
+	testCases := []struct {
+		d   Dec32
+		ref string
+	}{
+		{mustEncode(t, -125, -2), "Dec32(0xb180007d): sign=- form=small exp=-2 coeff=125"},
+		{mustEncode(t, 9999999, 0), "Dec32(0x6cb8967f): sign=+ form=large exp=0 coeff=9999999"},
+		{Dec32(0x6cbfffff), "Dec32(0x6cbfffff): sign=+ form=large exp=0 coeff=10485759 non-canonical"},
+		{inf32 | signMask, "Dec32(0xf8000000): sign=- form=Infinity"},
+		{inf32 | 1, "Dec32(0x78000001): sign=+ form=Infinity non-canonical"},
+		{nan32 | 42, "Dec32(0x7c00002a): sign=+ form=NaN payload=42"},
+		{Dec32(snanMask), "Dec32(0x7e000000): sign=+ form=sNaN payload=0"},
+		{nan32 | maxPayload, "Dec32(0x7c0f423f): sign=+ form=NaN payload=999999"},
+		{nan32 | (maxPayload + 1), "Dec32(0x7c0f4240): sign=+ form=NaN payload=1000000 non-canonical"},
+		{Dec32(0x7c100000), "Dec32(0x7c100000): sign=+ form=NaN payload=0 non-canonical"},
+		{Dec32(0xfe100007), "Dec32(0xfe100007): sign=- form=sNaN payload=7 non-canonical"},
+	}
+	for i, testCase := range testCases {
+		if s := testCase.d.Dump(); s != testCase.ref {
+			t.Errorf("testCase #%d: expect %q, got %q", i, testCase.ref, s)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import "math/big"
+
+// Dec128 stores a decimal128 value: a 128-bit signed decimal floating-point
+// number as defined in IEEE-754-2008. Dec128 can hold a significand of up to
+// 34 digits, multiplied by 10^exp, where -6176 <= exp <= 6111. Equivalently,
+// the exponent of the most significant digit ranges from -6143 to 6144. Like
+// Dec32, this implementation stores the significand as a binary integer
+// decimal. Dec128 values are comparable, and equal when their encodings are.
+type Dec128 struct {
+	hi, lo uint64
+}
+
+const (
+	// Representation of the high 64 bits, with coefficients in 113 bits:
+	//	s eeeeeeeeeeeeee ccccc...(49 bits)
+	// The form with an implied 100 prefix, s 11 eeeeeeeeeeeeee, only
+	// holds coefficients above the maximum, and so encodes zeros. The high
+	// five bits of the combination field are 11110 for infinity and 11111
+	// for NaN, followed by a 1 bit for a signaling NaN, as in decimal32.
+	signMask128       = 0x8000000000000000
+	combMask128       = 0x7c00000000000000
+	snanMask128       = 0x7e00000000000000
+	largeMask128      = 0x6000000000000000
+	smallExpMask128   = 0x7ffe000000000000
+	smallCoeffMask128 = 0x0001ffffffffffff
+	largeExpMask128   = 0x1fff800000000000
+
+	combOffset128     = 58
+	smallExpOffset128 = 49
+	largeExpOffset128 = 47
+
+	minExp128  = -6176
+	maxExp128  = 6111
+	expBias128 = 6176
+)
+
+var (
+	// maxCoeff128 is the largest decimal128 coefficient, 10^34 - 1.
+	maxCoeff128 = new(big.Int).Sub(new(big.Int).Exp(big.NewInt(10), big.NewInt(34), nil), big.NewInt(1))
+
+	failDec128 = Dec128{0xffffffffffffffff, 0xffffffffffffffff}
+	inf128     = Dec128{hi: 0x7800000000000000}
+	nan128     = Dec128{hi: 0x7c00000000000000}
+)
+
+// Dec128FromBits returns the decimal128 value with the interchange encoding
+// whose high and low 64 bits are hi and lo.
+func Dec128FromBits(hi, lo uint64) Dec128 {
+	return Dec128{hi, lo}
+}
+
+// Bits returns the high and low 64 bits of the interchange encoding of d.
+func (d Dec128) Bits() (hi, lo uint64) {
+	return d.hi, d.lo
+}
+
+// Sign returns -1 if the decimal is negative, 1 if the decimal is positive.
+// Zero values can be either positive or negative.
+func (d Dec128) Sign() int {
+	if (d.hi & signMask128) == signMask128 {
+		return -1
+	}
+	return 1
+}
+
+// Zero returns whether the decimal128 represents a zero value. Coefficient
+// values greater than the maximum 10^34 - 1 also represent zero according to
+// the IEEE-754-2008 spec.
+func (d Dec128) Zero() bool {
+	coeff, _, ok := d.Decode()
+	return ok && (coeff.Sign() == 0 || coeff.CmpAbs(maxCoeff128) > 0)
+}
+
+// Valid returns whether the decimal value is well-formed according to the
+// IEEE-754-2008 specification. Exponent and coefficient values beyond the
+// spec limits are invalid.
+func (d Dec128) Valid() bool {
+	coeff, exp, ok := d.Decode()
+	return ok && exp >= minExp128 && exp <= maxExp128 && coeff.CmpAbs(maxCoeff128) <= 0
+}
+
+// IsInf returns whether the decimal128 value is infinite.
+func (d Dec128) IsInf() bool {
+	return d.combBits() == 0x1e
+}
+
+// IsNaN returns whether the decimal128 value is not-a-number (NaN).
+func (d Dec128) IsNaN() bool {
+	return d.combBits() == 0x1f
+}
+
+func (d Dec128) combBits() uint64 {
+	return (d.hi & combMask128) >> combOffset128
+}
+
+// EncodeDec128 encodes the given coefficient and exponent into a decimal
+// value. The coefficient holds up to 34 digits, so it is given as a
+// big.Int, whose sign is the sign of the result.
+func EncodeDec128(coeff *big.Int, exp int16) (Dec128, bool) {
+	if coeff.CmpAbs(maxCoeff128) > 0 {
+		return failDec128, false
+	}
+	if exp < minExp128 || exp > maxExp128 {
+		return failDec128, false
+	}
+	return pack128(coeff.Sign() < 0, coeff, int(exp)), true
+}
+
+// MustEncodeDec128 is like EncodeDec128 but panics if the coefficient or
+// exponent is out of range. It simplifies initialization of decimal
+// variables.
+func MustEncodeDec128(coeff *big.Int, exp int16) Dec128 {
+	d, ok := EncodeDec128(coeff, exp)
+	if !ok {
+		panic("decimal: coefficient or exponent out of range in MustEncodeDec128")
+	}
+	return d
+}
+
+// pack128 encodes a sign, and the magnitude of a coefficient and an exponent
+// known to be within the decimal128 limits. Unlike EncodeDec128, it can
+// encode negative zero.
+func pack128(neg bool, coeff *big.Int, exp int) Dec128 {
+	var c big.Int
+	c.Abs(coeff)
+	lo := c.Uint64()
+	hi := c.Rsh(&c, 64).Uint64()
+	hi |= uint64(exp+expBias128) << smallExpOffset128
+	if neg {
+		hi |= signMask128
+	}
+	return Dec128{hi, lo}
+}
+
+// unpack decodes a finite decimal128 value into its sign, coefficient
+// magnitude and exponent. Non-canonical coefficients decode as zero.
+func (d Dec128) unpack() (neg bool, coeff *big.Int, exp int) {
+	c, e, _ := d.Decode()
+	neg = (d.hi & signMask128) == signMask128
+	c.Abs(c)
+	if c.Cmp(maxCoeff128) > 0 {
+		c.SetInt64(0)
+	}
+	return neg, c, int(e)
+}
+
+// Decode decodes a decimal128 value into its coefficient and exponent
+// components, and whether the value can be decoded. Infinite, NaN and illegal
+// values cannot be decoded to a coefficient and exponent.
+func (d Dec128) Decode() (coeff *big.Int, expn int16, ok bool) {
+	if d.IsInf() || d.IsNaN() {
+		return new(big.Int), 0, false
+	}
+	var hi, bexp uint64
+	if (d.hi & largeMask128) == largeMask128 {
+		// The implied 100 prefix makes the coefficient at least 2^113.
+		hi = 1<<49 | (d.hi & (1<<47 - 1))
+		bexp = (d.hi & largeExpMask128) >> largeExpOffset128
+	} else {
+		hi = d.hi & smallCoeffMask128
+		bexp = (d.hi & smallExpMask128) >> smallExpOffset128
+	}
+	coeff = new(big.Int).SetUint64(hi)
+	coeff.Lsh(coeff, 64)
+	coeff.Or(coeff, new(big.Int).SetUint64(d.lo))
+	expn = int16(int64(bexp) - expBias128)
+	if (d.hi & signMask128) == signMask128 {
+		coeff.Neg(coeff)
+	}
+	return coeff, expn, true
+}
@@ -0,0 +1,115 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"math/big"
+	"testing"
+)
+
+func mustBigInt(t *testing.T, s string) *big.Int {
+	c, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		t.Fatalf("bad integer %q", s)
+	}
+	return c
+}
+
+func TestEncDec128(t *testing.T) {
+	testCases := []struct {
+		coeff  string
+		exp    int16
+		hi, lo uint64
+		ok     bool
+	}{
+		{"1", 0, 0x3040000000000000, 1, true},
+		// Min exp
+		{"2", -6176, 0, 2, true},
+		// Min exp - 1
+		{"2", -6177, 0, 0, false},
+		// Max exp
+		{"2", 6111, 0x5ffe000000000000, 2, true},
+		// Max exp + 1
+		{"2", 6112, 0, 0, false},
+		// Negative exp
+		{"-125", -2, 0xb03c000000000000, 125, true},
+		// Max coeff
+		{"9999999999999999999999999999999999", 0, 0x3041ed09bead87c0, 0x378d8e63ffffffff, true},
+		// Max coeff at min exp
+		{"-9999999999999999999999999999999999", -6176, 0x8001ed09bead87c0, 0x378d8e63ffffffff, true},
+		// Max coeff + 1
+		{"10000000000000000000000000000000000", 0, 0, 0, false},
+		// A coefficient spanning both words
+		{"18446744073709551616", 0, 0x3040000000000001, 0, true},
+	}
+	for i, testCase := range testCases {
+		coeff := mustBigInt(t, testCase.coeff)
+		d, ok := EncodeDec128(coeff, testCase.exp)
+		if ok != testCase.ok {
+			t.Errorf("testCase #%d: expect ok=%v, got %v", i, testCase.ok, ok)
+		}
+		if !ok {
+			continue
+		}
+		if hi, lo := d.Bits(); hi != testCase.hi || lo != testCase.lo {
+			t.Errorf("testCase #%d: expect dec128=%016x%016x, got %016x%016x", i, testCase.hi, testCase.lo, hi, lo)
+		}
+		c, exp, ok := d.Decode()
+		if c.Cmp(coeff) != 0 || exp != testCase.exp || !ok {
+			t.Errorf("testCase #%d: expect %v %d, got %v %d %v", i, coeff, testCase.exp, c, exp, ok)
+		}
+		if !d.Valid() {
+			t.Errorf("testCase #%d: not valid", i)
+		}
+		if sign := d.Sign(); (sign < 0) != (coeff.Sign() < 0) {
+			t.Errorf("testCase #%d: unexpected sign %d", i, sign)
+		}
+		if Dec128FromBits(d.Bits()) != d {
+			t.Errorf("testCase #%d: bits do not round-trip", i)
+		}
+	}
+}
+
+func TestDec128Predicates(t *testing.T) {
+	testCases := []struct {
+		d                 Dec128
+		zero, inf, nan    bool
+		valid, decodeable bool
+	}{
+		{MustEncodeDec128(big.NewInt(0), 0), true, false, false, true, true},
+		{Dec128FromBits(0xb03c000000000000, 0), true, false, false, true, true},
+		{MustEncodeDec128(big.NewInt(7), 0), false, false, false, true, true},
+		// Coefficients above the maximum are non-canonical zeros, in either
+		// form.
+		{Dec128FromBits(0x3041ed09bead87c0, 0x378d8e6400000000), true, false, false, false, true},
+		{Dec128FromBits(0x6820000000000000, 0), true, false, false, false, true},
+		{inf128, false, true, false, false, false},
+		{Dec128FromBits(0xf800000000000000, 0), false, true, false, false, false},
+		{nan128, false, false, true, false, false},
+		{Dec128FromBits(snanMask128, 0), false, false, true, false, false},
+	}
+	for i, testCase := range testCases {
+		d := testCase.d
+		if d.Zero() != testCase.zero || d.IsInf() != testCase.inf || d.IsNaN() != testCase.nan {
+			t.Errorf("testCase #%d: %x: expect zero=%v inf=%v nan=%v, got %v %v %v", i, d,
+				testCase.zero, testCase.inf, testCase.nan, d.Zero(), d.IsInf(), d.IsNaN())
+		}
+		if d.Valid() != testCase.valid {
+			t.Errorf("testCase #%d: %x: expect valid=%v", i, d, testCase.valid)
+		}
+		if _, _, ok := d.Decode(); ok != testCase.decodeable {
+			t.Errorf("testCase #%d: %x: expect decode ok=%v", i, d, testCase.decodeable)
+		}
+	}
+}
+
+func TestMustEncodeDec128(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic")
+		}
+	}()
+	MustEncodeDec128(big.NewInt(1), 6112)
+}
@@ -8,27 +8,38 @@ import "math"
 
 // Dec32 stores a decimal32 value: a 32-bit signed decimal floating-point number
 // as defined in IEEE-754-2008. Dec32 can hold a significand in the range
-// of 0-9999999, multiplied by 10^exp, where -95 <= exp <= 96.
+// of 0-9999999, multiplied by 10^exp, where -101 <= exp <= 90. Equivalently,
+// the exponent of the most significant digit ranges from -95 to 96.
 // This implementation stores the significand as a binary integer decimal.
 type Dec32 uint32
 
 const (
-	// Representation:         s mmmmm xxxxxx cccccccccccccccccccc
-	signMask = 0x80000000 // --+ |     |      |
-	combMask = 0x7c000000 // ----+     |      |
-	expMask  = 0x03f00000 // ----------+      |
-	contMask = 0x000fffff // -----------------+
+	// Representation of coefficients that fit in 23 bits:
+	//                         s eeeeeeee ccccccccccccccccccccccc
+	// and of larger coefficients, which have an implied 100 prefix:
+	//                         s 11 eeeeeeee ccccccccccccccccccccc
+	// The high five bits of the combination field are 11110 for infinity
+	// and 11111 for NaN, followed by a 1 bit for a signaling NaN.
+	signMask       = 0x80000000
+	combMask       = 0x7c000000
+	snanMask       = 0x7e000000
+	largeMask      = 0x60000000
+	smallExpMask   = 0x7f800000
+	smallCoeffMask = 0x007fffff
+	largeExpMask   = 0x1fe00000
+	largeCoeffMask = 0x001fffff
 
 	coeffMaxBits = 0x00800000
-	coeffMaxMask = 0x00100000
 
-	signOffset = 31
-	combOffset = 26
-	expOffset  = 20
+	signOffset     = 31
+	combOffset     = 26
+	smallExpOffset = 23
+	largeExpOffset = 21
 
 	maxCoeff = 9999999
-	minExp   = -95
-	maxExp   = 96
+	minExp   = -101
+	maxExp   = 90
+	expBias  = 101
 )
 
 // Sign returns -1 if the decimal is negative, 1 if the decimal is positive.
@@ -44,8 +55,45 @@ func (d Dec32) Sign() int {
 // values greater than the maximum 9,999,999 also represent zero according to
 // the IEEE-754-2008 spec.
 func (d Dec32) Zero() bool {
-	coeff, _, _ := d.Decode()
-	return coeff == 0 || coeff > maxCoeff
+	coeff, _, ok := d.Decode()
+	return ok && (coeff == 0 || coeff > maxCoeff || coeff < -maxCoeff)
+}
+
+// IsCanonicalZero returns whether the decimal32 value is a zero encoded with
+// a zero coefficient. Unlike Zero, it is false for the non-canonical
+// encodings whose coefficient exceeds 9,999,999, so applications can tell
+// true zeros from such values received off the wire.
+func (d Dec32) IsCanonicalZero() bool {
+	coeff, _, ok := d.Decode()
+	return ok && coeff == 0
+}
+
+// NormalizeZero returns d with a non-canonical coefficient replaced by zero,
+// keeping its sign and exponent, so that the result is a canonical zero
+// wherever Zero is true. Other values are returned unchanged.
+func (d Dec32) NormalizeZero() Dec32 {
+	coeff, exp, ok := d.Decode()
+	if !ok || (coeff <= maxCoeff && coeff >= -maxCoeff) {
+		return d
+	}
+	return pack32(coeff < 0, 0, int(exp))
+}
+
+// IsNegZero returns whether the decimal32 value is a zero with its sign bit
+// set. Negative zeros compare equal to positive zeros, but arise from
+// operations such as rounding a tiny negative value, and are written "-0".
+func (d Dec32) IsNegZero() bool {
+	return d.Zero() && d.Sign() < 0
+}
+
+// NormalizeNegZero returns a negative zero as the positive zero with the same
+// exponent, for serialization to systems that reject "-0". Other values are
+// returned unchanged.
+func (d Dec32) NormalizeNegZero() Dec32 {
+	if d.IsNegZero() {
+		return d &^ signMask
+	}
+	return d
 }
 
 // Valid returns whether the decimal value is well-formed according to the
@@ -53,7 +101,7 @@ func (d Dec32) Zero() bool {
 // spec limits are invalid.
 func (d Dec32) Valid() bool {
 	coeff, exp, ok := d.Decode()
-	return ok && exp >= minExp && exp <= maxExp && coeff <= maxCoeff
+	return ok && exp >= minExp && exp <= maxExp && coeff <= maxCoeff && coeff >= -maxCoeff
 }
 
 // IsInf returns whether the decimal32 value is infinite.
@@ -66,25 +114,65 @@ func (d Dec32) IsNaN() bool {
 	return d.combBits() == 0x1f
 }
 
-func (d Dec32) combBits() uint32 {
-	return ((uint32(d) & combMask) >> combOffset)
+// AdjustedExponent returns the exponent of the most significant digit of the
+// decimal value: the exponent plus the number of coefficient digits, minus 1.
+// This is the adjusted exponent of the decNumber specification. Zero values
+// return their exponent. Infinite and NaN values have no exponent; for them
+// AdjustedExponent returns math.MaxInt32.
+func (d Dec32) AdjustedExponent() int {
+	coeff, exp, ok := d.Decode()
+	if !ok {
+		return math.MaxInt32
+	}
+	if d.Zero() {
+		return int(exp)
+	}
+	if coeff < 0 {
+		coeff = -coeff
+	}
+	return int(exp) + numDigits(uint64(coeff)) - 1
 }
 
-func (d Dec32) expBits() uint32 {
-	return ((uint32(d) & expMask) >> expOffset)
+// ILogB returns the adjusted exponent of the decimal value as an integer,
+// following the conventions of math.Ilogb for special values:
+//
+//	ILogB(±0) = math.MinInt32
+//	ILogB(±Inf) = math.MaxInt32
+//	ILogB(NaN) = math.MaxInt32
+func (d Dec32) ILogB() int {
+	if d.IsInf() || d.IsNaN() {
+		return math.MaxInt32
+	}
+	if d.Zero() {
+		return math.MinInt32
+	}
+	return d.AdjustedExponent()
 }
 
-func (d Dec32) contBits() uint32 {
-	return (uint32(d) & contMask)
+// numDigits returns the number of decimal digits in c. Zero has one digit.
+func numDigits(c uint64) int {
+	n := 1
+	for c >= 10 {
+		c /= 10
+		n++
+	}
+	return n
 }
 
-var failDec32 = Dec32(0xffffffff)
+func (d Dec32) combBits() uint32 {
+	return ((uint32(d) & combMask) >> combOffset)
+}
+
+var (
+	failDec32 = Dec32(0xffffffff)
+	inf32     = Dec32(0x78000000)
+	nan32     = Dec32(0x7c000000)
+)
 
 // EncodeDec32 encodes the given coefficient and exponent into a decimal value.
 func EncodeDec32(coeff int32, exp int8) (Dec32, bool) {
-	var result uint32
-	if coeff < 0 {
-		result = result | signMask
+	neg := coeff < 0
+	if neg {
 		coeff = 0 - coeff
 	}
 	if coeff > maxCoeff {
@@ -93,52 +181,71 @@ func EncodeDec32(coeff int32, exp int8) (Dec32, bool) {
 	if exp < minExp || exp > maxExp {
 		return failDec32, false
 	}
-	result |= (uint32(coeff) & contMask)
-	uexp := uint8(exp)
+	return pack32(neg, uint32(coeff), int(exp)), true
+}
+
+// MustEncodeDec32 is like EncodeDec32 but panics if the coefficient or
+// exponent is out of range. It simplifies initialization of decimal
+// variables.
+func MustEncodeDec32(coeff int32, exp int8) Dec32 {
+	d, ok := EncodeDec32(coeff, exp)
+	if !ok {
+		panic("decimal: coefficient or exponent out of range in MustEncodeDec32")
+	}
+	return d
+}
+
+// pack32 encodes a sign, coefficient and exponent known to be within the
+// decimal32 limits. Unlike EncodeDec32, it can encode negative zero.
+func pack32(neg bool, coeff uint32, exp int) Dec32 {
+	var result uint32
+	if neg {
+		result = signMask
+	}
+	bexp := uint32(exp + expBias)
 	if (coeffMaxBits & coeff) == coeffMaxBits {
 		// coefficient starts with 100
-		result |= 0x60000000 | (uint32(uexp&0xc0) << 21) | ((uint32(coeff) & coeffMaxMask) << 6) | (uint32(uexp&0x3f) << 20)
+		result |= largeMask | (bexp << largeExpOffset) | (coeff & largeCoeffMask)
 	} else {
-		result |= (uint32(uexp&0xc0) << 23) | ((uint32(coeff) &^ contMask) << 6) | (uint32(uexp&0x3f) << 20)
+		result |= (bexp << smallExpOffset) | coeff
 	}
-	return Dec32(result), true
+	return Dec32(result)
 }
 
-// Decode decodes a decimal32 value into its coefficient and exponent
-// components, and whether the value can be decoded. Infinite, NaN and illegal
-// values cannot be decoded to a coefficient and exponent.
-func (d Dec32) Decode() (coeff int32, expn int8, ok bool) {
-	comb, exp, cont := d.combBits(), d.expBits(), d.contBits()
-	if (comb & 0x18) == 0x18 {
-		coeff = int32(((comb & 0x01) << 20) | coeffMaxBits | cont)
-		expn = int8(((comb << 5) & 0xc0) | exp)
-		ok = true
-	} else {
-		coeff = int32(((comb & 0x07) << 20) | cont)
-		expn = int8(((comb << 3) & 0xc0) | exp)
-		ok = true
+// unpack decodes a finite decimal32 value into its sign, coefficient
+// magnitude and exponent. Non-canonical coefficients decode as zero, or panic
+// if built with the decdebug tag.
+func (d Dec32) unpack() (neg bool, coeff uint32, exp int) {
+	c, e, _ := d.Decode()
+	checkCanonical(d, c)
+	neg = (d & signMask) == signMask
+	if c < 0 {
+		c = -c
 	}
-	if ok {
-		if (d & signMask) == signMask {
-			coeff = 0 - coeff
-		}
+	if c > maxCoeff {
+		c = 0
 	}
-	return coeff, expn, ok
+	return neg, uint32(c), int(e)
 }
 
-// Float32 returns a binary floating-point approximation of the decimal value.
-func (d Dec32) Float32() float32 {
-	coeff, exp, ok := d.Decode()
-	if !ok {
-		return float32(math.NaN())
+// Decode decodes a decimal32 value into its coefficient and exponent
+// components, and whether the value can be decoded. Infinite, NaN and illegal
+// values cannot be decoded to a coefficient and exponent.
+func (d Dec32) Decode() (coeff int32, expn int8, ok bool) {
+	if d.IsInf() || d.IsNaN() {
+		return 0, 0, false
 	}
-	var expf float32
-	if exp > 0 {
-		expf = 10.0 * float32(exp)
-	} else if exp < 0 {
-		expf = 1.0 / (10.0 * float32(exp))
+	var bexp uint32
+	if (uint32(d) & largeMask) == largeMask {
+		coeff = int32(coeffMaxBits | (uint32(d) & largeCoeffMask))
+		bexp = (uint32(d) & largeExpMask) >> largeExpOffset
 	} else {
-		return float32(coeff)
+		coeff = int32(uint32(d) & smallCoeffMask)
+		bexp = (uint32(d) & smallExpMask) >> smallExpOffset
+	}
+	expn = int8(int32(bexp) - expBias)
+	if (d & signMask) == signMask {
+		coeff = 0 - coeff
 	}
-	return float32(coeff) * expf
+	return coeff, expn, true
 }
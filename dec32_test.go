@@ -5,11 +5,12 @@
 package decimal
 
 import (
+	"math"
 	"testing"
 )
 
 func TestMaxCoeff(t *testing.T) {
-	d := Dec32(0x6408967f)
+	d := Dec32(0x6cb8967f)
 	coeff, exp, ok := d.Decode()
 	if coeff != maxCoeff {
 		t.Errorf("unexpected coeff %d", coeff)
@@ -40,26 +41,30 @@ func TestEncDec(t *testing.T) {
 		ok    bool
 	}{
 		// Min exp
-		{2, -95, Dec32(0x42100002), true},
+		{2, -101, Dec32(0x00000002), true},
 		// Min exp - 1
-		{2, -96, failDec32, false},
+		{2, -102, failDec32, false},
 		// Max exp
-		{2, 96, Dec32(0x22000002), true},
+		{2, 90, Dec32(0x5f800002), true},
 		// Max exp + 1
-		{2, 97, failDec32, false},
+		{2, 91, failDec32, false},
+		// Negative exp
+		{-125, -2, Dec32(0xb180007d), true},
 		// Max coeff
-		{9999999, 0, Dec32(0x6408967f), true},
+		{9999999, 0, Dec32(0x6cb8967f), true},
+		// Max coeff at min exp
+		{-9999999, -101, Dec32(0xe018967f), true},
 		// Max coeff + 1
 		{10000000, 0, failDec32, false},
 		// Max coeff fitting in 23 bits
-		{8388607, 0, Dec32(0x1c0fffff), true},
+		{8388607, 0, Dec32(0x32ffffff), true},
 		// Max coeff fitting in 23 bits + 1
-		{8388608, 0, Dec32(0x60000000), true},
+		{8388608, 0, Dec32(0x6ca00000), true},
 	}
 	for i, testCase := range testCases {
 		d, ok := EncodeDec32(testCase.coeff, testCase.exp)
 		if ok != testCase.ok {
-			t.Errorf("testCase #%d: expect ok=%s, got %s", i, testCase.ok, ok)
+			t.Errorf("testCase #%d: expect ok=%v, got %v", i, testCase.ok, ok)
 		}
 		if ok {
 			if d != testCase.ref {
@@ -73,8 +78,127 @@ func TestEncDec(t *testing.T) {
 				t.Errorf("testCase #%d: expect exp=%d, got %d", i, testCase.exp, exp)
 			}
 			if ok != testCase.ok {
-				t.Errorf("testCase #%d: expect ok=%s, got %s", i, testCase.ok, ok)
+				t.Errorf("testCase #%d: expect ok=%v, got %v", i, testCase.ok, ok)
 			}
 		}
 	}
 }
+
+func TestAdjustedExponent(t *testing.T) {
+	testCases := []struct {
+		d        Dec32
+		adjusted int
+		ilogb    int
+	}{
+		{mustEncode(t, 1, 0), 0, 0},
+		{mustEncode(t, 9999999, 0), 6, 6},
+		{mustEncode(t, -1234, -2), 1, 1},
+		{mustEncode(t, 5, -101), -101, -101},
+		{mustEncode(t, 1000000, -101), -95, -95},
+		{mustEncode(t, 9999999, 90), 96, 96},
+		{mustEncode(t, 0, 3), 3, math.MinInt32},
+		{mustEncode(t, 0, -7), -7, math.MinInt32},
+		// Non-canonical coefficient is treated as zero.
+		{Dec32(0x6cbfffff), 0, math.MinInt32},
+		// Infinity
+		{Dec32(0x78000000), math.MaxInt32, math.MaxInt32},
+		// NaN
+		{Dec32(0x7c000000), math.MaxInt32, math.MaxInt32},
+	}
+	for i, testCase := range testCases {
+		if adjusted := testCase.d.AdjustedExponent(); adjusted != testCase.adjusted {
+			t.Errorf("testCase #%d: expect adjusted exponent %d, got %d", i, testCase.adjusted, adjusted)
+		}
+		if ilogb := testCase.d.ILogB(); ilogb != testCase.ilogb {
+			t.Errorf("testCase #%d: expect ilogb %d, got %d", i, testCase.ilogb, ilogb)
+		}
+	}
+}
+
+func mustEncode(t *testing.T, coeff int32, exp int8) Dec32 {
+	d, ok := EncodeDec32(coeff, exp)
+	if !ok {
+		t.Fatalf("failed to encode %de%d", coeff, exp)
+	}
+	return d
+}
+
+func TestSpecialDecode(t *testing.T) {
+	for _, d := range []Dec32{Dec32(0x78000000), Dec32(0xf8000000), Dec32(0x7c000000)} {
+		if _, _, ok := d.Decode(); ok {
+			t.Errorf("%x: expected decode to fail", uint32(d))
+		}
+		if d.Zero() {
+			t.Errorf("%x: should not be zero", uint32(d))
+		}
+		if d.Valid() {
+			t.Errorf("%x: should not be valid", uint32(d))
+		}
+	}
+}
+
+func TestMustEncodeDec32(t *testing.T) {
+	if d := MustEncodeDec32(-125, -2); d != Dec32(0xb180007d) {
+		t.Errorf("unexpected %x", uint32(d))
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic")
+		}
+	}()
+	MustEncodeDec32(10000000, 0)
+}
+
+func TestCanonicalZero(t *testing.T) {
+	testCases := []struct {
+		d         Dec32
+		zero      bool
+		canonical bool
+		norm      Dec32
+	}{
+		{Dec32(0x32800000), true, true, Dec32(0x32800000)},
+		{Dec32(0xb2800000), true, true, Dec32(0xb2800000)},
+		// Non-canonical coefficient 10485759 with exponent 0.
+		{Dec32(0x6cbfffff), true, false, Dec32(0x32800000)},
+		{Dec32(0xecbfffff), true, false, Dec32(0xb2800000)},
+		{mustEncode(t, 9999999, 0), false, false, mustEncode(t, 9999999, 0)},
+		{mustEncode(t, 1, -101), false, false, mustEncode(t, 1, -101)},
+		{inf32, false, false, inf32},
+		{nan32, false, false, nan32},
+	}
+	for i, testCase := range testCases {
+		if z := testCase.d.Zero(); z != testCase.zero {
+			t.Errorf("testCase #%d: expect zero %v, got %v", i, testCase.zero, z)
+		}
+		if c := testCase.d.IsCanonicalZero(); c != testCase.canonical {
+			t.Errorf("testCase #%d: expect canonical zero %v, got %v", i, testCase.canonical, c)
+		}
+		if n := testCase.d.NormalizeZero(); n != testCase.norm {
+			t.Errorf("testCase #%d: expect %x, got %x", i, uint32(testCase.norm), uint32(n))
+		}
+	}
+}
+
+func TestNegZero(t *testing.T) {
+	testCases := []struct {
+		d    Dec32
+		neg  bool
+		norm Dec32
+	}{
+		{Dec32(0xb2800000), true, Dec32(0x32800000)},
+		{Dec32(0x32800000), false, Dec32(0x32800000)},
+		// Non-canonical negative zero.
+		{Dec32(0xecbfffff), true, Dec32(0x6cbfffff)},
+		{mustEncode(t, -1, 0), false, mustEncode(t, -1, 0)},
+		{inf32 | signMask, false, inf32 | signMask},
+		{nan32 | signMask, false, nan32 | signMask},
+	}
+	for i, testCase := range testCases {
+		if n := testCase.d.IsNegZero(); n != testCase.neg {
+			t.Errorf("testCase #%d: expect negative zero %v, got %v", i, testCase.neg, n)
+		}
+		if n := testCase.d.NormalizeNegZero(); n != testCase.norm {
+			t.Errorf("testCase #%d: expect %x, got %x", i, uint32(testCase.norm), uint32(n))
+		}
+	}
+}
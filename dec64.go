@@ -0,0 +1,182 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+// Dec64 stores a decimal64 value: a 64-bit signed decimal floating-point number
+// as defined in IEEE-754-2008. Dec64 can hold a significand in the range
+// of 0-9999999999999999, multiplied by 10^exp, where -398 <= exp <= 369.
+// Equivalently, the exponent of the most significant digit ranges from -383
+// to 384. Like Dec32, this implementation stores the significand as a binary
+// integer decimal.
+type Dec64 uint64
+
+const (
+	// Representation of coefficients that fit in 53 bits:
+	//	s eeeeeeeeee ccccc...(53 bits)
+	// and of larger coefficients, which have an implied 100 prefix:
+	//	s 11 eeeeeeeeee ccccc...(51 bits)
+	// The high five bits of the combination field are 11110 for infinity
+	// and 11111 for NaN, followed by a 1 bit for a signaling NaN, as in
+	// decimal32.
+	signMask64       = 0x8000000000000000
+	combMask64       = 0x7c00000000000000
+	snanMask64       = 0x7e00000000000000
+	largeMask64      = 0x6000000000000000
+	smallExpMask64   = 0x7fe0000000000000
+	smallCoeffMask64 = 0x001fffffffffffff
+	largeExpMask64   = 0x1ff8000000000000
+	largeCoeffMask64 = 0x0007ffffffffffff
+
+	coeffMaxBits64 = 0x0020000000000000
+
+	combOffset64     = 58
+	smallExpOffset64 = 53
+	largeExpOffset64 = 51
+
+	maxCoeff64 = 9999999999999999
+	minExp64   = -398
+	maxExp64   = 369
+	expBias64  = 398
+)
+
+var (
+	failDec64 = Dec64(0xffffffffffffffff)
+	inf64     = Dec64(0x7800000000000000)
+	nan64     = Dec64(0x7c00000000000000)
+)
+
+// Sign returns -1 if the decimal is negative, 1 if the decimal is positive.
+// Zero values can be either positive or negative.
+func (d Dec64) Sign() int {
+	if (d & signMask64) == signMask64 {
+		return -1
+	}
+	return 1
+}
+
+// Zero returns whether the decimal64 represents a zero value. Coefficient
+// values greater than the maximum 9,999,999,999,999,999 also represent zero
+// according to the IEEE-754-2008 spec.
+func (d Dec64) Zero() bool {
+	coeff, _, ok := d.Decode()
+	return ok && (coeff == 0 || coeff > maxCoeff64 || coeff < -maxCoeff64)
+}
+
+// IsNegZero returns whether the decimal64 value is a zero with its sign bit
+// set, as for Dec32.IsNegZero.
+func (d Dec64) IsNegZero() bool {
+	return d.Zero() && d.Sign() < 0
+}
+
+// NormalizeNegZero returns a negative zero as the positive zero with the same
+// exponent, as for Dec32.NormalizeNegZero. Other values are returned
+// unchanged.
+func (d Dec64) NormalizeNegZero() Dec64 {
+	if d.IsNegZero() {
+		return d &^ signMask64
+	}
+	return d
+}
+
+// Valid returns whether the decimal value is well-formed according to the
+// IEEE-754-2008 specification. Exponent and coefficient values beyond the
+// spec limits are invalid.
+func (d Dec64) Valid() bool {
+	coeff, exp, ok := d.Decode()
+	return ok && exp >= minExp64 && exp <= maxExp64 && coeff <= maxCoeff64 && coeff >= -maxCoeff64
+}
+
+// IsInf returns whether the decimal64 value is infinite.
+func (d Dec64) IsInf() bool {
+	return d.combBits() == 0x1e
+}
+
+// IsNaN returns whether the decimal64 value is not-a-number (NaN).
+func (d Dec64) IsNaN() bool {
+	return d.combBits() == 0x1f
+}
+
+func (d Dec64) combBits() uint64 {
+	return (uint64(d) & combMask64) >> combOffset64
+}
+
+// EncodeDec64 encodes the given coefficient and exponent into a decimal value.
+func EncodeDec64(coeff int64, exp int16) (Dec64, bool) {
+	neg := coeff < 0
+	if neg {
+		coeff = 0 - coeff
+	}
+	if coeff > maxCoeff64 || coeff < 0 {
+		return failDec64, false
+	}
+	if exp < minExp64 || exp > maxExp64 {
+		return failDec64, false
+	}
+	return pack64(neg, uint64(coeff), int(exp)), true
+}
+
+// MustEncodeDec64 is like EncodeDec64 but panics if the coefficient or
+// exponent is out of range. It simplifies initialization of decimal
+// variables.
+func MustEncodeDec64(coeff int64, exp int16) Dec64 {
+	d, ok := EncodeDec64(coeff, exp)
+	if !ok {
+		panic("decimal: coefficient or exponent out of range in MustEncodeDec64")
+	}
+	return d
+}
+
+// pack64 encodes a sign, coefficient and exponent known to be within the
+// decimal64 limits. Unlike EncodeDec64, it can encode negative zero.
+func pack64(neg bool, coeff uint64, exp int) Dec64 {
+	var result uint64
+	if neg {
+		result = signMask64
+	}
+	bexp := uint64(exp + expBias64)
+	if (coeffMaxBits64 & coeff) == coeffMaxBits64 {
+		// coefficient starts with 100
+		result |= largeMask64 | (bexp << largeExpOffset64) | (coeff & largeCoeffMask64)
+	} else {
+		result |= (bexp << smallExpOffset64) | coeff
+	}
+	return Dec64(result)
+}
+
+// unpack decodes a finite decimal64 value into its sign, coefficient
+// magnitude and exponent. Non-canonical coefficients decode as zero.
+func (d Dec64) unpack() (neg bool, coeff uint64, exp int) {
+	c, e, _ := d.Decode()
+	neg = (d & signMask64) == signMask64
+	if c < 0 {
+		c = -c
+	}
+	if c > maxCoeff64 {
+		c = 0
+	}
+	return neg, uint64(c), int(e)
+}
+
+// Decode decodes a decimal64 value into its coefficient and exponent
+// components, and whether the value can be decoded. Infinite, NaN and illegal
+// values cannot be decoded to a coefficient and exponent.
+func (d Dec64) Decode() (coeff int64, expn int16, ok bool) {
+	if d.IsInf() || d.IsNaN() {
+		return 0, 0, false
+	}
+	var bexp uint64
+	if (uint64(d) & largeMask64) == largeMask64 {
+		coeff = int64(coeffMaxBits64 | (uint64(d) & largeCoeffMask64))
+		bexp = (uint64(d) & largeExpMask64) >> largeExpOffset64
+	} else {
+		coeff = int64(uint64(d) & smallCoeffMask64)
+		bexp = (uint64(d) & smallExpMask64) >> smallExpOffset64
+	}
+	expn = int16(int64(bexp) - expBias64)
+	if (d & signMask64) == signMask64 {
+		coeff = 0 - coeff
+	}
+	return coeff, expn, true
+}
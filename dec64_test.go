@@ -0,0 +1,156 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import "testing"
+
+func TestEncDec64(t *testing.T) {
+	testCases := []struct {
+		coeff int64
+		exp   int16
+		ref   Dec64
+		ok    bool
+	}{
+		{1, 0, Dec64(0x31c0000000000001), true},
+		// Min exp
+		{2, -398, Dec64(0x0000000000000002), true},
+		// Min exp - 1
+		{2, -399, failDec64, false},
+		// Max exp
+		{2, 369, Dec64(0x5fe0000000000002), true},
+		// Max exp + 1
+		{2, 370, failDec64, false},
+		// Negative exp
+		{-125, -2, Dec64(0xb18000000000007d), true},
+		// Max coeff
+		{9999999999999999, 0, Dec64(0x6c7386f26fc0ffff), true},
+		// Max coeff at min exp
+		{-9999999999999999, -398, Dec64(0xe00386f26fc0ffff), true},
+		// Max coeff + 1
+		{10000000000000000, 0, failDec64, false},
+		// Max coeff fitting in 53 bits
+		{9007199254740991, 0, Dec64(0x31dfffffffffffff), true},
+		// Max coeff fitting in 53 bits + 1
+		{9007199254740992, 0, Dec64(0x6c70000000000000), true},
+		{-9223372036854775808, 0, failDec64, false},
+	}
+	for i, testCase := range testCases {
+		d, ok := EncodeDec64(testCase.coeff, testCase.exp)
+		if ok != testCase.ok {
+			t.Errorf("testCase #%d: expect ok=%v, got %v", i, testCase.ok, ok)
+		}
+		if !ok {
+			continue
+		}
+		if d != testCase.ref {
+			t.Errorf("testCase #%d: expect dec64=%x, got %x", i, uint64(testCase.ref), uint64(d))
+		}
+		coeff, exp, ok := d.Decode()
+		if coeff != testCase.coeff || exp != testCase.exp || !ok {
+			t.Errorf("testCase #%d: expect %d %d, got %d %d %v", i, testCase.coeff, testCase.exp, coeff, exp, ok)
+		}
+		if !d.Valid() {
+			t.Errorf("testCase #%d: not valid", i)
+		}
+		if sign := d.Sign(); (sign < 0) != (testCase.coeff < 0) {
+			t.Errorf("testCase #%d: unexpected sign %d", i, sign)
+		}
+	}
+}
+
+func TestDec64Predicates(t *testing.T) {
+	testCases := []struct {
+		d                 Dec64
+		zero, inf, nan    bool
+		valid, decodeable bool
+	}{
+		{MustEncodeDec64(0, 0), true, false, false, true, true},
+		{MustEncodeDec64(0, -5) | signMask64, true, false, false, true, true},
+		{MustEncodeDec64(7, 0), false, false, false, true, true},
+		// A coefficient above the maximum is a non-canonical zero.
+		{Dec64(0x6c7fffffffffffff), true, false, false, false, true},
+		{inf64, false, true, false, false, false},
+		{inf64 | signMask64, false, true, false, false, false},
+		{nan64, false, false, true, false, false},
+		{Dec64(snanMask64), false, false, true, false, false},
+	}
+	for i, testCase := range testCases {
+		d := testCase.d
+		if d.Zero() != testCase.zero || d.IsInf() != testCase.inf || d.IsNaN() != testCase.nan {
+			t.Errorf("testCase #%d: %x: expect zero=%v inf=%v nan=%v, got %v %v %v", i, uint64(d),
+				testCase.zero, testCase.inf, testCase.nan, d.Zero(), d.IsInf(), d.IsNaN())
+		}
+		if d.Valid() != testCase.valid {
+			t.Errorf("testCase #%d: %x: expect valid=%v", i, uint64(d), testCase.valid)
+		}
+		if _, _, ok := d.Decode(); ok != testCase.decodeable {
+			t.Errorf("testCase #%d: %x: expect decode ok=%v", i, uint64(d), testCase.decodeable)
+		}
+	}
+}
+
+func TestMustEncodeDec64(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic")
+		}
+	}()
+	MustEncodeDec64(1, 370)
+}
+
+func TestDec64String(t *testing.T) {
+	testCases := []struct {
+		d   Dec64
+		ref string
+	}{
+		{MustEncodeDec64(1234567890123456, -9), "1234567.890123456"},
+		{MustEncodeDec64(-125, -2), "-1.25"},
+		{MustEncodeDec64(100, -2), "1.00"},
+		{MustEncodeDec64(1, -7), "1E-7"},
+		{MustEncodeDec64(1, 369), "1E+369"},
+		{MustEncodeDec64(9999999999999999, 369), "9.999999999999999E+384"},
+		{MustEncodeDec64(0, -398), "0E-398"},
+		{pack64(true, 0, 0), "-0"},
+		{inf64 | signMask64, "-Infinity"},
+		{nan64, "NaN"},
+		{Dec64(snanMask64), "sNaN"},
+	}
+	for i, testCase := range testCases {
+		s := testCase.d.String()
+		if s != testCase.ref {
+			t.Errorf("testCase #%d: expect %q, got %q", i, testCase.ref, s)
+		}
+		if d, err := ParseDec64(s); err != nil || d != testCase.d {
+			t.Errorf("testCase #%d: %q parses as %x %v", i, s, uint64(d), err)
+		}
+	}
+}
+
+func TestDec64PlainString(t *testing.T) {
+	testCases := []struct {
+		d   Dec64
+		ref string
+	}{
+		{MustEncodeDec64(1234567890123456, -9), "1234567.890123456"},
+		{MustEncodeDec64(12, 18), "12000000000000000000"},
+		{MustEncodeDec64(-1, -20), "-0.00000000000000000001"},
+		{pack64(true, 0, -2), "-0.00"},
+		{inf64, "Infinity"},
+	}
+	for i, testCase := range testCases {
+		if s := testCase.d.PlainString(); s != testCase.ref {
+			t.Errorf("testCase #%d: expect %q, got %q", i, testCase.ref, s)
+		}
+	}
+}
+
+func TestDec64NormalizeNegZero(t *testing.T) {
+	if d := pack64(true, 0, -2).NormalizeNegZero(); d != MustEncodeDec64(0, -2) {
+		t.Errorf("unexpected %v", d)
+	}
+	if d := MustEncodeDec64(-1, 0); d.NormalizeNegZero() != d || d.IsNegZero() {
+		t.Errorf("unexpected %v", d)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package decbench provides reproducible decimal workloads and benchmarks
+// that compare decimal32 arithmetic with float64, big.Float and
+// github.com/shopspring/decimal, so that the cost of adopting decimals can be
+// measured on the hardware that will run them:
+//
+//	go test -bench . github.com/cmars/ieee754-dec/decbench
+//
+// The workload generators are exported for use in benchmarks of
+// applications' own code paths.
+package decbench
+
+import (
+	"math/rand/v2"
+	"strconv"
+	"strings"
+)
+
+// A Workload is a named set of decimal strings, all of which decimal32
+// represents exactly.
+type Workload struct {
+	Name   string
+	Inputs []string
+}
+
+// Amounts returns n monetary amounts with the given number of fraction
+// digits and at most seven significant digits, such as "1234.56" for scale
+// 2, drawn from a generator seeded with seed so that runs are comparable.
+// About one in ten amounts is negative, as refunds and corrections are.
+func Amounts(n, scale int, seed uint64) Workload {
+	r := rand.New(rand.NewPCG(seed, 0))
+	inputs := make([]string, n)
+	for i := range inputs {
+		// Skew towards small amounts, as prices are.
+		digits := 1 + r.IntN(7)
+		coeff := r.Int64N(pow10(digits))
+		inputs[i] = formatScaled(coeff, scale, r.IntN(10) == 0)
+	}
+	return Workload{Name: "amounts/scale" + strconv.Itoa(scale), Inputs: inputs}
+}
+
+// Rates returns n rates between 0 and 1 with up to seven significant digits,
+// such as tax, interest and exchange rates, drawn from a generator seeded
+// with seed.
+func Rates(n int, seed uint64) Workload {
+	r := rand.New(rand.NewPCG(seed, 1))
+	inputs := make([]string, n)
+	for i := range inputs {
+		scale := 1 + r.IntN(7)
+		inputs[i] = formatScaled(r.Int64N(pow10(scale)), scale, false)
+	}
+	return Workload{Name: "rates", Inputs: inputs}
+}
+
+// Scientific returns n values with seven significant digits and exponents
+// spread over the decimal32 range, in scientific notation, drawn from a
+// generator seeded with seed.
+func Scientific(n int, seed uint64) Workload {
+	r := rand.New(rand.NewPCG(seed, 2))
+	inputs := make([]string, n)
+	for i := range inputs {
+		coeff := 1000000 + r.Int64N(9000000)
+		exp := -95 + r.IntN(95+90)
+		inputs[i] = strconv.FormatInt(coeff, 10) + "E" + strconv.Itoa(exp)
+	}
+	return Workload{Name: "scientific", Inputs: inputs}
+}
+
+func pow10(n int) int64 {
+	p := int64(1)
+	for ; n > 0; n-- {
+		p *= 10
+	}
+	return p
+}
+
+// formatScaled returns coeff * 10^-scale in plain notation.
+func formatScaled(coeff int64, scale int, neg bool) string {
+	s := strconv.FormatInt(coeff, 10)
+	if len(s) <= scale {
+		s = strings.Repeat("0", scale-len(s)+1) + s
+	}
+	if scale > 0 {
+		s = s[:len(s)-scale] + "." + s[len(s)-scale:]
+	}
+	if neg {
+		s = "-" + s
+	}
+	return s
+}
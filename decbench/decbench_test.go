@@ -0,0 +1,185 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decbench
+
+import (
+	"math/big"
+	"strconv"
+	"testing"
+
+	shopspring "github.com/shopspring/decimal"
+
+	decimal "github.com/cmars/ieee754-dec"
+)
+
+const benchN = 1000
+
+func workloads() []Workload {
+	return []Workload{Amounts(benchN, 2, 1), Rates(benchN, 1), Scientific(benchN, 1)}
+}
+
+func TestWorkloads(t *testing.T) {
+	for _, w := range workloads() {
+		if len(w.Inputs) != benchN {
+			t.Errorf("%s: expect %d inputs, got %d", w.Name, benchN, len(w.Inputs))
+		}
+		for _, s := range w.Inputs {
+			d, err := decimal.ParseDec32(s)
+			if err != nil {
+				t.Fatalf("%s: %v", w.Name, err)
+			}
+			// Every input is exact, so parsing and formatting round-trip
+			// the value.
+			if d2 := decimal.MustParseDec32(d.String()); d2 != d {
+				t.Errorf("%s: %q does not round-trip: %v", w.Name, s, d)
+			}
+		}
+	}
+	a, b := Amounts(10, 2, 7), Amounts(10, 2, 7)
+	for i := range a.Inputs {
+		if a.Inputs[i] != b.Inputs[i] {
+			t.Fatalf("expect reproducible workloads, got %q and %q", a.Inputs[i], b.Inputs[i])
+		}
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	for _, w := range workloads() {
+		b.Run(w.Name+"/Dec32", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, s := range w.Inputs {
+					decimal.ParseDec32(s)
+				}
+			}
+		})
+		b.Run(w.Name+"/float64", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, s := range w.Inputs {
+					strconv.ParseFloat(s, 64)
+				}
+			}
+		})
+		b.Run(w.Name+"/big.Float", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, s := range w.Inputs {
+					new(big.Float).SetPrec(24).SetString(s)
+				}
+			}
+		})
+		b.Run(w.Name+"/shopspring", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, s := range w.Inputs {
+					shopspring.NewFromString(s)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkFormat(b *testing.B) {
+	for _, w := range workloads() {
+		ds := make([]decimal.Dec32, len(w.Inputs))
+		fs := make([]float64, len(w.Inputs))
+		bs := make([]*big.Float, len(w.Inputs))
+		ss := make([]shopspring.Decimal, len(w.Inputs))
+		for i, s := range w.Inputs {
+			ds[i] = decimal.MustParseDec32(s)
+			fs[i], _ = strconv.ParseFloat(s, 64)
+			bs[i], _ = new(big.Float).SetPrec(24).SetString(s)
+			ss[i], _ = shopspring.NewFromString(s)
+		}
+		b.Run(w.Name+"/Dec32", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, d := range ds {
+					_ = d.String()
+				}
+			}
+		})
+		b.Run(w.Name+"/float64", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, f := range fs {
+					strconv.FormatFloat(f, 'g', -1, 64)
+				}
+			}
+		})
+		b.Run(w.Name+"/big.Float", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, f := range bs {
+					f.Text('g', 7)
+				}
+			}
+		})
+		b.Run(w.Name+"/shopspring", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, s := range ss {
+					_ = s.String()
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkSumProduct computes the sum of the products of amounts and rates,
+// as in applying tax rates to line items.
+func BenchmarkSumProduct(b *testing.B) {
+	amounts, rates := Amounts(benchN, 2, 1).Inputs, Rates(benchN, 1).Inputs
+	b.Run("DecWork", func(b *testing.B) {
+		xs := make([]*decimal.DecWork, benchN)
+		ys := make([]*decimal.DecWork, benchN)
+		for i := range xs {
+			xs[i] = decimal.NewDecWork(decimal.MustParseDec32(amounts[i]))
+			ys[i] = decimal.NewDecWork(decimal.MustParseDec32(rates[i]))
+		}
+		var sum, p decimal.DecWork
+		for i := 0; i < b.N; i++ {
+			sum.SetDec32(decimal.Dec32(0))
+			for j := range xs {
+				sum.Add(&sum, p.Mul(xs[j], ys[j]))
+			}
+		}
+	})
+	b.Run("float64", func(b *testing.B) {
+		xs, ys := make([]float64, benchN), make([]float64, benchN)
+		for i := range xs {
+			xs[i], _ = strconv.ParseFloat(amounts[i], 64)
+			ys[i], _ = strconv.ParseFloat(rates[i], 64)
+		}
+		for i := 0; i < b.N; i++ {
+			sum := 0.0
+			for j := range xs {
+				sum += xs[j] * ys[j]
+			}
+			_ = sum
+		}
+	})
+	b.Run("big.Float", func(b *testing.B) {
+		xs, ys := make([]*big.Float, benchN), make([]*big.Float, benchN)
+		for i := range xs {
+			xs[i], _ = new(big.Float).SetPrec(24).SetString(amounts[i])
+			ys[i], _ = new(big.Float).SetPrec(24).SetString(rates[i])
+		}
+		sum, p := new(big.Float).SetPrec(24), new(big.Float).SetPrec(24)
+		for i := 0; i < b.N; i++ {
+			sum.SetInt64(0)
+			for j := range xs {
+				sum.Add(sum, p.Mul(xs[j], ys[j]))
+			}
+		}
+	})
+	b.Run("shopspring", func(b *testing.B) {
+		xs, ys := make([]shopspring.Decimal, benchN), make([]shopspring.Decimal, benchN)
+		for i := range xs {
+			xs[i], _ = shopspring.NewFromString(amounts[i])
+			ys[i], _ = shopspring.NewFromString(rates[i])
+		}
+		for i := 0; i < b.N; i++ {
+			sum := shopspring.Zero
+			for j := range xs {
+				sum = sum.Add(xs[j].Mul(ys[j]))
+			}
+			_ = sum
+		}
+	})
+}
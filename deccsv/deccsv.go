@@ -0,0 +1,291 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package deccsv reads and writes decimal columns of CSV files, parsing
+// fields directly into decimal values and writing their canonical string
+// form, without passing through binary floating point. Columns are read and
+// written as decimal32 values, or as decimal64 values with the methods
+// suffixed 64.
+//
+//	r := deccsv.NewReader(csv.NewReader(f),
+//		deccsv.Column{Field: 2, Scale: 2},
+//		deccsv.Column{Field: 3, Scale: deccsv.AnyScale})
+//	cols, err := r.ReadAll()
+package deccsv
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+
+	decimal "github.com/cmars/ieee754-dec"
+)
+
+// AnyScale is a Column.Scale that accepts values with any number of fraction
+// digits.
+const AnyScale = -1
+
+// A Column selects a field of each CSV record holding decimal values.
+type Column struct {
+	// Field is the zero-based index of the field in each record.
+	Field int
+	// Scale is the largest number of fraction digits a value may have,
+	// not counting trailing zeros, so 2 accepts "1.50", "1.500" and "3"
+	// but rejects "0.125". AnyScale accepts all values.
+	Scale int
+}
+
+// ErrScale is returned, wrapped in a *ParseError, for values with more
+// fraction digits than their column allows.
+var ErrScale = errors.New("too many fraction digits")
+
+// ErrInexact is returned, wrapped in a *ParseError, for values with more
+// significant digits than the decimal format holds, which would otherwise be
+// rounded silently, such as "12345678.9" for a decimal32.
+var ErrInexact = errors.New("value cannot be represented exactly")
+
+// A ParseError is returned for fields that are not valid decimals.
+type ParseError struct {
+	Line  int    // line where the record starts
+	Field int    // zero-based field index
+	Value string // field text
+	Err   error  // the underlying error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("deccsv: record on line %d: field %d: parsing %q: %v", e.Line, e.Field, e.Value, e.Err)
+}
+
+func (e *ParseError) Unwrap() error { return e.Err }
+
+// Reader reads decimal columns from CSV records.
+type Reader struct {
+	r       *csv.Reader
+	columns []Column
+}
+
+// NewReader returns a Reader that reads the given columns from records read
+// with r.
+func NewReader(r *csv.Reader, columns ...Column) *Reader {
+	return &Reader{r: r, columns: columns}
+}
+
+// Read reads one record, parses its decimal columns into dst, which must
+// have one element per column, and returns the whole record. At end of input
+// it returns io.EOF.
+func (r *Reader) Read(dst []decimal.Dec32) ([]string, error) {
+	return r.read(len(dst), func(i int, s string, scale int) error {
+		d, err := parseField(s, scale)
+		if err == nil {
+			dst[i] = d
+		}
+		return err
+	})
+}
+
+// Read64 is like Read, but parses the columns into decimal64 values.
+func (r *Reader) Read64(dst []decimal.Dec64) ([]string, error) {
+	return r.read(len(dst), func(i int, s string, scale int) error {
+		d, err := parseField64(s, scale)
+		if err == nil {
+			dst[i] = d
+		}
+		return err
+	})
+}
+
+// read reads one record and parses the field of each column i with parse,
+// given n destination values.
+func (r *Reader) read(n int, parse func(i int, s string, scale int) error) ([]string, error) {
+	if n != len(r.columns) {
+		return nil, fmt.Errorf("deccsv: %d destination values for %d columns", n, len(r.columns))
+	}
+	record, err := r.r.Read()
+	if err != nil {
+		return nil, err
+	}
+	line, _ := r.r.FieldPos(0)
+	for i, c := range r.columns {
+		if c.Field >= len(record) {
+			return record, &ParseError{Line: line, Field: c.Field, Err: csv.ErrFieldCount}
+		}
+		if err := parse(i, record[c.Field], c.Scale); err != nil {
+			return record, &ParseError{Line: line, Field: c.Field, Value: record[c.Field], Err: err}
+		}
+	}
+	return record, nil
+}
+
+// ReadAll reads the remaining records and returns the values of each column,
+// in the order the columns were given to NewReader.
+func (r *Reader) ReadAll() ([][]decimal.Dec32, error) {
+	cols := make([][]decimal.Dec32, len(r.columns))
+	row := make([]decimal.Dec32, len(r.columns))
+	for {
+		_, err := r.Read(row)
+		if err == io.EOF {
+			return cols, nil
+		}
+		if err != nil {
+			return cols, err
+		}
+		for i, d := range row {
+			cols[i] = append(cols[i], d)
+		}
+	}
+}
+
+// ReadAll64 is like ReadAll, but returns decimal64 columns.
+func (r *Reader) ReadAll64() ([][]decimal.Dec64, error) {
+	cols := make([][]decimal.Dec64, len(r.columns))
+	row := make([]decimal.Dec64, len(r.columns))
+	for {
+		_, err := r.Read64(row)
+		if err == io.EOF {
+			return cols, nil
+		}
+		if err != nil {
+			return cols, err
+		}
+		for i, d := range row {
+			cols[i] = append(cols[i], d)
+		}
+	}
+}
+
+// parseField parses a decimal field exactly and checks its scale.
+func parseField(s string, scale int) (decimal.Dec32, error) {
+	d, exact, err := decimal.ParseDec32Exact(s)
+	if err != nil {
+		return d, errors.Unwrap(err)
+	}
+	if !exact {
+		return d, ErrInexact
+	}
+	if coeff, exp, ok := d.Decode(); ok {
+		return d, checkScale(int64(coeff), int(exp), scale)
+	}
+	return d, nil
+}
+
+// parseField64 is like parseField, for decimal64.
+func parseField64(s string, scale int) (decimal.Dec64, error) {
+	d, exact, err := decimal.ParseDec64Exact(s)
+	if err != nil {
+		return d, errors.Unwrap(err)
+	}
+	if !exact {
+		return d, ErrInexact
+	}
+	if coeff, exp, ok := d.Decode(); ok {
+		return d, checkScale(coeff, int(exp), scale)
+	}
+	return d, nil
+}
+
+// checkScale returns ErrScale if coeff×10^exp has more fraction digits than
+// scale allows.
+func checkScale(coeff int64, exp, scale int) error {
+	if scale < 0 {
+		return nil
+	}
+	// Trailing zeros beyond the scale do not change the value.
+	for coeff%10 == 0 && coeff != 0 && exp < -scale {
+		coeff /= 10
+		exp++
+	}
+	if exp < -scale {
+		return ErrScale
+	}
+	return nil
+}
+
+// Writer writes records of decimal values in their canonical string form.
+type Writer struct {
+	// NoNegZero writes negative zeros as positive zeros, such as "0.00"
+	// for -0.00, for consumers that reject "-0".
+	NoNegZero bool
+
+	w      *csv.Writer
+	record []string
+}
+
+// NewWriter returns a Writer that writes records with w.
+func NewWriter(w *csv.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Write writes a single record of decimal values. As with csv.Writer, the
+// output is buffered until Flush is called.
+func (w *Writer) Write(record []decimal.Dec32) error {
+	w.record = w.record[:0]
+	for _, d := range record {
+		if w.NoNegZero {
+			d = d.NormalizeNegZero()
+		}
+		w.record = append(w.record, d.String())
+	}
+	return w.w.Write(w.record)
+}
+
+// Write64 is like Write, for a record of decimal64 values.
+func (w *Writer) Write64(record []decimal.Dec64) error {
+	w.record = w.record[:0]
+	for _, d := range record {
+		if w.NoNegZero {
+			d = d.NormalizeNegZero()
+		}
+		w.record = append(w.record, d.String())
+	}
+	return w.w.Write(w.record)
+}
+
+// WriteColumns writes one record for each row of the given columns, which
+// must all have the same length, and flushes the output.
+func (w *Writer) WriteColumns(cols [][]decimal.Dec32) error {
+	row := make([]decimal.Dec32, len(cols))
+	for i := 0; len(cols) > 0 && i < len(cols[0]); i++ {
+		for j, col := range cols {
+			if len(col) != len(cols[0]) {
+				return fmt.Errorf("deccsv: column %d has %d values, want %d", j, len(col), len(cols[0]))
+			}
+			row[j] = col[i]
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.w.Error()
+}
+
+// WriteColumns64 is like WriteColumns, for decimal64 columns.
+func (w *Writer) WriteColumns64(cols [][]decimal.Dec64) error {
+	row := make([]decimal.Dec64, len(cols))
+	for i := 0; len(cols) > 0 && i < len(cols[0]); i++ {
+		for j, col := range cols {
+			if len(col) != len(cols[0]) {
+				return fmt.Errorf("deccsv: column %d has %d values, want %d", j, len(col), len(cols[0]))
+			}
+			row[j] = col[i]
+		}
+		if err := w.Write64(row); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.w.Error()
+}
+
+// Flush writes any buffered data to the underlying io.Writer.
+func (w *Writer) Flush() {
+	w.w.Flush()
+}
+
+// Error reports any error that has occurred during a previous Write or
+// Flush.
+func (w *Writer) Error() error {
+	return w.w.Error()
+}
@@ -0,0 +1,178 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package deccsv
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	decimal "github.com/cmars/ieee754-dec"
+)
+
+const input = `sku,qty,price,rate
+a,1,1.50,0.0725
+b,2,10,1E-3
+c,3,0.125,-0
+`
+
+func TestReadAll(t *testing.T) {
+	cr := csv.NewReader(strings.NewReader(input))
+	if _, err := cr.Read(); err != nil {
+		t.Fatal(err)
+	}
+	r := NewReader(cr, Column{Field: 2, Scale: 3}, Column{Field: 3, Scale: AnyScale})
+	cols, err := r.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref := [][]string{{"1.50", "10", "0.125"}, {"0.0725", "0.001", "-0"}}
+	for i := range ref {
+		if len(cols[i]) != len(ref[i]) {
+			t.Fatalf("column %d: expect %d values, got %d", i, len(ref[i]), len(cols[i]))
+		}
+		for j := range ref[i] {
+			if s := cols[i][j].String(); s != ref[i][j] {
+				t.Errorf("column %d row %d: expect %s, got %s", i, j, ref[i][j], s)
+			}
+		}
+	}
+}
+
+func TestReadAll64(t *testing.T) {
+	const input = "a,1234567.890123456,0.125\nb,-1E+300,12345678901234567\n"
+	r := NewReader(csv.NewReader(strings.NewReader(input)), Column{Field: 1, Scale: AnyScale}, Column{Field: 2, Scale: 3})
+	cols, err := r.ReadAll64()
+	var perr *ParseError
+	if !errors.As(err, &perr) || !errors.Is(err, ErrInexact) || perr.Line != 2 || perr.Field != 2 {
+		t.Fatalf("expect ErrInexact on line 2, got %v", err)
+	}
+	ref := [][]string{{"1234567.890123456"}, {"0.125"}}
+	for i := range ref {
+		if len(cols[i]) != len(ref[i]) || cols[i][0].String() != ref[i][0] {
+			t.Errorf("column %d: expect %v, got %v", i, ref[i], cols[i])
+		}
+	}
+	if _, err := r.Read64(make([]decimal.Dec64, 1)); err == nil {
+		t.Errorf("expected error for short destination")
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	testCases := []struct {
+		input string
+		err   error
+	}{
+		{"x,0.125\n", ErrScale},
+		{"x,1.2.3\n", strconv.ErrSyntax},
+		{"x,1E+1000\n", strconv.ErrRange},
+		{"x,1234567.891\n", ErrInexact},
+		{"x,1E-200\n", ErrInexact},
+		{"x\n", csv.ErrFieldCount},
+	}
+	for i, testCase := range testCases {
+		cr := csv.NewReader(strings.NewReader("x,1.00\n" + testCase.input))
+		cr.FieldsPerRecord = -1
+		r := NewReader(cr, Column{Field: 1, Scale: 2})
+		_, err := r.ReadAll()
+		var perr *ParseError
+		if !errors.As(err, &perr) || !errors.Is(err, testCase.err) {
+			t.Errorf("testCase #%d: expect %v, got %v", i, testCase.err, err)
+			continue
+		}
+		if perr.Line != 2 || perr.Field != 1 {
+			t.Errorf("testCase #%d: unexpected position %d:%d", i, perr.Line, perr.Field)
+		}
+	}
+
+	r := NewReader(csv.NewReader(strings.NewReader("1\n")), Column{Field: 0, Scale: AnyScale})
+	if _, err := r.Read(nil); err == nil {
+		t.Errorf("expected error for short destination")
+	}
+}
+
+func TestParseFieldExact(t *testing.T) {
+	testCases := []struct {
+		s     string
+		scale int
+		ref   string
+		err   error
+	}{
+		{"1234567", AnyScale, "1234567", nil},
+		{"12345670", AnyScale, "1.234567E+7", nil},
+		{"12345678.9", AnyScale, "", ErrInexact},
+		{"1234567.891", 2, "", ErrInexact},
+		{"12345.67", 2, "12345.67", nil},
+	}
+	for i, testCase := range testCases {
+		d, err := parseField(testCase.s, testCase.scale)
+		if err != testCase.err || err == nil && d.String() != testCase.ref {
+			t.Errorf("testCase #%d: %q: expect %s %v, got %v %v", i, testCase.s, testCase.ref, testCase.err, d, err)
+		}
+	}
+	if d, err := parseField64("12345678.9", AnyScale); err != nil || d.String() != "12345678.9" {
+		t.Errorf("unexpected %v %v", d, err)
+	}
+	if d, err := parseField64("1234567890123.456", 2); err != ErrScale {
+		t.Errorf("unexpected %v %v", d, err)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(csv.NewWriter(&buf))
+	cols := [][]decimal.Dec32{
+		{decimal.MustParseDec32("1.50"), decimal.MustParseDec32("-2E+3")},
+		{decimal.MustParseDec32("0.0725"), decimal.MustParseDec32("NaN")},
+	}
+	if err := w.WriteColumns(cols); err != nil {
+		t.Fatal(err)
+	}
+	if s := buf.String(); s != "1.50,0.0725\n-2E+3,NaN\n" {
+		t.Errorf("unexpected output %q", s)
+	}
+	if err := w.WriteColumns([][]decimal.Dec32{{1, 2}, {3}}); err == nil {
+		t.Errorf("expected error for ragged columns")
+	}
+}
+
+func TestWriter64(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(csv.NewWriter(&buf))
+	w.NoNegZero = true
+	negZero, err := decimal.ParseDec64("-0.00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cols := [][]decimal.Dec64{
+		{decimal.MustEncodeDec64(1234567890123456, -9), decimal.MustEncodeDec64(-2, 300)},
+		{negZero, decimal.MustEncodeDec64(-1, 0)},
+	}
+	if err := w.WriteColumns64(cols); err != nil {
+		t.Fatal(err)
+	}
+	if s := buf.String(); s != "1234567.890123456,0.00\n-2E+300,-1\n" {
+		t.Errorf("unexpected output %q", s)
+	}
+	if err := w.WriteColumns64([][]decimal.Dec64{{1, 2}, {3}}); err == nil {
+		t.Errorf("expected error for ragged columns")
+	}
+}
+
+func TestWriterNoNegZero(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(csv.NewWriter(&buf))
+	record := []decimal.Dec32{decimal.MustParseDec32("-0.00"), decimal.MustParseDec32("-1")}
+	w.Write(record)
+	w.NoNegZero = true
+	w.Write(record)
+	w.Flush()
+	if s := buf.String(); s != "-0.00,-1\n0.00,-1\n" {
+		t.Errorf("unexpected output %q", s)
+	}
+}
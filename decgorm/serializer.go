@@ -0,0 +1,136 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package decgorm provides a GORM serializer that persists decimal values to
+// DECIMAL and NUMERIC columns exactly. Importing the package registers the
+// serializer under the name "decimal":
+//
+//	import _ "github.com/cmars/ieee754-dec/decgorm"
+//
+//	type Item struct {
+//		ID    uint
+//		Price decimal.Dec32 `gorm:"type:decimal(12,2);serializer:decimal"`
+//		Total decimal.Dec64 `gorm:"type:decimal(18,2);serializer:decimal"`
+//	}
+//
+// Values are written in plain notation, which every common database accepts
+// as a DECIMAL literal, and read back from the text, integer or float forms
+// that drivers return.
+package decgorm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"gorm.io/gorm/schema"
+
+	decimal "github.com/cmars/ieee754-dec"
+)
+
+func init() {
+	schema.RegisterSerializer("decimal", Serializer{})
+}
+
+// Serializer is a GORM serializer for fields of type decimal.Dec32,
+// decimal.Dec64 or pointers to them.
+type Serializer struct{}
+
+var (
+	dec32Type = reflect.TypeOf(decimal.Dec32(0))
+	dec64Type = reflect.TypeOf(decimal.Dec64(0))
+)
+
+// Scan sets the field in dst from the database value. NULL sets a pointer
+// field to nil and a value field to zero.
+func (Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	fieldValue := reflect.New(field.FieldType)
+	if dbValue != nil {
+		t := field.FieldType
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		var d any
+		var err error
+		switch t {
+		case dec32Type:
+			d, err = scanValue(dbValue)
+		case dec64Type:
+			d, err = scanValue64(dbValue)
+		default:
+			return fmt.Errorf("decgorm: field %s: unsupported type %v", field.Name, field.FieldType)
+		}
+		if err != nil {
+			return fmt.Errorf("decgorm: field %s: %w", field.Name, err)
+		}
+		v := reflect.ValueOf(d)
+		if field.FieldType.Kind() == reflect.Ptr {
+			p := reflect.New(t)
+			p.Elem().Set(v)
+			v = p
+		}
+		fieldValue.Elem().Set(v)
+	}
+	field.ReflectValueOf(ctx, dst).Set(fieldValue.Elem())
+	return nil
+}
+
+// scanValue converts a value returned by a database driver to a decimal.
+func scanValue(v interface{}) (decimal.Dec32, error) {
+	s, err := valueString(v)
+	if err != nil {
+		return 0, err
+	}
+	return decimal.ParseDec32(s)
+}
+
+// scanValue64 is like scanValue, for decimal64.
+func scanValue64(v interface{}) (decimal.Dec64, error) {
+	s, err := valueString(v)
+	if err != nil {
+		return 0, err
+	}
+	return decimal.ParseDec64(s)
+}
+
+// valueString returns the decimal string form of a value returned by a
+// database driver.
+func valueString(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case []byte:
+		return string(v), nil
+	case string:
+		return v, nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		// Drivers that report DECIMAL columns as floats have already
+		// rounded them; their shortest representation is the best guess
+		// at the stored value.
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	}
+	return "", fmt.Errorf("cannot convert %T to decimal", v)
+}
+
+// Value returns the field value in plain notation, or nil for a nil pointer.
+func (Serializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	switch v := fieldValue.(type) {
+	case decimal.Dec32:
+		return v.PlainString(), nil
+	case *decimal.Dec32:
+		if v == nil {
+			return nil, nil
+		}
+		return v.PlainString(), nil
+	case decimal.Dec64:
+		return v.PlainString(), nil
+	case *decimal.Dec64:
+		if v == nil {
+			return nil, nil
+		}
+		return v.PlainString(), nil
+	}
+	return nil, fmt.Errorf("decgorm: field %s: unsupported type %T", field.Name, fieldValue)
+}
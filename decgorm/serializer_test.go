@@ -0,0 +1,136 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decgorm
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"gorm.io/gorm/schema"
+
+	decimal "github.com/cmars/ieee754-dec"
+)
+
+type item struct {
+	Price    decimal.Dec32
+	Discount *decimal.Dec32
+	Total    decimal.Dec64
+	Refund   *decimal.Dec64
+	Name     string
+}
+
+func field(name string) *schema.Field {
+	sf, _ := reflect.TypeOf(item{}).FieldByName(name)
+	return &schema.Field{
+		Name:      name,
+		FieldType: sf.Type,
+		ReflectValueOf: func(ctx context.Context, v reflect.Value) reflect.Value {
+			return reflect.Indirect(v).FieldByIndex(sf.Index)
+		},
+	}
+}
+
+func TestScan(t *testing.T) {
+	ctx := context.Background()
+	testCases := []struct {
+		dbValue interface{}
+		ref     string
+	}{
+		{[]byte("123.45"), "123.45"},
+		{"0.10", "0.10"},
+		{int64(-42), "-42"},
+		{float64(0.1), "0.1"},
+	}
+	for i, testCase := range testCases {
+		var it item
+		if err := (Serializer{}).Scan(ctx, field("Price"), reflect.ValueOf(&it), testCase.dbValue); err != nil {
+			t.Fatalf("testCase #%d: %v", i, err)
+		}
+		if s := it.Price.String(); s != testCase.ref {
+			t.Errorf("testCase #%d: expect %s, got %s", i, testCase.ref, s)
+		}
+		if err := (Serializer{}).Scan(ctx, field("Discount"), reflect.ValueOf(&it), testCase.dbValue); err != nil {
+			t.Fatalf("testCase #%d: %v", i, err)
+		}
+		if it.Discount == nil || it.Discount.String() != testCase.ref {
+			t.Errorf("testCase #%d: expect %s, got %v", i, testCase.ref, it.Discount)
+		}
+	}
+
+	it := item{Discount: new(decimal.Dec32)}
+	if err := (Serializer{}).Scan(ctx, field("Discount"), reflect.ValueOf(&it), nil); err != nil || it.Discount != nil {
+		t.Errorf("expected nil discount, got %v %v", it.Discount, err)
+	}
+	if err := (Serializer{}).Scan(ctx, field("Price"), reflect.ValueOf(&it), "bogus"); err == nil {
+		t.Errorf("expected parse error")
+	}
+	if err := (Serializer{}).Scan(ctx, field("Price"), reflect.ValueOf(&it), true); err == nil {
+		t.Errorf("expected type error")
+	}
+}
+
+func TestScan64(t *testing.T) {
+	ctx := context.Background()
+	testCases := []struct {
+		dbValue interface{}
+		ref     string
+	}{
+		{[]byte("1234567.890123456"), "1234567.890123456"},
+		{"0.10", "0.10"},
+		{int64(-9007199254740993), "-9007199254740993"},
+		{float64(0.1), "0.1"},
+	}
+	for i, testCase := range testCases {
+		var it item
+		if err := (Serializer{}).Scan(ctx, field("Total"), reflect.ValueOf(&it), testCase.dbValue); err != nil {
+			t.Fatalf("testCase #%d: %v", i, err)
+		}
+		if s := it.Total.String(); s != testCase.ref {
+			t.Errorf("testCase #%d: expect %s, got %s", i, testCase.ref, s)
+		}
+		if err := (Serializer{}).Scan(ctx, field("Refund"), reflect.ValueOf(&it), testCase.dbValue); err != nil {
+			t.Fatalf("testCase #%d: %v", i, err)
+		}
+		if it.Refund == nil || it.Refund.String() != testCase.ref {
+			t.Errorf("testCase #%d: expect %s, got %v", i, testCase.ref, it.Refund)
+		}
+	}
+
+	it := item{Refund: new(decimal.Dec64)}
+	if err := (Serializer{}).Scan(ctx, field("Refund"), reflect.ValueOf(&it), nil); err != nil || it.Refund != nil {
+		t.Errorf("expected nil refund, got %v %v", it.Refund, err)
+	}
+	if err := (Serializer{}).Scan(ctx, field("Name"), reflect.ValueOf(&it), "1"); err == nil {
+		t.Errorf("expected unsupported type error")
+	}
+}
+
+func TestValue(t *testing.T) {
+	ctx := context.Background()
+	d := decimal.MustParseDec32("1.2E+5")
+	total := decimal.MustEncodeDec64(1234567890123456, -9)
+	testCases := []struct {
+		fieldValue interface{}
+		ref        interface{}
+	}{
+		{d, "120000"},
+		{&d, "120000"},
+		{decimal.MustParseDec32("1.50"), "1.50"},
+		{(*decimal.Dec32)(nil), nil},
+		{decimal.MustEncodeDec64(12, 18), "12000000000000000000"},
+		{&total, "1234567.890123456"},
+		{(*decimal.Dec64)(nil), nil},
+	}
+	for i, testCase := range testCases {
+		v, err := (Serializer{}).Value(ctx, field("Price"), reflect.Value{}, testCase.fieldValue)
+		if err != nil || v != testCase.ref {
+			t.Errorf("testCase #%d: expect %v, got %v %v", i, testCase.ref, v, err)
+		}
+	}
+	if _, err := (Serializer{}).Value(ctx, field("Price"), reflect.Value{}, 1.5); err == nil {
+		t.Errorf("expected type error")
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package decimaltest generates random decimal32 values for property tests
+// of code that uses github.com/cmars/ieee754-dec. Values are drawn from a
+// caller's *rand.Rand, so that a seed reproduces a failure, and the Value and
+// Finite types implement quick.Generator for use with testing/quick:
+//
+//	f := func(v decimaltest.Finite) bool {
+//		d := v.Dec32()
+//		return d.Add(d.Neg()).Zero()
+//	}
+//	if err := quick.Check(f, nil); err != nil {
+//		t.Error(err)
+//	}
+package decimaltest
+
+import (
+	"math/rand"
+	"reflect"
+	"testing/quick"
+
+	decimal "github.com/cmars/ieee754-dec"
+)
+
+var (
+	_ quick.Generator = Value(0)
+	_ quick.Generator = Finite(0)
+)
+
+// The decimal32 limits, as the decimal package defines them.
+const (
+	minExp   = -101
+	maxExp   = 90
+	maxCoeff = 9999999
+	// subnormalExp is the largest exponent at which a coefficient of one
+	// digit is subnormal.
+	subnormalExp = minExp + 5
+)
+
+// Random returns a random decimal32 encoding. Most are finite, canonical
+// values of every exponent and coefficient length, with subnormals and zeros
+// of either sign well represented, and the rest are infinities, quiet and
+// signaling NaNs with payloads, and non-canonical encodings of each kind,
+// which operations must treat as the values they denote.
+func Random(r *rand.Rand) decimal.Dec32 {
+	switch n := r.Intn(20); {
+	case n < 14:
+		return RandomFinite(r)
+	case n < 15:
+		return decimal.Inf(randomSign(r))
+	case n < 17:
+		return randomNaN(r)
+	}
+	return randomNonCanonical(r)
+}
+
+// RandomFinite returns a random finite, canonical decimal32 value, as Random
+// does but without infinities, NaNs and non-canonical encodings.
+func RandomFinite(r *rand.Rand) decimal.Dec32 {
+	n := r.Intn(10)
+	var coeff int32
+	var exp int
+	switch {
+	case n < 7:
+		// Normal, or subnormal at the least exponents.
+		coeff, exp = randomCoeff(r), minExp+r.Intn(maxExp-minExp+1)
+	case n < 9:
+		// Subnormal: fewer digits than the exponent needs to be normal.
+		exp = minExp + r.Intn(subnormalExp-minExp+1)
+		digits := 1 + r.Intn(subnormalExp-exp+1)
+		coeff = 1 + r.Int31n(pow10(digits)-1)
+	default:
+		exp = minExp + r.Intn(maxExp-minExp+1)
+	}
+	if randomSign(r) < 0 {
+		coeff = -coeff
+	}
+	d, ok := decimal.EncodeDec32(coeff, int8(exp))
+	if !ok {
+		panic("decimaltest: coefficient or exponent out of range")
+	}
+	if coeff == 0 && randomSign(r) < 0 {
+		d = d.Neg()
+	}
+	return d
+}
+
+// randomCoeff returns a random positive coefficient, with a uniformly random
+// number of digits so that short coefficients are as common as long ones.
+func randomCoeff(r *rand.Rand) int32 {
+	digits := 1 + r.Intn(7)
+	lo := pow10(digits - 1)
+	return lo + r.Int31n(pow10(digits)-lo)
+}
+
+// randomNaN returns a canonical quiet or signaling NaN, with a random sign
+// and, usually, a random payload.
+func randomNaN(r *rand.Rand) decimal.Dec32 {
+	var payload uint32
+	if r.Intn(2) == 0 {
+		payload = uint32(randomCoeff(r) % 1000000)
+	}
+	f := decimal.QuietNaN
+	if r.Intn(2) == 0 {
+		f = decimal.SignalingNaN
+	}
+	d, _ := f(payload)
+	if randomSign(r) < 0 {
+		d = d.Neg()
+	}
+	return d
+}
+
+// randomNonCanonical returns a random non-canonical encoding: a coefficient
+// above 9,999,999 in the large-coefficient form, an infinity with trailing
+// bits, or a NaN with a payload above 999,999 or bits set between its
+// signaling bit and its payload.
+func randomNonCanonical(r *rand.Rand) decimal.Dec32 {
+	sign := uint32(r.Intn(2)) << 31
+	switch r.Intn(3) {
+	case 0:
+		// The large form holds 0x800000 plus 21 bits, up to 16,777,215.
+		const lo = maxCoeff + 1 - 0x800000
+		bexp := uint32(r.Intn(maxExp - minExp + 1))
+		return decimal.Dec32(sign | 0x60000000 | bexp<<21 | uint32(lo+r.Intn(0x200000-lo)))
+	case 1:
+		return decimal.Dec32(sign | 0x78000000 | uint32(1+r.Intn(0x3ffffff)))
+	}
+	d := sign | 0x7c000000 | uint32(r.Intn(2))<<25
+	if r.Intn(2) == 0 {
+		return decimal.Dec32(d | uint32(1+r.Intn(0x1f))<<20 | uint32(r.Intn(1000000)))
+	}
+	return decimal.Dec32(d | uint32(1000000+r.Intn(0x100000-1000000)))
+}
+
+// randomSign returns -1 or +1 with equal probability.
+func randomSign(r *rand.Rand) int {
+	return 1 - 2*r.Intn(2)
+}
+
+// pow10 returns 10^n for n from 0 to 7.
+func pow10(n int) int32 {
+	p := int32(1)
+	for ; n > 0; n-- {
+		p *= 10
+	}
+	return p
+}
+
+// Value is a decimal32 encoding that testing/quick generates with Random.
+type Value decimal.Dec32
+
+// Generate implements the quick.Generator interface, returning a Value drawn
+// by Random. The size hint is ignored, as every decimal32 is the same size.
+func (Value) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(Value(Random(r)))
+}
+
+// Dec32 returns v as a decimal.Dec32.
+func (v Value) Dec32() decimal.Dec32 {
+	return decimal.Dec32(v)
+}
+
+// Finite is a finite, canonical decimal32 value that testing/quick generates
+// with RandomFinite.
+type Finite decimal.Dec32
+
+// Generate implements the quick.Generator interface, returning a Finite
+// drawn by RandomFinite. The size hint is ignored.
+func (Finite) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(Finite(RandomFinite(r)))
+}
+
+// Dec32 returns f as a decimal.Dec32.
+func (f Finite) Dec32() decimal.Dec32 {
+	return decimal.Dec32(f)
+}
@@ -0,0 +1,100 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimaltest
+
+import (
+	"math/rand"
+	"testing"
+	"testing/quick"
+
+	decimal "github.com/cmars/ieee754-dec"
+)
+
+func TestRandomDeterministic(t *testing.T) {
+	r1, r2 := rand.New(rand.NewSource(1)), rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		if d1, d2 := Random(r1), Random(r2); d1 != d2 {
+			t.Fatalf("draw %d: %x differs from %x", i, uint32(d1), uint32(d2))
+		}
+	}
+}
+
+func TestRandomCoverage(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	classes := make(map[decimal.Class]int)
+	nonCanonical := make(map[string]int)
+	for i := 0; i < 20000; i++ {
+		d := Random(r)
+		classes[d.Class()]++
+		if !d.IsCanonical() {
+			switch {
+			case d.IsNaN():
+				nonCanonical["NaN"]++
+			case d.IsInf():
+				nonCanonical["Inf"]++
+			default:
+				nonCanonical["finite"]++
+			}
+		}
+	}
+	for c := decimal.ClassSignalingNaN; c <= decimal.ClassPositiveInfinity; c++ {
+		if classes[c] == 0 {
+			t.Errorf("no %v values drawn", c)
+		}
+	}
+	for _, kind := range []string{"NaN", "Inf", "finite"} {
+		if nonCanonical[kind] == 0 {
+			t.Errorf("no non-canonical %s encodings drawn", kind)
+		}
+	}
+}
+
+func TestRandomFinite(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	digits := make(map[int]bool)
+	for i := 0; i < 10000; i++ {
+		d := RandomFinite(r)
+		if d.IsInf() || d.IsNaN() || !d.IsCanonical() {
+			t.Fatalf("draw %d: %x is not finite and canonical", i, uint32(d))
+		}
+		coeff, _, _ := d.Decode()
+		n := 0
+		for ; coeff != 0; coeff /= 10 {
+			n++
+		}
+		digits[n] = true
+	}
+	for n := 0; n <= 7; n++ {
+		if !digits[n] {
+			t.Errorf("no coefficients of %d digits drawn", n)
+		}
+	}
+}
+
+func TestQuick(t *testing.T) {
+	config := &quick.Config{Rand: rand.New(rand.NewSource(4)), MaxCount: 2000}
+	binary := func(v Value) bool {
+		b, err := v.Dec32().MarshalBinary()
+		var d decimal.Dec32
+		return err == nil && d.UnmarshalBinary(b) == nil && d == v.Dec32()
+	}
+	if err := quick.Check(binary, config); err != nil {
+		t.Error(err)
+	}
+	text := func(f Finite) bool {
+		d, err := decimal.ParseDec32(f.Dec32().String())
+		return err == nil && d == f.Dec32()
+	}
+	if err := quick.Check(text, config); err != nil {
+		t.Error(err)
+	}
+	negate := func(f Finite) bool {
+		d := f.Dec32()
+		return d.Add(d.Neg()).Zero()
+	}
+	if err := quick.Check(negate, config); err != nil {
+		t.Error(err)
+	}
+}
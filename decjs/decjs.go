@@ -0,0 +1,173 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build js
+
+// Package decjs converts decimal values to and from JavaScript values for
+// WebAssembly front ends, without passing them through JavaScript numbers,
+// which are binary floats. A decimal goes to JavaScript either as its exact
+// string, for display, or as a scaled pair {value, scale} of a BigInt and a
+// number denoting value * 10^-scale, for arithmetic with BigInt:
+//
+//	price := decimal.MustParseDec32("12.50")
+//	decjs.String(price) // "12.50"
+//	decjs.Scaled(price) // {value: 1250n, scale: 2}
+//
+// FromJS accepts either form back, as well as BigInts and, rounded from
+// their shortest decimal representation, numbers.
+package decjs
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"syscall/js"
+
+	decimal "github.com/cmars/ieee754-dec"
+)
+
+var (
+	errNotDecimal = errors.New("decjs: value is not a decimal string, number, BigInt or scaled pair")
+	errScale      = errors.New("decjs: scale is not an integer")
+)
+
+// String returns the string form of d, as returned by its String method, as
+// a JavaScript string.
+func String(d decimal.Dec32) js.Value {
+	return js.ValueOf(d.String())
+}
+
+// Scaled returns the finite d as a JavaScript object {value, scale}, where
+// value is a BigInt holding the coefficient of d and scale is the negated
+// exponent, so that 12.50 is {value: 1250n, scale: 2}. Infinities and NaNs
+// have no scaled form, and are returned as their strings.
+func Scaled(d decimal.Dec32) js.Value {
+	coeff, exp, ok := d.Decode()
+	if !ok {
+		return String(d)
+	}
+	// The sign of a negative zero is lost, as BigInt has no -0n.
+	return scaledPair(strconv.FormatInt(int64(coeff), 10), -int(exp))
+}
+
+// ScaledTo returns the finite d as a scaled pair with the given scale, so
+// that 12.5 with scale 4 is {value: 125000n, scale: 4}, for APIs that expect
+// a fixed number of fraction digits. It returns false if d is not finite or
+// has more than scale fraction digits, not counting trailing zeros.
+func ScaledTo(d decimal.Dec32, scale int) (js.Value, bool) {
+	coeff, exp, ok := d.Decode()
+	if !ok {
+		return js.Undefined(), false
+	}
+	digits := strconv.FormatInt(int64(coeff), 10)
+	if shift := int(exp) + scale; shift >= 0 {
+		if coeff != 0 {
+			digits += strings.Repeat("0", shift)
+		}
+	} else {
+		trimmed := strings.TrimRight(digits, "0")
+		if coeff != 0 && len(digits)-len(trimmed) < -shift {
+			return js.Undefined(), false
+		}
+		if coeff != 0 {
+			digits = digits[:len(digits)+shift]
+		}
+	}
+	return scaledPair(digits, scale), true
+}
+
+// scaledPair returns the JavaScript object {value: BigInt(digits), scale}.
+func scaledPair(digits string, scale int) js.Value {
+	pair := js.Global().Get("Object").New()
+	pair.Set("value", js.Global().Get("BigInt").Invoke(digits))
+	pair.Set("scale", scale)
+	return pair
+}
+
+// FromJS converts the JavaScript value v to a decimal. It accepts:
+//
+//   - a string, parsed with decimal.ParseDec32;
+//   - a number, taken as the shortest decimal string that JavaScript would
+//     display for it, so that 0.1 is 0.1 rather than the binary value
+//     nearest it, and rounded to the nearest decimal32;
+//   - a BigInt, or another object whose toString method returns an
+//     integer;
+//   - a scaled pair {value, scale} as returned by Scaled, whose value is a
+//     BigInt, integer string or number and whose scale is an integer.
+//
+// Values with more than seven significant digits are rounded to the nearest
+// decimal32, ties to even. The errors for strings and overflow are those of
+// decimal.ParseDec32.
+func FromJS(v js.Value) (decimal.Dec32, error) {
+	if isBigInt(v) {
+		return decimal.ParseDec32(jsString(v))
+	}
+	switch v.Type() {
+	case js.TypeString:
+		return decimal.ParseDec32(v.String())
+	case js.TypeNumber:
+		return decimal.ParseDec32(strconv.FormatFloat(v.Float(), 'g', -1, 64))
+	case js.TypeObject:
+		if scale := v.Get("scale"); scale.Type() != js.TypeUndefined {
+			return fromScaled(v.Get("value"), scale)
+		}
+		s := jsString(v)
+		if !isInteger(s) {
+			return decimal.Dec32(0), errNotDecimal
+		}
+		return decimal.ParseDec32(s)
+	}
+	return decimal.Dec32(0), errNotDecimal
+}
+
+// fromScaled converts the scaled pair of value and scale to a decimal.
+func fromScaled(value, scale js.Value) (decimal.Dec32, error) {
+	if scale.Type() != js.TypeNumber {
+		return decimal.Dec32(0), errScale
+	}
+	f := scale.Float()
+	n := int(f)
+	if float64(n) != f {
+		return decimal.Dec32(0), errScale
+	}
+	var s string
+	switch {
+	case isBigInt(value), value.Type() == js.TypeString, value.Type() == js.TypeObject:
+		s = jsString(value)
+	case value.Type() == js.TypeNumber:
+		s = strconv.FormatFloat(value.Float(), 'f', -1, 64)
+	}
+	if !isInteger(s) {
+		return decimal.Dec32(0), errNotDecimal
+	}
+	return decimal.ParseDec32(s + "E" + strconv.Itoa(-n))
+}
+
+// isBigInt reports whether v is a BigInt, which syscall/js cannot give the
+// type of.
+func isBigInt(v js.Value) bool {
+	toString := js.Global().Get("Object").Get("prototype").Get("toString")
+	return toString.Call("call", v).String() == "[object BigInt]"
+}
+
+// jsString returns the JavaScript String conversion of v, which unlike its
+// methods is defined for BigInts too.
+func jsString(v js.Value) string {
+	return js.Global().Get("String").Invoke(v).String()
+}
+
+// isInteger reports whether s is a decimal integer with an optional minus
+// sign.
+func isInteger(s string) bool {
+	s = strings.TrimPrefix(s, "-")
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,118 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build js
+
+package decjs
+
+import (
+	"syscall/js"
+	"testing"
+
+	decimal "github.com/cmars/ieee754-dec"
+)
+
+func TestScaled(t *testing.T) {
+	testCases := []struct {
+		s     string
+		value string
+		scale int
+	}{
+		{"12.50", "1250", 2},
+		{"-0.001", "-1", 3},
+		{"1.5E+7", "15", -6},
+		{"0", "0", 0},
+	}
+	for i, testCase := range testCases {
+		d := decimal.MustParseDec32(testCase.s)
+		v := Scaled(d)
+		if value := jsString(v.Get("value")); value != testCase.value {
+			t.Errorf("testCase #%d: expect value %s, got %s", i, testCase.value, value)
+		}
+		if scale := v.Get("scale").Int(); scale != testCase.scale {
+			t.Errorf("testCase #%d: expect scale %d, got %d", i, testCase.scale, scale)
+		}
+		if r, err := FromJS(v); err != nil || r != d {
+			t.Errorf("testCase #%d: expect round trip to %v, got %v %v", i, d, r, err)
+		}
+		if s := String(d).String(); s != testCase.s {
+			t.Errorf("testCase #%d: expect string %q, got %q", i, testCase.s, s)
+		}
+	}
+	if v := Scaled(decimal.MustParseDec32("NaN")); v.String() != "NaN" {
+		t.Errorf("expect NaN string, got %v", v)
+	}
+}
+
+func TestScaledTo(t *testing.T) {
+	testCases := []struct {
+		s     string
+		scale int
+		value string
+		ok    bool
+	}{
+		{"12.5", 4, "125000", true},
+		{"12.500", 1, "125", true},
+		{"-3E+2", 0, "-300", true},
+		{"0.00", 0, "0", true},
+		{"12.55", 1, "", false},
+		{"Infinity", 2, "", false},
+	}
+	for i, testCase := range testCases {
+		v, ok := ScaledTo(decimal.MustParseDec32(testCase.s), testCase.scale)
+		if ok != testCase.ok {
+			t.Errorf("testCase #%d: expect %v, got %v", i, testCase.ok, ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if value := jsString(v.Get("value")); value != testCase.value {
+			t.Errorf("testCase #%d: expect value %s, got %s", i, testCase.value, value)
+		}
+		if scale := v.Get("scale").Int(); scale != testCase.scale {
+			t.Errorf("testCase #%d: expect scale %d, got %d", i, testCase.scale, scale)
+		}
+	}
+}
+
+func TestFromJS(t *testing.T) {
+	bigInt := js.Global().Get("BigInt")
+	pair := func(value interface{}, scale interface{}) js.Value {
+		v := js.Global().Get("Object").New()
+		v.Set("value", value)
+		v.Set("scale", scale)
+		return v
+	}
+	testCases := []struct {
+		v   js.Value
+		ref string
+		ok  bool
+	}{
+		{js.ValueOf("1.20"), "1.20", true},
+		{js.ValueOf(0.1), "0.1", true},
+		{js.ValueOf(1e21), "1E+21", true},
+		{js.ValueOf(12345678.9), "1.234568E+7", true},
+		{bigInt.Invoke("-42"), "-42", true},
+		{pair(bigInt.Invoke("1250"), 2), "12.50", true},
+		{pair("1250", -1), "1.250E+4", true},
+		{pair(7, 0), "7", true},
+		{pair("1.5", 2), "", false},
+		{pair("15", 0.5), "", false},
+		{js.ValueOf("x"), "", false},
+		{js.ValueOf(true), "", false},
+		{js.Null(), "", false},
+		{js.Global().Get("Object").New(), "", false},
+	}
+	for i, testCase := range testCases {
+		d, err := FromJS(testCase.v)
+		if (err == nil) != testCase.ok {
+			t.Errorf("testCase #%d: unexpected error %v", i, err)
+			continue
+		}
+		if testCase.ok && d.String() != testCase.ref {
+			t.Errorf("testCase #%d: expect %s, got %v", i, testCase.ref, d)
+		}
+	}
+}
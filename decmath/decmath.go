@@ -0,0 +1,276 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package decmath provides transcendental functions of decimal values,
+// computed without binary floating-point intermediates.
+//
+// Every function is correctly rounded: it returns the decimal32 value
+// nearest to the exact mathematical result, as if that result were computed
+// with unbounded precision and rounded once. The functions evaluate their
+// series in decimal fixed point with math/big, bound the error of each
+// evaluation, and repeat with more digits until the bounds determine the
+// rounded result. The results of these functions at nonzero arguments are
+// irrational, so they never fall exactly halfway between two decimal32
+// values and the repetition always ends.
+package decmath
+
+import (
+	"math/big"
+	"strconv"
+
+	decimal "github.com/cmars/ieee754-dec"
+)
+
+var nan = decimal.MustParseDec32("NaN")
+
+// Pi returns π rounded to the nearest decimal32, 3.141593.
+func Pi() decimal.Dec32 {
+	return ziv(20, pi)
+}
+
+// Sin returns the sine of the radian argument x. Sin(±0) is ±0. Sin of an
+// infinity or NaN is NaN.
+func Sin(x decimal.Dec32) decimal.Dec32 {
+	switch {
+	case x.IsInf() || x.IsNaN():
+		return nan
+	case x.Zero():
+		return x
+	}
+	return ziv(startDigits(x), func(w int) *big.Int {
+		s, _ := sinCos(x, w)
+		return s
+	})
+}
+
+// Cos returns the cosine of the radian argument x. Cos of an infinity or NaN
+// is NaN.
+func Cos(x decimal.Dec32) decimal.Dec32 {
+	switch {
+	case x.IsInf() || x.IsNaN():
+		return nan
+	case x.Zero():
+		return decimal.MustEncodeDec32(1, 0)
+	}
+	return ziv(20, func(w int) *big.Int {
+		_, c := sinCos(x, w)
+		return c
+	})
+}
+
+// Atan returns the arctangent, in radians, of x. Atan(±0) is ±0, and
+// Atan(±Inf) is ±π/2 rounded. Atan of a NaN is NaN.
+func Atan(x decimal.Dec32) decimal.Dec32 {
+	switch {
+	case x.IsNaN():
+		return nan
+	case x.IsInf():
+		return ziv(20, func(w int) *big.Int {
+			h := halfPi(w)
+			if x.Sign() < 0 {
+				h.Neg(h)
+			}
+			return h
+		})
+	case x.Zero():
+		return x
+	}
+	return ziv(startDigits(x), func(w int) *big.Int {
+		return atan(fixed(x, w), w)
+	})
+}
+
+// Asin returns the arcsine, in radians, of x. Asin(±0) is ±0. Asin of a NaN
+// or of a value outside [-1, 1] is NaN.
+func Asin(x decimal.Dec32) decimal.Dec32 {
+	switch {
+	case x.IsNaN() || x.IsInf() || x.CmpInt64(1) > 0 || x.CmpInt64(-1) < 0:
+		return nan
+	case x.Zero():
+		return x
+	}
+	_, exp, _ := x.Decode()
+	// Start with enough digits to hold x exactly, so that 1-x² is exact.
+	return ziv(max(startDigits(x), 10-int(exp)), func(w int) *big.Int {
+		// asin(x) = 2 atan(x / (1 + sqrt(1-x²)))
+		z := fixed(x, w)
+		var t big.Int
+		t.Sub(pow10(2*w), t.Mul(z, z))
+		t.Sqrt(&t)
+		t.Add(&t, pow10(w))
+		z = atan(div(z, &t, w), w)
+		return z.Lsh(z, 1)
+	})
+}
+
+// ziv returns the correctly rounded decimal32 value of a function evaluated
+// by f in fixed point with w fraction digits, starting with w digits and
+// doubling them until the error bound of the evaluation determines the
+// rounding. The evaluations are accurate to 100w units in the last place.
+func ziv(w int, f func(w int) *big.Int) decimal.Dec32 {
+	for ; ; w *= 2 {
+		v := f(w)
+		e := big.NewInt(100 * int64(w))
+		lo := round(new(big.Int).Sub(v, e), w)
+		hi := round(new(big.Int).Add(v, e), w)
+		if lo == hi {
+			return lo
+		}
+	}
+}
+
+// round returns v * 10^-w rounded to the nearest decimal32.
+func round(v *big.Int, w int) decimal.Dec32 {
+	d, _ := decimal.ParseDec32(v.String() + "E-" + strconv.Itoa(w))
+	return d
+}
+
+// startDigits returns a first working precision for a function whose result
+// has about the magnitude of x when x is small.
+func startDigits(x decimal.Dec32) int {
+	return 20 + max(-x.AdjustedExponent(), 0)
+}
+
+// fixed returns x in fixed point with w fraction digits, truncated.
+func fixed(x decimal.Dec32, w int) *big.Int {
+	coeff, exp, _ := x.Decode()
+	v := big.NewInt(int64(coeff))
+	if s := int(exp) + w; s < 0 {
+		return v.Quo(v, pow10(-s))
+	}
+	return v.Mul(v, pow10(int(exp)+w))
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// mul returns the fixed-point product a*b with w fraction digits.
+func mul(a, b *big.Int, w int) *big.Int {
+	z := new(big.Int).Mul(a, b)
+	return z.Quo(z, pow10(w))
+}
+
+// div returns the fixed-point quotient a/b with w fraction digits.
+func div(a, b *big.Int, w int) *big.Int {
+	z := new(big.Int).Mul(a, pow10(w))
+	return z.Quo(z, b)
+}
+
+// pi returns π in fixed point with w fraction digits, by Machin's formula
+// π = 16 atan(1/5) - 4 atan(1/239).
+func pi(w int) *big.Int {
+	const guard = 3
+	a := atanInv(5, w+guard)
+	b := atanInv(239, w+guard)
+	a.Lsh(a, 4)
+	b.Lsh(b, 2)
+	a.Sub(a, b)
+	return a.Quo(a, pow10(guard))
+}
+
+func halfPi(w int) *big.Int {
+	p := pi(w + 1)
+	p.Rsh(p, 1)
+	return p.Quo(p, big.NewInt(10))
+}
+
+// atanInv returns atan(1/n) in fixed point with w fraction digits.
+func atanInv(n int64, w int) *big.Int {
+	sum := new(big.Int)
+	nn := big.NewInt(n * n)
+	term := new(big.Int).Quo(pow10(w), big.NewInt(n))
+	var t big.Int
+	for k := int64(0); term.Sign() != 0; k++ {
+		t.Quo(term, big.NewInt(2*k+1))
+		if k%2 == 0 {
+			sum.Add(sum, &t)
+		} else {
+			sum.Sub(sum, &t)
+		}
+		term.Quo(term, nn)
+	}
+	return sum
+}
+
+// atan returns the arctangent of the fixed-point z with w fraction digits.
+func atan(z *big.Int, w int) *big.Int {
+	one := pow10(w)
+	var abs big.Int
+	if abs.Abs(z).Cmp(one) > 0 {
+		// atan(z) = ±π/2 - atan(1/z)
+		r := atan(div(one, z, w), w)
+		h := halfPi(w)
+		if z.Sign() < 0 {
+			h.Neg(h)
+		}
+		return h.Sub(h, r)
+	}
+	// Halve the argument twice with atan(z) = 2 atan(z / (1 + sqrt(1+z²))),
+	// leaving |z| <= tan(π/16) for the series.
+	for i := 0; i < 2; i++ {
+		var t big.Int
+		t.Add(pow10(2*w), t.Mul(z, z))
+		t.Sqrt(&t)
+		z = div(z, t.Add(&t, one), w)
+	}
+	// atan(z) = z - z³/3 + z⁵/5 - ...
+	sum := new(big.Int)
+	z2 := mul(z, z, w)
+	term := new(big.Int).Set(z)
+	var t big.Int
+	for k := int64(0); term.Sign() != 0; k++ {
+		t.Quo(term, big.NewInt(2*k+1))
+		if k%2 == 0 {
+			sum.Add(sum, &t)
+		} else {
+			sum.Sub(sum, &t)
+		}
+		term = mul(term, z2, w)
+	}
+	return sum.Lsh(sum, 2)
+}
+
+// sinCos returns the sine and cosine of x in fixed point with w fraction
+// digits.
+func sinCos(x decimal.Dec32, w int) (sin, cos *big.Int) {
+	// Reduce x by the nearest multiple k of π/2, using enough digits of π
+	// that the error of k π/2 is below one unit in the last place.
+	xw := fixed(x, w)
+	p := w + 3 + max(len(xw.String())-w, 0)
+	h := halfPi(p)
+	xp := new(big.Int).Mul(xw, pow10(p-w))
+	var k big.Int
+	k.Add(k.Lsh(xp, 1), h)
+	k.Div(&k, new(big.Int).Lsh(h, 1))
+	r := xp.Sub(xp, new(big.Int).Mul(&k, h))
+	r.Quo(r, pow10(p-w))
+
+	s, c := new(big.Int), new(big.Int)
+	r2 := mul(r, r, w)
+	// sin r = r - r³/3! + r⁵/5! - ...
+	term := new(big.Int).Set(r)
+	for n := int64(1); term.Sign() != 0; n += 2 {
+		s.Add(s, term)
+		term = mul(term, r2, w)
+		term.Quo(term.Neg(term), big.NewInt((n+1)*(n+2)))
+	}
+	// cos r = 1 - r²/2! + r⁴/4! - ...
+	term = pow10(w)
+	for n := int64(0); term.Sign() != 0; n += 2 {
+		c.Add(c, term)
+		term = mul(term, r2, w)
+		term.Quo(term.Neg(term), big.NewInt((n+1)*(n+2)))
+	}
+
+	switch k.Mod(&k, big.NewInt(4)).Int64() {
+	case 1:
+		return c, s.Neg(s)
+	case 2:
+		return s.Neg(s), c.Neg(c)
+	case 3:
+		return c.Neg(c), s
+	}
+	return s, c
+}
@@ -0,0 +1,92 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decmath
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	decimal "github.com/cmars/ieee754-dec"
+)
+
+func TestPi(t *testing.T) {
+	if p := Pi(); p.String() != "3.141593" {
+		t.Errorf("unexpected %v", p)
+	}
+}
+
+func TestFunctions(t *testing.T) {
+	testCases := []struct {
+		f   func(decimal.Dec32) decimal.Dec32
+		x   string
+		ref string
+	}{
+		{Sin, "1", "0.8414710"},
+		{Sin, "-0.5", "-0.4794255"},
+		{Sin, "355", "-0.00003014435"},
+		{Sin, "1E+22", "-0.8522008"},
+		{Sin, "1E-101", "1E-101"},
+		{Sin, "-0.00", "-0.00"},
+		{Cos, "1", "0.5403023"},
+		{Cos, "2", "-0.4161468"},
+		{Cos, "1E+22", "0.5232148"},
+		{Cos, "0E+5", "1"},
+		{Atan, "1", "0.7853982"},
+		{Atan, "-0.5", "-0.4636476"},
+		{Atan, "9.999999E+96", "1.570796"},
+		{Atan, "-Infinity", "-1.570796"},
+		// Only two digits are available at this magnitude.
+		{Atan, "1E-100", "1.0E-100"},
+		{Asin, "1", "1.570796"},
+		{Asin, "-1", "-1.570796"},
+		{Asin, "0.9999999", "1.570349"},
+		{Asin, "0.5", "0.5235988"},
+		{Asin, "1.000001", "NaN"},
+		{Sin, "Infinity", "NaN"},
+		{Cos, "NaN", "NaN"},
+	}
+	for i, testCase := range testCases {
+		if r := testCase.f(decimal.MustParseDec32(testCase.x)); r.String() != testCase.ref {
+			t.Errorf("testCase #%d: %s: expect %s, got %v", i, testCase.x, testCase.ref, r)
+		}
+	}
+}
+
+// TestAgainstFloat64 compares results with the float64 functions of package
+// math where the float64 result is far enough from a rounding boundary for
+// its error not to matter.
+func TestAgainstFloat64(t *testing.T) {
+	fns := []struct {
+		name string
+		f    func(decimal.Dec32) decimal.Dec32
+		ref  func(float64) float64
+		max  int
+	}{
+		{"Sin", Sin, math.Sin, 2},
+		{"Cos", Cos, math.Cos, 2},
+		{"Atan", Atan, math.Atan, 6},
+		{"Asin", Asin, math.Asin, 0},
+	}
+	r := rand.New(rand.NewSource(1))
+	for _, fn := range fns {
+		for i := 0; i < 300; i++ {
+			coeff := int32(r.Intn(19999999) - 9999999)
+			exp := int8(-7 - r.Intn(6) + r.Intn(fn.max+1))
+			x := decimal.MustEncodeDec32(coeff, exp)
+			xf, _ := strconv.ParseFloat(x.String(), 64)
+			yf := fn.ref(xf)
+			lo, _ := decimal.ParseDec32(strconv.FormatFloat(yf-math.Abs(yf)*1e-12, 'e', -1, 64))
+			hi, _ := decimal.ParseDec32(strconv.FormatFloat(yf+math.Abs(yf)*1e-12, 'e', -1, 64))
+			if lo.String() != hi.String() {
+				continue
+			}
+			if y := fn.f(x); y.String() != lo.String() {
+				t.Errorf("%s(%v): expect %v, got %v", fn.name, x, lo, y)
+			}
+		}
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package decpgx maps decimal values to the PostgreSQL NUMERIC type in pgx v5,
+// in both the text and binary formats, so that they can be used directly as
+// query arguments and scan targets:
+//
+//	conn, err := pgx.Connect(ctx, url)
+//	...
+//	decpgx.Register(conn.TypeMap())
+//	var price decimal.Dec32
+//	err = conn.QueryRow(ctx, "select price from items where id = $1", id).Scan(&price)
+//
+// decimal.Dec64 values and targets are supported too. Scanned values are
+// rounded to the nearest value of the target's format, ties to even; values
+// too large in magnitude are an error.
+package decpgx
+
+import (
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	decimal "github.com/cmars/ieee754-dec"
+)
+
+// Register registers Codec for the numeric type in m, and makes it the
+// default type for decimal.Dec32 and decimal.Dec64 values.
+func Register(m *pgtype.Map) {
+	m.RegisterType(&pgtype.Type{Name: "numeric", OID: pgtype.NumericOID, Codec: Codec{}})
+	m.RegisterDefaultPgType(decimal.Dec32(0), "numeric")
+	m.RegisterDefaultPgType(decimal.Dec64(0), "numeric")
+}
+
+// Codec is a pgtype.Codec for NUMERIC values that encodes decimal.Dec32 and
+// decimal.Dec64 values and scans into *decimal.Dec32 and *decimal.Dec64
+// targets.
+type Codec struct{}
+
+var _ pgtype.Codec = Codec{}
+
+// FormatSupported returns whether the codec supports the given format code.
+func (Codec) FormatSupported(format int16) bool {
+	return format == pgtype.TextFormatCode || format == pgtype.BinaryFormatCode
+}
+
+// PreferredFormat returns the binary format code.
+func (Codec) PreferredFormat() int16 {
+	return pgtype.BinaryFormatCode
+}
+
+// PlanEncode returns a plan for encoding decimal.Dec32 and decimal.Dec64
+// values, or nil for other types.
+func (Codec) PlanEncode(m *pgtype.Map, oid uint32, format int16, value any) pgtype.EncodePlan {
+	switch value.(type) {
+	case decimal.Dec32, decimal.Dec64:
+	default:
+		return nil
+	}
+	switch format {
+	case pgtype.BinaryFormatCode:
+		return encodeBinary{}
+	case pgtype.TextFormatCode:
+		return encodeText{}
+	}
+	return nil
+}
+
+type encodeBinary struct{}
+
+func (encodeBinary) Encode(value any, buf []byte) ([]byte, error) {
+	if d, ok := value.(decimal.Dec64); ok {
+		return d.AppendPGNumeric(buf), nil
+	}
+	return value.(decimal.Dec32).AppendPGNumeric(buf), nil
+}
+
+type encodeText struct{}
+
+func (encodeText) Encode(value any, buf []byte) ([]byte, error) {
+	var s string
+	switch d := value.(type) {
+	case decimal.Dec32:
+		if d.IsNaN() {
+			// PostgreSQL has no signaling NaN.
+			return append(buf, "NaN"...), nil
+		}
+		s = d.String()
+	case decimal.Dec64:
+		if d.IsNaN() {
+			return append(buf, "NaN"...), nil
+		}
+		s = d.String()
+	}
+	return append(buf, s...), nil
+}
+
+// PlanScan returns a plan for scanning into *decimal.Dec32 and
+// *decimal.Dec64 targets, or nil for other types.
+func (Codec) PlanScan(m *pgtype.Map, oid uint32, format int16, target any) pgtype.ScanPlan {
+	switch target.(type) {
+	case *decimal.Dec32, *decimal.Dec64:
+	default:
+		return nil
+	}
+	switch format {
+	case pgtype.BinaryFormatCode:
+		return scanBinary{}
+	case pgtype.TextFormatCode:
+		return scanText{}
+	}
+	return nil
+}
+
+type scanBinary struct{}
+
+func (scanBinary) Scan(src []byte, target any) error {
+	if src == nil {
+		return fmt.Errorf("decpgx: cannot scan NULL into %T", target)
+	}
+	if p, ok := target.(*decimal.Dec64); ok {
+		d, err := decimal.DecodePGNumeric64(src)
+		if err != nil {
+			return err
+		}
+		*p = d
+		return nil
+	}
+	d, err := decimal.DecodePGNumeric(src)
+	if err != nil {
+		return err
+	}
+	*target.(*decimal.Dec32) = d
+	return nil
+}
+
+type scanText struct{}
+
+func (scanText) Scan(src []byte, target any) error {
+	if src == nil {
+		return fmt.Errorf("decpgx: cannot scan NULL into %T", target)
+	}
+	if p, ok := target.(*decimal.Dec64); ok {
+		d, err := decimal.ParseDec64(string(src))
+		if err != nil {
+			return err
+		}
+		*p = d
+		return nil
+	}
+	d, err := decimal.ParseDec32(string(src))
+	if err != nil {
+		return err
+	}
+	*target.(*decimal.Dec32) = d
+	return nil
+}
+
+// DecodeDatabaseSQLValue returns the value in src as its canonical string
+// form, which database/sql drivers can represent exactly.
+func (c Codec) DecodeDatabaseSQLValue(m *pgtype.Map, oid uint32, format int16, src []byte) (driver.Value, error) {
+	d, err := c.DecodeValue(m, oid, format, src)
+	if err != nil || d == nil {
+		return nil, err
+	}
+	return d.(decimal.Dec32).String(), nil
+}
+
+// DecodeValue returns the value in src as a decimal.Dec32, or nil for NULL.
+func (c Codec) DecodeValue(m *pgtype.Map, oid uint32, format int16, src []byte) (any, error) {
+	if src == nil {
+		return nil, nil
+	}
+	var d decimal.Dec32
+	var err error
+	switch format {
+	case pgtype.BinaryFormatCode:
+		err = scanBinary{}.Scan(src, &d)
+	case pgtype.TextFormatCode:
+		err = scanText{}.Scan(src, &d)
+	default:
+		err = fmt.Errorf("decpgx: unknown format code %d", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
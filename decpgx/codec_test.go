@@ -0,0 +1,71 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decpgx
+
+import (
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	decimal "github.com/cmars/ieee754-dec"
+)
+
+func TestCodecRoundTrip(t *testing.T) {
+	m := pgtype.NewMap()
+	Register(m)
+	for _, format := range []int16{pgtype.TextFormatCode, pgtype.BinaryFormatCode} {
+		for _, s := range []string{"123.45", "-0.001", "1.20", "9999999", "NaN", "Infinity", "-Infinity"} {
+			d := decimal.MustParseDec32(s)
+			buf, err := m.Encode(pgtype.NumericOID, format, d, nil)
+			if err != nil {
+				t.Fatalf("format %d: %s: %v", format, s, err)
+			}
+			var got decimal.Dec32
+			if err := m.Scan(pgtype.NumericOID, format, buf, &got); err != nil {
+				t.Fatalf("format %d: %s: %v", format, s, err)
+			}
+			if got.String() != s {
+				t.Errorf("format %d: expect %s, got %v", format, s, got)
+			}
+		}
+	}
+}
+
+func TestCodecRoundTrip64(t *testing.T) {
+	m := pgtype.NewMap()
+	Register(m)
+	for _, format := range []int16{pgtype.TextFormatCode, pgtype.BinaryFormatCode} {
+		for _, s := range []string{"1234567.890123456", "-0.001", "1.20", "9999999999999999", "NaN", "Infinity", "-Infinity"} {
+			d, err := decimal.ParseDec64(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			buf, err := m.Encode(pgtype.NumericOID, format, d, nil)
+			if err != nil {
+				t.Fatalf("format %d: %s: %v", format, s, err)
+			}
+			var got decimal.Dec64
+			if err := m.Scan(pgtype.NumericOID, format, buf, &got); err != nil {
+				t.Fatalf("format %d: %s: %v", format, s, err)
+			}
+			if got.String() != s {
+				t.Errorf("format %d: expect %s, got %v", format, s, got)
+			}
+		}
+	}
+}
+
+func TestScanNull(t *testing.T) {
+	m := pgtype.NewMap()
+	Register(m)
+	var d decimal.Dec32
+	if err := m.Scan(pgtype.NumericOID, pgtype.BinaryFormatCode, nil, &d); err == nil {
+		t.Errorf("expected error scanning NULL")
+	}
+	var p *decimal.Dec32
+	if err := m.Scan(pgtype.NumericOID, pgtype.BinaryFormatCode, nil, &p); err != nil || p != nil {
+		t.Errorf("expected nil pointer, got %v %v", p, err)
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dectest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A Case is a test case read from a .decTest file.
+type Case struct {
+	// ID is the identifier of the case, such as "addx001".
+	ID string
+	// Op is the operation, in lower case, such as "add" or "tosci".
+	Op string
+	// Operands and Result are the tokens of the operands and of the
+	// expected result, unquoted.
+	Operands []string
+	Result   string
+	// Conditions are the conditions the operation is expected to raise,
+	// in lower case, such as "inexact" and "rounded".
+	Conditions []string
+	// Directives holds the directives in effect for the case, with keys
+	// and values in lower case, such as "precision" and "9". It is shared
+	// with other cases and must not be modified.
+	Directives map[string]string
+	// Line is the line number of the case in its file.
+	Line int
+}
+
+// A Reader reads test cases from a .decTest file. It does not follow the
+// dectest directives that include other files; they are recorded in the
+// Directives of the cases after them like other directives.
+type Reader struct {
+	s          *bufio.Scanner
+	line       int
+	directives map[string]string
+	shared     bool // whether directives belongs to a returned Case
+}
+
+// NewReader returns a Reader that reads from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{s: bufio.NewScanner(r), directives: make(map[string]string)}
+}
+
+// Next returns the next test case, applying the directives before it. It
+// returns io.EOF when there are no more cases.
+func (r *Reader) Next() (*Case, error) {
+	for r.s.Scan() {
+		r.line++
+		tokens, err := tokenize(r.s.Text())
+		if err != nil {
+			return nil, fmt.Errorf("dectest: line %d: %v", r.line, err)
+		}
+		switch {
+		case len(tokens) == 0:
+			continue
+		case strings.HasSuffix(tokens[0], ":"):
+			r.directive(tokens)
+			continue
+		}
+		return r.parseCase(tokens)
+	}
+	if err := r.s.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// directive applies the directive in tokens, "key:" followed by its value.
+func (r *Reader) directive(tokens []string) {
+	if r.shared {
+		m := make(map[string]string, len(r.directives)+1)
+		for k, v := range r.directives {
+			m[k] = v
+		}
+		r.directives, r.shared = m, false
+	}
+	key := strings.ToLower(strings.TrimSuffix(tokens[0], ":"))
+	r.directives[key] = strings.ToLower(strings.Join(tokens[1:], " "))
+}
+
+// parseCase parses the tokens of a test case line: the identifier, the
+// operation and its operands, "->", the result and the conditions.
+func (r *Reader) parseCase(tokens []string) (*Case, error) {
+	arrow := -1
+	for i, t := range tokens {
+		if t == "->" {
+			arrow = i
+			break
+		}
+	}
+	if arrow < 2 || arrow == len(tokens)-1 {
+		return nil, fmt.Errorf("dectest: line %d: malformed test case", r.line)
+	}
+	c := &Case{
+		ID:         tokens[0],
+		Op:         strings.ToLower(tokens[1]),
+		Operands:   tokens[2:arrow],
+		Result:     tokens[arrow+1],
+		Directives: r.directives,
+		Line:       r.line,
+	}
+	for _, cond := range tokens[arrow+2:] {
+		c.Conditions = append(c.Conditions, strings.ToLower(cond))
+	}
+	r.shared = true
+	return c, nil
+}
+
+// tokenize splits a line into tokens separated by white space, removing a
+// trailing comment introduced by "--" outside a quoted token. Tokens may be quoted with ' or ", with
+// the quote doubled within them to stand for itself.
+func tokenize(line string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(line); {
+		switch c := line[i]; {
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case strings.HasPrefix(line[i:], "--"):
+			return tokens, nil
+		case c == '\'' || c == '"':
+			var b strings.Builder
+			for i++; ; i++ {
+				if i == len(line) {
+					return nil, fmt.Errorf("unterminated quoted token")
+				}
+				if line[i] == c {
+					if i+1 < len(line) && line[i+1] == c {
+						i++
+					} else {
+						i++
+						break
+					}
+				}
+				b.WriteByte(line[i])
+			}
+			tokens = append(tokens, b.String())
+		default:
+			j := i
+			for j < len(line) && line[j] != ' ' && line[j] != '\t' && line[j] != '\r' &&
+				!strings.HasPrefix(line[j:], "--") {
+				j++
+			}
+			tokens = append(tokens, line[i:j])
+			i = j
+		}
+	}
+	return tokens, nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dectest
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	testCases := []struct {
+		line string
+		ref  []string
+	}{
+		{"", nil},
+		{"  -- a comment", nil},
+		{"add001 add 1 2 -> 3", []string{"add001", "add", "1", "2", "->", "3"}},
+		{"add002\tadd '1' \"2\" -> 3 -- comment", []string{"add002", "add", "1", "2", "->", "3"}},
+		{"tosci 'it''s' \"\"\"\" ''", []string{"tosci", "it's", `"`, ""}},
+		{"x '--' -- y", []string{"x", "--"}},
+		{"ab-c -1--", []string{"ab-c", "-1"}},
+	}
+	for i, testCase := range testCases {
+		tokens, err := tokenize(testCase.line)
+		if err != nil {
+			t.Errorf("testCase #%d: %q: %v", i, testCase.line, err)
+		} else if !reflect.DeepEqual(tokens, testCase.ref) {
+			t.Errorf("testCase #%d: %q: expect %q, got %q", i, testCase.line, testCase.ref, tokens)
+		}
+	}
+	if _, err := tokenize("tosci 'abc"); err == nil {
+		t.Error("expected an error for an unterminated token")
+	}
+}
+
+func TestReader(t *testing.T) {
+	const input = `-- A test file
+Precision:   7
+rounding: HALF_EVEN
+
+ADD001 Add 1 '2' -> 3
+add002 add 9999999 1 -> 1.000000E+7 Rounded
+precision: 9
+add003 add 1 2 -> 3 -- a comment
+`
+	r := NewReader(strings.NewReader(input))
+	refs := []Case{
+		{ID: "ADD001", Op: "add", Operands: []string{"1", "2"}, Result: "3",
+			Directives: map[string]string{"precision": "7", "rounding": "half_even"}, Line: 5},
+		{ID: "add002", Op: "add", Operands: []string{"9999999", "1"}, Result: "1.000000E+7",
+			Conditions: []string{"rounded"},
+			Directives: map[string]string{"precision": "7", "rounding": "half_even"}, Line: 6},
+		{ID: "add003", Op: "add", Operands: []string{"1", "2"}, Result: "3",
+			Directives: map[string]string{"precision": "9", "rounding": "half_even"}, Line: 8},
+	}
+	var cases []*Case
+	for _, ref := range refs {
+		c, err := r.Next()
+		if err != nil {
+			t.Fatalf("%s: %v", ref.ID, err)
+		}
+		if !reflect.DeepEqual(*c, ref) {
+			t.Errorf("expect %+v, got %+v", ref, *c)
+		}
+		cases = append(cases, c)
+	}
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("expect EOF, got %v", err)
+	}
+	// Later directives do not change the directives of earlier cases.
+	if p := cases[0].Directives["precision"]; p != "7" {
+		t.Errorf("expect precision 7 for %s, got %s", cases[0].ID, p)
+	}
+}
+
+func TestReaderError(t *testing.T) {
+	for i, input := range []string{
+		"add001 add 1 2",
+		"add001 -> 3",
+		"add001 add 1 2 ->",
+		"add001 add '1 -> 3",
+	} {
+		r := NewReader(strings.NewReader("precision: 7\n" + input + "\n"))
+		if _, err := r.Next(); err == nil || err == io.EOF {
+			t.Errorf("testCase #%d: %q: expected an error, got %v", i, input, err)
+		} else if !strings.Contains(err.Error(), "line 2") {
+			t.Errorf("testCase #%d: %q: error does not give the line: %v", i, input, err)
+		}
+	}
+}
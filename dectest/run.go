@@ -0,0 +1,364 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dectest
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	decimal "github.com/cmars/ieee754-dec"
+)
+
+// ErrSkip is returned, wrapped with the reason, by Run for test cases that do
+// not apply to this module: those for other formats or contexts, for
+// operations it lacks, or with operands that decimal32 cannot hold exactly.
+var ErrSkip = errors.New("dectest: test case skipped")
+
+// roundingModes holds the rounding modes of the values of the rounding
+// directive.
+var roundingModes = func() map[string]decimal.RoundingMode {
+	m := make(map[string]decimal.RoundingMode, len(roundingNames))
+	for mode, name := range roundingNames {
+		m[name] = mode
+	}
+	return m
+}()
+
+// conditionFlags holds the flags that correspond to the conditions of the
+// General Decimal Arithmetic specification. Conditions without a flag, such
+// as rounded and subnormal, are not checked.
+var conditionFlags = map[string]decimal.Flags{
+	"inexact":             decimal.Inexact,
+	"overflow":            decimal.Overflow,
+	"underflow":           decimal.Underflow,
+	"invalid_operation":   decimal.Invalid,
+	"conversion_syntax":   decimal.Invalid,
+	"division_impossible": decimal.Invalid,
+	"division_undefined":  decimal.Invalid,
+	"invalid_context":     decimal.Invalid,
+	"division_by_zero":    decimal.DivisionByZero,
+	"clamped":             decimal.Clamped,
+}
+
+// An operation maps an operation of the .decTest format onto this module.
+// It returns the result as a decimal.Dec32 where the result is a value and as
+// a string otherwise, as class does.
+type operation struct {
+	arity int
+	// fixed is set for operations that do not round, and so apply only
+	// with precision 7.
+	fixed bool
+	fn    func(c *decimal.Context, x []decimal.Dec32) any
+}
+
+var operations = map[string]operation{
+	"abs": {1, false, func(c *decimal.Context, x []decimal.Dec32) any {
+		if x[0].IsNaN() {
+			return c.Round(x[0])
+		}
+		return c.Round(x[0].Abs())
+	}},
+	"add":        {2, false, func(c *decimal.Context, x []decimal.Dec32) any { return c.Add(x[0], x[1]) }},
+	"subtract":   {2, false, func(c *decimal.Context, x []decimal.Dec32) any { return c.Sub(x[0], x[1]) }},
+	"multiply":   {2, false, func(c *decimal.Context, x []decimal.Dec32) any { return c.Mul(x[0], x[1]) }},
+	"divide":     {2, false, func(c *decimal.Context, x []decimal.Dec32) any { return c.Div(x[0], x[1]) }},
+	"fma":        {3, false, func(c *decimal.Context, x []decimal.Dec32) any { return c.FMA(x[0], x[1], x[2]) }},
+	"squareroot": {1, false, func(c *decimal.Context, x []decimal.Dec32) any { return c.Sqrt(x[0]) }},
+	"minus": {1, false, func(c *decimal.Context, x []decimal.Dec32) any {
+		return c.Sub(zeroWithExponent(x[0]), x[0])
+	}},
+	"plus": {1, false, func(c *decimal.Context, x []decimal.Dec32) any {
+		return c.Add(zeroWithExponent(x[0]), x[0])
+	}},
+	"max":    {2, false, func(c *decimal.Context, x []decimal.Dec32) any { return c.Round(c.Max(x[0], x[1])) }},
+	"min":    {2, false, func(c *decimal.Context, x []decimal.Dec32) any { return c.Round(c.Min(x[0], x[1])) }},
+	"maxmag": {2, false, func(c *decimal.Context, x []decimal.Dec32) any { return c.Round(c.MaxMag(x[0], x[1])) }},
+	"minmag": {2, false, func(c *decimal.Context, x []decimal.Dec32) any { return c.Round(c.MinMag(x[0], x[1])) }},
+	"quantize": {2, false, func(c *decimal.Context, x []decimal.Dec32) any {
+		return c.Quantize(x[0], x[1])
+	}},
+	"logb": {1, false, func(c *decimal.Context, x []decimal.Dec32) any { return c.LogB(x[0]) }},
+	"scaleb": {2, false, func(c *decimal.Context, x []decimal.Dec32) any {
+		if x[0].IsNaN() || x[1].IsNaN() {
+			return c.Add(x[0], x[1])
+		}
+		n, ok := x[1].Int64()
+		if !ok || n < -1e9 || n > 1e9 {
+			c.Flags |= decimal.Invalid
+			return decimal.NaN()
+		}
+		return c.ScaleB(x[0], int(n))
+	}},
+	"compare": {2, true, func(c *decimal.Context, x []decimal.Dec32) any {
+		if x[0].IsNaN() || x[1].IsNaN() {
+			// Add propagates NaNs as comparisons must.
+			return c.Add(x[0], x[1])
+		}
+		return integer(x[0].Cmp(x[1]))
+	}},
+	"comparetotal": {2, true, func(c *decimal.Context, x []decimal.Dec32) any {
+		return integer(x[0].CmpTotal(x[1]))
+	}},
+	"copy":        {1, true, func(c *decimal.Context, x []decimal.Dec32) any { return x[0] }},
+	"copyabs":     {1, true, func(c *decimal.Context, x []decimal.Dec32) any { return x[0].Abs() }},
+	"copynegate":  {1, true, func(c *decimal.Context, x []decimal.Dec32) any { return x[0].Neg() }},
+	"copysign":    {2, true, func(c *decimal.Context, x []decimal.Dec32) any { return x[0].CopySign(x[1]) }},
+	"canonical":   {1, true, func(c *decimal.Context, x []decimal.Dec32) any { return x[0].Canonical() }},
+	"class":       {1, true, func(c *decimal.Context, x []decimal.Dec32) any { return x[0].Class().String() }},
+	"iscanonical": {1, true, func(c *decimal.Context, x []decimal.Dec32) any { return boolean(x[0].IsCanonical()) }},
+	"samequantum": {2, true, func(c *decimal.Context, x []decimal.Dec32) any {
+		return boolean(x[0].SameQuantum(x[1]))
+	}},
+	"nextplus":      {1, true, quiet(decimal.Dec32.NextUp)},
+	"nextminus":     {1, true, quiet(decimal.Dec32.NextDown)},
+	"remainder":     {2, true, quiet2(decimal.Dec32.Mod)},
+	"remaindernear": {2, true, quiet2(decimal.Dec32.Rem)},
+	"tointegral": {1, true, func(c *decimal.Context, x []decimal.Dec32) any {
+		return quiet(func(d decimal.Dec32) decimal.Dec32 { return d.RoundToIntegral(c.Mode) })(c, x)
+	}},
+}
+
+// quiet adapts an operation f that raises no flags, raising Invalid as the
+// operations of a Context do: when an operand is a signaling NaN, or the
+// result is a NaN although no operand is.
+func quiet(f func(decimal.Dec32) decimal.Dec32) func(*decimal.Context, []decimal.Dec32) any {
+	return func(c *decimal.Context, x []decimal.Dec32) any {
+		r := f(x[0])
+		raiseInvalid(c, r, x)
+		return r
+	}
+}
+
+// quiet2 is like quiet for operations with two operands.
+func quiet2(f func(x, y decimal.Dec32) decimal.Dec32) func(*decimal.Context, []decimal.Dec32) any {
+	return func(c *decimal.Context, x []decimal.Dec32) any {
+		r := f(x[0], x[1])
+		raiseInvalid(c, r, x)
+		return r
+	}
+}
+
+// raiseInvalid raises Invalid in c for the result r of an operation on x, as
+// described for quiet.
+func raiseInvalid(c *decimal.Context, r decimal.Dec32, x []decimal.Dec32) {
+	nan := false
+	for _, d := range x {
+		if d.IsSignalingNaN() {
+			c.Flags |= decimal.Invalid
+			return
+		}
+		nan = nan || d.IsNaN()
+	}
+	if r.IsNaN() && !nan {
+		c.Flags |= decimal.Invalid
+	}
+}
+
+// zeroWithExponent returns the positive zero with the exponent of d, the zero
+// that the minus and plus operations add d to.
+func zeroWithExponent(d decimal.Dec32) decimal.Dec32 {
+	_, exp, ok := d.Decode()
+	if !ok {
+		return decimal.Zero(1, 0)
+	}
+	return decimal.Zero(1, exp)
+}
+
+// integer returns n as a decimal.
+func integer(n int) decimal.Dec32 {
+	d, _ := decimal.FromInt64(int64(n))
+	return d
+}
+
+// boolean returns 1 if b is set and 0 otherwise.
+func boolean(b bool) decimal.Dec32 {
+	if b {
+		return integer(1)
+	}
+	return integer(0)
+}
+
+// Run runs the test case c and returns nil if this module gives the expected
+// result and raises the flags of the expected conditions. It returns an error
+// wrapping ErrSkip if c does not apply to this module: if the directives in
+// effect do not describe decimal32, with a precision of at most 7,
+// exponents from -95 to 96 and clamping, or the operation is not supported,
+// or an operand or the result cannot be represented exactly.
+//
+// Operands and results are read as by ParseDec32, or as the hexadecimal
+// encoding after a '#', and NaNs may have a payload, such as "NaN12".
+// Results are compared by encoding, so that 1.0 differs from 1.00 and the
+// payloads of NaNs must match.
+func Run(c *Case) error {
+	ctx, err := decContext(c.Directives)
+	if err != nil {
+		return err
+	}
+	var got any
+	switch c.Op {
+	case "tosci", "toeng", "apply":
+		if len(c.Operands) != 1 {
+			return fmt.Errorf("dectest: %s: %s needs 1 operand", c.ID, c.Op)
+		}
+		got = convert(&ctx, c.Op, c.Operands[0])
+	default:
+		op, ok := operations[c.Op]
+		switch {
+		case !ok:
+			return fmt.Errorf("%w: %s: unsupported operation %s", ErrSkip, c.ID, c.Op)
+		case len(c.Operands) != op.arity:
+			return fmt.Errorf("dectest: %s: %s needs %d operands", c.ID, c.Op, op.arity)
+		case op.fixed && ctx.Precision != 7:
+			return fmt.Errorf("%w: %s: %s with precision %d", ErrSkip, c.ID, c.Op, ctx.Precision)
+		}
+		x := make([]decimal.Dec32, len(c.Operands))
+		for i, s := range c.Operands {
+			if x[i], err = parseOperand(s); err != nil {
+				return fmt.Errorf("%w: %s: %v", ErrSkip, c.ID, err)
+			}
+		}
+		got = op.fn(&ctx, x)
+	}
+
+	var want decimal.Flags
+	for _, cond := range c.Conditions {
+		want |= conditionFlags[cond]
+	}
+	var ok bool
+	switch got := got.(type) {
+	case decimal.Dec32:
+		r, err := parseOperand(c.Result)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrSkip, c.ID, err)
+		}
+		ok = got == r
+	case string:
+		ok = strings.EqualFold(got, c.Result)
+	}
+	if !ok || ctx.Flags != want {
+		return fmt.Errorf("dectest: %s: %s %s -> %s %v, want %s %v", c.ID, c.Op, strings.Join(c.Operands, " "),
+			formatResult(got), ctx.Flags, c.Result, want)
+	}
+	return nil
+}
+
+// decContext returns the context that the directives describe, or an error
+// wrapping ErrSkip if they do not describe decimal32 arithmetic.
+func decContext(directives map[string]string) (decimal.Context, error) {
+	var c decimal.Context
+	for _, d := range [][2]string{
+		{"maxexponent", "96"},
+		{"minexponent", "-95"},
+		{"clamp", "1"},
+	} {
+		if v := directives[d[0]]; v != d[1] {
+			return c, fmt.Errorf("%w: %s %q", ErrSkip, d[0], v)
+		}
+	}
+	if v, ok := directives["extended"]; ok && v != "1" {
+		return c, fmt.Errorf("%w: extended %q", ErrSkip, v)
+	}
+	prec, err := strconv.Atoi(directives["precision"])
+	if err != nil || prec < 1 || prec > 7 {
+		return c, fmt.Errorf("%w: precision %q", ErrSkip, directives["precision"])
+	}
+	mode, ok := roundingModes[directives["rounding"]]
+	if !ok {
+		return c, fmt.Errorf("%w: rounding %q", ErrSkip, directives["rounding"])
+	}
+	c.Precision, c.Mode = prec, mode
+	return c, nil
+}
+
+// convert performs the conversion op on the string s, returning the
+// resulting decimal for apply and its string form otherwise. NaN results
+// are returned as decimals, since String does not write payloads.
+func convert(c *decimal.Context, op, s string) any {
+	d, err := parseNaN(s)
+	if err != nil {
+		d, err = c.ParseDec32(s)
+		var numErr *strconv.NumError
+		if errors.As(err, &numErr) && numErr.Err == strconv.ErrSyntax {
+			c.Flags |= decimal.Invalid
+		}
+	}
+	switch {
+	case op == "apply" || d.IsNaN():
+		return d
+	case op == "toeng":
+		return d.EngineeringString()
+	}
+	return d.String()
+}
+
+// parseOperand parses an operand or result of a test case exactly.
+func parseOperand(s string) (decimal.Dec32, error) {
+	switch {
+	case s == "?":
+		return 0, errors.New("undefined result")
+	case strings.HasPrefix(s, "#"):
+		if len(s) != 9 {
+			return 0, fmt.Errorf("encoding %s is not decimal32", s)
+		}
+		u, err := strconv.ParseUint(s[1:], 16, 32)
+		if err != nil {
+			return 0, fmt.Errorf("malformed encoding %s", s)
+		}
+		return decimal.Dec32(u), nil
+	}
+	if d, err := parseNaN(s); err == nil {
+		return d, nil
+	}
+	d, exact, err := decimal.ParseDec32Exact(s)
+	if err != nil || !exact {
+		return 0, fmt.Errorf("operand %s is not a decimal32 value", s)
+	}
+	return d, nil
+}
+
+// parseNaN parses a NaN with an optional sign and payload, such as "-sNaN12".
+func parseNaN(s string) (decimal.Dec32, error) {
+	t := strings.ToLower(s)
+	neg := false
+	if t != "" && (t[0] == '+' || t[0] == '-') {
+		neg, t = t[0] == '-', t[1:]
+	}
+	signaling := strings.HasPrefix(t, "snan")
+	if signaling {
+		t = t[1:]
+	}
+	if !strings.HasPrefix(t, "nan") {
+		return 0, fmt.Errorf("%s is not a NaN", s)
+	}
+	var payload uint64
+	if t = t[3:]; t != "" {
+		var err error
+		if payload, err = strconv.ParseUint(t, 10, 32); err != nil {
+			return 0, fmt.Errorf("%s is not a NaN", s)
+		}
+	}
+	f := decimal.QuietNaN
+	if signaling {
+		f = decimal.SignalingNaN
+	}
+	d, ok := f(uint32(payload))
+	if !ok {
+		return 0, fmt.Errorf("NaN payload of %s is too large for decimal32", s)
+	}
+	if neg {
+		d = d.Neg()
+	}
+	return d, nil
+}
+
+// formatResult formats a result of Run for an error message.
+func formatResult(r any) string {
+	if d, ok := r.(decimal.Dec32); ok {
+		return Format(d)
+	}
+	return fmt.Sprint(r)
+}
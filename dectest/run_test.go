@@ -0,0 +1,132 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dectest
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	decimal "github.com/cmars/ieee754-dec"
+)
+
+const header = `extended:    1
+clamp:       1
+precision:   7
+rounding:    half_even
+maxExponent: 96
+minExponent: -95
+`
+
+// readCases returns the test cases of input.
+func readCases(t *testing.T, input string) []*Case {
+	var cases []*Case
+	r := NewReader(strings.NewReader(input))
+	for {
+		c, err := r.Next()
+		if err == io.EOF {
+			return cases
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		cases = append(cases, c)
+	}
+}
+
+func TestRun(t *testing.T) {
+	const input = header + `
+run001 add 9999999 1 -> 1.000000E+7 Rounded
+run002 divide 1 3 -> 0.3333333 Inexact Rounded
+run003 divide 1 0 -> Infinity Division_by_zero
+run004 multiply 9E+96 10 -> Infinity Overflow Inexact Rounded
+run005 add sNaN12 NaN3 -> NaN12 Invalid_operation
+run006 quantize 1.2345 0.01 -> 1.23 Inexact Rounded
+run007 multiply 1E-95 1E-7 -> 0E-101 Underflow Subnormal Inexact Rounded Clamped
+run008 apply 1.23456789 -> 1.234568 Inexact Rounded
+run009 tosci abc -> NaN Conversion_syntax
+run010 max NaN -2 -> -2
+run011 class -0 -> -Zero
+run012 comparetotal 1.0 1 -> -1
+run013 abs #a2500001 -> #22500001
+precision: 3
+run014 add 1.234 0 -> 1.23 Inexact Rounded
+run015 squareroot 2 -> 1.41 Inexact Rounded
+run016 squareroot 1.44 -> 1.2
+run017 squareroot -1 -> NaN Invalid_operation
+precision: 7
+run018 multiply 1E+90 1E+1 -> 1.0E+91 Clamped
+run019 multiply 0E+90 1E+5 -> 0E+90 Clamped
+run020 multiply 1E-95 1E-6 -> 1E-101 Subnormal
+`
+	for _, c := range readCases(t, input) {
+		if err := Run(c); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestRunSkip(t *testing.T) {
+	const input = header + `
+skip001 power 2 3 -> 8
+skip002 add 1.23456789 0 -> 1.234568 Inexact Rounded
+skip003 add ? 1 -> ?
+skip004 copy 1 -> 1
+precision: 3
+skip005 copy 1 -> 1
+precision: 16
+skip006 add 1 2 -> 3
+precision: 7
+maxExponent: 384
+skip007 add 1 2 -> 3
+`
+	for _, c := range readCases(t, input) {
+		err := Run(c)
+		switch c.ID {
+		case "skip004":
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", c.ID, err)
+			}
+		default:
+			if !errors.Is(err, ErrSkip) {
+				t.Errorf("%s: expected a skip, got %v", c.ID, err)
+			}
+		}
+	}
+}
+
+func TestRunMismatch(t *testing.T) {
+	const input = header + `
+bad001 add 1 2 -> 4
+bad002 add 1.0 2 -> 3
+bad003 divide 1 3 -> 0.3333333 Rounded
+bad004 add 1 2 -> 3 Inexact
+bad005 add 1 2 3 -> 6
+`
+	for _, c := range readCases(t, input) {
+		if err := Run(c); err == nil || errors.Is(err, ErrSkip) {
+			t.Errorf("%s: expected a mismatch, got %v", c.ID, err)
+		} else if !strings.Contains(err.Error(), c.ID) {
+			t.Errorf("%s: error does not name the case: %v", c.ID, err)
+		}
+	}
+}
+
+func TestRunWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, "wri")
+	if err := w.Header(decimal.ToNearestEven); err != nil {
+		t.Fatal(err)
+	}
+	x, y := decimal.MustParseDec32("1.5"), decimal.MustParseDec32("-0.25")
+	w.Record("add", x.Add(y), x, y)
+	w.Record("multiply", x.Mul(y), x, y)
+	for _, c := range readCases(t, buf.String()) {
+		if err := Run(c); err != nil {
+			t.Error(err)
+		}
+	}
+}
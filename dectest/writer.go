@@ -0,0 +1,152 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package dectest works with test cases in the .decTest format of the
+// General Decimal Arithmetic specification, the format of the decNumber test
+// suites, so that results of this module can be compared with other
+// implementations.
+//
+// A Writer records operations and their results as test cases:
+//
+//	w := dectest.NewWriter(f, "bug")
+//	w.Header(decimal.ToNearestEven)
+//	w.Record("hypot", x.Hypot(y), x, y)
+//
+// writes the directives describing decimal32 arithmetic followed by a line
+// such as
+//
+//	bug001 hypot 3 4 -> 5
+//
+// A Reader reads the test cases of a file, such as those of the decNumber
+// suites, and Run checks each against this module, skipping those for other
+// formats or operations it lacks:
+//
+//	r := dectest.NewReader(f)
+//	for {
+//		c, err := r.Next()
+//		if err == io.EOF {
+//			break
+//		} else if err != nil {
+//			return err
+//		}
+//		if err := dectest.Run(c); err != nil && !errors.Is(err, dectest.ErrSkip) {
+//			log.Print(err)
+//		}
+//	}
+package dectest
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	decimal "github.com/cmars/ieee754-dec"
+)
+
+// roundingNames holds the values of the rounding directive.
+var roundingNames = map[decimal.RoundingMode]string{
+	decimal.ToNearestEven:       "half_even",
+	decimal.ToNearestAway:       "half_up",
+	decimal.ToZero:              "down",
+	decimal.ToPositiveInf:       "ceiling",
+	decimal.ToNegativeInf:       "floor",
+	decimal.ToNearestTowardZero: "half_down",
+	decimal.AwayFromZero:        "up",
+	decimal.ZeroFiveUp:          "05up",
+}
+
+// A Writer writes test cases in the .decTest format. Each test case is given
+// an identifier made of a prefix and a sequence number.
+type Writer struct {
+	w      io.Writer
+	prefix string
+	n      int
+}
+
+// NewWriter returns a Writer that writes to w, numbering test cases after
+// the given identifier prefix.
+func NewWriter(w io.Writer, prefix string) *Writer {
+	return &Writer{w: w, prefix: prefix}
+}
+
+// Header writes the directives that describe decimal32 arithmetic with the
+// given rounding mode: seven digits of precision and exponents from -95 to
+// 96, with clamping.
+func (w *Writer) Header(mode decimal.RoundingMode) error {
+	rounding, ok := roundingNames[mode]
+	if !ok {
+		return fmt.Errorf("dectest: unknown rounding mode %v", mode)
+	}
+	for _, d := range [][2]string{
+		{"extended", "1"},
+		{"clamp", "1"},
+		{"precision", "7"},
+		{"rounding", rounding},
+		{"maxExponent", "96"},
+		{"minExponent", "-95"},
+	} {
+		if err := w.Directive(d[0], d[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Directive writes the directive "key: value".
+func (w *Writer) Directive(key, value string) error {
+	_, err := fmt.Fprintf(w.w, "%s: %s\n", key, value)
+	return err
+}
+
+// Comment writes each line of s as a comment.
+func (w *Writer) Comment(s string) error {
+	for _, line := range strings.Split(s, "\n") {
+		if _, err := fmt.Fprintf(w.w, "-- %s\n", line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Record writes a test case for the operation op, such as "add" or
+// "squareroot", applied to the operands with the given result. The
+// conditions the operation raised, such as "Inexact" or "Rounded", can be
+// appended with RecordConditions.
+func (w *Writer) Record(op string, result decimal.Dec32, operands ...decimal.Dec32) error {
+	return w.RecordConditions(op, result, nil, operands...)
+}
+
+// RecordConditions is like Record but also lists the conditions raised by
+// the operation.
+func (w *Writer) RecordConditions(op string, result decimal.Dec32, conditions []string, operands ...decimal.Dec32) error {
+	w.n++
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s%03d %s", w.prefix, w.n, op)
+	for _, d := range operands {
+		b.WriteByte(' ')
+		b.WriteString(Format(d))
+	}
+	b.WriteString(" -> ")
+	b.WriteString(Format(result))
+	for _, c := range conditions {
+		b.WriteByte(' ')
+		b.WriteString(c)
+	}
+	b.WriteByte('\n')
+	_, err := io.WriteString(w.w, b.String())
+	return err
+}
+
+// Format returns d as a .decTest operand: its scientific string form, or,
+// for values that the string cannot reproduce, such as a NaN payload or a
+// non-canonical coefficient, a '#' followed by the hexadecimal encoding.
+func Format(d decimal.Dec32) string {
+	if !d.IsCanonical() {
+		return fmt.Sprintf("#%08x", uint32(d))
+	}
+	if r, err := decimal.ParseDec32(d.String()); err == nil && r == d {
+		return d.String()
+	}
+	return fmt.Sprintf("#%08x", uint32(d))
+}
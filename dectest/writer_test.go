@@ -0,0 +1,44 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dectest
+
+import (
+	"bytes"
+	"testing"
+
+	decimal "github.com/cmars/ieee754-dec"
+)
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, "hyp")
+	if err := w.Header(decimal.ToNearestAway); err != nil {
+		t.Fatal(err)
+	}
+	w.Comment("Hypot cases\nfrom a bug report")
+	x, y := decimal.MustParseDec32("3"), decimal.MustParseDec32("-4.0")
+	w.Record("hypot", x.Hypot(y), x, y)
+	w.RecordConditions("multiply", decimal.MustParseDec32("Infinity"), []string{"Inexact", "Overflow", "Rounded"},
+		decimal.MustParseDec32("9E+96"), decimal.MustParseDec32("10"))
+	w.Record("abs", decimal.Dec32(0x7c00002a), decimal.Dec32(0x6cbfffff))
+	const ref = `extended: 1
+clamp: 1
+precision: 7
+rounding: half_up
+maxExponent: 96
+minExponent: -95
+-- Hypot cases
+-- from a bug report
+hyp001 hypot 3 -4.0 -> 5.0
+hyp002 multiply 9.000000E+96 10 -> Infinity Inexact Overflow Rounded
+hyp003 abs #6cbfffff -> #7c00002a
+`
+	if s := buf.String(); s != ref {
+		t.Errorf("unexpected output:\n%s", s)
+	}
+	if err := w.Header(decimal.RoundingMode(99)); err == nil {
+		t.Errorf("expected error for unknown rounding mode")
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package decvalidator adds validation tags for decimal fields to
+// github.com/go-playground/validator:
+//
+//	v := validator.New()
+//	if err := decvalidator.Register(v); err != nil {
+//		...
+//	}
+//
+//	type Order struct {
+//		Price    decimal.Dec32 `validate:"required,dec_gt=0,dec_scale=2"`
+//		Discount decimal.Dec32 `validate:"dec_gte=0,dec_lte=0.5"`
+//		Total    decimal.Dec64 `validate:"dec_lt=1E+12"`
+//	}
+//
+// The comparison tags dec_gt, dec_gte, dec_lt and dec_lte compare the field
+// with their parameter exactly, as decimals, and dec_scale=n accepts values
+// with at most n fraction digits, not counting trailing zeros, so 1.50
+// passes dec_scale=1. NaN fails all of them. The tags apply to decimal.Dec32
+// and decimal.Dec64 fields, and also accept string fields holding decimal
+// strings, which are read to the 16 digits of decimal64.
+package decvalidator
+
+import (
+	"math/big"
+	"reflect"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+
+	decimal "github.com/cmars/ieee754-dec"
+)
+
+var (
+	dec32Type = reflect.TypeOf(decimal.Dec32(0))
+	dec64Type = reflect.TypeOf(decimal.Dec64(0))
+)
+
+// Register registers ValueOf as the custom type function for decimal.Dec32
+// and decimal.Dec64, and the dec_gt, dec_gte, dec_lt, dec_lte and dec_scale
+// validations with v.
+func Register(v *validator.Validate) error {
+	v.RegisterCustomTypeFunc(ValueOf, decimal.Dec32(0), decimal.Dec64(0))
+	validations := []struct {
+		tag string
+		fn  validator.Func
+	}{
+		{"dec_gt", compare(func(c int) bool { return c > 0 })},
+		{"dec_gte", compare(func(c int) bool { return c >= 0 })},
+		{"dec_lt", compare(func(c int) bool { return c < 0 })},
+		{"dec_lte", compare(func(c int) bool { return c <= 0 })},
+		{"dec_scale", Scale},
+	}
+	for _, val := range validations {
+		if err := v.RegisterValidation(val.tag, val.fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValueOf is a validator.CustomTypeFunc for decimal.Dec32 and decimal.Dec64
+// fields. It returns the string form of the decimal, so that built-in tags
+// such as required and omitempty treat all numbers as present, and nil for a
+// NaN, which they treat as missing.
+func ValueOf(field reflect.Value) interface{} {
+	switch d := field.Interface().(type) {
+	case decimal.Dec32:
+		if !d.IsNaN() {
+			return d.String()
+		}
+	case decimal.Dec64:
+		if !d.IsNaN() {
+			return d.String()
+		}
+	}
+	return nil
+}
+
+// compare returns a validation comparing the field with the tag parameter
+// and passing if ok accepts the result.
+func compare(ok func(c int) bool) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		d, valid := fieldValue(fl)
+		if !valid {
+			return false
+		}
+		p, err := decimal.ParseDec64(fl.Param())
+		if err != nil || p.IsNaN() {
+			panic("decvalidator: bad comparison parameter " + strconv.Quote(fl.Param()))
+		}
+		return ok(cmp(d, p))
+	}
+}
+
+// Scale is the dec_scale validation. It passes if the field has at most as
+// many fraction digits as the tag parameter, not counting trailing zeros.
+// Infinities pass.
+func Scale(fl validator.FieldLevel) bool {
+	d, ok := fieldValue(fl)
+	if !ok {
+		return false
+	}
+	scale, err := strconv.Atoi(fl.Param())
+	if err != nil {
+		panic("decvalidator: bad dec_scale parameter " + strconv.Quote(fl.Param()))
+	}
+	coeff, exp, finite := d.Decode()
+	if !finite || coeff == 0 {
+		return true
+	}
+	for coeff%10 == 0 && int(exp) < -scale {
+		coeff /= 10
+		exp++
+	}
+	return int(exp) >= -scale
+}
+
+// fieldValue returns the decimal held by the validated field, which is a
+// decimal.Dec32, a decimal.Dec64 or a string, as returned by ValueOf,
+// converted exactly to a decimal64. It returns false for NaN and for strings
+// that are not decimals.
+func fieldValue(fl validator.FieldLevel) (decimal.Dec64, bool) {
+	field := fl.Field()
+	var d decimal.Dec64
+	switch {
+	case field.Type() == dec32Type:
+		d = decimal.Dec32(field.Uint()).ToDec64()
+	case field.Type() == dec64Type:
+		d = decimal.Dec64(field.Uint())
+	case field.Kind() == reflect.String:
+		var err error
+		if d, err = decimal.ParseDec64(field.String()); err != nil {
+			return d, false
+		}
+	default:
+		return d, false
+	}
+	return d, !d.IsNaN()
+}
+
+// cmp compares the values of x and y, neither of which is NaN.
+func cmp(x, y decimal.Dec64) int {
+	if x.IsInf() || y.IsInf() {
+		xs, ys := infSign(x), infSign(y)
+		switch {
+		case xs < ys:
+			return -1
+		case xs > ys:
+			return 1
+		}
+		return 0
+	}
+	return rat(x).Cmp(rat(y))
+}
+
+// infSign orders the infinities below and above every finite value.
+func infSign(d decimal.Dec64) int {
+	if d.IsInf() {
+		return d.Sign()
+	}
+	return 0
+}
+
+// rat returns the exact value of the finite d.
+func rat(d decimal.Dec64) *big.Rat {
+	coeff, exp, _ := d.Decode()
+	r := new(big.Rat).SetInt64(coeff)
+	if exp < 0 {
+		return r.Quo(r, new(big.Rat).SetInt(pow10(-int64(exp))))
+	}
+	return r.Mul(r, new(big.Rat).SetInt(pow10(int64(exp))))
+}
+
+func pow10(n int64) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(n), nil)
+}
@@ -0,0 +1,99 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decvalidator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+
+	decimal "github.com/cmars/ieee754-dec"
+)
+
+type fieldLevel struct {
+	validator.FieldLevel
+	field reflect.Value
+	param string
+}
+
+func (fl fieldLevel) Field() reflect.Value { return fl.field }
+func (fl fieldLevel) Param() string        { return fl.param }
+
+func TestValidations(t *testing.T) {
+	fns := map[string]validator.Func{
+		"dec_gt":    compare(func(c int) bool { return c > 0 }),
+		"dec_gte":   compare(func(c int) bool { return c >= 0 }),
+		"dec_lt":    compare(func(c int) bool { return c < 0 }),
+		"dec_lte":   compare(func(c int) bool { return c <= 0 }),
+		"dec_scale": Scale,
+	}
+	testCases := []struct {
+		tag, param string
+		field      interface{}
+		ok         bool
+	}{
+		{"dec_gt", "0", decimal.MustParseDec32("0.01"), true},
+		{"dec_gt", "0", decimal.MustParseDec32("-0"), false},
+		{"dec_gt", "0.1", decimal.MustParseDec32("0.10"), false},
+		{"dec_gte", "0.1", decimal.MustParseDec32("0.10"), true},
+		{"dec_gte", "0", decimal.MustParseDec32("-0"), true},
+		{"dec_lt", "1E+96", decimal.MustParseDec32("Infinity"), false},
+		{"dec_lt", "-1E+96", decimal.MustParseDec32("-Infinity"), true},
+		{"dec_lte", "0.5", decimal.MustParseDec32("0.5000"), true},
+		{"dec_lte", "0.5", decimal.MustParseDec32("0.5001"), false},
+		{"dec_lte", "0.5", decimal.MustParseDec32("NaN"), false},
+		{"dec_gte", "0", decimal.MustParseDec32("NaN"), false},
+		{"dec_gt", "0", "12.5", true},
+		{"dec_gt", "0", "-12.5", false},
+		{"dec_gt", "0", "abc", false},
+		{"dec_gt", "0", 12, false},
+		{"dec_scale", "2", decimal.MustParseDec32("1.25"), true},
+		{"dec_scale", "2", decimal.MustParseDec32("1.2500"), true},
+		{"dec_scale", "2", decimal.MustParseDec32("0.125"), false},
+		{"dec_scale", "0", decimal.MustParseDec32("1.5E+3"), true},
+		{"dec_scale", "0", decimal.MustParseDec32("0.000"), true},
+		{"dec_scale", "0", decimal.MustParseDec32("-Infinity"), true},
+		{"dec_scale", "3", decimal.MustParseDec32("NaN"), false},
+		{"dec_scale", "1", "2.50", true},
+		{"dec_gt", "0.1234567890123455", decimal.MustEncodeDec64(1234567890123456, -16), true},
+		{"dec_gt", "0.1234567890123456", decimal.MustEncodeDec64(1234567890123456, -16), false},
+		{"dec_lte", "1E+300", decimal.MustEncodeDec64(1, 300), true},
+		{"dec_gt", "1E+96", decimal.MustEncodeDec64(1, 97), true},
+		{"dec_lt", "1E+96", decimal.MustParseDec32("9.999999E+96"), false},
+		{"dec_gt", "0.1234567", "0.12345671", true},
+		{"dec_scale", "12", decimal.MustEncodeDec64(1234567890123456, -12), true},
+		{"dec_scale", "11", decimal.MustEncodeDec64(1234567890123456, -12), false},
+		{"dec_scale", "2", decimal.MustEncodeDec64(125, 0), true},
+	}
+	for i, testCase := range testCases {
+		fl := fieldLevel{field: reflect.ValueOf(testCase.field), param: testCase.param}
+		if ok := fns[testCase.tag](fl); ok != testCase.ok {
+			t.Errorf("testCase #%d: %s=%s on %v: expect %v, got %v", i, testCase.tag, testCase.param, testCase.field, testCase.ok, ok)
+		}
+	}
+}
+
+func TestValueOf(t *testing.T) {
+	if v := ValueOf(reflect.ValueOf(decimal.MustParseDec32("1.50"))); v != "1.50" {
+		t.Errorf("expect 1.50, got %v", v)
+	}
+	if v := ValueOf(reflect.ValueOf(decimal.MustParseDec32("NaN"))); v != nil {
+		t.Errorf("expect nil for NaN, got %v", v)
+	}
+	if v := ValueOf(reflect.ValueOf(decimal.MustEncodeDec64(1234567890123456, -9))); v != "1234567.890123456" {
+		t.Errorf("expect 1234567.890123456, got %v", v)
+	}
+	nan, _ := decimal.ParseDec64("NaN")
+	if v := ValueOf(reflect.ValueOf(nan)); v != nil {
+		t.Errorf("expect nil for NaN, got %v", v)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	if err := Register(validator.New()); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,342 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import "math/big"
+
+// The densely packed decimal (DPD) encoding of IEEE 754-2008 stores the
+// coefficient of a decimal format as a leading digit, in the combination
+// field with the two high bits of the exponent, and 10-bit declets each
+// holding three digits, in place of the binary integer of the BID encoding
+// that Dec32, Dec64 and Dec128 use. It is the encoding of IBM hardware and of
+// the decNumber library. The sign, exponent range and special values are the
+// same in both encodings, so every value converts exactly.
+
+// dpdFormat describes the layout of a DPD interchange format.
+type dpdFormat struct {
+	width   int // bits in the encoding
+	declets int // declets in the trailing significand
+	bias    int // exponent bias
+}
+
+var (
+	dpd32  = dpdFormat{32, 2, expBias}
+	dpd64  = dpdFormat{64, 5, expBias64}
+	dpd128 = dpdFormat{128, 11, expBias128}
+)
+
+// binToDPD and dpdToBin convert between three-digit numbers and declets.
+// Every number has one declet; of the 1024 declets, 24 are non-canonical
+// and decode as other declets do.
+var (
+	binToDPD [1000]uint16
+	dpdToBin [1024]uint16
+)
+
+func init() {
+	for n := range binToDPD {
+		binToDPD[n] = encodeDeclet(uint16(n))
+	}
+	for dpd := range dpdToBin {
+		dpdToBin[dpd] = decodeDeclet(uint16(dpd))
+	}
+}
+
+// encodeDeclet returns the canonical declet of the three-digit number n,
+// following Table 3.3 of IEEE 754-2008.
+func encodeDeclet(n uint16) uint16 {
+	d1, d2, d3 := n/100, n/10%10, n%10
+	bit := func(d, i uint16) uint16 { return d >> i & 1 }
+	b, c, d := bit(d1, 2), bit(d1, 1), bit(d1, 0)
+	f, g, h := bit(d2, 2), bit(d2, 1), bit(d2, 0)
+	j, k, m := bit(d3, 2), bit(d3, 1), bit(d3, 0)
+	var p, q, r, s, t, u, v, w, x, y uint16
+	r, u, y = d, h, m
+	switch a, e, i := d1 >= 8, d2 >= 8, d3 >= 8; {
+	case !a && !e && !i:
+		p, q, s, t, w, x = b, c, f, g, j, k
+	case !a && !e && i:
+		p, q, s, t, v = b, c, f, g, 1
+	case !a && e && !i:
+		p, q, s, t, v, x = b, c, j, k, 1, 1
+	case a && !e && !i:
+		p, q, s, t, v, w = j, k, f, g, 1, 1
+	case a && e && !i:
+		p, q, v, w, x = j, k, 1, 1, 1
+	case a && !e && i:
+		p, q, t, v, w, x = f, g, 1, 1, 1, 1
+	case !a && e && i:
+		p, q, s, v, w, x = b, c, 1, 1, 1, 1
+	default:
+		s, t, v, w, x = 1, 1, 1, 1, 1
+	}
+	return p<<9 | q<<8 | r<<7 | s<<6 | t<<5 | u<<4 | v<<3 | w<<2 | x<<1 | y
+}
+
+// decodeDeclet returns the three-digit number that the declet dpd encodes,
+// following Table 3.2 of IEEE 754-2008.
+func decodeDeclet(dpd uint16) uint16 {
+	pqr, stu, wxy := dpd>>7, dpd>>4&7, dpd&7
+	pq, st := dpd>>8, dpd>>5&3
+	r, u, y := dpd>>7&1, dpd>>4&1, dpd&1
+	var d1, d2, d3 uint16
+	switch {
+	case dpd>>3&1 == 0:
+		d1, d2, d3 = pqr, stu, wxy
+	case dpd>>1&3 == 0:
+		d1, d2, d3 = pqr, stu, 8+y
+	case dpd>>1&3 == 1:
+		d1, d2, d3 = pqr, 8+u, st<<1|y
+	case dpd>>1&3 == 2:
+		d1, d2, d3 = 8+r, stu, pq<<1|y
+	case st == 0:
+		d1, d2, d3 = 8+r, 8+u, pq<<1|y
+	case st == 1:
+		d1, d2, d3 = 8+r, pq<<1|u, 8+y
+	case st == 2:
+		d1, d2, d3 = pqr, 8+u, 8+y
+	default:
+		d1, d2, d3 = 8+r, 8+u, 8+y
+	}
+	return d1*100 + d2*10 + d3
+}
+
+// trailingBits returns the number of bits in the trailing significand of f,
+// and ecBits that of the exponent continuation field between it and the
+// combination field.
+func (f dpdFormat) trailingBits() int { return 10 * f.declets }
+func (f dpdFormat) ecBits() int       { return f.width - 6 - f.trailingBits() }
+
+// maxTrailing returns 10^(3*declets), the bound on the trailing digits.
+func (f dpdFormat) maxTrailing() *big.Int {
+	return bigPow10(3 * f.declets)
+}
+
+// lowBits returns the low n bits of x.
+func lowBits(x *big.Int, n int) *big.Int {
+	mask := new(big.Int).Lsh(big.NewInt(1), uint(n))
+	mask.Sub(mask, big.NewInt(1))
+	return mask.And(mask, x)
+}
+
+// encodeTrailing returns the declets encoding the low 3*declets digits of c,
+// and the digits above them.
+func (f dpdFormat) encodeTrailing(c *big.Int) (bits *big.Int, rest *big.Int) {
+	bits, rest = new(big.Int), new(big.Int).Set(c)
+	var r big.Int
+	thousand := big.NewInt(1000)
+	for i := 0; i < f.declets; i++ {
+		rest.QuoRem(rest, thousand, &r)
+		bits.Or(bits, new(big.Int).Lsh(big.NewInt(int64(binToDPD[r.Int64()])), uint(10*i)))
+	}
+	return bits, rest
+}
+
+// decodeTrailing returns the number encoded by the declets of bits.
+func (f dpdFormat) decodeTrailing(bits *big.Int) *big.Int {
+	c := new(big.Int)
+	thousand := big.NewInt(1000)
+	for i := f.declets - 1; i >= 0; i-- {
+		declet := new(big.Int).Rsh(bits, uint(10*i)).Uint64() & 0x3ff
+		c.Mul(c, thousand)
+		c.Add(c, big.NewInt(int64(dpdToBin[declet])))
+	}
+	return c
+}
+
+// special converts the encoding b of an infinity or NaN between BID and DPD:
+// infinities keep their sign and NaNs their sign, signaling bit and payload,
+// which conv converts from the trailing significand of b.
+func (f dpdFormat) special(b *big.Int, conv func(payload *big.Int) *big.Int) *big.Int {
+	top := new(big.Int).Rsh(b, uint(f.width-7)).Uint64()
+	if top>>1&0x1f == 0x1e {
+		return new(big.Int).Lsh(big.NewInt(int64(top&0x40|0x3c)), uint(f.width-7))
+	}
+	payload := conv(lowBits(b, f.trailingBits()))
+	r := new(big.Int).Lsh(big.NewInt(int64(top)), uint(f.width-7))
+	return r.Or(r, payload)
+}
+
+// isSpecial reports whether the encoding b, in either encoding, is an
+// infinity or a NaN.
+func (f dpdFormat) isSpecial(b *big.Int) bool {
+	comb := new(big.Int).Rsh(b, uint(f.width-6)).Uint64() & 0x1f
+	return comb>>1 == 0xf
+}
+
+// fromBID returns the DPD encoding of the value with the BID encoding b.
+func (f dpdFormat) fromBID(b *big.Int) *big.Int {
+	if f.isSpecial(b) {
+		return f.special(b, func(payload *big.Int) *big.Int {
+			// Payloads out of range are non-canonical and denote zero.
+			if payload.Cmp(f.maxTrailing()) >= 0 {
+				return new(big.Int)
+			}
+			bits, _ := f.encodeTrailing(payload)
+			return bits
+		})
+	}
+	neg := b.Bit(f.width-1) == 1
+	expBits := f.ecBits() + 2
+	var coeff *big.Int
+	var bexp uint64
+	if b.Bit(f.width-2) == 1 && b.Bit(f.width-3) == 1 {
+		n := f.width - 3 - expBits
+		bexp = lowBits(new(big.Int).Rsh(b, uint(n)), expBits).Uint64()
+		coeff = lowBits(b, n)
+		coeff.SetBit(coeff, n+2, 1)
+	} else {
+		n := f.width - 1 - expBits
+		bexp = lowBits(new(big.Int).Rsh(b, uint(n)), expBits).Uint64()
+		coeff = lowBits(b, n)
+	}
+	trailing, lead := f.encodeTrailing(coeff)
+	if lead.Cmp(bigTen) >= 0 {
+		// A non-canonical coefficient denotes zero.
+		trailing, lead = new(big.Int), new(big.Int)
+	}
+	d := lead.Uint64()
+	msb, cont := bexp>>f.ecBits(), lowBits(new(big.Int).SetUint64(bexp), f.ecBits())
+	var comb uint64
+	if d < 8 {
+		comb = msb<<3 | d
+	} else {
+		comb = 0x18 | msb<<1 | d&1
+	}
+	r := new(big.Int).SetUint64(comb)
+	if neg {
+		r.SetBit(r, 5, 1)
+	}
+	r.Lsh(r, uint(f.ecBits()))
+	r.Or(r, cont)
+	r.Lsh(r, uint(f.trailingBits()))
+	return r.Or(r, trailing)
+}
+
+// toBID returns the BID encoding of the value with the DPD encoding b.
+func (f dpdFormat) toBID(b *big.Int) *big.Int {
+	if f.isSpecial(b) {
+		return f.special(b, f.decodeTrailing)
+	}
+	neg := b.Bit(f.width-1) == 1
+	comb := new(big.Int).Rsh(b, uint(f.width-6)).Uint64() & 0x1f
+	var msb, d uint64
+	if comb>>3 == 3 {
+		msb, d = comb>>1&3, 8+comb&1
+	} else {
+		msb, d = comb>>3, comb&7
+	}
+	cont := lowBits(new(big.Int).Rsh(b, uint(f.trailingBits())), f.ecBits()).Uint64()
+	bexp := msb<<f.ecBits() | cont
+	coeff := new(big.Int).Mul(big.NewInt(int64(d)), f.maxTrailing())
+	coeff.Add(coeff, f.decodeTrailing(lowBits(b, f.trailingBits())))
+
+	expBits := f.ecBits() + 2
+	n := f.width - 1 - expBits
+	r := new(big.Int)
+	if neg {
+		r.SetBit(r, f.width-1, 1)
+	}
+	if coeff.BitLen() <= n {
+		r.Or(r, new(big.Int).Lsh(new(big.Int).SetUint64(bexp), uint(n)))
+		return r.Or(r, coeff)
+	}
+	// The coefficient needs the form with an implied 100 prefix.
+	n -= 2
+	r.Or(r, new(big.Int).Lsh(big.NewInt(3), uint(f.width-3)))
+	r.Or(r, new(big.Int).Lsh(new(big.Int).SetUint64(bexp), uint(n)))
+	return r.Or(r, lowBits(coeff, n))
+}
+
+// ToDPD returns the DPD interchange encoding of d. Non-canonical
+// coefficients are encoded as the zero they denote.
+func (d Dec32) ToDPD() uint32 {
+	return uint32(dpd32.fromBID(new(big.Int).SetUint64(uint64(d))).Uint64())
+}
+
+// FromDPD32 returns the decimal32 value with the DPD interchange encoding b.
+// Non-canonical declets decode as the standard specifies.
+func FromDPD32(b uint32) Dec32 {
+	return Dec32(dpd32.toBID(new(big.Int).SetUint64(uint64(b))).Uint64())
+}
+
+// EncodeDec32DPD is like EncodeDec32 but returns the DPD encoding of the
+// value.
+func EncodeDec32DPD(coeff int32, exp int8) (uint32, bool) {
+	d, ok := EncodeDec32(coeff, exp)
+	if !ok {
+		return uint32(failDec32), false
+	}
+	return d.ToDPD(), true
+}
+
+// DecodeDec32DPD is like Dec32.Decode but decodes a DPD encoding.
+func DecodeDec32DPD(b uint32) (coeff int32, exp int8, ok bool) {
+	return FromDPD32(b).Decode()
+}
+
+// ToDPD returns the DPD interchange encoding of d. Non-canonical
+// coefficients are encoded as the zero they denote.
+func (d Dec64) ToDPD() uint64 {
+	return dpd64.fromBID(new(big.Int).SetUint64(uint64(d))).Uint64()
+}
+
+// FromDPD64 returns the decimal64 value with the DPD interchange encoding b.
+func FromDPD64(b uint64) Dec64 {
+	return Dec64(dpd64.toBID(new(big.Int).SetUint64(b)).Uint64())
+}
+
+// EncodeDec64DPD is like EncodeDec64 but returns the DPD encoding of the
+// value.
+func EncodeDec64DPD(coeff int64, exp int16) (uint64, bool) {
+	d, ok := EncodeDec64(coeff, exp)
+	if !ok {
+		return uint64(failDec64), false
+	}
+	return d.ToDPD(), true
+}
+
+// DecodeDec64DPD is like Dec64.Decode but decodes a DPD encoding.
+func DecodeDec64DPD(b uint64) (coeff int64, exp int16, ok bool) {
+	return FromDPD64(b).Decode()
+}
+
+// ToDPD returns the high and low 64 bits of the DPD interchange encoding of
+// d. Non-canonical coefficients are encoded as the zero they denote.
+func (d Dec128) ToDPD() (hi, lo uint64) {
+	b := dpd128.fromBID(d.bigBits())
+	return new(big.Int).Rsh(b, 64).Uint64(), b.Uint64()
+}
+
+// FromDPD128 returns the decimal128 value whose DPD interchange encoding has
+// the high and low 64 bits hi and lo.
+func FromDPD128(hi, lo uint64) Dec128 {
+	b := dpd128.toBID(Dec128{hi, lo}.bigBits())
+	return Dec128{new(big.Int).Rsh(b, 64).Uint64(), b.Uint64()}
+}
+
+// EncodeDec128DPD is like EncodeDec128 but returns the high and low 64 bits
+// of the DPD encoding of the value.
+func EncodeDec128DPD(coeff *big.Int, exp int16) (hi, lo uint64, ok bool) {
+	d, ok := EncodeDec128(coeff, exp)
+	if !ok {
+		return failDec128.hi, failDec128.lo, false
+	}
+	hi, lo = d.ToDPD()
+	return hi, lo, true
+}
+
+// DecodeDec128DPD is like Dec128.Decode but decodes the DPD encoding with
+// high and low 64 bits hi and lo.
+func DecodeDec128DPD(hi, lo uint64) (coeff *big.Int, exp int16, ok bool) {
+	return FromDPD128(hi, lo).Decode()
+}
+
+// bigBits returns the encoding of d as an integer.
+func (d Dec128) bigBits() *big.Int {
+	b := new(big.Int).SetUint64(d.hi)
+	b.Lsh(b, 64)
+	return b.Or(b, new(big.Int).SetUint64(d.lo))
+}
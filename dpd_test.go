@@ -0,0 +1,175 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestDeclets(t *testing.T) {
+	seen := make(map[uint16]bool)
+	for n := uint16(0); n < 1000; n++ {
+		dpd := binToDPD[n]
+		if seen[dpd] {
+			t.Errorf("%d: duplicate declet %03x", n, dpd)
+		}
+		seen[dpd] = true
+		if m := dpdToBin[dpd]; m != n {
+			t.Errorf("%d: declet %03x decodes as %d", n, dpd, m)
+		}
+	}
+	testCases := []struct {
+		n   uint16
+		dpd uint16
+	}{
+		{0, 0x000}, {9, 0x009}, {10, 0x010}, {750, 0x3d0}, {999, 0x0ff}, {888, 0x06e}, {809, 0x02f},
+	}
+	for i, testCase := range testCases {
+		if dpd := binToDPD[testCase.n]; dpd != testCase.dpd {
+			t.Errorf("testCase #%d: expect %03x, got %03x", i, testCase.dpd, dpd)
+		}
+	}
+	// The non-canonical declets decode as the canonical one with pq = 00.
+	for _, dpd := range []uint16{0x16e, 0x26e, 0x36e, 0x1ff, 0x2ff, 0x3ff} {
+		if n := dpdToBin[dpd]; n != dpdToBin[dpd&0xff] {
+			t.Errorf("declet %03x: expect %d, got %d", dpd, dpdToBin[dpd&0xff], n)
+		}
+	}
+}
+
+func TestDPD32(t *testing.T) {
+	testCases := []struct {
+		d   Dec32
+		dpd uint32
+	}{
+		{mustEncode(t, 1, 0), 0x22500001},
+		{mustEncode(t, -750, -2), 0xa23003d0},
+		{mustEncode(t, 9999999, 90), 0x77f3fcff},
+		{mustEncode(t, 0, -101), 0x00000000},
+		{mustEncode(t, 8000000, -101), 0x60000000},
+		{pack32(true, 0, 0), 0xa2500000},
+		{inf32 | signMask, 0xf8000000},
+		{nan32, 0x7c000000},
+		{Dec32(snanMask | 123456), 0x7e028e56},
+	}
+	for i, testCase := range testCases {
+		if dpd := testCase.d.ToDPD(); dpd != testCase.dpd {
+			t.Errorf("testCase #%d: %v: expect %08x, got %08x", i, testCase.d, testCase.dpd, dpd)
+		}
+		if d := FromDPD32(testCase.dpd); d != testCase.d {
+			t.Errorf("testCase #%d: %08x: expect %x, got %x", i, testCase.dpd, uint32(testCase.d), uint32(d))
+		}
+	}
+
+	// Non-canonical encodings convert to the canonical encodings of their
+	// values.
+	if dpd := Dec32(0x6cbfffff).ToDPD(); dpd != 0x22500000 {
+		t.Errorf("expect non-canonical coefficient to encode as zero, got %08x", dpd)
+	}
+	if dpd := Dec32(nan32 | 0xfffff).ToDPD(); dpd != 0x7c000000 {
+		t.Errorf("expect out-of-range payload to be dropped, got %08x", dpd)
+	}
+	if d := FromDPD32(0x225003ff); d != mustEncode(t, 999, 0) {
+		t.Errorf("expect non-canonical declet to decode as 999, got %v", d)
+	}
+
+	if b, ok := EncodeDec32DPD(-750, -2); !ok || b != 0xa23003d0 {
+		t.Errorf("unexpected EncodeDec32DPD %08x %v", b, ok)
+	}
+	if _, ok := EncodeDec32DPD(10000000, 0); ok {
+		t.Errorf("expect EncodeDec32DPD to fail")
+	}
+	if coeff, exp, ok := DecodeDec32DPD(0xa23003d0); coeff != -750 || exp != -2 || !ok {
+		t.Errorf("unexpected DecodeDec32DPD %d %d %v", coeff, exp, ok)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		d := pack32(r.Intn(2) == 0, uint32(r.Intn(maxCoeff+1)), minExp+r.Intn(maxExp-minExp+1))
+		if got := FromDPD32(d.ToDPD()); got != d {
+			t.Fatalf("%v: round trip gives %v", d, got)
+		}
+	}
+}
+
+func TestDPD64(t *testing.T) {
+	testCases := []struct {
+		d   Dec64
+		dpd uint64
+	}{
+		{MustEncodeDec64(1, 0), 0x2238000000000001},
+		{MustEncodeDec64(-750, -2), 0xa2300000000003d0},
+		{MustEncodeDec64(9999999999999999, 369), 0x77fcff3fcff3fcff},
+		{MustEncodeDec64(0, -398), 0x0000000000000000},
+		{inf64, 0x7800000000000000},
+		{nan64 | signMask64, 0xfc00000000000000},
+	}
+	for i, testCase := range testCases {
+		if dpd := testCase.d.ToDPD(); dpd != testCase.dpd {
+			t.Errorf("testCase #%d: expect %016x, got %016x", i, testCase.dpd, dpd)
+		}
+		if d := FromDPD64(testCase.dpd); d != testCase.d {
+			t.Errorf("testCase #%d: %016x: expect %x, got %x", i, testCase.dpd, uint64(testCase.d), uint64(d))
+		}
+	}
+	if b, ok := EncodeDec64DPD(1, 0); !ok || b != 0x2238000000000001 {
+		t.Errorf("unexpected EncodeDec64DPD %016x %v", b, ok)
+	}
+	if coeff, exp, ok := DecodeDec64DPD(0xa2300000000003d0); coeff != -750 || exp != -2 || !ok {
+		t.Errorf("unexpected DecodeDec64DPD %d %d %v", coeff, exp, ok)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		d := pack64(r.Intn(2) == 0, uint64(r.Int63n(maxCoeff64+1)), minExp64+r.Intn(maxExp64-minExp64+1))
+		if got := FromDPD64(d.ToDPD()); got != d {
+			t.Fatalf("%x: round trip gives %x", uint64(d), uint64(got))
+		}
+	}
+}
+
+func TestDPD128(t *testing.T) {
+	testCases := []struct {
+		coeff  string
+		exp    int16
+		hi, lo uint64
+	}{
+		{"1", 0, 0x2208000000000000, 0x0000000000000001},
+		{"-750", -2, 0xa207800000000000, 0x00000000000003d0},
+		{"9999999999999999999999999999999999", 6111, 0x77ffcff3fcff3fcf, 0xf3fcff3fcff3fcff},
+	}
+	for i, testCase := range testCases {
+		coeff := mustBigInt(t, testCase.coeff)
+		d := MustEncodeDec128(coeff, testCase.exp)
+		if hi, lo := d.ToDPD(); hi != testCase.hi || lo != testCase.lo {
+			t.Errorf("testCase #%d: expect %016x%016x, got %016x%016x", i, testCase.hi, testCase.lo, hi, lo)
+		}
+		if got := FromDPD128(testCase.hi, testCase.lo); got != d {
+			t.Errorf("testCase #%d: expect %x, got %x", i, d, got)
+		}
+		hi, lo, ok := EncodeDec128DPD(coeff, testCase.exp)
+		if !ok || hi != testCase.hi || lo != testCase.lo {
+			t.Errorf("testCase #%d: unexpected EncodeDec128DPD %016x%016x %v", i, hi, lo, ok)
+		}
+		c, exp, ok := DecodeDec128DPD(hi, lo)
+		if !ok || c.Cmp(coeff) != 0 || exp != testCase.exp {
+			t.Errorf("testCase #%d: unexpected DecodeDec128DPD %v %d %v", i, c, exp, ok)
+		}
+	}
+	if hi, lo := nan128.ToDPD(); hi != nan128.hi || lo != 0 {
+		t.Errorf("unexpected NaN %016x%016x", hi, lo)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		coeff := new(big.Int).Rand(r, new(big.Int).Add(maxCoeff128, big.NewInt(1)))
+		d := pack128(r.Intn(2) == 0, coeff, minExp128+r.Intn(maxExp128-minExp128+1))
+		if got := FromDPD128(d.ToDPD()); got != d {
+			t.Fatalf("%x: round trip gives %x", d, got)
+		}
+	}
+}
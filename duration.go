@@ -0,0 +1,67 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"math/big"
+	"time"
+)
+
+// FromDuration returns the duration dur as a decimal count of the given unit,
+// such as time.Second or time.Hour, rounded to the nearest decimal32, ties to
+// even, and whether the result is exact. FromDuration(90*time.Minute,
+// time.Hour) is exactly 1.5. A unit that is not positive yields NaN.
+func FromDuration(dur, unit time.Duration) (Dec32, bool) {
+	if unit <= 0 {
+		return nan32, false
+	}
+	return durationQuo(dur, unit, format32.digits).dec32()
+}
+
+// FromDuration64 is like FromDuration, but rounds to the nearest decimal64,
+// so that quotients of up to 16 significant digits are exact: 1234567890123
+// nanoseconds in units of time.Second is 1234.567890123.
+func FromDuration64(dur, unit time.Duration) (Dec64, bool) {
+	if unit <= 0 {
+		return nan64, false
+	}
+	return durationQuo(dur, unit, format64.digits).dec64()
+}
+
+// durationQuo returns dur/unit as bigDec.quo does, so that rounding it to
+// prec digits is correct.
+func durationQuo(dur, unit time.Duration, prec int) *bigDec {
+	x := &bigDec{neg: dur < 0}
+	x.coeff.Abs(big.NewInt(int64(dur)))
+	y := &bigDec{}
+	y.coeff.SetInt64(int64(unit))
+	return new(bigDec).quo(x, y, prec)
+}
+
+// ToDuration returns the decimal, taken as a count of the given unit, as a
+// time.Duration. It returns false unless the value is a whole number of
+// nanoseconds within the range of time.Duration and unit is positive.
+func (d Dec32) ToDuration(unit time.Duration) (time.Duration, bool) {
+	if unit <= 0 || d.IsInf() || d.IsNaN() {
+		return 0, false
+	}
+	return toDuration(newBigDec32(d), unit)
+}
+
+// ToDuration is like Dec32.ToDuration, for decimal64 values.
+func (d Dec64) ToDuration(unit time.Duration) (time.Duration, bool) {
+	if unit <= 0 || d.IsInf() || d.IsNaN() {
+		return 0, false
+	}
+	return toDuration(newBigDec64(d), unit)
+}
+
+// toDuration returns the finite x counting units of unit as a duration.
+func toDuration(x *bigDec, unit time.Duration) (time.Duration, bool) {
+	u := &bigDec{}
+	u.coeff.SetInt64(int64(unit))
+	ns, ok := new(bigDec).mul(x, u).scaledInt64(0)
+	return time.Duration(ns), ok
+}
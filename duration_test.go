@@ -0,0 +1,97 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestFromDuration(t *testing.T) {
+	testCases := []struct {
+		dur, unit time.Duration
+		ref       string
+		exact     bool
+	}{
+		{1500 * time.Millisecond, time.Second, "1.5", true},
+		{90 * time.Minute, time.Hour, "1.5", true},
+		{-250 * time.Microsecond, time.Millisecond, "-0.25", true},
+		{time.Second, time.Millisecond, "1000", true},
+		{time.Nanosecond, time.Second, "1E-9", true},
+		{0, time.Second, "0", true},
+		{time.Minute, time.Hour, "0.01666667", false},
+		{2*time.Hour + 9, time.Second, "7200.000", false},
+		{time.Hour, 0, "NaN", false},
+	}
+	for i, testCase := range testCases {
+		d, exact := FromDuration(testCase.dur, testCase.unit)
+		if d.String() != testCase.ref || exact != testCase.exact {
+			t.Errorf("testCase #%d: expect %s %v, got %v %v", i, testCase.ref, testCase.exact, d, exact)
+		}
+	}
+}
+
+func TestToDuration(t *testing.T) {
+	testCases := []struct {
+		d    Dec32
+		unit time.Duration
+		ref  time.Duration
+		ok   bool
+	}{
+		{MustParseDec32("1.5"), time.Hour, 90 * time.Minute, true},
+		{MustParseDec32("-0.000000001"), time.Second, -time.Nanosecond, true},
+		{MustParseDec32("1E+3"), time.Millisecond, time.Second, true},
+		{MustParseDec32("0.5"), time.Nanosecond, 0, false},
+		{MustParseDec32("1E+10"), time.Second, 0, false},
+		{MustParseDec32("9.223372E+18"), time.Nanosecond, 9223372000000000000, true},
+		{MustParseDec32("Infinity"), time.Second, 0, false},
+		{MustParseDec32("1"), -time.Second, 0, false},
+	}
+	for i, testCase := range testCases {
+		dur, ok := testCase.d.ToDuration(testCase.unit)
+		if dur != testCase.ref || ok != testCase.ok {
+			t.Errorf("testCase #%d: expect %v %v, got %v %v", i, testCase.ref, testCase.ok, dur, ok)
+		}
+	}
+	if _, ok := MustParseDec32("9.223373E+18").ToDuration(time.Nanosecond); ok {
+		t.Errorf("expected overflow beyond %d", int64(math.MaxInt64))
+	}
+}
+
+func TestDuration64(t *testing.T) {
+	testCases := []struct {
+		dur, unit time.Duration
+		ref       string
+		exact     bool
+	}{
+		{1234567890123, time.Second, "1234.567890123", true},
+		{time.Minute, time.Hour, "0.01666666666666667", false},
+		{2*time.Hour + 9, time.Second, "7200.000000009", true},
+		{math.MaxInt64, time.Nanosecond, "9.223372036854776E+18", false},
+		{-250 * time.Microsecond, time.Millisecond, "-0.25", true},
+		{time.Hour, 0, "NaN", false},
+	}
+	for i, testCase := range testCases {
+		d, exact := FromDuration64(testCase.dur, testCase.unit)
+		if d.String() != testCase.ref || exact != testCase.exact {
+			t.Errorf("testCase #%d: expect %s %v, got %v %v", i, testCase.ref, testCase.exact, d, exact)
+		}
+		if !exact {
+			continue
+		}
+		if dur, ok := d.ToDuration(testCase.unit); !ok || dur != testCase.dur {
+			t.Errorf("testCase #%d: %v: expect %v, got %v %v", i, d, testCase.dur, dur, ok)
+		}
+	}
+	for i, d := range []Dec64{MustEncodeDec64(5, -1), MustEncodeDec64(1, 19), inf64, nan64} {
+		if dur, ok := d.ToDuration(time.Nanosecond); ok {
+			t.Errorf("testCase #%d: %v: expected failure, got %v", i, d, dur)
+		}
+	}
+	if _, ok := MustEncodeDec64(1, 0).ToDuration(-time.Second); ok {
+		t.Errorf("expected failure for negative unit")
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import "math/big"
+
+// emvAmountLen is the length of EMV numeric amount fields such as Amount,
+// Authorised (tag 9F02), which hold 12 BCD digits.
+const emvAmountLen = 6
+
+// FromEMVAmount converts an EMV "n 12" amount field, such as Amount,
+// Authorised (9F02) or Amount, Other (9F03), to a decimal. The field holds
+// 12 BCD digits, two per byte, counting units of the currency exponent
+// (5F36), so with exponent 2 the field 000000012345 is 123.45. It returns
+// false if b is not 6 bytes of valid BCD, or if the value cannot be
+// represented exactly in decimal32, in which case a well-formed value is
+// rounded to the nearest decimal32, ties to even.
+func FromEMVAmount(b []byte, exponent int) (Dec32, bool) {
+	x, ok := parseEMVAmount(b, exponent)
+	if !ok {
+		return failDec32, false
+	}
+	return x.dec32()
+}
+
+// FromEMVAmount64 is like FromEMVAmount, but converts the field to a
+// decimal64, which holds every 12-digit amount exactly.
+func FromEMVAmount64(b []byte, exponent int) (Dec64, bool) {
+	x, ok := parseEMVAmount(b, exponent)
+	if !ok {
+		return failDec64, false
+	}
+	return x.dec64()
+}
+
+// parseEMVAmount returns the value of the EMV amount field b with the given
+// currency exponent, reporting false if b is not 6 bytes of valid BCD.
+func parseEMVAmount(b []byte, exponent int) (*bigDec, bool) {
+	if len(b) != emvAmountLen {
+		return nil, false
+	}
+	var n uint64
+	for _, c := range b {
+		hi, lo := c>>4, c&0xf
+		if hi > 9 || lo > 9 {
+			return nil, false
+		}
+		n = n*100 + uint64(hi)*10 + uint64(lo)
+	}
+	x := &bigDec{exp: -exponent}
+	x.coeff.SetUint64(n)
+	return x, true
+}
+
+// EMVAmount returns the decimal value as an EMV "n 12" amount field of 12
+// BCD digits counting units of the currency exponent, as for FromEMVAmount.
+// It returns false if the value is negative or not finite, has more than
+// exponent fraction digits, or needs more than 12 digits.
+func (d Dec32) EMVAmount(exponent int) ([]byte, bool) {
+	if d.IsInf() || d.IsNaN() || (d.Sign() < 0 && !d.Zero()) {
+		return nil, false
+	}
+	return emvAmount(newBigDec32(d), exponent)
+}
+
+// EMVAmount is like Dec32.EMVAmount, for decimal64 values, which can hold
+// all 12 digits of the field.
+func (d Dec64) EMVAmount(exponent int) ([]byte, bool) {
+	if d.IsInf() || d.IsNaN() || (d.Sign() < 0 && !d.Zero()) {
+		return nil, false
+	}
+	return emvAmount(newBigDec64(d), exponent)
+}
+
+// emvAmount returns the finite, non-negative x as an EMV amount field with
+// the given currency exponent.
+func emvAmount(x *bigDec, exponent int) ([]byte, bool) {
+	var c big.Int
+	if !x.scaledInt(&c, exponent) || bigDigits(&c) > 2*emvAmountLen {
+		return nil, false
+	}
+	n := c.Uint64()
+	b := make([]byte, emvAmountLen)
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i] = byte(n%10) | byte(n/10%10)<<4
+		n /= 100
+	}
+	return b, true
+}
@@ -0,0 +1,98 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEMVAmount(t *testing.T) {
+	testCases := []struct {
+		d        Dec32
+		exponent int
+		ref      []byte
+	}{
+		{mustEncode(t, 12345, -2), 2, []byte{0, 0, 0, 0x01, 0x23, 0x45}},
+		{mustEncode(t, 15, -1), 2, []byte{0, 0, 0, 0, 0x01, 0x50}},
+		{mustEncode(t, 1000, 0), 0, []byte{0, 0, 0, 0, 0x10, 0x00}},
+		{mustEncode(t, 9999999, 5), 0, []byte{0x99, 0x99, 0x99, 0x90, 0x00, 0x00}},
+		{mustEncode(t, 0, 0), 2, []byte{0, 0, 0, 0, 0, 0}},
+		{mustEncode(t, 1234, -3), 3, []byte{0, 0, 0, 0, 0x12, 0x34}},
+		{mustEncode(t, 1234, -3), 2, nil},
+		{mustEncode(t, 1, 12), 0, nil},
+		{mustEncode(t, -1, 0), 2, nil},
+		{inf32, 2, nil},
+	}
+	for i, testCase := range testCases {
+		b, ok := testCase.d.EMVAmount(testCase.exponent)
+		if ok != (testCase.ref != nil) || !bytes.Equal(b, testCase.ref) {
+			t.Errorf("testCase #%d: %v: expect %x, got %x %v", i, testCase.d, testCase.ref, b, ok)
+		}
+		if !ok {
+			continue
+		}
+		d, ok := FromEMVAmount(b, testCase.exponent)
+		if !ok || cmp32(d, testCase.d) != 0 {
+			t.Errorf("testCase #%d: %x: expect %v, got %v %v", i, b, testCase.d, d, ok)
+		}
+	}
+}
+
+func TestFromEMVAmount(t *testing.T) {
+	testCases := []struct {
+		b        []byte
+		exponent int
+		ref      string
+		ok       bool
+	}{
+		{[]byte{0, 0, 0, 0x01, 0x23, 0x45}, 2, "123.45", true},
+		{[]byte{0, 0, 0, 0, 0, 0x05}, 2, "0.05", true},
+		{[]byte{0, 0, 0, 0, 0, 0}, 2, "0.00", true},
+		{[]byte{0x12, 0x34, 0x56, 0x78, 0x90, 0x12}, 2, "1.234568E+9", false},
+		{[]byte{0x10, 0, 0, 0, 0, 0}, 2, "1.000000E+9", true},
+		{[]byte{0, 0, 0, 0, 0, 0x0a}, 2, "", false},
+		{[]byte{0, 0, 0, 0, 0x01}, 2, "", false},
+	}
+	for i, testCase := range testCases {
+		d, ok := FromEMVAmount(testCase.b, testCase.exponent)
+		if ok != testCase.ok || testCase.ref != "" && d.String() != testCase.ref {
+			t.Errorf("testCase #%d: %x: expect %s %v, got %v %v", i, testCase.b, testCase.ref, testCase.ok, d, ok)
+		}
+	}
+}
+
+func TestEMVAmount64(t *testing.T) {
+	testCases := []struct {
+		b        []byte
+		exponent int
+		ref      string
+	}{
+		{[]byte{0x12, 0x34, 0x56, 0x78, 0x90, 0x12}, 2, "1234567890.12"},
+		{[]byte{0x99, 0x99, 0x99, 0x99, 0x99, 0x99}, 0, "999999999999"},
+		{[]byte{0x99, 0x99, 0x99, 0x99, 0x99, 0x99}, 3, "999999999.999"},
+		{[]byte{0, 0, 0, 0, 0, 0x05}, 2, "0.05"},
+		{[]byte{0, 0, 0, 0, 0, 0}, 2, "0.00"},
+	}
+	for i, testCase := range testCases {
+		d, ok := FromEMVAmount64(testCase.b, testCase.exponent)
+		if !ok || d.String() != testCase.ref {
+			t.Errorf("testCase #%d: %x: expect %s, got %v %v", i, testCase.b, testCase.ref, d, ok)
+			continue
+		}
+		if b, ok := d.EMVAmount(testCase.exponent); !ok || !bytes.Equal(b, testCase.b) {
+			t.Errorf("testCase #%d: %v: expect %x, got %x %v", i, d, testCase.b, b, ok)
+		}
+	}
+
+	if _, ok := FromEMVAmount64([]byte{0, 0, 0, 0, 0, 0x0a}, 2); ok {
+		t.Errorf("expected failure for invalid BCD")
+	}
+	for i, d := range []Dec64{MustEncodeDec64(1234, -3), MustEncodeDec64(1, 12), MustEncodeDec64(-1, 0), inf64, nan64} {
+		if b, ok := d.EMVAmount(2); ok {
+			t.Errorf("testCase #%d: %v: expected failure, got %x", i, d, b)
+		}
+	}
+}
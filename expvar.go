@@ -0,0 +1,48 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"expvar"
+	"strconv"
+	"sync/atomic"
+)
+
+// Dec32Var is a decimal32 variable that satisfies the expvar.Var interface.
+// It exports its value as a JSON string holding the canonical string form,
+// so that consumers of the expvar endpoint see the exact decimal rather than
+// a binary floating-point approximation. Its zero value holds zero with
+// exponent 0, published as "0"; it is safe for concurrent use.
+type Dec32Var struct {
+	// v holds the encoding XOR varZero, so that the zero value of v is
+	// 0E+0 rather than the 0E-101 of a zero Dec32.
+	v atomic.Uint32
+}
+
+// varZero is the encoding of 0E+0.
+const varZero = Dec32(expBias << smallExpOffset)
+
+// NewDec32Var creates a new Dec32Var and publishes it under the given name
+// with expvar.Publish.
+func NewDec32Var(name string) *Dec32Var {
+	v := new(Dec32Var)
+	expvar.Publish(name, v)
+	return v
+}
+
+// Value returns the current value of the variable.
+func (v *Dec32Var) Value() Dec32 {
+	return Dec32(v.v.Load()) ^ varZero
+}
+
+// Set sets the variable to d.
+func (v *Dec32Var) Set(d Dec32) {
+	v.v.Store(uint32(d ^ varZero))
+}
+
+// String implements expvar.Var, returning the value as a quoted JSON string.
+func (v *Dec32Var) String() string {
+	return strconv.Quote(v.Value().String())
+}
@@ -0,0 +1,47 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+)
+
+func TestDec32Var(t *testing.T) {
+	v := NewDec32Var("TestDec32Var")
+	if expvar.Get("TestDec32Var") != v {
+		t.Fatalf("variable not published")
+	}
+	v.Set(mustEncode(t, 1050, -2))
+	if got := v.Value(); got != mustEncode(t, 1050, -2) {
+		t.Errorf("unexpected value %x", uint32(got))
+	}
+	var s string
+	if err := json.Unmarshal([]byte(v.String()), &s); err != nil {
+		t.Fatalf("invalid JSON %q: %v", v.String(), err)
+	}
+	if s != "10.50" {
+		t.Errorf("expect \"10.50\", got %q", s)
+	}
+	v.Set(inf32 | signMask)
+	if v.String() != `"-Infinity"` {
+		t.Errorf("unexpected %s", v.String())
+	}
+}
+
+func TestDec32VarZero(t *testing.T) {
+	var v Dec32Var
+	if got := v.Value(); got != pack32(false, 0, 0) {
+		t.Errorf("expect 0E+0, got %x", uint32(got))
+	}
+	if v.String() != `"0"` {
+		t.Errorf("expect \"0\", got %s", v.String())
+	}
+	v.Set(Dec32(0))
+	if got := v.Value(); got != Dec32(0) || v.String() != `"0E-101"` {
+		t.Errorf("expect 0E-101 kept, got %x %s", uint32(got), v.String())
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"errors"
+	"strconv"
+)
+
+// fhirMaxFraction is the largest number of fraction digits the FHIR decimal
+// regular expression allows in plain notation.
+const fhirMaxFraction = 17
+
+var errFHIRPrecision = errors.New("more significant digits than decimal32 holds")
+
+// FormatFHIR returns d in the form of an HL7 FHIR decimal, keeping its
+// precision as FHIR requires: trailing zeros are written, so 0.010 and 0.01
+// stay distinct. Values with a non-positive exponent of at most 17 fraction
+// digits are written in plain notation; others, such as 1.2E+3, whose plain
+// form would add insignificant digits, use exponent notation, which the
+// FHIR syntax permits. It returns false if d is not finite.
+func (d Dec32) FormatFHIR() (string, bool) {
+	if d.IsInf() || d.IsNaN() {
+		return "", false
+	}
+	neg, coeff, exp := d.unpack()
+	var buf [24]byte
+	b := buf[:0]
+	if neg {
+		b = append(b, '-')
+	}
+	var digits [10]byte
+	c := strconv.AppendUint(digits[:0], uint64(coeff), 10)
+	if exp <= 0 && -exp <= fhirMaxFraction {
+		return string(appendPlainDigits(b, c, exp)), true
+	}
+	return string(appendExponential(b, c, exp)), true
+}
+
+// ParseFHIR parses s as an HL7 FHIR decimal, a JSON number such as "0.010"
+// or "-2.5e3", keeping the exponent it implies so that trailing zeros are
+// preserved. Because FHIR treats the precision of a decimal as significant,
+// values whose precision decimal32 cannot hold, with more than seven
+// significant digits or an exponent out of range, are rejected rather than
+// rounded or padded. err.Err is strconv.ErrRange for values too large in
+// magnitude and strconv.ErrSyntax for strings outside the FHIR syntax. The
+// errors have concrete type *strconv.NumError.
+func ParseFHIR(s string) (Dec32, error) {
+	if !validFHIR(s) {
+		return nan32, &strconv.NumError{Func: "ParseFHIR", Num: s, Err: strconv.ErrSyntax}
+	}
+	x, _, _ := parseDecimal(s)
+	d, _ := x.dec32()
+	if d.IsInf() {
+		return d, &strconv.NumError{Func: "ParseFHIR", Num: s, Err: strconv.ErrRange}
+	}
+	// The precision is kept exactly when the exponent is.
+	if _, _, exp := d.unpack(); exp != x.exp {
+		return d, &strconv.NumError{Func: "ParseFHIR", Num: s, Err: errFHIRPrecision}
+	}
+	return d, nil
+}
+
+// validFHIR reports whether s matches the FHIR decimal syntax,
+// -?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?, which is that of JSON
+// numbers.
+func validFHIR(s string) bool {
+	i := 0
+	if i < len(s) && s[i] == '-' {
+		i++
+	}
+	digits := func() int {
+		j := i
+		for i < len(s) && '0' <= s[i] && s[i] <= '9' {
+			i++
+		}
+		return i - j
+	}
+	start := i
+	if n := digits(); n == 0 || n > 1 && s[start] == '0' {
+		return false
+	}
+	if i < len(s) && s[i] == '.' {
+		i++
+		if digits() == 0 {
+			return false
+		}
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		if i < len(s) && (s[i] == '+' || s[i] == '-') {
+			i++
+		}
+		if digits() == 0 {
+			return false
+		}
+	}
+	return i == len(s)
+}
+
+// FHIRDecimal is a decimal that is marshaled to and from JSON as a FHIR
+// decimal: a bare JSON number keeping its precision, as written by
+// FormatFHIR and read by ParseFHIR.
+type FHIRDecimal Dec32
+
+// MarshalJSON implements the json.Marshaler interface. It returns an error
+// for values that are not finite, which JSON cannot represent.
+func (f FHIRDecimal) MarshalJSON() ([]byte, error) {
+	s, ok := Dec32(f).FormatFHIR()
+	if !ok {
+		return nil, errors.New("decimal: " + Dec32(f).String() + " is not a FHIR decimal")
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. The JSON value
+// must be a number; null leaves f unchanged, as is the convention.
+func (f *FHIRDecimal) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+	d, err := ParseFHIR(string(b))
+	if err != nil {
+		return err
+	}
+	*f = FHIRDecimal(d)
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestFormatFHIR(t *testing.T) {
+	testCases := []struct {
+		d   Dec32
+		ref string
+		ok  bool
+	}{
+		{mustEncode(t, 10, -3), "0.010", true},
+		{mustEncode(t, 1, -2), "0.01", true},
+		{mustEncode(t, -12345, -2), "-123.45", true},
+		{mustEncode(t, 7, 0), "7", true},
+		{mustEncode(t, 1, -17), "0.00000000000000001", true},
+		{mustEncode(t, 1, -18), "1E-18", true},
+		{mustEncode(t, 12, 2), "1.2E+3", true},
+		{mustEncode(t, 0, 0) | signMask, "-0", true},
+		{inf32, "", false},
+		{nan32, "", false},
+	}
+	for i, testCase := range testCases {
+		s, ok := testCase.d.FormatFHIR()
+		if s != testCase.ref || ok != testCase.ok {
+			t.Errorf("testCase #%d: %v: expect %q %v, got %q %v", i, testCase.d, testCase.ref, testCase.ok, s, ok)
+		}
+		if !ok {
+			continue
+		}
+		if d, err := ParseFHIR(s); err != nil || d != testCase.d {
+			t.Errorf("testCase #%d: %q: expect %v, got %v %v", i, s, testCase.d, d, err)
+		}
+	}
+}
+
+func TestParseFHIR(t *testing.T) {
+	testCases := []struct {
+		s   string
+		ref Dec32
+		err error
+	}{
+		{"0.010", mustEncode(t, 10, -3), nil},
+		{"-2.5e3", mustEncode(t, -25, 2), nil},
+		{"1E-2", mustEncode(t, 1, -2), nil},
+		{"1.2345670", 0, errFHIRPrecision},
+		{"1e97", 0, strconv.ErrRange},
+		{"1e96", 0, errFHIRPrecision},
+		{"0e-102", 0, errFHIRPrecision},
+		{"+1", 0, strconv.ErrSyntax},
+		{"01", 0, strconv.ErrSyntax},
+		{".5", 0, strconv.ErrSyntax},
+		{"1.", 0, strconv.ErrSyntax},
+		{"1e", 0, strconv.ErrSyntax},
+		{"NaN", 0, strconv.ErrSyntax},
+		{"", 0, strconv.ErrSyntax},
+	}
+	for i, testCase := range testCases {
+		d, err := ParseFHIR(testCase.s)
+		if !errors.Is(err, testCase.err) || err == nil && d != testCase.ref {
+			t.Errorf("testCase #%d: %q: expect %v %v, got %v %v", i, testCase.s, testCase.ref, testCase.err, d, err)
+		}
+	}
+}
+
+func TestFHIRDecimalJSON(t *testing.T) {
+	var obs struct {
+		Value FHIRDecimal `json:"value"`
+	}
+	if err := json.Unmarshal([]byte(`{"value": 98.60}`), &obs); err != nil {
+		t.Fatal(err)
+	}
+	if d := Dec32(obs.Value); d != mustEncode(t, 9860, -2) {
+		t.Errorf("expect 98.60, got %v", d)
+	}
+	b, err := json.Marshal(obs)
+	if err != nil || string(b) != `{"value":98.60}` {
+		t.Errorf("unexpected %s %v", b, err)
+	}
+	if err := json.Unmarshal([]byte(`{"value": "98.60"}`), &obs); err == nil {
+		t.Errorf("expect strings rejected")
+	}
+	if _, err := json.Marshal(FHIRDecimal(nan32)); err == nil {
+		t.Errorf("expect NaN rejected")
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+// NetPresentValue returns the net present value of the cash flows at the
+// periodic discount rate rate, as the spreadsheet function NPV computes it:
+//
+//	cashflows[0]/(1+rate) + cashflows[1]/(1+rate)^2 + ...
+//
+// so that the first flow is discounted by one period; add an initial flow
+// at time zero to the result separately. The sum is computed exactly and
+// rounded once to the nearest decimal32, ties to even. NetPresentValue
+// returns zero if there are no cash flows, and NaN if the rate is -1 or any
+// value is NaN or infinite.
+func NetPresentValue(rate Dec32, cashflows []Dec32) Dec32 {
+	flows, ok := bigFlows(cashflows)
+	if !ok || rate.IsNaN() || rate.IsInf() {
+		return nan32
+	}
+	if len(flows) == 0 {
+		return pack32(false, 0, 0)
+	}
+	x := new(bigDec).add(newBigDec32(rate), newBigDecInt64(1))
+	if x.isZero() {
+		return nan32
+	}
+	// The sum is g(x) / x^n, where g is evaluated at x = 1+rate with the
+	// first flow as the leading coefficient.
+	g, _ := flowPoly(flows, x, false)
+	den := newBigDecInt64(1)
+	for range flows {
+		den.mul(den, x)
+	}
+	d, _ := new(bigDec).quo(g, den, 7).dec32()
+	return d
+}
+
+// irrDigits is the working precision in significant digits of the Newton
+// iterations of InternalRateOfReturn, and irrIterations the limit on their
+// number.
+const (
+	irrDigits     = 24
+	irrIterations = 50
+)
+
+// InternalRateOfReturn returns the internal rate of return of the cash flows,
+// the periodic rate at which their net present value is zero, as the
+// spreadsheet function IRR computes it: the first flow is at time zero and
+// is not discounted. It reports false if there is no such rate or it was not
+// found.
+//
+// The rate is found by Newton's method starting from guess, or from 0.1 if
+// guess is NaN, with the iterates carried to 24 significant digits, for at
+// most 50 iterations until they agree to 20 digits. The candidate is then
+// rounded to the nearest decimal32, ties to even, and accepted only if the
+// exact net present value changes sign, or is zero, within half a unit in
+// the last place of it, so that the result is the decimal32 value nearest a
+// root. It must also be greater than -1, and is returned with trailing zeros
+// removed, so that a rate of exactly 10% is 0.1. Cash flows that change sign
+// more than once can have several roots; which is found depends on guess.
+//
+// InternalRateOfReturn reports false if there are fewer than two cash flows,
+// if they are not of both signs, or if any is NaN or infinite.
+func InternalRateOfReturn(cashflows []Dec32, guess Dec32) (Dec32, bool) {
+	flows, ok := bigFlows(cashflows)
+	if !ok || len(flows) < 2 {
+		return nan32, false
+	}
+	pos, neg := false, false
+	for _, v := range flows {
+		pos = pos || !v.isZero() && !v.neg
+		neg = neg || !v.isZero() && v.neg
+	}
+	if !pos || !neg {
+		return nan32, false
+	}
+	if guess.IsNaN() || guess.IsInf() {
+		guess = pack32(false, 1, -1)
+	}
+	one := newBigDecInt64(1)
+	// Newton's method on x = 1+rate, as the net present value discounted to
+	// the last flow is the polynomial g(x).
+	x := new(bigDec).add(newBigDec32(guess), one)
+	converged := false
+	for i := 0; i < irrIterations && !converged; i++ {
+		g, dg := flowPoly(flows, x, true)
+		if dg.isZero() {
+			return nan32, false
+		}
+		next := new(bigDec).sub(x, new(bigDec).quo(g, dg, irrDigits))
+		next.roundDigits(irrDigits, ToNearestEven)
+		converged = sameDigits(x, next, irrDigits-4)
+		x = next
+	}
+	if !converged {
+		return nan32, false
+	}
+	r, _ := new(bigDec).sub(x, one).dec32()
+	if r.IsInf() || cmp32(r, pack32(true, 1, 0)) <= 0 {
+		return nan32, false
+	}
+
+	// Check that a root lies within half an ulp of r.
+	c := newBigDec32(r)
+	half := &bigDec{exp: c.exp - 1}
+	half.coeff.SetInt64(5)
+	lo, hi := new(bigDec).sub(c, half), new(bigDec).add(c, half)
+	lo.add(lo, one)
+	hi.add(hi, one)
+	glo, _ := flowPoly(flows, lo, false)
+	ghi, _ := flowPoly(flows, hi, false)
+	if glo.isZero() || ghi.isZero() || glo.neg != ghi.neg {
+		return r.reduce(), true
+	}
+	return nan32, false
+}
+
+// bigFlows returns the exact values of cashflows, and false if any is NaN or
+// infinite.
+func bigFlows(cashflows []Dec32) ([]*bigDec, bool) {
+	flows := make([]*bigDec, len(cashflows))
+	for i, v := range cashflows {
+		if v.IsNaN() || v.IsInf() {
+			return nil, false
+		}
+		flows[i] = newBigDec32(v)
+	}
+	return flows, true
+}
+
+// flowPoly evaluates exactly, by Horner's rule, the polynomial in x whose
+// coefficients are flows, leading coefficient first, and, if deriv, its
+// derivative.
+func flowPoly(flows []*bigDec, x *bigDec, deriv bool) (g, dg *bigDec) {
+	g, dg = new(bigDec), new(bigDec)
+	for _, v := range flows {
+		if deriv {
+			dg.mul(dg, x)
+			dg.add(dg, g)
+		}
+		g.mul(g, x)
+		g.add(g, v)
+	}
+	return g, dg
+}
+
+// sameDigits reports whether x and y agree when rounded to n significant
+// digits.
+func sameDigits(x, y *bigDec, n int) bool {
+	a := new(bigDec).setSigned(x.signed(), x.exp, x.neg)
+	b := new(bigDec).setSigned(y.signed(), y.exp, y.neg)
+	a.roundDigits(n, ToNearestEven)
+	b.roundDigits(n, ToNearestEven)
+	return a.cmp(b) == 0
+}
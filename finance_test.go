@@ -0,0 +1,70 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import "testing"
+
+func TestNetPresentValue(t *testing.T) {
+	testCases := []struct {
+		rate      Dec32
+		cashflows []Dec32
+		ref       Dec32
+	}{
+		{
+			mustEncode(t, 1, -1),
+			[]Dec32{mustEncode(t, -10000, 0), mustEncode(t, 3000, 0), mustEncode(t, 4200, 0), mustEncode(t, 6800, 0)},
+			mustEncode(t, 1188443, -3),
+		},
+		{
+			mustEncode(t, 8, -2),
+			[]Dec32{mustEncode(t, 8000, 0), mustEncode(t, 9200, 0), mustEncode(t, 10000, 0), mustEncode(t, 12000, 0), mustEncode(t, 14500, 0)},
+			mustEncode(t, 4192206, -2),
+		},
+		// Exact values keep the exponent of the quotient.
+		{mustEncode(t, 1, -1), []Dec32{mustEncode(t, 110, 0), mustEncode(t, 121, 0)}, mustEncode(t, 200, 0)},
+		{mustEncode(t, 0, 0), []Dec32{mustEncode(t, 150, -2), mustEncode(t, -25, -1)}, mustEncode(t, -100, -2)},
+		{mustEncode(t, 5, -2), nil, mustEncode(t, 0, 0)},
+		{mustEncode(t, -1, 0), []Dec32{mustEncode(t, 1, 0)}, nan32},
+		{nan32, []Dec32{mustEncode(t, 1, 0)}, nan32},
+		{mustEncode(t, 1, -1), []Dec32{inf32}, nan32},
+	}
+	for i, testCase := range testCases {
+		if v := NetPresentValue(testCase.rate, testCase.cashflows); v != testCase.ref {
+			t.Errorf("testCase #%d: expect %v, got %v", i, testCase.ref, v)
+		}
+	}
+}
+
+func TestInternalRateOfReturn(t *testing.T) {
+	flows := func(vs ...int32) []Dec32 {
+		ds := make([]Dec32, len(vs))
+		for i, v := range vs {
+			ds[i] = mustEncode(t, v, 0)
+		}
+		return ds
+	}
+	testCases := []struct {
+		cashflows []Dec32
+		guess     Dec32
+		ref       Dec32
+		ok        bool
+	}{
+		{flows(-70000, 12000, 15000, 18000, 21000, 26000), nan32, mustEncode(t, 8663095, -8), true},
+		{flows(-70000, 12000, 15000, 18000, 21000), nan32, mustEncode(t, -2124485, -8), true},
+		{flows(-70000, 12000, 15000, 18000, 21000), mustEncode(t, -1, -1), mustEncode(t, -2124485, -8), true},
+		{flows(-100, 110), nan32, mustEncode(t, 1, -1), true},
+		{flows(-100, 0, 121), mustEncode(t, 5, -1), mustEncode(t, 1, -1), true},
+		{flows(-1000, 1500), nan32, mustEncode(t, 5, -1), true},
+		{flows(100, 110), nan32, nan32, false},
+		{flows(-100), nan32, nan32, false},
+		{[]Dec32{mustEncode(t, -1, 0), nan32}, nan32, nan32, false},
+	}
+	for i, testCase := range testCases {
+		r, ok := InternalRateOfReturn(testCase.cashflows, testCase.guess)
+		if r != testCase.ref || ok != testCase.ok {
+			t.Errorf("testCase #%d: expect %v %v, got %v %v", i, testCase.ref, testCase.ok, r, ok)
+		}
+	}
+}
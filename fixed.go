@@ -0,0 +1,140 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+// A FixedScale names the number of fraction digits of the values of a Fixed
+// type. Scale must return a constant between -90 and 101, so that the
+// exponent of the values is within the decimal32 range.
+type FixedScale interface {
+	Scale() int
+}
+
+// Common fixed scales. Other scales can be defined in the same way:
+//
+//	type Mills struct{}
+//
+//	func (Mills) Scale() int { return 3 }
+type (
+	Scale0 struct{}
+	Scale1 struct{}
+	Scale2 struct{}
+	Scale3 struct{}
+	Scale4 struct{}
+)
+
+func (Scale0) Scale() int { return 0 }
+func (Scale1) Scale() int { return 1 }
+func (Scale2) Scale() int { return 2 }
+func (Scale3) Scale() int { return 3 }
+func (Scale4) Scale() int { return 4 }
+
+// Fixed is a finite decimal32 value that always has the scale given by S,
+// that is the exponent -S.Scale(), so that an invariant such as "amounts
+// have two decimal places" is carried by the type Fixed[Scale2]. Values are
+// quantized when constructed, and the results of arithmetic are rounded back
+// to the scale. The zero value is zero at the scale.
+type Fixed[S FixedScale] struct {
+	c int32 // signed coefficient, at most 7 digits
+}
+
+// fixedScale returns the scale of S, panicking if it is out of range.
+func fixedScale[S FixedScale]() int {
+	var s S
+	scale := s.Scale()
+	if -scale < minExp || -scale > maxExp {
+		panic("decimal: Fixed scale out of range")
+	}
+	return scale
+}
+
+// NewFixed returns d quantized to the scale of S, rounding with the given
+// mode, so that NewFixed[Scale2] of 1.005 is 1.00 ties to even. It returns
+// false if d is not finite or the quantized value needs more than seven
+// digits.
+func NewFixed[S FixedScale](d Dec32, mode RoundingMode) (Fixed[S], bool) {
+	if d.IsInf() || d.IsNaN() {
+		return Fixed[S]{}, false
+	}
+	return fixedOf[S](newBigDec32(d), mode)
+}
+
+// fixedOf rounds x to the scale of S.
+func fixedOf[S FixedScale](x *bigDec, mode RoundingMode) (Fixed[S], bool) {
+	x.rescaleMode(-fixedScale[S](), mode)
+	if bigDigits(&x.coeff) > 7 {
+		return Fixed[S]{}, false
+	}
+	c := int32(x.coeff.Int64())
+	if x.neg {
+		c = -c
+	}
+	return Fixed[S]{c}, true
+}
+
+// Dec32 returns the value of f, with exponent -S.Scale().
+func (f Fixed[S]) Dec32() Dec32 {
+	c := f.c
+	if c < 0 {
+		c = -c
+	}
+	return pack32(f.c < 0, uint32(c), -fixedScale[S]())
+}
+
+// String returns the value of f in plain notation, with exactly S.Scale()
+// fraction digits.
+func (f Fixed[S]) String() string {
+	return f.Dec32().PlainString()
+}
+
+// Add returns the exact sum f+g, and false if it needs more than seven
+// digits.
+func (f Fixed[S]) Add(g Fixed[S]) (Fixed[S], bool) {
+	return fixedInt[S](int64(f.c) + int64(g.c))
+}
+
+// Sub returns the exact difference f-g, and false if it needs more than
+// seven digits.
+func (f Fixed[S]) Sub(g Fixed[S]) (Fixed[S], bool) {
+	return fixedInt[S](int64(f.c) - int64(g.c))
+}
+
+func fixedInt[S FixedScale](c int64) (Fixed[S], bool) {
+	if c > maxCoeff || c < -maxCoeff {
+		return Fixed[S]{}, false
+	}
+	return Fixed[S]{int32(c)}, true
+}
+
+// Mul returns the product f*g rounded once to the scale with the given mode,
+// and false if it needs more than seven digits.
+func (f Fixed[S]) Mul(g Fixed[S], mode RoundingMode) (Fixed[S], bool) {
+	return fixedOf[S](new(bigDec).mul(newBigDec32(f.Dec32()), newBigDec32(g.Dec32())), mode)
+}
+
+// Div returns the quotient f/g rounded once to the scale with the given
+// mode, and false if g is zero or the quotient needs more than seven digits.
+func (f Fixed[S]) Div(g Fixed[S], mode RoundingMode) (Fixed[S], bool) {
+	q := f.Dec32().DivQuantize(g.Dec32(), fixedScale[S](), mode)
+	if q.IsNaN() {
+		return Fixed[S]{}, false
+	}
+	return NewFixed[S](q, mode)
+}
+
+// Neg returns -f.
+func (f Fixed[S]) Neg() Fixed[S] {
+	return Fixed[S]{-f.c}
+}
+
+// Cmp compares f and g, returning -1, 0 or +1.
+func (f Fixed[S]) Cmp(g Fixed[S]) int {
+	switch {
+	case f.c < g.c:
+		return -1
+	case f.c > g.c:
+		return 1
+	}
+	return 0
+}
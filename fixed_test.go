@@ -0,0 +1,92 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import "testing"
+
+type scaleHundreds struct{}
+
+func (scaleHundreds) Scale() int { return -2 }
+
+func TestNewFixed(t *testing.T) {
+	testCases := []struct {
+		d    Dec32
+		mode RoundingMode
+		ref  string
+		ok   bool
+	}{
+		{mustEncode(t, 15, -1), ToNearestEven, "1.50", true},
+		{mustEncode(t, 3, 0), ToNearestEven, "3.00", true},
+		{mustEncode(t, 1005, -3), ToNearestEven, "1.00", true},
+		{mustEncode(t, 1005, -3), ToNearestAway, "1.01", true},
+		{mustEncode(t, -1005, -3), ToNegativeInf, "-1.01", true},
+		{mustEncode(t, 99999, 0), ToNearestEven, "99999.00", true},
+		{mustEncode(t, 100000, 0), ToNearestEven, "", false},
+		{inf32, ToNearestEven, "", false},
+		{nan32, ToNearestEven, "", false},
+	}
+	for i, testCase := range testCases {
+		f, ok := NewFixed[Scale2](testCase.d, testCase.mode)
+		if ok != testCase.ok || (ok && f.String() != testCase.ref) {
+			t.Errorf("testCase #%d: expect %q %v, got %q %v", i, testCase.ref, testCase.ok, f, ok)
+		}
+	}
+
+	var zero Fixed[Scale2]
+	if d := zero.Dec32(); d != mustEncode(t, 0, -2) {
+		t.Errorf("unexpected zero value %v", d)
+	}
+	if f, ok := NewFixed[scaleHundreds](mustEncode(t, 12345, 0), ToNearestEven); !ok || f.Dec32() != mustEncode(t, 123, 2) {
+		t.Errorf("unexpected %v %v", f.Dec32(), ok)
+	}
+}
+
+func TestFixedArith(t *testing.T) {
+	fixed := func(s string) Fixed[Scale2] {
+		f, ok := NewFixed[Scale2](MustParseDec32(s), ToNearestEven)
+		if !ok {
+			t.Fatalf("cannot make %s", s)
+		}
+		return f
+	}
+	check := func(op string, f Fixed[Scale2], ok bool, ref string) {
+		t.Helper()
+		if ref == "" {
+			if ok {
+				t.Errorf("%s: expect failure, got %v", op, f)
+			}
+		} else if !ok || f.String() != ref {
+			t.Errorf("%s: expect %s, got %v %v", op, ref, f, ok)
+		}
+	}
+	a, b := fixed("10.25"), fixed("3")
+	f, ok := a.Add(b)
+	check("add", f, ok, "13.25")
+	f, ok = a.Sub(b)
+	check("sub", f, ok, "7.25")
+	f, ok = b.Sub(a)
+	check("sub", f, ok, "-7.25")
+	f, ok = fixed("99999.99").Add(fixed("0.01"))
+	check("add overflow", f, ok, "")
+	f, ok = a.Mul(b, ToNearestEven)
+	check("mul", f, ok, "30.75")
+	f, ok = a.Mul(fixed("0.5"), ToNearestEven)
+	check("mul tie", f, ok, "5.12")
+	f, ok = a.Mul(fixed("0.5"), ToNearestAway)
+	check("mul tie", f, ok, "5.13")
+	f, ok = a.Div(b, ToNearestEven)
+	check("div", f, ok, "3.42")
+	f, ok = a.Div(b, ToPositiveInf)
+	check("div", f, ok, "3.42")
+	f, ok = a.Neg().Div(b, ToNegativeInf)
+	check("div", f, ok, "-3.42")
+	f, ok = a.Div(fixed("0.01"), ToNearestEven)
+	check("div", f, ok, "1025.00")
+	f, ok = a.Div(fixed("0"), ToNearestEven)
+	check("div by zero", f, ok, "")
+	if a.Cmp(b) != 1 || b.Cmp(a) != -1 || a.Cmp(fixed("10.250")) != 0 {
+		t.Errorf("unexpected comparison")
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Flags is a set of the IEEE 754 exception flags that operations raise. The
+// operations of a Context add the exceptions they raise to its Flags, which
+// are sticky: they stay set until the caller clears them, so that a sequence
+// of operations can be checked once at its end.
+type Flags uint8
+
+const (
+	// Inexact is raised when a result is rounded, discarding nonzero
+	// digits, or overflows.
+	Inexact Flags = 1 << iota
+	// Overflow is raised when a rounded result is too large in magnitude
+	// to represent, whether it becomes an infinity or the largest finite
+	// value.
+	Overflow
+	// Underflow is raised when a result is both inexact and tiny, with a
+	// nonzero exact value of magnitude below 1E-95, the smallest normal
+	// decimal32 value, so that it is rounded to a subnormal or zero.
+	Underflow
+	// Invalid is raised when an operation has no useful result and gives
+	// a NaN, as Inf-Inf, 0*Inf, 0/0 and Inf/Inf do, and when an operand is
+	// a signaling NaN.
+	Invalid
+	// DivisionByZero is raised when a finite nonzero value is divided by
+	// zero, giving an infinity.
+	DivisionByZero
+	// Clamped is raised when the exponent of a result is altered to fit
+	// the format: a zero whose exponent is out of range, a coefficient
+	// padded with zeros to bring a large exponent down to 90, or a
+	// subnormal result rounded to zero. A saturating Context also raises
+	// it on replacing an infinite result with the largest finite value.
+	Clamped
+)
+
+var flagNames = [...]string{"Inexact", "Overflow", "Underflow", "Invalid", "DivisionByZero", "Clamped"}
+
+// String returns the names of the flags in f separated by '|', such as
+// "Inexact|Overflow", or "0" if f is empty.
+func (f Flags) String() string {
+	if f == 0 {
+		return "0"
+	}
+	var names []string
+	for i, name := range flagNames {
+		if f&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	if rest := f &^ (1<<len(flagNames) - 1); rest != 0 {
+		names = append(names, "0x"+strconv.FormatUint(uint64(rest), 16))
+	}
+	return strings.Join(names, "|")
+}
+
+// nanFlags returns the flags raised by an operation with the operands ds that
+// gives a NaN: Invalid if any operand is a signaling NaN, or if none is a
+// NaN at all.
+func nanFlags(ds ...Dec32) Flags {
+	quiet := false
+	for _, d := range ds {
+		switch {
+		case d.IsSignalingNaN():
+			return Invalid
+		case d.IsNaN():
+			quiet = true
+		}
+	}
+	if quiet {
+		return 0
+	}
+	return Invalid
+}
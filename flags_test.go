@@ -0,0 +1,115 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import "testing"
+
+func TestFlagsString(t *testing.T) {
+	testCases := []struct {
+		f   Flags
+		ref string
+	}{
+		{0, "0"},
+		{Inexact, "Inexact"},
+		{Inexact | Overflow, "Inexact|Overflow"},
+		{Underflow | DivisionByZero, "Underflow|DivisionByZero"},
+		{Overflow | Clamped, "Overflow|Clamped"},
+		{Invalid | 0x80, "Invalid|0x80"},
+	}
+	for i, testCase := range testCases {
+		if s := testCase.f.String(); s != testCase.ref {
+			t.Errorf("testCase #%d: expect %q, got %q", i, testCase.ref, s)
+		}
+	}
+}
+
+func TestContextFlags(t *testing.T) {
+	one, three := mustEncode(t, 1, 0), mustEncode(t, 3, 0)
+	zero, inf, snan := mustEncode(t, 0, 0), inf32, Dec32(snanMask)
+	testCases := []struct {
+		c    Context
+		op   func(c *Context, x, y Dec32) Dec32
+		x, y Dec32
+		ref  Flags
+	}{
+		{Context{}, (*Context).Add, one, three, 0},
+		{Context{}, (*Context).Div, one, three, Inexact},
+		{Context{}, (*Context).Div, one, mustEncode(t, 4, 0), 0},
+		{Context{Precision: 3}, (*Context).Mul, three, mustEncode(t, 1234, 0), Inexact},
+		{Context{}, (*Context).Mul, mustEncode(t, 1, 50), mustEncode(t, 1, 47), Overflow | Inexact},
+		{Context{Mode: ToZero}, (*Context).Mul, mustEncode(t, 1, 50), mustEncode(t, 1, 47), Overflow | Inexact},
+		{Context{Saturate: true}, (*Context).Add, mustEncode(t, 9999999, 90), mustEncode(t, 1, 90), Overflow | Inexact | Clamped},
+		{Context{Mode: ToZero, Saturate: true}, (*Context).Add, mustEncode(t, 9999999, 90), mustEncode(t, 1, 90), Overflow | Inexact},
+		// Subnormal results underflow only if they are inexact.
+		{Context{}, (*Context).Mul, mustEncode(t, 11, -50), mustEncode(t, 1, -50), 0},
+		{Context{}, (*Context).Mul, mustEncode(t, 11, -50), mustEncode(t, 1, -52), Underflow | Inexact},
+		{Context{}, (*Context).Mul, mustEncode(t, 1, -60), mustEncode(t, 1, -60), Underflow | Inexact | Clamped},
+		// A result that rounds up to the smallest normal value is still tiny.
+		{Context{}, (*Context).Mul, mustEncode(t, 9999999, -51), mustEncode(t, 1, -51), Underflow | Inexact},
+		{Context{}, (*Context).Div, one, zero, DivisionByZero},
+		{Context{}, (*Context).Div, inf, zero, 0},
+		{Context{}, (*Context).Div, zero, zero, Invalid},
+		{Context{}, (*Context).Div, inf, inf, Invalid},
+		{Context{}, (*Context).Mul, zero, inf, Invalid},
+		{Context{}, (*Context).Sub, inf, inf, Invalid},
+		{Context{}, (*Context).Add, inf, inf, 0},
+		{Context{}, (*Context).Add, nan32, inf, 0},
+		{Context{}, (*Context).Add, one, snan, Invalid},
+		{Context{}, (*Context).Mul, nan32, snan, Invalid},
+		// Flags accumulate.
+		{Context{Flags: Invalid}, (*Context).Div, one, three, Invalid | Inexact},
+	}
+	for i, testCase := range testCases {
+		testCase.op(&testCase.c, testCase.x, testCase.y)
+		if testCase.c.Flags != testCase.ref {
+			t.Errorf("testCase #%d: %v, %v: expect %v, got %v", i, testCase.x, testCase.y, testCase.ref, testCase.c.Flags)
+		}
+	}
+
+	var c Context
+	if c.Recip(zero); c.Flags != DivisionByZero {
+		t.Errorf("1/0: expect DivisionByZero, got %v", c.Flags)
+	}
+	c.Flags = 0
+	if c.Round(snan); c.Flags != Invalid {
+		t.Errorf("round sNaN: expect Invalid, got %v", c.Flags)
+	}
+	c.Flags = 0
+	if _, ok := c.FromScaledInt64(123456789, 2); ok || c.Flags != Inexact {
+		t.Errorf("expect Inexact, got %v %v", ok, c.Flags)
+	}
+	c.Flags = 0
+	if _, err := c.ParseDec32("1E-200"); err != nil || c.Flags != Underflow|Inexact|Clamped {
+		t.Errorf("expect Underflow|Inexact|Clamped, got %v %v", err, c.Flags)
+	}
+}
+
+func TestContextClamped(t *testing.T) {
+	testCases := []struct {
+		c   Context
+		s   string
+		ref Dec32
+		f   Flags
+	}{
+		{Context{}, "1E+90", mustEncode(t, 1, 90), 0},
+		{Context{}, "1E+91", mustEncode(t, 10, 90), Clamped},
+		{Context{}, "1234567E+90", mustEncode(t, 1234567, 90), 0},
+		{Context{}, "0E+100", mustEncode(t, 0, 90), Clamped},
+		{Context{}, "0E-110", mustEncode(t, 0, -101), Clamped},
+		{Context{}, "1E-101", mustEncode(t, 1, -101), 0},
+		{Context{}, "1E-102", mustEncode(t, 0, -101), Underflow | Inexact | Clamped},
+		{Context{}, "6E-102", mustEncode(t, 1, -101), Underflow | Inexact},
+		{Context{Precision: 3}, "1.2345", mustEncode(t, 123, -2), Inexact},
+		{Context{Saturate: true}, "1E+97", mustEncode(t, 9999999, 90), Overflow | Inexact | Clamped},
+		{Context{Saturate: true, Mode: ToZero}, "1E+97", mustEncode(t, 9999999, 90), Overflow | Inexact},
+		{Context{}, "1E+97", inf32, Overflow | Inexact},
+	}
+	for i, testCase := range testCases {
+		d, _ := testCase.c.ParseDec32(testCase.s)
+		if d != testCase.ref || testCase.c.Flags != testCase.f {
+			t.Errorf("testCase #%d: %s: expect %v %v, got %v %v", i, testCase.s, testCase.ref, testCase.f, d, testCase.c.Flags)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"math"
+	"math/big"
+)
+
+// FromFloat64 converts f to the nearest decimal32, ties to even, and reports
+// whether the conversion is exact. The rounding is done once, from the exact
+// binary value of f, so no digits are lost to an intermediate string. If f
+// has at most seven significant digits, the result has the exponent of the
+// last of them, so 2.5 is 25 with exponent -1 and 100 is 100. Otherwise the
+// rounded result has its trailing zeros removed, so that 0.1, whose binary
+// value is 0.1000000000000000055511151231257827..., gives 0.1 rather than
+// 0.1000000, and 1e20 gives 1E+20. Values too large in magnitude become
+// infinities.
+// Infinities and zeros convert exactly, keeping their sign, and NaNs convert
+// to a quiet NaN and are reported inexact.
+//
+// A binary float is the wrong starting point for a decimal constant: 0.1 as
+// a float64 is not one tenth. Use MustParseDec32 for those.
+func FromFloat64(f float64) (Dec32, bool) {
+	var c Context
+	return c.FromFloat64(f)
+}
+
+// FromFloat32 is like FromFloat64 for a float32 value, which it converts from
+// exactly.
+func FromFloat32(f float32) (Dec32, bool) {
+	var c Context
+	return c.FromFloat64(float64(f))
+}
+
+// Float64 returns the float64 nearest to d, ties to even. The rounding is
+// done once, from the exact decimal value, so that every decimal32 value that
+// is exactly a float64, such as 0.5 and 1E+22, converts exactly, and the
+// others, such as 0.1, convert to the same float64 as their decimal string
+// does with strconv.ParseFloat. Infinities convert to infinities, NaNs to NaN,
+// and zeros to zeros, keeping their sign.
+func (d Dec32) Float64() float64 {
+	switch {
+	case d.IsNaN():
+		return math.NaN()
+	case d.IsInf():
+		return math.Inf(d.Sign())
+	case d.Zero():
+		return math.Copysign(0, float64(d.Sign()))
+	}
+	f, _ := d.rat().Float64()
+	return f
+}
+
+// Float32 is like Float64 for the nearest float32.
+func (d Dec32) Float32() float32 {
+	switch {
+	case d.IsNaN():
+		return float32(math.NaN())
+	case d.IsInf():
+		return float32(math.Inf(d.Sign()))
+	case d.Zero():
+		return float32(math.Copysign(0, float64(d.Sign())))
+	}
+	f, _ := d.rat().Float32()
+	return f
+}
+
+// rat returns the exact value of the finite d.
+func (d Dec32) rat() *big.Rat {
+	x := newBigDec32(d)
+	r := new(big.Rat).SetInt(x.signed())
+	if x.exp >= 0 {
+		return r.Mul(r, new(big.Rat).SetInt(bigPow10(x.exp)))
+	}
+	return r.Quo(r, new(big.Rat).SetInt(bigPow10(-x.exp)))
+}
@@ -0,0 +1,118 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"math"
+	"math/big"
+)
+
+// IEEE 754 binary16 layout.
+const (
+	f16SignMask = 0x8000
+	f16Inf      = 0x7c00
+	f16NaN      = 0x7e00
+	f16MantBits = 10
+	f16Bias     = 15
+	f16MinExp   = -24 // exponent of the least significant subnormal bit
+	f16MaxExp   = 15 - f16MantBits
+)
+
+// FromFloat16 converts the IEEE 754 binary16 (half precision) value with the
+// bit pattern h to the nearest decimal32, ties to even, and reports whether
+// the conversion is exact. Binary16 values with more than seven significant
+// decimal digits, such as the smallest subnormal 5.9604644775390625E-8, are
+// rounded. Infinities convert exactly; NaNs convert to a quiet NaN and are
+// reported inexact.
+func FromFloat16(h uint16) (Dec32, bool) {
+	neg := h&f16SignMask != 0
+	exp := int(h>>f16MantBits) & 0x1f
+	mant := uint64(h) & (1<<f16MantBits - 1)
+	switch {
+	case exp == 0x1f && mant != 0:
+		return nan32, false
+	case exp == 0x1f:
+		return inf32 | signOf(neg), true
+	case exp == 0 && mant == 0:
+		return pack32(neg, 0, 0), true
+	case exp == 0:
+		exp = f16MinExp
+	default:
+		mant |= 1 << f16MantBits
+		exp += f16MinExp - 1
+	}
+	// Every binary16 value is exactly a float64.
+	f := math.Ldexp(float64(mant), exp)
+	if neg {
+		f = -f
+	}
+	return newBigDecFloat64(f).dec32()
+}
+
+// Float16 returns the bit pattern of the IEEE 754 binary16 (half precision)
+// value nearest to d, ties to even, and reports whether the conversion is
+// exact. The rounding is done once, from the exact decimal value, so that
+// values in the binary16 subnormal range are rounded correctly. Values
+// beyond the binary16 range convert to infinities, and NaNs to a quiet NaN;
+// both are reported inexact, except that infinities convert exactly. The
+// sign of zero is kept.
+func (d Dec32) Float16() (uint16, bool) {
+	var sign uint16
+	if d.Sign() < 0 {
+		sign = f16SignMask
+	}
+	switch {
+	case d.IsNaN():
+		return f16NaN, false
+	case d.IsInf():
+		return sign | f16Inf, true
+	case d.Zero():
+		return sign, true
+	}
+	// The value is num/den.
+	x := newBigDec32(d)
+	num, den := new(big.Int).Set(&x.coeff), big.NewInt(1)
+	if x.exp >= 0 {
+		num.Mul(num, bigPow10(x.exp))
+	} else {
+		den.Set(bigPow10(-x.exp))
+	}
+	// Choose the binary exponent e of the last mantissa bit, so that the
+	// mantissa has 11 bits, or fewer for subnormals.
+	log2 := num.BitLen() - den.BitLen()
+	if new(big.Int).Lsh(den, uint(max(log2, 0))).Cmp(new(big.Int).Lsh(num, uint(max(-log2, 0)))) > 0 {
+		log2--
+	}
+	e := max(log2-f16MantBits, f16MinExp)
+	if e > f16MaxExp {
+		return sign | f16Inf, false
+	}
+	if e < 0 {
+		num.Lsh(num, uint(-e))
+	} else {
+		den.Lsh(den, uint(e))
+	}
+	var m, r big.Int
+	m.QuoRem(num, den, &r)
+	exact := r.Sign() == 0
+	if c := r.Lsh(&r, 1).Cmp(den); c > 0 || c == 0 && m.Bit(0) == 1 {
+		m.Add(&m, big.NewInt(1))
+	}
+	mant := m.Uint64()
+	if mant == 1<<(f16MantBits+1) {
+		// Rounding carried into a twelfth bit.
+		mant >>= 1
+		e++
+	}
+	switch {
+	case e > f16MaxExp:
+		return sign | f16Inf, false
+	case mant < 1<<f16MantBits:
+		// Subnormal; a subnormal that rounds up to 2^10 is the smallest
+		// normal, encoded below.
+		return sign | uint16(mant), exact
+	}
+	return sign | uint16(e-f16MinExp+1)<<f16MantBits | uint16(mant&(1<<f16MantBits-1)), exact
+}
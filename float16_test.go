@@ -0,0 +1,80 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import "testing"
+
+func TestFloat16(t *testing.T) {
+	testCases := []struct {
+		d     Dec32
+		h     uint16
+		exact bool
+	}{
+		{mustEncode(t, 1, 0), 0x3c00, true},
+		{mustEncode(t, -2, 0), 0xc000, true},
+		{mustEncode(t, 15, -1), 0x3e00, true},
+		{mustEncode(t, 65504, 0), 0x7bff, true},
+		{mustEncode(t, 65519, 0), 0x7bff, false},
+		{mustEncode(t, 6552, 1), 0x7c00, false},
+		{mustEncode(t, 1, -1), 0x2e66, false},
+		{mustEncode(t, 1, 1), 0x4900, true},
+		{mustEncode(t, 2049, 0), 0x6800, false},
+		{mustEncode(t, 2051, 0), 0x6802, false},
+		{mustEncode(t, 3333333, -7), 0x3555, false},
+		// Subnormals: 2^-24 is about 5.96E-8, and half of it rounds to
+		// zero, ties to even.
+		{mustEncode(t, 5960464, -14), 0x0001, false},
+		{mustEncode(t, 2980232, -14), 0x0000, false},
+		{mustEncode(t, 2980233, -14), 0x0001, false},
+		{mustEncode(t, 6103516, -11), 0x0400, false},
+		{mustEncode(t, 1, -101), 0x0000, false},
+		{mustEncode(t, 1, 90), 0x7c00, false},
+		{mustEncode(t, 0, 0) | signMask, 0x8000, true},
+		{inf32 | signMask, 0xfc00, true},
+		{nan32, 0x7e00, false},
+	}
+	for i, testCase := range testCases {
+		h, exact := testCase.d.Float16()
+		if h != testCase.h || exact != testCase.exact {
+			t.Errorf("testCase #%d: %v: expect %#04x %v, got %#04x %v", i, testCase.d, testCase.h, testCase.exact, h, exact)
+		}
+	}
+}
+
+func TestFromFloat16(t *testing.T) {
+	testCases := []struct {
+		h     uint16
+		ref   string
+		exact bool
+	}{
+		{0x3c00, "1", true},
+		{0x3e00, "1.5", true},
+		{0x7bff, "65504", true},
+		{0x2e66, "0.09997559", false},
+		{0x2400, "0.015625", true},
+		{0x0001, "5.960464E-8", false},
+		{0x0400, "0.00006103516", false},
+		{0x8000, "-0", true},
+		{0xfc00, "-Infinity", true},
+		{0x7e01, "NaN", false},
+	}
+	for i, testCase := range testCases {
+		d, exact := FromFloat16(testCase.h)
+		if d.String() != testCase.ref || exact != testCase.exact {
+			t.Errorf("testCase #%d: %#04x: expect %s %v, got %v %v", i, testCase.h, testCase.ref, testCase.exact, d, exact)
+		}
+	}
+	// Every finite binary16 value converts to a decimal that converts
+	// back to it.
+	for h := 0; h < 1<<16; h++ {
+		if h&0x7c00 == 0x7c00 {
+			continue
+		}
+		d, _ := FromFloat16(uint16(h))
+		if back, _ := d.Float16(); back != uint16(h) {
+			t.Fatalf("%#04x: converts to %v and back to %#04x", h, d, back)
+		}
+	}
+}
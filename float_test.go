@@ -0,0 +1,132 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFromFloat64(t *testing.T) {
+	testCases := []struct {
+		f     float64
+		ref   Dec32
+		exact bool
+	}{
+		{0.1, mustEncode(t, 1, -1), false},
+		{2.5, mustEncode(t, 25, -1), true},
+		{100, mustEncode(t, 100, 0), true},
+		{-0.375, mustEncode(t, -375, -3), true},
+		{1.0 / 3, mustEncode(t, 3333333, -7), false},
+		{2.0 / 3, mustEncode(t, 6666667, -7), false},
+		{123456789, mustEncode(t, 1234568, 2), false},
+		{1e20, mustEncode(t, 1, 20), true},
+		{1e300, inf32, false},
+		{-1e97, inf32 | signMask, false},
+		{9.9999994e96, mustEncode(t, 9999999, 90), false},
+		{9.9999995e96, mustEncode(t, 9999999, 90), false},
+		{9.99999951e96, inf32, false},
+		{1.2345678e-100, mustEncode(t, 12, -101), false},
+		{1.5e-101, mustEncode(t, 2, -101), false},
+		{5e-324, mustEncode(t, 0, 0), false},
+		{-5e-324, pack32(true, 0, 0), false},
+		{0, mustEncode(t, 0, 0), true},
+		{math.Copysign(0, -1), pack32(true, 0, 0), true},
+		{math.Inf(-1), inf32 | signMask, true},
+		{math.NaN(), nan32, false},
+	}
+	for i, testCase := range testCases {
+		if d, exact := FromFloat64(testCase.f); d != testCase.ref || exact != testCase.exact {
+			t.Errorf("testCase #%d: %v: expect %v %v, got %v %v", i, testCase.f, testCase.ref, testCase.exact, d, exact)
+		}
+	}
+}
+
+func TestFromFloat32(t *testing.T) {
+	if d, exact := FromFloat32(0.1); d != mustEncode(t, 1, -1) || exact {
+		t.Errorf("expect inexact 0.1, got %v %v", d, exact)
+	}
+	if d, exact := FromFloat32(16777217); d != mustEncode(t, 1677722, 1) || exact {
+		t.Errorf("expect inexact 1.677722E+7, got %v %v", d, exact)
+	}
+	if d, exact := FromFloat32(-0.5); d != mustEncode(t, -5, -1) || !exact {
+		t.Errorf("expect exact -0.5, got %v %v", d, exact)
+	}
+}
+
+func TestContextFromFloat64(t *testing.T) {
+	testCases := []struct {
+		c   Context
+		f   float64
+		ref Dec32
+	}{
+		// 0.15 is just below the binary value 0.15.
+		{Context{Precision: 1}, 0.15, mustEncode(t, 1, -1)},
+		{Context{Precision: 1, Mode: ToNearestAway}, 0.15, mustEncode(t, 1, -1)},
+		{Context{Precision: 1, Mode: ToPositiveInf}, 0.15, mustEncode(t, 2, -1)},
+		{Context{Precision: 3, Mode: ToNearestAway}, 2.675, mustEncode(t, 267, -2)},
+		{Context{Mode: ToZero}, 0.1, mustEncode(t, 1, -1)},
+		{Context{Mode: ToPositiveInf}, 0.1, mustEncode(t, 1000001, -7)},
+		{Context{Mode: ToZero}, 1e300, mustEncode(t, 9999999, 90)},
+	}
+	for i, testCase := range testCases {
+		if d, _ := testCase.c.FromFloat64(testCase.f); d != testCase.ref {
+			t.Errorf("testCase #%d: %+v: %v: expect %v, got %v", i, testCase.c, testCase.f, testCase.ref, d)
+		}
+	}
+	var c Context
+	if c.FromFloat64(0.1); c.Flags != Inexact {
+		t.Errorf("expect Inexact, got %v", c.Flags)
+	}
+}
+
+func TestFloat64(t *testing.T) {
+	testCases := []struct {
+		d   Dec32
+		ref float64
+	}{
+		{mustEncode(t, 5, -1), 0.5},
+		{mustEncode(t, 1, -1), 0.1},
+		{mustEncode(t, 3333333, -7), 0.3333333},
+		{mustEncode(t, -12345, -2), -123.45},
+		{mustEncode(t, 1, 22), 1e22},
+		{mustEncode(t, 9999999, 90), 9.999999e96},
+		{mustEncode(t, 1, -101), 1e-101},
+		{mustEncode(t, 1234567, -101), 1.234567e-95},
+		{mustEncode(t, 0, 5), 0},
+		{inf32, math.Inf(1)},
+		{inf32 | signMask, math.Inf(-1)},
+	}
+	for i, testCase := range testCases {
+		if f := testCase.d.Float64(); f != testCase.ref {
+			t.Errorf("testCase #%d: %v: expect %v, got %v", i, testCase.d, testCase.ref, f)
+		}
+		if f := testCase.d.Float32(); f != float32(testCase.ref) && !math.IsInf(testCase.ref, 0) && testCase.ref < math.MaxFloat32 {
+			t.Errorf("testCase #%d: %v: expect float32 %v, got %v", i, testCase.d, float32(testCase.ref), f)
+		}
+	}
+	if f := pack32(true, 0, 0).Float64(); f != 0 || !math.Signbit(f) {
+		t.Errorf("expect -0, got %v", f)
+	}
+	if f := nan32.Float64(); !math.IsNaN(f) {
+		t.Errorf("expect NaN, got %v", f)
+	}
+	if f := mustEncode(t, 1, 50).Float32(); !math.IsInf(float64(f), 1) {
+		t.Errorf("expect float32 overflow to +Inf, got %v", f)
+	}
+	if f := mustEncode(t, 1, -50).Float32(); f != 0 {
+		t.Errorf("expect float32 underflow to 0, got %v", f)
+	}
+}
+
+func TestFloat64RoundTrip(t *testing.T) {
+	// Seven significant digits always survive a round trip through float64.
+	for _, s := range []string{"0.1", "2.675", "9.999999E+96", "1.234567E-95", "1E-101", "-3.141593", "0.3333333"} {
+		d := MustParseDec32(s)
+		if r, _ := FromFloat64(d.Float64()); r.reduce() != d.reduce() {
+			t.Errorf("%s: round trip gives %v", s, r)
+		}
+	}
+}
@@ -0,0 +1,496 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// String returns the decimal value in the scientific string form of the
+// decNumber specification. Values whose exponent is not positive and whose
+// adjusted exponent is at least -6 are written without an exponent, keeping
+// any trailing zeros of the coefficient, so 1.00 and 1 print differently:
+//
+//	123.45, -0.00, 0.000001
+//
+// Other values are written in scientific notation with one digit before the
+// decimal point: 1.2345E+7, 1E-10. Special values are written as Infinity,
+// -Infinity, NaN and sNaN.
+func (d Dec32) String() string {
+	var buf [24]byte
+	return string(d.appendString(buf[:0]))
+}
+
+// EngineeringString returns the decimal value in the engineering string form
+// of the decNumber specification. It is the form of String, except that an
+// exponent, when one is written, is a multiple of three, with one to three
+// digits before the decimal point: 1.23E+4 is written 12.3E+3 and 1E-7 as
+// 100E-9. Zeros written with an exponent gain fraction zeros instead, so
+// 0E+4 is written 0.00E+6.
+func (d Dec32) EngineeringString() string {
+	if d.IsInf() || d.IsNaN() {
+		return d.String()
+	}
+	neg, coeff, exp := d.unpack()
+	var buf [24]byte
+	b := buf[:0]
+	if neg {
+		b = append(b, '-')
+	}
+	var digits [10]byte
+	return string(appendEngineering(b, strconv.AppendUint(digits[:0], uint64(coeff), 10), exp))
+}
+
+// PlainString returns the decimal value in plain notation, without an
+// exponent, like Java's BigDecimal.toPlainString: 1.23E+5 is written as
+// 123000 and 1E-8 as 0.00000001. Special values are written as by String.
+// Plain notation suits consumers that reject exponents, such as SQL DECIMAL
+// literals, at the cost of long strings for extreme exponents.
+func (d Dec32) PlainString() string {
+	if d.IsInf() || d.IsNaN() {
+		return d.String()
+	}
+	var buf [24]byte
+	return string(d.appendPlain(buf[:0]))
+}
+
+// PlainString is like Dec32.PlainString, for decimal64 values.
+func (d Dec64) PlainString() string {
+	if d.IsInf() || d.IsNaN() {
+		return d.String()
+	}
+	var buf [48]byte
+	return string(d.appendPlain(buf[:0]))
+}
+
+// FormatPercent returns the decimal value multiplied by 100 in plain notation
+// with a trailing percent sign, computed exactly in decimal: 0.0725 is written
+// "7.25%" with places 2. The result has places fraction digits, rounding ties
+// to even; if places is negative, the digits of d are kept as they are.
+// Special values are written as by String, without a percent sign.
+func (d Dec32) FormatPercent(places int) string {
+	if d.IsInf() || d.IsNaN() {
+		return d.String()
+	}
+	x := newBigDec32(d)
+	x.exp += 2
+	if places >= 0 {
+		x.rescale(-places)
+	}
+	var buf []byte
+	if x.neg {
+		buf = append(buf, '-')
+	}
+	buf = appendPlainDigits(buf, []byte(x.coeff.String()), x.exp)
+	return string(append(buf, '%'))
+}
+
+// FormatSignificant returns the decimal value rounded to n significant
+// digits, ties to even, in the notation of String, showing exactly n digits:
+// 1234.5 is written "1.23E+3" and 1.5 "1.50" for n = 3. Trailing zeros are
+// significant, so the exponent notation is used whenever plain notation
+// would need zeros beyond the n digits. Zeros are written with n-1 fraction
+// digits. Values of n below 1 are treated as 1. Special values are written as
+// by String.
+func (d Dec32) FormatSignificant(n int) string {
+	if d.IsInf() || d.IsNaN() {
+		return d.String()
+	}
+	n = max(n, 1)
+	x := newBigDec32(d)
+	var adjusted int
+	if x.isZero() {
+		adjusted = 0
+	} else {
+		x.roundDigits(n, ToNearestEven)
+		adjusted = x.exp + bigDigits(&x.coeff) - 1
+	}
+	x.rescale(adjusted - n + 1)
+	var buf []byte
+	if x.neg {
+		buf = append(buf, '-')
+	}
+	c := []byte(x.coeff.String())
+	if x.isZero() {
+		return string(appendPlainDigits(buf, c, x.exp))
+	}
+	return string(appendScientific(buf, c, x.exp))
+}
+
+// FormatNotation returns the decimal value in plain notation if its adjusted
+// exponent, the exponent of its most significant digit, is between minPlain
+// and maxPlain inclusive, and otherwise in the scientific notation of String.
+// For example, FormatNotation(-6, 20) writes 1.5E+9 as 1500000000 and 1E-7
+// as 1E-7, for consumers that accept exponents only for extreme values;
+// FormatNotation(math.MinInt, math.MaxInt) is PlainString. Special values are
+// written as by String.
+func (d Dec32) FormatNotation(minPlain, maxPlain int) string {
+	if d.IsInf() || d.IsNaN() {
+		return d.String()
+	}
+	var buf [24]byte
+	if a := d.AdjustedExponent(); a < minPlain || a > maxPlain {
+		neg, coeff, exp := d.unpack()
+		b := buf[:0]
+		if neg {
+			b = append(b, '-')
+		}
+		var digits [10]byte
+		return string(appendExponential(b, strconv.AppendUint(digits[:0], uint64(coeff), 10), exp))
+	}
+	return string(d.appendPlain(buf[:0]))
+}
+
+// GoString returns Go source that reconstructs d, for use with the %#v
+// format: a call to MustEncodeDec32 when d is a canonical value that
+// EncodeDec32 can produce, and a conversion of the bit pattern otherwise.
+func (d Dec32) GoString() string {
+	coeff, exp, ok := d.Decode()
+	if ok && d.Valid() {
+		if e, _ := EncodeDec32(coeff, exp); e == d {
+			return "decimal.MustEncodeDec32(" + strconv.Itoa(int(coeff)) + ", " + strconv.Itoa(int(exp)) + ")"
+		}
+	}
+	return "decimal.Dec32(0x" + strconv.FormatUint(uint64(d), 16) + ")"
+}
+
+// Format implements fmt.Formatter. Its verbs are:
+//
+//	%v, %s  the scientific string form of String, with %#v giving GoString
+//	%e, %E  exponential notation, such as 1.2345e+07, with a two-digit
+//	        exponent at least
+//	%f, %F  plain notation, as for PlainString
+//	%g, %G  the scientific string form, with a lower case exponent for %g
+//
+// Without a precision, all the digits of the coefficient are written, so
+// 1.00 and 1 print differently with every verb. A precision rounds the value
+// to nearest, ties to even, to that many fraction digits for %e and %f, and
+// to that many significant digits, written exactly as for FormatSignificant,
+// for %v, %s and %g. The '+' and ' ' flags write a sign or space before
+// values that are not negative, and the width pads with spaces on the left,
+// or on the right with the '-' flag, or after the sign with zeros for the
+// '0' flag. Infinities and NaNs are written as by String with every verb.
+// The integer verbs %b, %o, %O, %d, %x and %X format the encoding of d as a
+// uint32.
+func (d Dec32) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v', 's', 'e', 'E', 'f', 'F', 'g', 'G':
+	case 'b', 'o', 'O', 'd', 'x', 'X':
+		fmt.Fprintf(s, fmt.FormatString(s, verb), uint32(d))
+		return
+	default:
+		fmt.Fprintf(s, "%%!%c(decimal.Dec32=%s)", verb, d.String())
+		return
+	}
+	if verb == 'v' && s.Flag('#') {
+		io.WriteString(s, d.GoString())
+		return
+	}
+	var buf [32]byte
+	b := buf[:0]
+	switch {
+	case d.Sign() < 0:
+		b = append(b, '-')
+	case s.Flag('+'):
+		b = append(b, '+')
+	case s.Flag(' '):
+		b = append(b, ' ')
+	}
+	sign := len(b)
+	prec, hasPrec := s.Precision()
+	b = (d &^ signMask).appendVerb(b, verb, prec, hasPrec)
+	pad := 0
+	if w, ok := s.Width(); ok {
+		pad = max(w-len(b), 0)
+	}
+	switch {
+	case s.Flag('-'):
+		s.Write(b)
+		io.WriteString(s, strings.Repeat(" ", pad))
+	case s.Flag('0') && !d.IsInf() && !d.IsNaN():
+		s.Write(b[:sign])
+		io.WriteString(s, strings.Repeat("0", pad))
+		s.Write(b[sign:])
+	default:
+		io.WriteString(s, strings.Repeat(" ", pad))
+		s.Write(b)
+	}
+}
+
+// appendVerb appends the non-negative d to buf as Format writes it for verb
+// and the precision prec, if hasPrec.
+func (d Dec32) appendVerb(buf []byte, verb rune, prec int, hasPrec bool) []byte {
+	if d.IsInf() || d.IsNaN() {
+		return d.appendString(buf)
+	}
+	switch verb {
+	case 'e', 'E':
+		x := newBigDec32(d)
+		if hasPrec && !x.isZero() {
+			x.roundDigits(prec+1, ToNearestEven)
+		}
+		c := []byte(x.coeff.String())
+		adjusted := x.exp + len(c) - 1
+		for hasPrec && len(c) < prec+1 {
+			c = append(c, '0')
+		}
+		buf = append(buf, c[0])
+		if len(c) > 1 {
+			buf = append(buf, '.')
+			buf = append(buf, c[1:]...)
+		}
+		buf = append(buf, byte(verb))
+		if adjusted < 0 {
+			buf = append(buf, '-')
+			adjusted = -adjusted
+		} else {
+			buf = append(buf, '+')
+		}
+		if adjusted < 10 {
+			buf = append(buf, '0')
+		}
+		return strconv.AppendInt(buf, int64(adjusted), 10)
+	case 'f', 'F':
+		if !hasPrec {
+			return d.appendPlain(buf)
+		}
+		x := newBigDec32(d)
+		x.rescale(-prec)
+		return appendPlainDigits(buf, []byte(x.coeff.String()), x.exp)
+	}
+	start := len(buf)
+	if hasPrec {
+		buf = append(buf, d.FormatSignificant(prec)...)
+	} else {
+		buf = d.appendString(buf)
+	}
+	if verb == 'g' {
+		for i := start; i < len(buf); i++ {
+			if buf[i] == 'E' {
+				buf[i] = 'e'
+			}
+		}
+	}
+	return buf
+}
+
+// Parts holds the components of the scientific string form of a decimal
+// value, as returned by FormatParts, for renderers that style them
+// separately.
+type Parts struct {
+	Neg     bool   // whether a minus sign is written
+	Int     string // digits before the decimal point
+	Frac    string // digits after the decimal point, if any
+	HasExp  bool   // whether an exponent is written
+	Exp     int    // the exponent, if HasExp
+	Special string // "Infinity", "NaN" or "sNaN" for special values
+}
+
+// FormatParts returns the components of the string form of d, as written by
+// String: 1.2345E+7 has Int "1", Frac "2345" and Exp 7, and -0.050 has Int
+// "0" and Frac "050" with Neg set. For special values only Neg and Special
+// are set.
+func (d Dec32) FormatParts() Parts {
+	p := Parts{Neg: d.Sign() < 0}
+	switch {
+	case d.IsInf():
+		p.Special = "Infinity"
+		return p
+	case d.IsNaN():
+		p.Special = "NaN"
+		if d&snanMask == snanMask {
+			p.Special = "sNaN"
+		}
+		return p
+	}
+	_, coeff, exp := d.unpack()
+	c := strconv.FormatUint(uint64(coeff), 10)
+	adjusted := exp + len(c) - 1
+	switch {
+	case exp > 0 || adjusted < -6:
+		p.Int, p.Frac = c[:1], c[1:]
+		p.HasExp, p.Exp = true, adjusted
+	case adjusted >= 0:
+		p.Int, p.Frac = c[:adjusted+1], c[adjusted+1:]
+	default:
+		p.Int = "0"
+		p.Frac = strings.Repeat("0", -adjusted-1) + c
+	}
+	return p
+}
+
+// appendString appends the scientific string form of d to buf.
+func (d Dec32) appendString(buf []byte) []byte {
+	if d.Sign() < 0 {
+		buf = append(buf, '-')
+	}
+	switch {
+	case d.IsInf():
+		return append(buf, "Infinity"...)
+	case d.IsNaN():
+		if d&snanMask == snanMask {
+			buf = append(buf, 's')
+		}
+		return append(buf, "NaN"...)
+	}
+	_, coeff, exp := d.unpack()
+	var digits [10]byte
+	return appendScientific(buf, strconv.AppendUint(digits[:0], uint64(coeff), 10), exp)
+}
+
+// String returns the decimal64 value in the scientific string form of the
+// decNumber specification, as for Dec32.String, such as 1234567.890123456
+// and 1.234567890123456E+300.
+func (d Dec64) String() string {
+	var buf [32]byte
+	return string(d.appendString(buf[:0]))
+}
+
+// appendString appends the scientific string form of d to buf.
+func (d Dec64) appendString(buf []byte) []byte {
+	if d.Sign() < 0 {
+		buf = append(buf, '-')
+	}
+	switch {
+	case d.IsInf():
+		return append(buf, "Infinity"...)
+	case d.IsNaN():
+		if d&snanMask64 == snanMask64 {
+			buf = append(buf, 's')
+		}
+		return append(buf, "NaN"...)
+	}
+	_, coeff, exp := d.unpack()
+	var digits [20]byte
+	return appendScientific(buf, strconv.AppendUint(digits[:0], coeff, 10), exp)
+}
+
+// appendScientific appends the scientific string form of the coefficient
+// digits c, which have no leading zeros, multiplied by 10^exp.
+func appendScientific(buf, c []byte, exp int) []byte {
+	if adjusted := exp + len(c) - 1; exp <= 0 && adjusted >= -6 {
+		return appendPlainDigits(buf, c, exp)
+	}
+	return appendExponential(buf, c, exp)
+}
+
+// appendExponential appends the coefficient digits c, which have no leading
+// zeros, multiplied by 10^exp, in exponential notation with one digit before
+// the decimal point.
+func appendExponential(buf, c []byte, exp int) []byte {
+	adjusted := exp + len(c) - 1
+	buf = append(buf, c[0])
+	if len(c) > 1 {
+		buf = append(buf, '.')
+		buf = append(buf, c[1:]...)
+	}
+	buf = append(buf, 'E')
+	if adjusted >= 0 {
+		buf = append(buf, '+')
+	}
+	return strconv.AppendInt(buf, int64(adjusted), 10)
+}
+
+// appendEngineering appends the engineering string form of the coefficient
+// digits c, which have no leading zeros, multiplied by 10^exp.
+func appendEngineering(buf, c []byte, exp int) []byte {
+	adjusted := exp + len(c) - 1
+	if exp <= 0 && adjusted >= -6 {
+		return appendPlainDigits(buf, c, exp)
+	}
+	// point is the number of digits before the decimal point.
+	var point int
+	if len(c) == 1 && c[0] == '0' {
+		point = floorMod(adjusted+2, 3) - 1
+	} else {
+		point = floorMod(adjusted, 3) + 1
+	}
+	switch {
+	case point <= 0:
+		buf = append(buf, '0', '.')
+		for i := point; i < 0; i++ {
+			buf = append(buf, '0')
+		}
+		buf = append(buf, c...)
+	case point >= len(c):
+		buf = append(buf, c...)
+		for i := len(c); i < point; i++ {
+			buf = append(buf, '0')
+		}
+	default:
+		buf = append(buf, c[:point]...)
+		buf = append(buf, '.')
+		buf = append(buf, c[point:]...)
+	}
+	if e := adjusted + 1 - point; e != 0 {
+		buf = append(buf, 'E')
+		if e > 0 {
+			buf = append(buf, '+')
+		}
+		buf = strconv.AppendInt(buf, int64(e), 10)
+	}
+	return buf
+}
+
+// floorMod returns a modulo n, rounding the quotient towards negative
+// infinity, so that the result is never negative for positive n.
+func floorMod(a, n int) int {
+	m := a % n
+	if m < 0 {
+		m += n
+	}
+	return m
+}
+
+// appendPlain appends the value of the finite d to buf in plain notation,
+// without an exponent: positive exponents are written as trailing zeros, and
+// negative exponents as that many fraction digits.
+func (d Dec32) appendPlain(buf []byte) []byte {
+	neg, coeff, exp := d.unpack()
+	if neg {
+		buf = append(buf, '-')
+	}
+	var digits [10]byte
+	return appendPlainDigits(buf, strconv.AppendUint(digits[:0], uint64(coeff), 10), exp)
+}
+
+// appendPlain is like Dec32.appendPlain, for decimal64 values.
+func (d Dec64) appendPlain(buf []byte) []byte {
+	neg, coeff, exp := d.unpack()
+	if neg {
+		buf = append(buf, '-')
+	}
+	var digits [20]byte
+	return appendPlainDigits(buf, strconv.AppendUint(digits[:0], coeff, 10), exp)
+}
+
+// appendPlainDigits appends the coefficient digits c, which have no leading
+// zeros, multiplied by 10^exp, in plain notation.
+func appendPlainDigits(buf, c []byte, exp int) []byte {
+	if exp >= 0 {
+		buf = append(buf, c...)
+		if len(c) == 1 && c[0] == '0' {
+			return buf
+		}
+		for ; exp > 0; exp-- {
+			buf = append(buf, '0')
+		}
+		return buf
+	}
+	point := len(c) + exp
+	if point > 0 {
+		buf = append(buf, c[:point]...)
+		buf = append(buf, '.')
+		return append(buf, c[point:]...)
+	}
+	buf = append(buf, '0', '.')
+	for ; point < 0; point++ {
+		buf = append(buf, '0')
+	}
+	return append(buf, c...)
+}
@@ -0,0 +1,302 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestString(t *testing.T) {
+	testCases := []struct {
+		d   Dec32
+		ref string
+	}{
+		// Examples from the decNumber to-scientific-string specification.
+		{mustEncode(t, 123, 0), "123"},
+		{mustEncode(t, -123, 0), "-123"},
+		{mustEncode(t, 123, 1), "1.23E+3"},
+		{mustEncode(t, 123, 3), "1.23E+5"},
+		{mustEncode(t, 123, -1), "12.3"},
+		{mustEncode(t, 123, -5), "0.00123"},
+		{mustEncode(t, 123, -10), "1.23E-8"},
+		{mustEncode(t, -123, -12), "-1.23E-10"},
+		{mustEncode(t, 0, 0), "0"},
+		{mustEncode(t, 0, -2), "0.00"},
+		{mustEncode(t, 0, 2), "0E+2"},
+		{pack32(true, 0, 0), "-0"},
+		{mustEncode(t, 5, -6), "0.000005"},
+		{mustEncode(t, 50, -7), "0.0000050"},
+		{mustEncode(t, 5, -7), "5E-7"},
+		{mustEncode(t, 100, -2), "1.00"},
+		{mustEncode(t, 9999999, 90), "9.999999E+96"},
+		{mustEncode(t, 1, -101), "1E-101"},
+		{Dec32(0), "0E-101"},
+		{inf32, "Infinity"},
+		{inf32 | signMask, "-Infinity"},
+		{nan32, "NaN"},
+		{Dec32(snanMask), "sNaN"},
+	}
+	for i, testCase := range testCases {
+		if s := testCase.d.String(); s != testCase.ref {
+			t.Errorf("testCase #%d: expect %q, got %q", i, testCase.ref, s)
+		}
+	}
+}
+
+func TestGoString(t *testing.T) {
+	testCases := []struct {
+		d   Dec32
+		ref string
+	}{
+		{mustEncode(t, 125, -2), "decimal.MustEncodeDec32(125, -2)"},
+		{mustEncode(t, -9999999, 90), "decimal.MustEncodeDec32(-9999999, 90)"},
+		{mustEncode(t, 0, 0), "decimal.MustEncodeDec32(0, 0)"},
+		{pack32(true, 0, 0), "decimal.Dec32(0xb2800000)"},
+		{inf32, "decimal.Dec32(0x78000000)"},
+		// Non-canonical coefficient.
+		{Dec32(0x6cbfffff), "decimal.Dec32(0x6cbfffff)"},
+	}
+	for i, testCase := range testCases {
+		if s := fmt.Sprintf("%#v", testCase.d); s != testCase.ref {
+			t.Errorf("testCase #%d: expect %q, got %q", i, testCase.ref, s)
+		}
+	}
+}
+
+func TestPlainString(t *testing.T) {
+	testCases := []struct {
+		d   Dec32
+		ref string
+	}{
+		{mustEncode(t, 123, 0), "123"},
+		{mustEncode(t, 123, 3), "123000"},
+		{mustEncode(t, -123, -5), "-0.00123"},
+		{mustEncode(t, 123, -10), "0.0000000123"},
+		{mustEncode(t, 0, 2), "0"},
+		{mustEncode(t, 0, -2), "0.00"},
+		{pack32(true, 0, 0), "-0"},
+		{mustEncode(t, 1, 10), "10000000000"},
+		{inf32 | signMask, "-Infinity"},
+		{nan32, "NaN"},
+	}
+	for i, testCase := range testCases {
+		if s := testCase.d.PlainString(); s != testCase.ref {
+			t.Errorf("testCase #%d: expect %q, got %q", i, testCase.ref, s)
+		}
+	}
+}
+
+func TestFormatPercent(t *testing.T) {
+	testCases := []struct {
+		d      Dec32
+		places int
+		ref    string
+	}{
+		{mustEncode(t, 725, -4), 2, "7.25%"},
+		{mustEncode(t, 1, -1), 0, "10%"},
+		{mustEncode(t, 1, -1), 3, "10.000%"},
+		{mustEncode(t, 1, -1), -1, "10%"},
+		{mustEncode(t, 12345, -6), -1, "1.2345%"},
+		{mustEncode(t, 12345, -6), 2, "1.23%"},
+		{mustEncode(t, 12355, -6), 2, "1.24%"},
+		{mustEncode(t, 125, -5), 1, "0.1%"},
+		{mustEncode(t, -5, -1), 0, "-50%"},
+		{mustEncode(t, 3, 2), 1, "30000.0%"},
+		{mustEncode(t, 0, -3), -1, "0.0%"},
+		{inf32, 2, "Infinity"},
+		{nan32, 2, "NaN"},
+	}
+	for i, testCase := range testCases {
+		if s := testCase.d.FormatPercent(testCase.places); s != testCase.ref {
+			t.Errorf("testCase #%d: expect %q, got %q", i, testCase.ref, s)
+		}
+	}
+}
+
+func TestFormatParts(t *testing.T) {
+	testCases := []struct {
+		d   Dec32
+		ref Parts
+	}{
+		{mustEncode(t, 12345, -2), Parts{Int: "123", Frac: "45"}},
+		{mustEncode(t, 12345, 3), Parts{Int: "1", Frac: "2345", HasExp: true, Exp: 7}},
+		{mustEncode(t, -50, -3), Parts{Neg: true, Int: "0", Frac: "050"}},
+		{mustEncode(t, 1, -6), Parts{Int: "0", Frac: "000001"}},
+		{mustEncode(t, 1, -10), Parts{Int: "1", HasExp: true, Exp: -10}},
+		{mustEncode(t, 7, 0), Parts{Int: "7"}},
+		{mustEncode(t, 0, 2), Parts{Int: "0", HasExp: true, Exp: 2}},
+		{inf32 | signMask, Parts{Neg: true, Special: "Infinity"}},
+		{Dec32(snanMask), Parts{Special: "sNaN"}},
+	}
+	for i, testCase := range testCases {
+		if p := testCase.d.FormatParts(); p != testCase.ref {
+			t.Errorf("testCase #%d: expect %+v, got %+v", i, testCase.ref, p)
+		}
+	}
+
+	// The parts reassemble into the string form.
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		d := mustEncode(t, int32(r.Intn(2*maxCoeff+1)-maxCoeff)/int32(1+r.Intn(1000)), int8(r.Intn(maxExp-minExp+1)+minExp))
+		p := d.FormatParts()
+		var s string
+		if p.Neg {
+			s = "-"
+		}
+		s += p.Int
+		if p.Frac != "" {
+			s += "." + p.Frac
+		}
+		if p.HasExp {
+			s += fmt.Sprintf("E%+d", p.Exp)
+		}
+		if s != d.String() {
+			t.Fatalf("%v: parts %+v reassemble to %q", d, p, s)
+		}
+	}
+}
+
+func TestFormatSignificant(t *testing.T) {
+	testCases := []struct {
+		d   Dec32
+		n   int
+		ref string
+	}{
+		{mustEncode(t, 12345, -1), 3, "1.23E+3"},
+		{mustEncode(t, 15, -1), 3, "1.50"},
+		{mustEncode(t, 120, 0), 3, "120"},
+		{mustEncode(t, 120, 0), 2, "1.2E+2"},
+		{mustEncode(t, 125, 0), 2, "1.2E+2"},
+		{mustEncode(t, 135, 0), 2, "1.4E+2"},
+		{mustEncode(t, 9996, -3), 3, "10.0"},
+		{mustEncode(t, 9996, -1), 3, "1.00E+3"},
+		{mustEncode(t, -3141593, -6), 4, "-3.142"},
+		{mustEncode(t, 12, -8), 3, "1.20E-7"},
+		{mustEncode(t, 12, -7), 3, "0.00000120"},
+		{mustEncode(t, 1234567, 90), 1, "1E+96"},
+		{mustEncode(t, 7, 0), 0, "7"},
+		{mustEncode(t, 0, 5), 3, "0.00"},
+		{mustEncode(t, 0, 0) | signMask, 1, "-0"},
+		{nan32, 3, "NaN"},
+	}
+	for i, testCase := range testCases {
+		if s := testCase.d.FormatSignificant(testCase.n); s != testCase.ref {
+			t.Errorf("testCase #%d: expect %q, got %q", i, testCase.ref, s)
+		}
+	}
+}
+
+func TestFormatNotation(t *testing.T) {
+	testCases := []struct {
+		d        Dec32
+		min, max int
+		ref      string
+	}{
+		{mustEncode(t, 15, 8), -6, 20, "1500000000"},
+		{mustEncode(t, 1, -7), -6, 20, "1E-7"},
+		{mustEncode(t, 1, -6), -6, 20, "0.000001"},
+		{mustEncode(t, -12345, -2), 0, 2, "-123.45"},
+		{mustEncode(t, -12345, -2), 0, 1, "-1.2345E+2"},
+		{mustEncode(t, 12345, -6), 0, 1, "1.2345E-2"},
+		{mustEncode(t, 1, 21), -6, 20, "1E+21"},
+		{mustEncode(t, 0, 3), -6, 20, "0"},
+		{mustEncode(t, 0, 3), -6, 2, "0E+3"},
+		{mustEncode(t, 9999999, 90), math.MinInt, math.MaxInt, mustEncode(t, 9999999, 90).PlainString()},
+		{inf32, 0, 0, "Infinity"},
+	}
+	for i, testCase := range testCases {
+		if s := testCase.d.FormatNotation(testCase.min, testCase.max); s != testCase.ref {
+			t.Errorf("testCase #%d: expect %q, got %q", i, testCase.ref, s)
+		}
+	}
+}
+
+func TestEngineeringString(t *testing.T) {
+	testCases := []struct {
+		d   Dec32
+		ref string
+	}{
+		// Examples from the decNumber to-engineering-string specification.
+		{mustEncode(t, 123, 1), "1.23E+3"},
+		{mustEncode(t, 123, 3), "123E+3"},
+		{mustEncode(t, 123, -10), "12.3E-9"},
+		{mustEncode(t, -123, -12), "-123E-12"},
+		{mustEncode(t, 7, -7), "700E-9"},
+		{mustEncode(t, 7, 1), "70"},
+		{mustEncode(t, 0, 1), "0.00E+3"},
+		{mustEncode(t, 0, 2), "0.0E+3"},
+		{mustEncode(t, 0, 3), "0E+3"},
+		{mustEncode(t, 0, 4), "0.00E+6"},
+		{mustEncode(t, 0, -7), "0.0E-6"},
+		{mustEncode(t, 0, -8), "0.00E-6"},
+		{mustEncode(t, 0, -9), "0E-9"},
+		{mustEncode(t, 0, -10), "0.0E-9"},
+		{mustEncode(t, 12345, -2), "123.45"},
+		{mustEncode(t, 100, -2), "1.00"},
+		{mustEncode(t, 1234567, 4), "12.34567E+9"},
+		{mustEncode(t, 9999999, 90), "9.999999E+96"},
+		{mustEncode(t, 1, -101), "10E-102"},
+		{inf32 | signMask, "-Infinity"},
+		{nan32, "NaN"},
+	}
+	for i, testCase := range testCases {
+		if s := testCase.d.EngineeringString(); s != testCase.ref {
+			t.Errorf("testCase #%d: expect %q, got %q", i, testCase.ref, s)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	testCases := []struct {
+		format string
+		d      Dec32
+		ref    string
+	}{
+		{"%v", mustEncode(t, 100, -2), "1.00"},
+		{"%s", mustEncode(t, 123, 3), "1.23E+5"},
+		{"%.2v", mustEncode(t, 12345, -2), "1.2E+2"},
+		{"%e", mustEncode(t, 12345, -2), "1.2345e+02"},
+		{"%E", mustEncode(t, 100, -2), "1.00E+00"},
+		{"%e", mustEncode(t, 1, 0), "1e+00"},
+		{"%e", mustEncode(t, 0, -2), "0e-02"},
+		{"%.3e", mustEncode(t, 1, -101), "1.000e-101"},
+		{"%.2e", mustEncode(t, 12345, -2), "1.23e+02"},
+		{"%.1e", mustEncode(t, 995, -3), "1.0e+00"},
+		{"%.0e", mustEncode(t, -25, 0), "-2e+01"},
+		{"%f", mustEncode(t, 123, 3), "123000"},
+		{"%f", mustEncode(t, 5, -8), "0.00000005"},
+		{"%.2f", mustEncode(t, 12345, -3), "12.34"},
+		{"%.2f", mustEncode(t, 12355, -3), "12.36"},
+		{"%.3f", mustEncode(t, 15, -1), "1.500"},
+		{"%.0f", mustEncode(t, 25, -1), "2"},
+		{"%.1f", pack32(true, 0, -3), "-0.0"},
+		{"%g", mustEncode(t, 123, -10), "1.23e-8"},
+		{"%G", mustEncode(t, 123, -10), "1.23E-8"},
+		{"%.3g", mustEncode(t, 15, -1), "1.50"},
+		{"%+v", mustEncode(t, 15, -1), "+1.5"},
+		{"% v", mustEncode(t, 15, -1), " 1.5"},
+		{"%8v", mustEncode(t, -15, -1), "    -1.5"},
+		{"%-8v|", mustEncode(t, -15, -1), "-1.5    |"},
+		{"%08.2f", mustEncode(t, -15, -1), "-0001.50"},
+		{"%+010e", mustEncode(t, 15, -1), "+001.5e+00"},
+		{"%08v", inf32 | signMask, "-Infinity"},
+		{"%010v", inf32, "  Infinity"},
+		{"%.2f", nan32, "NaN"},
+		{"%e", Dec32(snanMask), "sNaN"},
+		{"%+f", inf32, "+Infinity"},
+		{"%x", mustEncode(t, 1, 0), "32800001"},
+		{"%#08x", mustEncode(t, 1, 0), "0x32800001"},
+		{"%d", Dec32(7), "7"},
+		{"%q", mustEncode(t, 1, 0), "%!q(decimal.Dec32=1)"},
+	}
+	for i, testCase := range testCases {
+		if s := fmt.Sprintf(testCase.format, testCase.d); s != testCase.ref {
+			t.Errorf("testCase #%d: %q: expect %q, got %q", i, testCase.format, testCase.ref, s)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+// RoundToIntegral returns d rounded to an integer with the given mode, the
+// IEEE 754 roundToIntegral operations, so that 2.5 rounds to 2 with
+// ToNearestEven and -2.5 to -3 with ToNegativeInf. A rounded result has
+// exponent 0 and keeps the sign of d, so -0.4 rounds to -0 with
+// ToNearestEven. Values with a nonnegative exponent, which are integers
+// already, and infinities are returned unchanged, and NaNs as a quiet NaN.
+func (d Dec32) RoundToIntegral(mode RoundingMode) Dec32 {
+	switch {
+	case d.IsNaN():
+		return d.quiet()
+	case d.IsInf():
+		return d
+	}
+	if _, exp, _ := d.Decode(); exp >= 0 {
+		return d
+	}
+	// Rounding to exponent 0 cannot need more than seven digits.
+	r, _ := d.Rescale(0, mode)
+	return r
+}
+
+// Ceil returns the least integer value greater than or equal to d, as
+// RoundToIntegral does with ToPositiveInf.
+func (d Dec32) Ceil() Dec32 {
+	return d.RoundToIntegral(ToPositiveInf)
+}
+
+// Floor returns the greatest integer value less than or equal to d, as
+// RoundToIntegral does with ToNegativeInf.
+func (d Dec32) Floor() Dec32 {
+	return d.RoundToIntegral(ToNegativeInf)
+}
+
+// Trunc returns the integer value of d with any fractional digits removed,
+// as RoundToIntegral does with ToZero.
+func (d Dec32) Trunc() Dec32 {
+	return d.RoundToIntegral(ToZero)
+}
@@ -0,0 +1,52 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import "testing"
+
+func TestRoundToIntegral(t *testing.T) {
+	testCases := []struct {
+		d                        Dec32
+		even, ceil, floor, trunc Dec32
+	}{
+		{mustEncode(t, 25, -1),
+			mustEncode(t, 2, 0), mustEncode(t, 3, 0), mustEncode(t, 2, 0), mustEncode(t, 2, 0)},
+		{mustEncode(t, -25, -1),
+			mustEncode(t, -2, 0), mustEncode(t, -2, 0), mustEncode(t, -3, 0), mustEncode(t, -2, 0)},
+		{mustEncode(t, 35, -1),
+			mustEncode(t, 4, 0), mustEncode(t, 4, 0), mustEncode(t, 3, 0), mustEncode(t, 3, 0)},
+		{mustEncode(t, -4, -1),
+			pack32(true, 0, 0), pack32(true, 0, 0), mustEncode(t, -1, 0), pack32(true, 0, 0)},
+		{mustEncode(t, 9999999, -1),
+			mustEncode(t, 1000000, 0), mustEncode(t, 1000000, 0), mustEncode(t, 999999, 0), mustEncode(t, 999999, 0)},
+		{mustEncode(t, 1200, -2),
+			mustEncode(t, 12, 0), mustEncode(t, 12, 0), mustEncode(t, 12, 0), mustEncode(t, 12, 0)},
+		{mustEncode(t, 12, 3),
+			mustEncode(t, 12, 3), mustEncode(t, 12, 3), mustEncode(t, 12, 3), mustEncode(t, 12, 3)},
+		{SmallestSubnormalDec32,
+			mustEncode(t, 0, 0), mustEncode(t, 1, 0), mustEncode(t, 0, 0), mustEncode(t, 0, 0)},
+		{inf32 | signMask, inf32 | signMask, inf32 | signMask, inf32 | signMask, inf32 | signMask},
+		{nan32, nan32, nan32, nan32, nan32},
+	}
+	for i, testCase := range testCases {
+		d := testCase.d
+		for _, op := range []struct {
+			name   string
+			r, ref Dec32
+		}{
+			{"RoundToIntegral", d.RoundToIntegral(ToNearestEven), testCase.even},
+			{"Ceil", d.Ceil(), testCase.ceil},
+			{"Floor", d.Floor(), testCase.floor},
+			{"Trunc", d.Trunc(), testCase.trunc},
+		} {
+			if op.r != op.ref {
+				t.Errorf("testCase #%d: %s(%v): expect %v, got %v", i, op.name, d, op.ref, op.r)
+			}
+		}
+	}
+	if r := mustEncode(t, 25, -1).RoundToIntegral(ToNearestAway); r != mustEncode(t, 3, 0) {
+		t.Errorf("expect 3, got %v", r)
+	}
+}
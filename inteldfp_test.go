@@ -0,0 +1,64 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build inteldfp && cgo
+
+package decimal
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/cmars/ieee754-dec/internal/inteldfp"
+)
+
+// randomDifferential returns a random decimal32 value, weighted towards
+// full-precision coefficients and the ends of the exponent range where
+// rounding bugs tend to hide.
+func randomDifferential(r *rand.Rand) Dec32 {
+	switch r.Intn(20) {
+	case 0:
+		return inf32 | signOf(r.Intn(2) == 0)
+	case 1:
+		return nan32
+	}
+	digits := 1 + r.Intn(7)
+	coeff := r.Int63n(int64(pow10Uint64[digits]))
+	var exp int
+	switch r.Intn(4) {
+	case 0:
+		exp = minExp + r.Intn(10)
+	case 1:
+		exp = maxExp - r.Intn(10)
+	default:
+		exp = -10 + r.Intn(20)
+	}
+	return pack32(r.Intn(2) == 0, uint32(coeff), exp)
+}
+
+func TestIntelDifferential(t *testing.T) {
+	ops := []struct {
+		name  string
+		work  func(z, x, y *DecWork) *DecWork
+		intel func(x, y uint32) (uint32, inteldfp.Flags)
+	}{
+		{"add", (*DecWork).Add, inteldfp.Add32},
+		{"sub", (*DecWork).Sub, inteldfp.Sub32},
+		{"mul", (*DecWork).Mul, inteldfp.Mul32},
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000000; i++ {
+		x, y := randomDifferential(r), randomDifferential(r)
+		for _, op := range ops {
+			got := op.work(new(DecWork), NewDecWork(x), NewDecWork(y)).Dec32()
+			ref, _ := op.intel(uint32(x), uint32(y))
+			if got.IsNaN() && Dec32(ref).IsNaN() {
+				continue
+			}
+			if uint32(got) != ref {
+				t.Errorf("%s(%v, %v): expect %v (%x), got %v (%x)", op.name, x, y, Dec32(ref), ref, got, uint32(got))
+			}
+		}
+	}
+}
@@ -0,0 +1,16 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package inteldfp binds the Intel Decimal Floating-Point Math Library for
+// differential testing of the decimal package. It is only built with the
+// inteldfp build tag and cgo enabled, and needs the library's headers and
+// libbid.a, built with its default calling conventions:
+//
+//	make CC=gcc CALL_BY_REF=0 GLOBAL_RND=0 GLOBAL_FLAGS=0 UNCHANGED_BINARY_FLAGS=0
+//
+// Point cgo at the build with CGO_CFLAGS=-I<LIBRARY>/src and
+// CGO_LDFLAGS=<LIBRARY>/libbid.a, then run
+//
+//	go test -tags inteldfp .
+package inteldfp
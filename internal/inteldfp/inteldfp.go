@@ -0,0 +1,51 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build inteldfp && cgo
+
+package inteldfp
+
+/*
+#include "bid_conf.h"
+#include "bid_functions.h"
+
+static BID_UINT32 dfp_bid32_add(BID_UINT32 x, BID_UINT32 y, _IDEC_flags *flags) {
+	return bid32_add(x, y, BID_ROUNDING_TO_NEAREST, flags);
+}
+
+static BID_UINT32 dfp_bid32_sub(BID_UINT32 x, BID_UINT32 y, _IDEC_flags *flags) {
+	return bid32_sub(x, y, BID_ROUNDING_TO_NEAREST, flags);
+}
+
+static BID_UINT32 dfp_bid32_mul(BID_UINT32 x, BID_UINT32 y, _IDEC_flags *flags) {
+	return bid32_mul(x, y, BID_ROUNDING_TO_NEAREST, flags);
+}
+*/
+import "C"
+
+// Flags holds the IEEE exception flags raised by an operation, as the
+// library's _IDEC_flags bit set.
+type Flags uint
+
+// Add32 returns the decimal32 sum x+y, rounded to nearest, ties to even.
+func Add32(x, y uint32) (uint32, Flags) {
+	var flags C._IDEC_flags
+	r := C.dfp_bid32_add(C.BID_UINT32(x), C.BID_UINT32(y), &flags)
+	return uint32(r), Flags(flags)
+}
+
+// Sub32 returns the decimal32 difference x-y, rounded to nearest, ties to
+// even.
+func Sub32(x, y uint32) (uint32, Flags) {
+	var flags C._IDEC_flags
+	r := C.dfp_bid32_sub(C.BID_UINT32(x), C.BID_UINT32(y), &flags)
+	return uint32(r), Flags(flags)
+}
+
+// Mul32 returns the decimal32 product x*y, rounded to nearest, ties to even.
+func Mul32(x, y uint32) (uint32, Flags) {
+	var flags C._IDEC_flags
+	r := C.dfp_bid32_mul(C.BID_UINT32(x), C.BID_UINT32(y), &flags)
+	return uint32(r), Flags(flags)
+}
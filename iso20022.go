@@ -0,0 +1,64 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+// Limits of the ISO 20022 amount types, such as ActiveCurrencyAndAmount.
+const (
+	iso20022TotalDigits    = 18
+	iso20022FractionDigits = 5
+)
+
+// FormatISO20022 returns d as an ISO 20022 currency amount, as used in pain
+// and pacs messages: plain notation of at most 18 digits, of which at most 5
+// follow the decimal point, without a sign or exponent. Trailing fraction
+// zeros beyond the fifth are dropped, so 0.1500000 is written "0.15000". It
+// returns false if d cannot be written exactly within those limits, or is
+// negative or not finite; ISO 20022 amounts are never negative, the
+// direction being given by a separate indicator such as CdtDbtInd.
+func (d Dec32) FormatISO20022() (string, bool) {
+	if d.IsInf() || d.IsNaN() || (d.Sign() < 0 && !d.Zero()) {
+		return "", false
+	}
+	x := newBigDec32(d)
+	if x.exp < -iso20022FractionDigits && x.rescale(-iso20022FractionDigits) {
+		return "", false
+	}
+	s := appendPlainDigits(nil, []byte(x.coeff.String()), x.exp)
+	if !ValidISO20022Amount(string(s)) {
+		return "", false
+	}
+	return string(s), true
+}
+
+// ValidISO20022Amount reports whether s satisfies the constraints of an ISO
+// 20022 currency amount: decimal digits with an optional decimal point
+// followed by one to five fraction digits, at most 18 digits in total not
+// counting leading zeros, and no sign or exponent.
+func ValidISO20022Amount(s string) bool {
+	digits, frac := 0, -1
+	leading := true
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case '0' <= c && c <= '9':
+			if leading && c == '0' && frac < 0 {
+				continue
+			}
+			leading = false
+			digits++
+			if frac >= 0 {
+				frac++
+			}
+		case c == '.' && frac < 0 && i > 0:
+			frac = 0
+		default:
+			return false
+		}
+	}
+	if s == "" || frac == 0 {
+		return false
+	}
+	return digits <= iso20022TotalDigits && frac <= iso20022FractionDigits
+}
@@ -0,0 +1,64 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import "testing"
+
+func TestFormatISO20022(t *testing.T) {
+	testCases := []struct {
+		d   Dec32
+		ref string
+		ok  bool
+	}{
+		{mustEncode(t, 123450, -2), "1234.50", true},
+		{mustEncode(t, 1500000, -7), "0.15000", true},
+		{mustEncode(t, 1500001, -7), "", false},
+		{mustEncode(t, 1500000, -6), "1.50000", true},
+		{mustEncode(t, 1, -5), "0.00001", true},
+		{mustEncode(t, 1, -6), "", false},
+		{mustEncode(t, 9999999, 11), "999999900000000000", true},
+		{mustEncode(t, 1, 18), "", false},
+		{mustEncode(t, 0, 3), "0", true},
+		{mustEncode(t, 0, -8) | signMask, "0.00000", true},
+		{mustEncode(t, -1, 0), "", false},
+		{inf32, "", false},
+		{nan32, "", false},
+	}
+	for i, testCase := range testCases {
+		s, ok := testCase.d.FormatISO20022()
+		if s != testCase.ref || ok != testCase.ok {
+			t.Errorf("testCase #%d: expect %q %v, got %q %v", i, testCase.ref, testCase.ok, s, ok)
+		}
+	}
+}
+
+func TestValidISO20022Amount(t *testing.T) {
+	testCases := []struct {
+		s  string
+		ok bool
+	}{
+		{"0", true},
+		{"1234.5", true},
+		{"0.00001", true},
+		{"0.000001", false},
+		{"123456789012345678", true},
+		{"1234567890123456789", false},
+		{"1234567890123.12345", true},
+		{"12345678901234.12345", false},
+		{"000000000000000000001", true},
+		{"", false},
+		{".5", false},
+		{"5.", false},
+		{"-1", false},
+		{"+1", false},
+		{"1E+3", false},
+		{"1.2.3", false},
+	}
+	for i, testCase := range testCases {
+		if ok := ValidISO20022Amount(testCase.s); ok != testCase.ok {
+			t.Errorf("testCase #%d: %q: expect %v, got %v", i, testCase.s, testCase.ok, ok)
+		}
+	}
+}
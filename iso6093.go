@@ -0,0 +1,125 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"strconv"
+	"strings"
+)
+
+// An NRForm is one of the numeric representations of ISO 6093, used by EDI
+// formats, instrument protocols and the base-10 ASN.1 REAL encoding. The
+// values of the constants are the form numbers that ASN.1 uses.
+type NRForm uint8
+
+const (
+	// NR1 is the integer form, such as "-42".
+	NR1 NRForm = 1 + iota
+	// NR2 is the form with a decimal mark, such as "1.50" or "12.".
+	NR2
+	// NR3 is the form with a decimal mark and an exponent, such as
+	// "1.5E+3".
+	NR3
+)
+
+// String returns the name of the form, such as "NR1".
+func (form NRForm) String() string {
+	if form < NR1 || form > NR3 {
+		return "NRForm(" + strconv.Itoa(int(form)) + ")"
+	}
+	return "NR" + strconv.Itoa(int(form))
+}
+
+// FormatNR returns d in the ISO 6093 form given, reporting whether the
+// string is exactly d. NR1 rounds d to an integer, ties to even, so that
+// 2.5 is written "2" and reported inexact. NR2 writes d in plain notation
+// with its trailing zeros and always a decimal mark, so that 12 is written
+// "12."; NR3 writes it in scientific notation with one digit before the
+// decimal mark and a signed exponent, "1.50E+0" for 1.50. ISO 6093 has no
+// representation of infinities and NaNs; for those, and for unknown forms,
+// FormatNR returns "" and false.
+func (d Dec32) FormatNR(form NRForm) (string, bool) {
+	if d.IsInf() || d.IsNaN() || form < NR1 || form > NR3 {
+		return "", false
+	}
+	x := newBigDec32(d)
+	exact := true
+	if form == NR1 && x.exp < 0 {
+		exact = !x.rescale(0)
+	}
+	var buf []byte
+	if x.neg {
+		buf = append(buf, '-')
+	}
+	c := []byte(x.coeff.String())
+	switch form {
+	case NR1:
+		buf = appendPlainDigits(buf, c, max(x.exp, 0))
+	case NR2:
+		buf = appendPlainDigits(buf, c, x.exp)
+		if x.exp >= 0 {
+			buf = append(buf, '.')
+		}
+	case NR3:
+		buf = append(buf, c[0], '.')
+		buf = append(buf, c[1:]...)
+		buf = append(buf, 'E')
+		adjusted := x.exp + len(c) - 1
+		if adjusted >= 0 {
+			buf = append(buf, '+')
+		}
+		buf = strconv.AppendInt(buf, int64(adjusted), 10)
+	}
+	return string(buf), exact
+}
+
+// ParseNR parses s in the ISO 6093 form given and rounds it to the nearest
+// decimal32, ties to even, reporting whether the result is exact. As ISO 6093
+// allows, s may have leading spaces, a '+' or '-' sign, a comma in place of
+// the decimal point, and a lowercase exponent mark. NR1 is an integer, NR2
+// requires a decimal mark with a digit on at least one side, and NR3 a
+// decimal mark and an exponent. The exponent of the result is that implied
+// by s, as for ParseDec32.
+//
+// The errors that ParseNR returns have concrete type *strconv.NumError, with
+// err.Err strconv.ErrSyntax if s is not in the given form and
+// strconv.ErrRange if it is too large in magnitude.
+func ParseNR(s string, form NRForm) (d Dec32, exact bool, err error) {
+	x, ok := parseNR(s, form)
+	if !ok {
+		return nan32, false, &strconv.NumError{Func: "ParseNR", Num: s, Err: strconv.ErrSyntax}
+	}
+	d, exact = x.dec32()
+	if d.IsInf() {
+		return d, false, &strconv.NumError{Func: "ParseNR", Num: s, Err: strconv.ErrRange}
+	}
+	return d, exact, nil
+}
+
+// parseNR parses the ISO 6093 numeric representation s in the given form
+// exactly, reporting whether it is valid.
+func parseNR(s string, form NRForm) (*bigDec, bool) {
+	s = strings.TrimLeft(s, " ")
+	end := strings.IndexAny(s, "Ee")
+	if end < 0 {
+		end = len(s)
+	}
+	mark := strings.IndexAny(s[:end], ".,")
+	switch {
+	case form == NR1 && (mark >= 0 || end < len(s)):
+		return nil, false
+	case form == NR2 && (mark < 0 || end < len(s)):
+		return nil, false
+	case form == NR3 && (mark < 0 || end == len(s)):
+		return nil, false
+	case form < NR1 || form > NR3:
+		return nil, false
+	}
+	if mark >= 0 {
+		s = s[:mark] + "." + s[mark+1:]
+	}
+	x, parsed, ok := parseDecimal(s)
+	return x, ok && parsed == parsedFinite
+}
@@ -0,0 +1,83 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestFormatNR(t *testing.T) {
+	testCases := []struct {
+		d     Dec32
+		form  NRForm
+		ref   string
+		exact bool
+	}{
+		{mustEncode(t, -42, 0), NR1, "-42", true},
+		{mustEncode(t, 25, -1), NR1, "2", false},
+		{mustEncode(t, 35, -1), NR1, "4", false},
+		{mustEncode(t, 300, -2), NR1, "3", true},
+		{mustEncode(t, 15, 2), NR1, "1500", true},
+		{mustEncode(t, 150, -2), NR2, "1.50", true},
+		{mustEncode(t, 12, 0), NR2, "12.", true},
+		{mustEncode(t, 12, 2), NR2, "1200.", true},
+		{mustEncode(t, -5, -3), NR2, "-0.005", true},
+		{mustEncode(t, 150, -2), NR3, "1.50E+0", true},
+		{mustEncode(t, 15, 2), NR3, "1.5E+3", true},
+		{mustEncode(t, 7, -9), NR3, "7.E-9", true},
+		{mustEncode(t, 0, -2), NR3, "0.E-2", true},
+		{inf32, NR2, "", false},
+		{nan32, NR3, "", false},
+		{mustEncode(t, 1, 0), NRForm(4), "", false},
+	}
+	for i, testCase := range testCases {
+		s, exact := testCase.d.FormatNR(testCase.form)
+		if s != testCase.ref || exact != testCase.exact {
+			t.Errorf("testCase #%d: %v %v: expect %q %v, got %q %v", i, testCase.d, testCase.form, testCase.ref, testCase.exact, s, exact)
+		}
+		if s == "" {
+			continue
+		}
+		d, exact, err := ParseNR(s, testCase.form)
+		if err != nil || !exact || testCase.exact && cmp32(d, testCase.d) != 0 {
+			t.Errorf("testCase #%d: %q: expect %v, got %v %v %v", i, s, testCase.d, d, exact, err)
+		}
+	}
+}
+
+func TestParseNR(t *testing.T) {
+	testCases := []struct {
+		s     string
+		form  NRForm
+		ref   string
+		exact bool
+		err   error
+	}{
+		{"  +42", NR1, "42", true, nil},
+		{"1,50", NR2, "1.50", true, nil},
+		{".5", NR2, "0.5", true, nil},
+		{"-12,5e-3", NR3, "-0.0125", true, nil},
+		{"123456789", NR1, "1.234568E+8", false, nil},
+		{"1.E97", NR3, "Infinity", false, strconv.ErrRange},
+		{"1.5", NR1, "", false, strconv.ErrSyntax},
+		{"15", NR2, "", false, strconv.ErrSyntax},
+		{"1.5E3", NR2, "", false, strconv.ErrSyntax},
+		{"15E3", NR3, "", false, strconv.ErrSyntax},
+		{"1.,5", NR2, "", false, strconv.ErrSyntax},
+		{"Inf", NR1, "", false, strconv.ErrSyntax},
+		{"1", NRForm(0), "", false, strconv.ErrSyntax},
+	}
+	for i, testCase := range testCases {
+		d, exact, err := ParseNR(testCase.s, testCase.form)
+		if !errors.Is(err, testCase.err) || exact != testCase.exact || testCase.ref != "" && d.String() != testCase.ref {
+			t.Errorf("testCase #%d: %q: expect %s %v %v, got %v %v %v", i, testCase.s, testCase.ref, testCase.exact, testCase.err, d, exact, err)
+		}
+	}
+	if s := NR3.String(); s != "NR3" {
+		t.Errorf("expect NR3, got %s", s)
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+// LogB returns the adjusted exponent of d, the exponent of its most
+// significant digit, as a decimal integer, the IEEE 754 logB operation in
+// the decimal form of decNumber: LogB(250) is 2 and LogB(0.03) is -2. As with
+// math.Logb, LogB(±0) is -Inf, LogB(±Inf) is +Inf and NaNs give a quiet NaN.
+// ILogB returns the exponent as an int.
+func (d Dec32) LogB() Dec32 {
+	var c Context
+	return c.LogB(d)
+}
+
+// ScaleB returns d multiplied by 10^n, the IEEE 754 scaleB operation, by
+// adding n to its exponent, so that scaling 1234 cents by -2 gives 12.34
+// dollars exactly. Where the exponent would exceed 90, zeros are appended to
+// the coefficient while it has room, and otherwise the result overflows to
+// an infinity. Below an exponent of -101, the coefficient loses digits,
+// rounded to nearest, ties to even, and may become zero. Infinities are
+// returned unchanged, and NaNs as a quiet NaN.
+func (d Dec32) ScaleB(n int) Dec32 {
+	var c Context
+	return c.ScaleB(d, n)
+}
+
+// scaleBLimit bounds the scale of ScaleB beyond which every finite nonzero
+// value overflows or underflows to zero, so that adding it to an exponent
+// cannot overflow.
+const scaleBLimit = 2 * (maxExp - minExp + 7)
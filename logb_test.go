@@ -0,0 +1,66 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLogB(t *testing.T) {
+	testCases := []struct {
+		d, ref Dec32
+	}{
+		{mustEncode(t, 250, 0), mustEncode(t, 2, 0)},
+		{mustEncode(t, 3, -2), mustEncode(t, -2, 0)},
+		{mustEncode(t, -7, 0), mustEncode(t, 0, 0)},
+		{SmallestSubnormalDec32, mustEncode(t, -101, 0)},
+		{MaxDec32, mustEncode(t, 96, 0)},
+		{mustEncode(t, 0, 3), inf32 | signMask},
+		{inf32 | signMask, inf32},
+		{nan32, nan32},
+	}
+	for i, testCase := range testCases {
+		if r := testCase.d.LogB(); r != testCase.ref {
+			t.Errorf("testCase #%d: LogB(%v): expect %v, got %v", i, testCase.d, testCase.ref, r)
+		}
+	}
+	var c Context
+	if c.LogB(mustEncode(t, 0, 0)); c.Flags != DivisionByZero {
+		t.Errorf("expect DivisionByZero, got %v", c.Flags)
+	}
+}
+
+func TestScaleB(t *testing.T) {
+	testCases := []struct {
+		d   Dec32
+		n   int
+		ref Dec32
+	}{
+		{mustEncode(t, 1234, 0), -2, mustEncode(t, 1234, -2)},
+		{mustEncode(t, 15, -1), 3, mustEncode(t, 15, 2)},
+		{mustEncode(t, 1, 90), 3, mustEncode(t, 1000, 90)},
+		{mustEncode(t, 1, 90), 7, inf32},
+		{mustEncode(t, -1234567, 90), 1, inf32 | signMask},
+		{mustEncode(t, 1234567, -101), -1, mustEncode(t, 123457, -101)},
+		{mustEncode(t, 15, -101), -1, mustEncode(t, 2, -101)},
+		{mustEncode(t, 25, -101), -1, mustEncode(t, 2, -101)},
+		{mustEncode(t, 5, -101), -1, mustEncode(t, 0, -101)},
+		{pack32(true, 0, -5), 200, pack32(true, 0, 90)},
+		{mustEncode(t, 1, 0), math.MinInt, mustEncode(t, 0, -101)},
+		{mustEncode(t, 1, 0), math.MaxInt, inf32},
+		{inf32 | signMask, 5, inf32 | signMask},
+		{nan32, 5, nan32},
+	}
+	for i, testCase := range testCases {
+		if r := testCase.d.ScaleB(testCase.n); r != testCase.ref {
+			t.Errorf("testCase #%d: ScaleB(%v, %d): expect %v, got %v", i, testCase.d, testCase.n, testCase.ref, r)
+		}
+	}
+	var c Context
+	if c.ScaleB(mustEncode(t, 15, -101), -1); c.Flags != Inexact|Underflow {
+		t.Errorf("expect Inexact|Underflow, got %v", c.Flags)
+	}
+}
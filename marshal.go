@@ -0,0 +1,227 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"encoding"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"strconv"
+)
+
+var (
+	_ encoding.TextAppender      = Dec32(0)
+	_ encoding.BinaryAppender    = Dec32(0)
+	_ encoding.TextMarshaler     = Dec32(0)
+	_ encoding.TextUnmarshaler   = (*Dec32)(nil)
+	_ encoding.BinaryMarshaler   = Dec32(0)
+	_ encoding.BinaryUnmarshaler = (*Dec32)(nil)
+	_ encoding.BinaryAppender    = Dec64(0)
+	_ encoding.BinaryMarshaler   = Dec64(0)
+	_ encoding.BinaryUnmarshaler = (*Dec64)(nil)
+	_ encoding.BinaryAppender    = Dec128{}
+	_ encoding.BinaryMarshaler   = Dec128{}
+	_ encoding.BinaryUnmarshaler = (*Dec128)(nil)
+	_ json.Marshaler             = Dec32(0)
+	_ json.Unmarshaler           = (*Dec32)(nil)
+	_ json.Marshaler             = QuotedDec32(0)
+	_ json.Unmarshaler           = (*QuotedDec32)(nil)
+)
+
+var (
+	errJSONDecimal       = errors.New("decimal: JSON value is not a number or string")
+	errEncodingLength64  = errors.New("decimal: decimal64 encoding is not 8 bytes")
+	errEncodingLength128 = errors.New("decimal: decimal128 encoding is not 16 bytes")
+)
+
+// AppendText implements the encoding.TextAppender interface, appending the
+// scientific string form of d, as returned by String, to b.
+func (d Dec32) AppendText(b []byte) ([]byte, error) {
+	return d.appendString(b), nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface, returning the
+// scientific string form of d, as returned by String.
+func (d Dec32) MarshalText() ([]byte, error) {
+	return d.AppendText(nil)
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface, parsing
+// the text as ParseDec32 does. If the text cannot be parsed, or is too large
+// in magnitude to represent, d is unchanged and the error of ParseDec32 is
+// returned.
+func (d *Dec32) UnmarshalText(b []byte) error {
+	v, err := ParseDec32(string(b))
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// AppendBinary implements the encoding.BinaryAppender interface, appending
+// the 4-byte decimal32 interchange encoding of d to b in big-endian order.
+func (d Dec32) AppendBinary(b []byte) ([]byte, error) {
+	return binary.BigEndian.AppendUint32(b, uint32(d)), nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface, returning
+// the 4-byte decimal32 interchange encoding of d in big-endian order.
+func (d Dec32) MarshalBinary() ([]byte, error) {
+	return d.AppendBinary(make([]byte, 0, 4))
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface,
+// decoding the 4-byte big-endian interchange encoding written by
+// MarshalBinary. Every encoding is kept as it is, non-canonical ones and NaN
+// payloads included, so that values round-trip bit for bit; DecodeStrict
+// rejects non-canonical encodings instead. It returns an error, leaving d
+// unchanged, if b is not 4 bytes long.
+func (d *Dec32) UnmarshalBinary(b []byte) error {
+	if len(b) != 4 {
+		return errEncodingLength
+	}
+	*d = Dec32(binary.BigEndian.Uint32(b))
+	return nil
+}
+
+// DecodeBytes decodes the 4-byte decimal32 interchange encoding b in the
+// given byte order, keeping the encoding as it is, as UnmarshalBinary does.
+// It returns an error if b is not 4 bytes long.
+func DecodeBytes(b []byte, order binary.ByteOrder) (Dec32, error) {
+	if len(b) != 4 {
+		return nan32, errEncodingLength
+	}
+	return Dec32(order.Uint32(b)), nil
+}
+
+// AppendBinary implements the encoding.BinaryAppender interface, appending
+// the 8-byte decimal64 interchange encoding of d to b in big-endian order,
+// the layout of the AMQP 1.0 decimal64 type and of IEEE 754 itself.
+func (d Dec64) AppendBinary(b []byte) ([]byte, error) {
+	return binary.BigEndian.AppendUint64(b, uint64(d)), nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface, returning
+// the 8-byte decimal64 interchange encoding of d in big-endian order.
+func (d Dec64) MarshalBinary() ([]byte, error) {
+	return d.AppendBinary(make([]byte, 0, 8))
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface,
+// decoding the 8-byte big-endian interchange encoding written by
+// MarshalBinary and keeping it as it is. It returns an error, leaving d
+// unchanged, if b is not 8 bytes long.
+func (d *Dec64) UnmarshalBinary(b []byte) error {
+	v, err := DecodeBytes64(b, binary.BigEndian)
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// DecodeBytes64 decodes the 8-byte decimal64 interchange encoding b in the
+// given byte order, as DecodeBytes does.
+func DecodeBytes64(b []byte, order binary.ByteOrder) (Dec64, error) {
+	if len(b) != 8 {
+		return nan64, errEncodingLength64
+	}
+	return Dec64(order.Uint64(b)), nil
+}
+
+// AppendBinary implements the encoding.BinaryAppender interface, appending
+// the 16-byte decimal128 interchange encoding of d to b in big-endian order,
+// the high 64 bits first.
+func (d Dec128) AppendBinary(b []byte) ([]byte, error) {
+	b = binary.BigEndian.AppendUint64(b, d.hi)
+	return binary.BigEndian.AppendUint64(b, d.lo), nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface, returning
+// the 16-byte decimal128 interchange encoding of d in big-endian order.
+func (d Dec128) MarshalBinary() ([]byte, error) {
+	return d.AppendBinary(make([]byte, 0, 16))
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface,
+// decoding the 16-byte big-endian interchange encoding written by
+// MarshalBinary and keeping it as it is. It returns an error, leaving d
+// unchanged, if b is not 16 bytes long.
+func (d *Dec128) UnmarshalBinary(b []byte) error {
+	v, err := DecodeBytes128(b, binary.BigEndian)
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// DecodeBytes128 decodes the 16-byte decimal128 interchange encoding b in
+// the given byte order, as DecodeBytes does. The whole encoding is in that
+// order, so a little-endian encoding holds the low 64 bits first.
+func DecodeBytes128(b []byte, order binary.ByteOrder) (Dec128, error) {
+	if len(b) != 16 {
+		return nan128, errEncodingLength128
+	}
+	hi, lo := order.Uint64(b[:8]), order.Uint64(b[8:])
+	if order.Uint16([]byte{1, 0}) == 1 {
+		// Little-endian: the low half comes first.
+		hi, lo = lo, hi
+	}
+	return Dec128{hi, lo}, nil
+}
+
+// MarshalJSON implements the json.Marshaler interface. A finite value is
+// written as a JSON number in the scientific string form of String, keeping
+// its exponent, so 1.50 is written 1.50. JSON has no numbers for infinities
+// and NaNs, which are written as the strings "Infinity", "-Infinity" and
+// "NaN". Use QuotedDec32 to write every value as a string.
+func (d Dec32) MarshalJSON() ([]byte, error) {
+	if d.IsInf() || d.IsNaN() {
+		return strconv.AppendQuote(nil, d.String()), nil
+	}
+	return d.AppendText(nil)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. The JSON value may
+// be a number, or a string holding any decimal string that ParseDec32
+// accepts, such as "1.50" or "Infinity". The value is rounded to decimal32
+// as ParseDec32 rounds it; values too large in magnitude are an error. null
+// leaves d unchanged, as is the convention.
+func (d *Dec32) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	switch {
+	case s == "null":
+		return nil
+	case len(s) > 0 && s[0] == '"':
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		return d.UnmarshalText([]byte(s))
+	case !validFHIR(s):
+		// The FHIR decimal syntax is that of JSON numbers.
+		return errJSONDecimal
+	}
+	return d.UnmarshalText(b)
+}
+
+// QuotedDec32 is a decimal that is marshaled to JSON as a string, such as
+// "1.50", for consumers that would read a JSON number as a binary float and
+// lose digits, as JavaScript does. It is unmarshaled as Dec32 is, from a
+// string or a number.
+type QuotedDec32 Dec32
+
+// MarshalJSON implements the json.Marshaler interface, writing the
+// scientific string form of the decimal as a JSON string.
+func (q QuotedDec32) MarshalJSON() ([]byte, error) {
+	return strconv.AppendQuote(nil, Dec32(q).String()), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, as Dec32 does.
+func (q *QuotedDec32) UnmarshalJSON(b []byte) error {
+	return (*Dec32)(q).UnmarshalJSON(b)
+}
@@ -0,0 +1,226 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func TestAppendText(t *testing.T) {
+	testCases := []struct {
+		d   Dec32
+		ref string
+	}{
+		{mustEncode(t, 12345, -2), "x=123.45"},
+		{mustEncode(t, -1, 10), "x=-1E+10"},
+		{nan32, "x=NaN"},
+	}
+	for i, testCase := range testCases {
+		b, err := testCase.d.AppendText([]byte("x="))
+		if err != nil || string(b) != testCase.ref {
+			t.Errorf("testCase #%d: expect %q, got %q %v", i, testCase.ref, b, err)
+		}
+	}
+}
+
+func TestAppendBinary(t *testing.T) {
+	testCases := []struct {
+		d   Dec32
+		ref []byte
+	}{
+		{mustEncode(t, 9999999, 0), []byte{0xff, 0x6c, 0xb8, 0x96, 0x7f}},
+		{mustEncode(t, -125, -2), []byte{0xff, 0xb1, 0x80, 0x00, 0x7d}},
+		{inf32, []byte{0xff, 0x78, 0x00, 0x00, 0x00}},
+	}
+	for i, testCase := range testCases {
+		b, err := testCase.d.AppendBinary([]byte{0xff})
+		if err != nil || !bytes.Equal(b, testCase.ref) {
+			t.Errorf("testCase #%d: expect %x, got %x %v", i, testCase.ref, b, err)
+		}
+	}
+}
+
+func TestAppendTextAllocs(t *testing.T) {
+	d := mustEncode(t, -9999999, 90)
+	buf := make([]byte, 0, 32)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf, _ = d.AppendText(buf[:0])
+	})
+	if allocs != 0 {
+		t.Errorf("expect no allocations, got %v", allocs)
+	}
+}
+
+func TestMarshalText(t *testing.T) {
+	for _, s := range []string{"123.45", "-0.00", "1.23E+5", "Infinity", "NaN", "sNaN"} {
+		b, err := MustParseDec32(s).MarshalText()
+		if err != nil || string(b) != s {
+			t.Errorf("%s: unexpected %q %v", s, b, err)
+		}
+		var d Dec32
+		if err := d.UnmarshalText(b); err != nil || d != MustParseDec32(s) {
+			t.Errorf("%s: unexpected %v %v", s, d, err)
+		}
+	}
+	d := mustEncode(t, 5, 0)
+	for _, s := range []string{"", "1.2.3", "1E+97"} {
+		if err := d.UnmarshalText([]byte(s)); err == nil || d != mustEncode(t, 5, 0) {
+			t.Errorf("%q: expect error and no change, got %v %v", s, d, err)
+		}
+	}
+}
+
+func TestMarshalBinary(t *testing.T) {
+	for _, d := range []Dec32{mustEncode(t, -125, -2), inf32 | 0x1234, nan32 | 42, Dec32(0x6cb89680)} {
+		b, err := d.MarshalBinary()
+		if err != nil || len(b) != 4 {
+			t.Fatalf("%x: unexpected %x %v", uint32(d), b, err)
+		}
+		var r Dec32
+		if err := r.UnmarshalBinary(b); err != nil || r != d {
+			t.Errorf("%x: round trip gives %x %v", uint32(d), uint32(r), err)
+		}
+	}
+	var r Dec32
+	if err := r.UnmarshalBinary([]byte{1, 2, 3}); err == nil || r != 0 {
+		t.Errorf("expect length error, got %x %v", uint32(r), err)
+	}
+
+	// Dec32 values are gob encoded with MarshalBinary.
+	type record struct{ Price Dec32 }
+	var buf bytes.Buffer
+	in := record{mustEncode(t, 1999, -2)}
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatal(err)
+	}
+	var out record
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil || out != in {
+		t.Errorf("gob round trip gives %v %v", out.Price, err)
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	type record struct {
+		Price  Dec32
+		Quoted QuotedDec32
+		Ptr    *Dec32 `json:",omitempty"`
+	}
+	testCases := []struct {
+		r   record
+		ref string
+	}{
+		{record{mustEncode(t, 150, -2), QuotedDec32(mustEncode(t, 150, -2)), nil}, `{"Price":1.50,"Quoted":"1.50"}`},
+		{record{mustEncode(t, -1, 10), QuotedDec32(pack32(true, 0, 0)), nil}, `{"Price":-1E+10,"Quoted":"-0"}`},
+		{record{inf32 | signMask, QuotedDec32(nan32), nil}, `{"Price":"-Infinity","Quoted":"NaN"}`},
+	}
+	for i, testCase := range testCases {
+		b, err := json.Marshal(testCase.r)
+		if err != nil || string(b) != testCase.ref {
+			t.Errorf("testCase #%d: expect %s, got %s %v", i, testCase.ref, b, err)
+			continue
+		}
+		var r record
+		if err := json.Unmarshal(b, &r); err != nil || r.Price.CmpTotal(testCase.r.Price) != 0 || Dec32(r.Quoted).CmpTotal(Dec32(testCase.r.Quoted)) != 0 {
+			t.Errorf("testCase #%d: round trip gives %+v %v", i, r, err)
+		}
+	}
+
+	unmarshalCases := []struct {
+		s   string
+		ref Dec32
+		ok  bool
+	}{
+		{`2.50`, mustEncode(t, 250, -2), true},
+		{`"2.50"`, mustEncode(t, 250, -2), true},
+		{`-2.5e3`, mustEncode(t, -25, 2), true},
+		{`"Infinity"`, inf32, true},
+		{`"1_0"`, 0, false},
+		{`12345678`, mustEncode(t, 1234568, 1), true},
+		{`null`, mustEncode(t, 7, 0), true},
+		{`1E+97`, 0, false},
+		{`true`, 0, false},
+		{`.5`, 0, false},
+		{`Infinity`, 0, false},
+	}
+	for i, testCase := range unmarshalCases {
+		d := mustEncode(t, 7, 0)
+		err := json.Unmarshal([]byte(testCase.s), &d)
+		if testCase.ok && (err != nil || d != testCase.ref) {
+			t.Errorf("testCase #%d: %s: expect %v, got %v %v", i, testCase.s, testCase.ref, d, err)
+		}
+		if !testCase.ok && err == nil {
+			t.Errorf("testCase #%d: %s: expect error, got %v", i, testCase.s, d)
+		}
+	}
+}
+
+func TestDecodeBytes(t *testing.T) {
+	b := []byte{0xb1, 0x80, 0x00, 0x7d}
+	if d, err := DecodeBytes(b, binary.BigEndian); err != nil || d != mustEncode(t, -125, -2) {
+		t.Errorf("expect -1.25, got %v %v", d, err)
+	}
+	if d, err := DecodeBytes([]byte{0x7d, 0x00, 0x80, 0xb1}, binary.LittleEndian); err != nil || d != mustEncode(t, -125, -2) {
+		t.Errorf("expect -1.25, got %v %v", d, err)
+	}
+	if _, err := DecodeBytes(b[:3], binary.BigEndian); err == nil {
+		t.Error("expect length error")
+	}
+}
+
+func TestMarshalBinary64(t *testing.T) {
+	one := MustEncodeDec64(1, 0)
+	b, err := one.MarshalBinary()
+	ref := []byte{0x31, 0xc0, 0, 0, 0, 0, 0, 1}
+	if err != nil || !bytes.Equal(b, ref) {
+		t.Fatalf("expect %x, got %x %v", ref, b, err)
+	}
+	for _, d := range []Dec64{one, MustEncodeDec64(-123456789012345, -300), inf64, nan64 | 42} {
+		b, _ := d.MarshalBinary()
+		var r Dec64
+		if err := r.UnmarshalBinary(b); err != nil || r != d {
+			t.Errorf("%x: round trip gives %x %v", uint64(d), uint64(r), err)
+		}
+		le := binary.LittleEndian.AppendUint64(nil, uint64(d))
+		if r, err := DecodeBytes64(le, binary.LittleEndian); err != nil || r != d {
+			t.Errorf("%x: little-endian decode gives %x %v", uint64(d), uint64(r), err)
+		}
+	}
+	var r Dec64
+	if err := r.UnmarshalBinary(b[:7]); err == nil || r != 0 {
+		t.Errorf("expect length error, got %x %v", uint64(r), err)
+	}
+}
+
+func TestMarshalBinary128(t *testing.T) {
+	one := MustEncodeDec128(big.NewInt(1), 0)
+	b, err := one.MarshalBinary()
+	ref := []byte{0x30, 0x40, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}
+	if err != nil || !bytes.Equal(b, ref) {
+		t.Fatalf("expect %x, got %x %v", ref, b, err)
+	}
+	for _, d := range []Dec128{one, Dec128FromBits(0xb040123456789abc, 0xdef0123456789abc), inf128, nan128} {
+		b, _ := d.MarshalBinary()
+		var r Dec128
+		if err := r.UnmarshalBinary(b); err != nil || r != d {
+			t.Errorf("%x: round trip gives %x %v", d, r, err)
+		}
+		le := make([]byte, 16)
+		for i := range b {
+			le[i] = b[15-i]
+		}
+		if r, err := DecodeBytes128(le, binary.LittleEndian); err != nil || r != d {
+			t.Errorf("%x: little-endian decode gives %x %v", d, r, err)
+		}
+	}
+	var r Dec128
+	if err := r.UnmarshalBinary(b[:15]); err == nil || r != (Dec128{}) {
+		t.Errorf("expect length error, got %x %v", r, err)
+	}
+}
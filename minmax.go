@@ -0,0 +1,68 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+// Min returns the lesser of x and y, as the IEEE 754-2008 minNum operation
+// does: if exactly one operand is a quiet NaN, the other is returned, so that
+// NaNs drop out of a running minimum. If both are NaNs or either is a
+// signaling NaN, the result is a quiet NaN. Of operands that compare equal,
+// the one ordered first by TotalOrder is returned, so Min(0, -0) is -0 and
+// Min(1, 1.0) is 1.0. The result is one of the operands, unrounded.
+func (x Dec32) Min(y Dec32) Dec32 {
+	var c Context
+	return c.Min(x, y)
+}
+
+// Max returns the greater of x and y, as the IEEE 754-2008 maxNum operation
+// does, with the NaN rules of Min. Of operands that compare equal, the one
+// ordered last by TotalOrder is returned, so Max(0, -0) is 0 and Max(1, 1.0)
+// is 1.
+func (x Dec32) Max(y Dec32) Dec32 {
+	var c Context
+	return c.Max(x, y)
+}
+
+// MinMag returns whichever of x and y is less in magnitude, as the IEEE
+// 754-2008 minNumMag operation does, with the NaN rules of Min. If the
+// magnitudes are equal it returns Min(x, y), so MinMag(-2, 2) is -2.
+func (x Dec32) MinMag(y Dec32) Dec32 {
+	var c Context
+	return c.MinMag(x, y)
+}
+
+// MaxMag returns whichever of x and y is greater in magnitude, as the IEEE
+// 754-2008 maxNumMag operation does, with the NaN rules of Min. If the
+// magnitudes are equal it returns Max(x, y), so MaxMag(-2, 2) is 2.
+func (x Dec32) MaxMag(y Dec32) Dec32 {
+	var c Context
+	return c.MaxMag(x, y)
+}
+
+// minMax returns the greater of x and y if greater is set and the lesser
+// otherwise, comparing magnitudes first if mag is set, with the NaN rules of
+// Min.
+func (c *Context) minMax(x, y Dec32, greater, mag bool) Dec32 {
+	checkSpecial(x, y)
+	switch {
+	case x.IsSignalingNaN() || y.IsSignalingNaN() || x.IsNaN() && y.IsNaN():
+		return c.nan(x, y)
+	case x.IsNaN():
+		return y
+	case y.IsNaN():
+		return x
+	}
+	r := 0
+	if mag {
+		r = cmp32(x.Abs(), y.Abs())
+	}
+	if r == 0 {
+		// CmpTotal agrees with cmp32 on values that differ numerically.
+		r = x.CmpTotal(y)
+	}
+	if (r >= 0) == greater {
+		return x
+	}
+	return y
+}
@@ -0,0 +1,68 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import "testing"
+
+func TestMinMax(t *testing.T) {
+	snan, _ := SignalingNaN(7)
+	nan3, _ := QuietNaN(3)
+	nan7, _ := QuietNaN(7)
+	negZero := mustEncode(t, 0, 0) | signMask
+	testCases := []struct {
+		x, y                     Dec32
+		min, max, minMag, maxMag Dec32
+	}{
+		{mustEncode(t, 1, 0), mustEncode(t, 2, 0),
+			mustEncode(t, 1, 0), mustEncode(t, 2, 0), mustEncode(t, 1, 0), mustEncode(t, 2, 0)},
+		{mustEncode(t, -3, 0), mustEncode(t, 2, 0),
+			mustEncode(t, -3, 0), mustEncode(t, 2, 0), mustEncode(t, 2, 0), mustEncode(t, -3, 0)},
+		{mustEncode(t, -2, 0), mustEncode(t, 2, 0),
+			mustEncode(t, -2, 0), mustEncode(t, 2, 0), mustEncode(t, -2, 0), mustEncode(t, 2, 0)},
+		{mustEncode(t, 1, 0), mustEncode(t, 10, -1),
+			mustEncode(t, 10, -1), mustEncode(t, 1, 0), mustEncode(t, 10, -1), mustEncode(t, 1, 0)},
+		{mustEncode(t, 0, 0), negZero,
+			negZero, mustEncode(t, 0, 0), negZero, mustEncode(t, 0, 0)},
+		{inf32 | signMask, mustEncode(t, 5, 0),
+			inf32 | signMask, mustEncode(t, 5, 0), mustEncode(t, 5, 0), inf32 | signMask},
+		{nan32, mustEncode(t, 5, 0),
+			mustEncode(t, 5, 0), mustEncode(t, 5, 0), mustEncode(t, 5, 0), mustEncode(t, 5, 0)},
+		{mustEncode(t, -5, 0), nan32 | signMask,
+			mustEncode(t, -5, 0), mustEncode(t, -5, 0), mustEncode(t, -5, 0), mustEncode(t, -5, 0)},
+		{nan3, nan7, nan3, nan3, nan3, nan3},
+		{nan3, snan, nan7, nan7, nan7, nan7},
+		{snan, mustEncode(t, 1, 0), nan7, nan7, nan7, nan7},
+	}
+	for i, testCase := range testCases {
+		x, y := testCase.x, testCase.y
+		for _, op := range []struct {
+			name string
+			r    Dec32
+			ref  Dec32
+		}{
+			{"Min", x.Min(y), testCase.min},
+			{"Max", x.Max(y), testCase.max},
+			{"MinMag", x.MinMag(y), testCase.minMag},
+			{"MaxMag", x.MaxMag(y), testCase.maxMag},
+		} {
+			if op.r != op.ref {
+				t.Errorf("testCase #%d: %s(%v, %v): expect %v, got %v", i, op.name, x, y, op.ref, op.r)
+			}
+		}
+	}
+}
+
+func TestContextMinMaxFlags(t *testing.T) {
+	snan, _ := SignalingNaN(0)
+	var c Context
+	c.Max(nan32, mustEncode(t, 1, 0))
+	c.Min(mustEncode(t, 1, 0), mustEncode(t, 2, 0))
+	if c.Flags != 0 {
+		t.Errorf("expect no flags, got %v", c.Flags)
+	}
+	if r := c.MinMag(mustEncode(t, 1, 0), snan); !r.IsNaN() || r.IsSignalingNaN() || c.Flags != Invalid {
+		t.Errorf("expect NaN and Invalid, got %v %v", r, c.Flags)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import "math/big"
+
+// SQL Server stores MONEY and SMALLMONEY values as integers counting
+// ten-thousandths of a currency unit.
+const moneyScale = 4
+
+// FromMoney converts a SQL Server MONEY value, an int64 count of
+// ten-thousandths, to a decimal. It returns false if the value cannot be
+// represented exactly in decimal32, in which case the result is rounded to
+// the nearest decimal32, ties to even.
+func FromMoney(m int64) (Dec32, bool) {
+	x := &bigDec{neg: m < 0, exp: -moneyScale}
+	x.coeff.Abs(big.NewInt(m))
+	return x.dec32()
+}
+
+// FromSmallMoney converts a SQL Server SMALLMONEY value, an int32 count of
+// ten-thousandths, to a decimal. It returns false if the value cannot be
+// represented exactly in decimal32, in which case the result is rounded to
+// the nearest decimal32, ties to even.
+func FromSmallMoney(m int32) (Dec32, bool) {
+	return FromMoney(int64(m))
+}
+
+// Money returns the decimal value as a SQL Server MONEY value, an int64 count
+// of ten-thousandths. It returns false if the value is not finite, has more
+// than four fraction digits, or is out of the MONEY range.
+func (d Dec32) Money() (int64, bool) {
+	if d.IsInf() || d.IsNaN() {
+		return 0, false
+	}
+	return newBigDec32(d).scaledInt64(moneyScale)
+}
+
+// SmallMoney returns the decimal value as a SQL Server SMALLMONEY value, an
+// int32 count of ten-thousandths. It returns false if the value is not
+// finite, has more than four fraction digits, or is out of the SMALLMONEY
+// range.
+func (d Dec32) SmallMoney() (int32, bool) {
+	m, ok := d.Money()
+	if !ok || m < -1<<31 || m > 1<<31-1 {
+		return 0, false
+	}
+	return int32(m), true
+}
+
+// tdsDecimalSize returns the number of magnitude bytes that TDS uses to send a
+// DECIMAL or NUMERIC value of the given precision, or 0 if the precision is
+// out of range.
+func tdsDecimalSize(precision uint8) int {
+	switch {
+	case precision < 1:
+		return 0
+	case precision <= 9:
+		return 4
+	case precision <= 19:
+		return 8
+	case precision <= 28:
+		return 12
+	case precision <= 38:
+		return 16
+	}
+	return 0
+}
+
+// FromTDSDecimal converts the TDS wire form of a SQL Server DECIMAL or
+// NUMERIC value with the given scale to a decimal. The wire form is a sign
+// byte, 1 for positive and 0 for negative, followed by the unscaled magnitude
+// as a 4, 8, 12 or 16 byte little-endian integer. It returns false if b is
+// malformed or the value cannot be represented exactly in decimal32, in
+// which case a well-formed value is rounded to the nearest decimal32, ties to
+// even.
+func FromTDSDecimal(b []byte, scale uint8) (Dec32, bool) {
+	switch len(b) {
+	case 5, 9, 13, 17:
+	default:
+		return failDec32, false
+	}
+	if b[0] > 1 || scale > 38 {
+		return failDec32, false
+	}
+	be := make([]byte, len(b)-1)
+	for i := range be {
+		be[i] = b[len(b)-1-i]
+	}
+	x := &bigDec{neg: b[0] == 0, exp: -int(scale)}
+	x.coeff.SetBytes(be)
+	return x.dec32()
+}
+
+// TDSDecimal returns the TDS wire form of the decimal value as a SQL Server
+// DECIMAL(precision, scale). It returns false if the value is not finite,
+// precision and scale are not a valid DECIMAL type, the value has more than
+// scale fraction digits, or it needs more than precision digits.
+func (d Dec32) TDSDecimal(precision, scale uint8) ([]byte, bool) {
+	size := tdsDecimalSize(precision)
+	if size == 0 || scale > precision || d.IsInf() || d.IsNaN() {
+		return nil, false
+	}
+	var c big.Int
+	x := newBigDec32(d)
+	if !x.scaledInt(&c, int(scale)) {
+		return nil, false
+	}
+	if c.Sign() != 0 && bigDigits(&c) > int(precision) {
+		return nil, false
+	}
+	b := make([]byte, 1+size)
+	if !x.neg || c.Sign() == 0 {
+		b[0] = 1
+	}
+	be := c.Bytes()
+	for i, c := range be {
+		b[len(be)-i] = c
+	}
+	return b, true
+}
@@ -0,0 +1,123 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMoney(t *testing.T) {
+	testCases := []struct {
+		money int64
+		ref   Dec32
+		exact bool
+	}{
+		// Eight digits, but the trailing zero can be dropped exactly.
+		{12345000, mustEncode(t, 1234500, -3), true},
+		{1234500, mustEncode(t, 1234500, -4), true},
+		{-1, mustEncode(t, -1, -4), true},
+		{0, mustEncode(t, 0, -4), true},
+		// 922,337,203,685,477.5807 rounds to seven digits.
+		{9223372036854775807, mustEncode(t, 9223372, 8), false},
+	}
+	for i, testCase := range testCases {
+		d, exact := FromMoney(testCase.money)
+		if d != testCase.ref || exact != testCase.exact {
+			t.Errorf("testCase #%d: expect %v %v, got %v %v", i, testCase.ref, testCase.exact, d, exact)
+		}
+	}
+	// 1234.5005 needs eight digits and rounds to even.
+	if d, exact := FromMoney(12345005); exact || d != mustEncode(t, 1234500, -3) {
+		t.Errorf("unexpected rounding: %v", d)
+	}
+
+	for i, testCase := range []struct {
+		d     Dec32
+		money int64
+		ok    bool
+	}{
+		{mustEncode(t, 1050, -2), 105000, true},
+		{mustEncode(t, -1, -4), -1, true},
+		{mustEncode(t, 3, 5), 3000000000, true},
+		{mustEncode(t, 1, -5), 0, false},
+		{mustEncode(t, 10, -5), 1, true},
+		{mustEncode(t, 1, 15), 0, false},
+		{inf32, 0, false},
+		{nan32, 0, false},
+	} {
+		m, ok := testCase.d.Money()
+		if m != testCase.money || ok != testCase.ok {
+			t.Errorf("testCase #%d: expect %d %v, got %d %v", i, testCase.money, testCase.ok, m, ok)
+		}
+	}
+}
+
+func TestSmallMoney(t *testing.T) {
+	if d, ok := FromSmallMoney(-2147483648); ok || d != mustEncode(t, -2147484, -1) {
+		t.Errorf("unexpected %v %v", d, ok)
+	}
+	if m, ok := mustEncode(t, 2147483, -1).SmallMoney(); m != 2147483000 || !ok {
+		t.Errorf("unexpected %d %v", m, ok)
+	}
+	if _, ok := mustEncode(t, 2147484, -1).SmallMoney(); ok {
+		t.Errorf("expected overflow")
+	}
+}
+
+func TestTDSDecimal(t *testing.T) {
+	testCases := []struct {
+		d         Dec32
+		precision uint8
+		scale     uint8
+		tds       []byte
+	}{
+		{mustEncode(t, 12345, -2), 9, 2, []byte{1, 0x39, 0x30, 0, 0}},
+		{mustEncode(t, -12345, -2), 10, 3, []byte{0, 0x3a, 0xe2, 0x01, 0, 0, 0, 0, 0}},
+		{mustEncode(t, 9999999, 20), 38, 10, []byte{1,
+			0x00, 0x00, 0x00, 0xc0, 0xb5, 0x48, 0x7f, 0xba,
+			0x08, 0xaa, 0xad, 0x36, 0x04, 0xee, 0x85, 0x07}},
+		{pack32(true, 0, 0), 5, 0, []byte{1, 0, 0, 0, 0}},
+	}
+	for i, testCase := range testCases {
+		b, ok := testCase.d.TDSDecimal(testCase.precision, testCase.scale)
+		if !ok || !bytes.Equal(b, testCase.tds) {
+			t.Errorf("testCase #%d: expect %x, got %x %v", i, testCase.tds, b, ok)
+			continue
+		}
+		d, ok := FromTDSDecimal(b, testCase.scale)
+		if !ok || cmp32(d, testCase.d) != 0 {
+			t.Errorf("testCase #%d: round trip failed: %v %v", i, d, ok)
+		}
+	}
+
+	for i, testCase := range []struct {
+		d         Dec32
+		precision uint8
+		scale     uint8
+	}{
+		// Too many fraction digits.
+		{mustEncode(t, 12345, -3), 9, 2},
+		// Too many digits.
+		{mustEncode(t, 12345, -2), 4, 2},
+		{mustEncode(t, 1, 0), 0, 0},
+		{mustEncode(t, 1, 0), 39, 0},
+		{mustEncode(t, 1, 0), 5, 6},
+		{inf32, 9, 2},
+	} {
+		if b, ok := testCase.d.TDSDecimal(testCase.precision, testCase.scale); ok {
+			t.Errorf("testCase #%d: expected failure, got %x", i, b)
+		}
+	}
+
+	for i, b := range [][]byte{nil, {1, 0, 0, 0}, {2, 0, 0, 0, 0}} {
+		if _, ok := FromTDSDecimal(b, 0); ok {
+			t.Errorf("testCase #%d: expected malformed input to fail", i)
+		}
+	}
+	if d, ok := FromTDSDecimal([]byte{1, 0x81, 0x96, 0x98, 0}, 0); ok || d != mustEncode(t, 1000000, 1) {
+		t.Errorf("expected 10000001 to round inexactly, got %v %v", d, ok)
+	}
+}
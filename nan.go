@@ -0,0 +1,50 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+// quietBit is the bit that distinguishes a signaling NaN from a quiet one.
+const quietBit = 0x02000000
+
+// IsSignalingNaN returns whether the decimal32 value is a signaling NaN. A
+// signaling NaN raises Invalid when used as an operand of the operations of
+// a Context, which return a quiet NaN for it.
+func (d Dec32) IsSignalingNaN() bool {
+	return d&snanMask == snanMask
+}
+
+// NaNPayload returns the payload of a NaN, the diagnostic integer carried in
+// its trailing significand, between 0 and 999,999. Payloads beyond 999,999
+// are non-canonical and read as 0, as IEEE 754-2008 specifies. NaNPayload
+// returns 0 for values that are not NaNs.
+func (d Dec32) NaNPayload() uint32 {
+	if !d.IsNaN() {
+		return 0
+	}
+	return uint32(d.canonicalEncoding() & nanPayloadMask)
+}
+
+// QuietNaN returns a positive quiet NaN with the given payload, reporting
+// false, with a payload of 0, if the payload is above 999,999.
+func QuietNaN(payload uint32) (Dec32, bool) {
+	if payload > maxPayload {
+		return nan32, false
+	}
+	return nan32 | Dec32(payload), true
+}
+
+// SignalingNaN returns a positive signaling NaN with the given payload,
+// reporting false, with a payload of 0, if the payload is above 999,999.
+func SignalingNaN(payload uint32) (Dec32, bool) {
+	if payload > maxPayload {
+		return Dec32(snanMask), false
+	}
+	return Dec32(snanMask) | Dec32(payload), true
+}
+
+// quiet returns the NaN d as a canonical quiet NaN with the same sign and
+// payload.
+func (d Dec32) quiet() Dec32 {
+	return d.canonicalEncoding() &^ quietBit
+}
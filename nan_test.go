@@ -0,0 +1,85 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestNaNPayload(t *testing.T) {
+	testCases := []struct {
+		d       Dec32
+		nan     bool
+		snan    bool
+		payload uint32
+	}{
+		{nan32, true, false, 0},
+		{nan32 | 42, true, false, 42},
+		{nan32 | signMask | 999999, true, false, 999999},
+		{Dec32(snanMask) | 7, true, true, 7},
+		// Payloads beyond 999,999 are non-canonical and read as zero.
+		{nan32 | 1000000, true, false, 0},
+		{nan32 | 0x000fffff, true, false, 0},
+		// Bits between the signaling bit and the payload are ignored.
+		{nan32 | 0x00f00005, true, false, 5},
+		{inf32 | 5, false, false, 0},
+		{mustEncode(t, 5, 0), false, false, 0},
+	}
+	for i, testCase := range testCases {
+		d := testCase.d
+		if d.IsNaN() != testCase.nan || d.IsSignalingNaN() != testCase.snan || d.NaNPayload() != testCase.payload {
+			t.Errorf("testCase #%d: %x: expect %v %v %d, got %v %v %d", i, uint32(d), testCase.nan, testCase.snan, testCase.payload,
+				d.IsNaN(), d.IsSignalingNaN(), d.NaNPayload())
+		}
+	}
+}
+
+func TestNaNConstructors(t *testing.T) {
+	if d, ok := QuietNaN(123); !ok || !d.IsNaN() || d.IsSignalingNaN() || d.NaNPayload() != 123 || d.Sign() < 0 {
+		t.Errorf("unexpected QuietNaN(123) %x %v", uint32(d), ok)
+	}
+	if d, ok := SignalingNaN(999999); !ok || !d.IsSignalingNaN() || d.NaNPayload() != 999999 {
+		t.Errorf("unexpected SignalingNaN(999999) %x %v", uint32(d), ok)
+	}
+	if d, ok := QuietNaN(1000000); ok || d != nan32 {
+		t.Errorf("unexpected QuietNaN(1000000) %x %v", uint32(d), ok)
+	}
+	if d, ok := SignalingNaN(1 << 20); ok || d != Dec32(snanMask) {
+		t.Errorf("unexpected SignalingNaN(1<<20) %x %v", uint32(d), ok)
+	}
+	// Payloads survive the interchange encoding.
+	d, _ := SignalingNaN(4242)
+	if r, err := DecodeStrict(binary.BigEndian.AppendUint32(nil, uint32(d))); err != nil || r != d {
+		t.Errorf("unexpected %x %v", uint32(r), err)
+	}
+}
+
+func TestNaNPropagation(t *testing.T) {
+	x, _ := QuietNaN(17)
+	s, _ := SignalingNaN(23)
+	one := mustEncode(t, 1, 0)
+	testCases := []struct {
+		r, ref Dec32
+	}{
+		{one.Add(x), x},
+		{x.Mul(s), nan32 | 23},
+		{s.Div(x), nan32 | 23},
+		{one.Sub(s | signMask), nan32 | signMask | 23},
+		{(x | signMask).Div(one), nan32 | signMask | 17},
+		{new(Context).Round(s), nan32 | 23},
+		{new(Context).Quantize(one, s), nan32 | 23},
+		{inf32.Sub(inf32), nan32},
+	}
+	if !debugStrict {
+		// A non-canonical payload is dropped.
+		testCases = append(testCases, struct{ r, ref Dec32 }{one.Add(nan32 | 0x000fffff), nan32})
+	}
+	for i, testCase := range testCases {
+		if testCase.r != testCase.ref {
+			t.Errorf("testCase #%d: expect %x, got %x", i, uint32(testCase.ref), uint32(testCase.r))
+		}
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+// NextUp returns the least decimal32 value greater than d, the IEEE 754
+// nextUp operation. A finite result has a full seven-digit coefficient where
+// the exponent range allows, so NextUp(1) is 1.000001 and NextUp(0) is
+// 1E-101. NextUp(MaxDec32) is +Inf and NextUp(-Inf) is -MaxDec32. The
+// negative value closest to zero steps to -0, which keeps its negative sign,
+// +Inf is its own NextUp, and NaNs give a quiet NaN.
+func (d Dec32) NextUp() Dec32 {
+	return d.next(false)
+}
+
+// NextDown returns the greatest decimal32 value less than d, the IEEE 754
+// nextDown operation. It is the negation of NextUp(-d).
+func (d Dec32) NextDown() Dec32 {
+	return d.next(true)
+}
+
+// next returns the neighbor of d towards -Inf if down is set, and towards
+// +Inf otherwise.
+func (d Dec32) next(down bool) Dec32 {
+	switch {
+	case d.IsNaN():
+		return d.quiet()
+	case d.IsInf():
+		if (d.Sign() < 0) == down {
+			return d
+		}
+		return MaxDec32 | d&signMask
+	case d.Zero():
+		return SmallestSubnormalDec32 | signOf(down)
+	}
+	neg, coeff, exp := d.unpack()
+	// Widen the coefficient to seven digits, so that its last digit is the
+	// least significant one the value set can hold.
+	for coeff <= maxCoeff/10 && exp > minExp {
+		coeff *= 10
+		exp--
+	}
+	if neg == down {
+		// Away from zero.
+		if coeff++; coeff > maxCoeff {
+			coeff, exp = (maxCoeff+1)/10, exp+1
+			if exp > maxExp {
+				return inf32 | signOf(neg)
+			}
+		}
+	} else {
+		// Towards zero, where an exponent below the minimum can only
+		// follow a coefficient of 1000000.
+		if coeff--; coeff <= maxCoeff/10 && exp > minExp {
+			coeff, exp = coeff*10+9, exp-1
+		}
+	}
+	return pack32(neg, coeff, exp)
+}
+
+// Ulp returns the unit in the last place of d at its exponent, 1E+exp for
+// the exponent exp of d, such as 0.01 for 1.00 and 1 for 100. It is the
+// quantum of d, the step between values that share its exponent, and is
+// greater than the distance to NextUp(d) unless the coefficient of d has
+// seven digits or the exponent is the least. Ulp returns +Inf for
+// infinities and a quiet NaN for NaNs.
+func (d Dec32) Ulp() Dec32 {
+	switch {
+	case d.IsNaN():
+		return d.quiet()
+	case d.IsInf():
+		return inf32
+	}
+	_, exp, _ := d.Decode()
+	return pack32(false, 1, int(exp))
+}
@@ -0,0 +1,59 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import "testing"
+
+func TestNextUpDown(t *testing.T) {
+	snan, _ := SignalingNaN(5)
+	nan5, _ := QuietNaN(5)
+	testCases := []struct {
+		d, up, down Dec32
+	}{
+		{mustEncode(t, 1, 0), mustEncode(t, 1000001, -6), mustEncode(t, 9999999, -7)},
+		{mustEncode(t, -1, 0), mustEncode(t, -9999999, -7), mustEncode(t, -1000001, -6)},
+		{mustEncode(t, 9999999, 0), mustEncode(t, 1000000, 1), mustEncode(t, 9999998, 0)},
+		{mustEncode(t, 125, -2), mustEncode(t, 1250001, -6), mustEncode(t, 1249999, -6)},
+		{mustEncode(t, 0, 5), SmallestSubnormalDec32, SmallestSubnormalDec32 | signMask},
+		{mustEncode(t, 0, 0) | signMask, SmallestSubnormalDec32, SmallestSubnormalDec32 | signMask},
+		{SmallestSubnormalDec32, mustEncode(t, 2, -101), mustEncode(t, 0, -101)},
+		{SmallestSubnormalDec32 | signMask, mustEncode(t, 0, -101) | signMask, mustEncode(t, -2, -101)},
+		{MinPositiveDec32, mustEncode(t, 1000001, -101), mustEncode(t, 999999, -101)},
+		{MaxDec32, inf32, mustEncode(t, 9999998, 90)},
+		{inf32, inf32, MaxDec32},
+		{inf32 | signMask, MaxDec32 | signMask, inf32 | signMask},
+		{snan, nan5, nan5},
+	}
+	for i, testCase := range testCases {
+		if r := testCase.d.NextUp(); r != testCase.up {
+			t.Errorf("testCase #%d: NextUp(%v): expect %v, got %v", i, testCase.d, testCase.up, r)
+		}
+		if r := testCase.d.NextDown(); r != testCase.down {
+			t.Errorf("testCase #%d: NextDown(%v): expect %v, got %v", i, testCase.d, testCase.down, r)
+		}
+		if r := testCase.d.Neg().NextUp().Neg(); r != testCase.down && !r.IsNaN() {
+			t.Errorf("testCase #%d: -NextUp(-%v): expect %v, got %v", i, testCase.d, testCase.down, r)
+		}
+	}
+}
+
+func TestUlp(t *testing.T) {
+	testCases := []struct {
+		d, ref Dec32
+	}{
+		{mustEncode(t, 100, -2), mustEncode(t, 1, -2)},
+		{mustEncode(t, -100, 0), mustEncode(t, 1, 0)},
+		{mustEncode(t, 0, 3) | signMask, mustEncode(t, 1, 3)},
+		{MaxDec32, mustEncode(t, 1, 90)},
+		{SmallestSubnormalDec32, SmallestSubnormalDec32},
+		{inf32 | signMask, inf32},
+		{nan32 | signMask, nan32 | signMask},
+	}
+	for i, testCase := range testCases {
+		if r := testCase.d.Ulp(); r != testCase.ref {
+			t.Errorf("testCase #%d: Ulp(%v): expect %v, got %v", i, testCase.d, testCase.ref, r)
+		}
+	}
+}
@@ -0,0 +1,510 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ParseDec32 converts the string s to the nearest decimal32 value, rounding
+// ties to even. It accepts the numeric string syntax of the decNumber
+// specification: an optional sign, digits with an optional decimal point, and
+// an optional exponent introduced by E or e, such as "123.45", "-0.00" and
+// "-9.999999E+90". The exponent of the result is that implied by the string,
+// so "1.20" has coefficient 120 and exponent -2. The special values "Inf",
+// "Infinity", "NaN" and "sNaN" are accepted in any case, with an optional
+// sign.
+//
+// The errors that ParseDec32 returns have concrete type *strconv.NumError.
+// If s is not syntactically valid, err.Err is strconv.ErrSyntax. If s is too
+// large in magnitude to be represented it returns ±Inf, with err.Err set to
+// strconv.ErrRange. Values too small to be represented become zero.
+func ParseDec32(s string) (Dec32, error) {
+	var p Parser
+	return p.ParseDec32(s)
+}
+
+// A Parser converts numeric strings to decimal values with options that
+// differ from the defaults of ParseDec32. The zero value parses as ParseDec32
+// does.
+type Parser struct {
+	// Normalize removes trailing zeros from the coefficients of parsed
+	// values, as far as the exponent range allows, so that "1.20" parses
+	// to coefficient 12 and exponent -1, and "1200" to 12 and exponent 2.
+	// Zeros parse with exponent 0. By default the exponent implied by the
+	// string is kept, since trailing zeros are meaningful in financial
+	// data.
+	Normalize bool
+
+	// Underscores accepts underscores between digits, as digit separators
+	// in the manner of Go numeric literals, so that "1_000_000.25" parses
+	// as 1000000.25. Each underscore must have a digit on both sides.
+	Underscores bool
+
+	// DecimalSeparator, if not zero, is the decimal point in place of
+	// '.', which is then rejected, such as ',' for "1234,56".
+	DecimalSeparator rune
+
+	// GroupSeparator, if not zero, is accepted between the digits of the
+	// integer part, such as '.' for "1.234,56" or '\u00a0' for the no-break
+	// space of French formatting. It must differ from the decimal point,
+	// or every parse fails with errSeparators. Group sizes are not checked, so Indian "1,00,000" parses too.
+	GroupSeparator rune
+}
+
+// ParseDec32 is like the ParseDec32 function, with the options of p.
+func (p *Parser) ParseDec32(s string) (Dec32, error) {
+	d, _, err := p.parse("ParseDec32", s)
+	return d, err
+}
+
+// ParseDec32Exact is like ParseDec32, but also reports whether the result is
+// exact: false if s has more significant digits than decimal32 holds, or is
+// too small in magnitude to keep them all, so that "1.00000001" parses as 1
+// inexactly. Infinities and NaNs are exact, and values out of range are not.
+func ParseDec32Exact(s string) (d Dec32, exact bool, err error) {
+	var p Parser
+	return p.ParseDec32Exact(s)
+}
+
+// ParseDec32Exact is like the ParseDec32Exact function, with the options of
+// p.
+func (p *Parser) ParseDec32Exact(s string) (d Dec32, exact bool, err error) {
+	return p.parse("ParseDec32Exact", s)
+}
+
+// ParseDec64 is like ParseDec32, but converts s to the nearest decimal64
+// value, so that "1234567.890123456" keeps all its digits.
+func ParseDec64(s string) (Dec64, error) {
+	var p Parser
+	return p.ParseDec64(s)
+}
+
+// ParseDec64 is like the ParseDec64 function, with the options of p.
+func (p *Parser) ParseDec64(s string) (Dec64, error) {
+	d, _, err := p.parse64("ParseDec64", s)
+	return d, err
+}
+
+// ParseDec64Exact is like ParseDec32Exact, for decimal64: it reports false
+// if s has more than 16 significant digits, or is too small in magnitude to
+// keep them all.
+func ParseDec64Exact(s string) (d Dec64, exact bool, err error) {
+	var p Parser
+	return p.ParseDec64Exact(s)
+}
+
+// ParseDec64Exact is like the ParseDec64Exact function, with the options of
+// p.
+func (p *Parser) ParseDec64Exact(s string) (d Dec64, exact bool, err error) {
+	return p.parse64("ParseDec64Exact", s)
+}
+
+// errSeparators is the error of a Parser whose group separator is also its
+// decimal point, which would make "1.5" ambiguous.
+var errSeparators = errors.New("decimal: group separator is the decimal point")
+
+// parse parses s with the options of p, reporting whether the result is
+// exact and naming fn in errors.
+func (p *Parser) parse(fn, s string) (Dec32, bool, error) {
+	x, form, err := p.parseExact(fn, s)
+	if err != nil {
+		return nan32, false, err
+	}
+	switch form {
+	case parsedInf:
+		return inf32 | signOf(x.neg), true, nil
+	case parsedNaN:
+		return nan32 | signOf(x.neg), true, nil
+	case parsedSNaN:
+		return Dec32(snanMask) | signOf(x.neg), true, nil
+	}
+	d, exact := x.dec32()
+	if d.IsInf() {
+		return d, false, &strconv.NumError{Func: fn, Num: s, Err: strconv.ErrRange}
+	}
+	if p.Normalize {
+		d = d.reduce()
+	}
+	return d, exact, nil
+}
+
+// parse64 is like parse, for decimal64.
+func (p *Parser) parse64(fn, s string) (Dec64, bool, error) {
+	x, form, err := p.parseExact(fn, s)
+	if err != nil {
+		return nan64, false, err
+	}
+	switch form {
+	case parsedInf:
+		return inf64 | signOf64(x.neg), true, nil
+	case parsedNaN:
+		return nan64 | signOf64(x.neg), true, nil
+	case parsedSNaN:
+		return Dec64(snanMask64) | signOf64(x.neg), true, nil
+	}
+	d, exact := x.dec64()
+	if d.IsInf() {
+		return d, false, &strconv.NumError{Func: fn, Num: s, Err: strconv.ErrRange}
+	}
+	if p.Normalize {
+		d = d.reduce()
+	}
+	return d, exact, nil
+}
+
+// parseExact returns the exact value of s and its kind, parsed with the
+// options of p and naming fn in errors.
+func (p *Parser) parseExact(fn, s string) (*bigDec, parsedForm, error) {
+	if p.GroupSeparator != 0 && p.GroupSeparator == p.point() {
+		return nil, parsedFinite, &strconv.NumError{Func: fn, Num: s, Err: errSeparators}
+	}
+	t, ok := p.localize(s)
+	if ok && p.Underscores {
+		t, ok = stripUnderscores(t)
+	}
+	x, form, valid := parseDecimal(t)
+	if !ok || !valid {
+		return nil, parsedFinite, &strconv.NumError{Func: fn, Num: s, Err: strconv.ErrSyntax}
+	}
+	return x, form, nil
+}
+
+// reduce returns the finite d with trailing zeros removed from its
+// coefficient while the exponent allows, and zeros with exponent 0.
+func (d Dec32) reduce() Dec32 {
+	x := newBigDec32(d)
+	if x.isZero() {
+		return pack32(x.neg, 0, 0)
+	}
+	x.reduce(maxExp)
+	return pack32(x.neg, uint32(x.coeff.Uint64()), x.exp)
+}
+
+// reduce is like Dec32.reduce, for decimal64.
+func (d Dec64) reduce() Dec64 {
+	x := newBigDec64(d)
+	if x.isZero() {
+		return pack64(x.neg, 0, 0)
+	}
+	x.reduce(maxExp64)
+	return pack64(x.neg, x.coeff.Uint64(), x.exp)
+}
+
+// localize returns s with the separators of p replaced by those of the
+// decNumber syntax: the decimal separator becomes '.' and group separators
+// are removed. It reports false if s contains a '.' that is no longer a
+// decimal point, or a group separator outside the integer part or without a
+// digit on both sides.
+func (p *Parser) localize(s string) (string, bool) {
+	if p.DecimalSeparator == 0 && p.GroupSeparator == 0 {
+		return s, true
+	}
+	point := p.point()
+	b := make([]byte, 0, len(s))
+	inInt, prevDigit := true, false
+	for i, r := range s {
+		switch {
+		case r == p.GroupSeparator && r != 0:
+			next, _ := utf8.DecodeRuneInString(s[i+utf8.RuneLen(r):])
+			if !inInt || !prevDigit || next < '0' || next > '9' {
+				return s, false
+			}
+			continue
+		case r == point:
+			b = append(b, '.')
+			inInt = false
+		case r == '.':
+			return s, false
+		default:
+			if r == 'e' || r == 'E' {
+				inInt = false
+			}
+			b = utf8.AppendRune(b, r)
+		}
+		prevDigit = '0' <= r && r <= '9'
+	}
+	return string(b), true
+}
+
+// point returns the decimal point of p.
+func (p *Parser) point() rune {
+	if p.DecimalSeparator == 0 {
+		return '.'
+	}
+	return p.DecimalSeparator
+}
+
+// stripUnderscores returns s without the underscores that separate its
+// digits, reporting false if an underscore does not have a digit on both
+// sides.
+func stripUnderscores(s string) (string, bool) {
+	if !strings.Contains(s, "_") {
+		return s, true
+	}
+	isDigit := func(i int) bool { return 0 <= i && i < len(s) && '0' <= s[i] && s[i] <= '9' }
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] != '_' {
+			b = append(b, s[i])
+		} else if !isDigit(i-1) || !isDigit(i+1) {
+			return s, false
+		}
+	}
+	return string(b), true
+}
+
+// MustParseDec32 is like ParseDec32 but panics if the string cannot be
+// parsed. It simplifies initialization of decimal variables.
+func MustParseDec32(s string) Dec32 {
+	d, err := ParseDec32(s)
+	if err != nil {
+		panic("decimal: " + err.Error())
+	}
+	return d
+}
+
+// parsedForm distinguishes the kinds of value a numeric string can denote.
+type parsedForm uint8
+
+const (
+	parsedFinite parsedForm = iota
+	parsedInf
+	parsedNaN
+	parsedSNaN
+)
+
+// maxParseExp bounds the magnitude of exponents read from strings, far beyond
+// any format's range, so that accumulating exponent digits cannot overflow.
+const maxParseExp = 1 << 30
+
+// parseDecimal parses the numeric string s exactly, returning its value, the
+// kind of value, and whether s is syntactically valid. The sign is set for
+// special values too.
+func parseDecimal(s string) (x *bigDec, form parsedForm, ok bool) {
+	x = new(bigDec)
+	if s == "" {
+		return x, parsedFinite, false
+	}
+	switch s[0] {
+	case '-':
+		x.neg = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+	switch strings.ToLower(s) {
+	case "inf", "infinity":
+		return x, parsedInf, true
+	case "nan":
+		return x, parsedNaN, true
+	case "snan":
+		return x, parsedSNaN, true
+	}
+
+	digits := make([]byte, 0, len(s))
+	sawDigit, sawPoint := false, false
+	frac := 0
+	i := 0
+	for ; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case '0' <= c && c <= '9':
+			sawDigit = true
+			if len(digits) > 0 || c != '0' {
+				digits = append(digits, c)
+			}
+			if sawPoint {
+				frac++
+			}
+			continue
+		case c == '.' && !sawPoint:
+			sawPoint = true
+			continue
+		}
+		break
+	}
+	if !sawDigit {
+		return x, parsedFinite, false
+	}
+
+	exp := 0
+	if i < len(s) {
+		if s[i] != 'e' && s[i] != 'E' {
+			return x, parsedFinite, false
+		}
+		i++
+		expNeg := false
+		if i < len(s) && (s[i] == '+' || s[i] == '-') {
+			expNeg = s[i] == '-'
+			i++
+		}
+		if i == len(s) {
+			return x, parsedFinite, false
+		}
+		for ; i < len(s); i++ {
+			c := s[i]
+			if c < '0' || c > '9' {
+				return x, parsedFinite, false
+			}
+			if exp < maxParseExp {
+				exp = exp*10 + int(c-'0')
+			}
+		}
+		if expNeg {
+			exp = -exp
+		}
+	}
+
+	x.exp = exp - frac
+	if len(digits) > 0 {
+		x.coeff.SetString(string(digits), 10)
+	}
+	return x, parsedFinite, true
+}
+
+// ParseSlice parses the decimal strings of fields into dst, as ParseDec32
+// does, and returns the number of values parsed, which is the minimum of
+// len(dst) and len(fields) unless a field is invalid. It stops at the first
+// field that cannot be parsed, returning its index and the error that
+// ParseDec32 reports for it; the values before it are stored in dst.
+func ParseSlice(dst []Dec32, fields [][]byte) (int, error) {
+	var p Parser
+	return p.ParseSlice(dst, fields)
+}
+
+// ParseSlice is like the ParseSlice function, with the options of p. Without
+// the separator and underscore options, fields of at most seven significant
+// digits with a representable exponent, the common case, are parsed without
+// allocating.
+func (p *Parser) ParseSlice(dst []Dec32, fields [][]byte) (int, error) {
+	n := min(len(dst), len(fields))
+	// The fast path knows only the default syntax.
+	plain := !p.Underscores && p.DecimalSeparator == 0 && p.GroupSeparator == 0
+	for i, b := range fields[:n] {
+		var d Dec32
+		ok := false
+		if plain {
+			d, ok = parseSmall(b)
+		}
+		if !ok {
+			var err error
+			if d, err = p.ParseDec32(string(b)); err != nil {
+				return i, err
+			}
+		} else if p.Normalize {
+			d = d.reduce()
+		}
+		dst[i] = d
+	}
+	return n, nil
+}
+
+// ParseSlice64 is like ParseSlice, but parses the fields into decimal64
+// values as ParseDec64 does.
+func ParseSlice64(dst []Dec64, fields [][]byte) (int, error) {
+	var p Parser
+	return p.ParseSlice64(dst, fields)
+}
+
+// ParseSlice64 is like the ParseSlice64 function, with the options of p. The
+// fields that the fast path of ParseSlice accepts are parsed without
+// allocating here too, since decimal64 holds their values exactly.
+func (p *Parser) ParseSlice64(dst []Dec64, fields [][]byte) (int, error) {
+	n := min(len(dst), len(fields))
+	plain := !p.Underscores && p.DecimalSeparator == 0 && p.GroupSeparator == 0
+	for i, b := range fields[:n] {
+		var d Dec64
+		ok := false
+		if plain {
+			var small Dec32
+			if small, ok = parseSmall(b); ok {
+				d = small.ToDec64()
+			}
+		}
+		if !ok {
+			var err error
+			if d, err = p.ParseDec64(string(b)); err != nil {
+				return i, err
+			}
+		} else if p.Normalize {
+			d = d.reduce()
+		}
+		dst[i] = d
+	}
+	return n, nil
+}
+
+// parseSmall parses b if it is a finite numeric string that has at most
+// seven significant digits and whose exponent is in the decimal32 range, so
+// that it needs no rounding. It returns false for all other strings, valid
+// or not.
+func parseSmall(b []byte) (Dec32, bool) {
+	i, neg := 0, false
+	if len(b) > 0 && (b[0] == '-' || b[0] == '+') {
+		neg = b[0] == '-'
+		i++
+	}
+	var coeff uint32
+	sig, frac := 0, 0
+	sawDigit, sawPoint := false, false
+	for ; i < len(b); i++ {
+		c := b[i]
+		switch {
+		case '0' <= c && c <= '9':
+			sawDigit = true
+			if sig > 0 || c != '0' {
+				if sig++; sig > 7 {
+					return 0, false
+				}
+				coeff = coeff*10 + uint32(c-'0')
+			}
+			if sawPoint {
+				frac++
+			}
+			continue
+		case c == '.' && !sawPoint:
+			sawPoint = true
+			continue
+		}
+		break
+	}
+	if !sawDigit {
+		return 0, false
+	}
+	exp := 0
+	if i < len(b) {
+		if b[i] != 'e' && b[i] != 'E' {
+			return 0, false
+		}
+		i++
+		expNeg := false
+		if i < len(b) && (b[i] == '+' || b[i] == '-') {
+			expNeg = b[i] == '-'
+			i++
+		}
+		if i == len(b) || len(b)-i > 4 {
+			return 0, false
+		}
+		for ; i < len(b); i++ {
+			c := b[i]
+			if c < '0' || c > '9' {
+				return 0, false
+			}
+			exp = exp*10 + int(c-'0')
+		}
+		if expNeg {
+			exp = -exp
+		}
+	}
+	exp -= frac
+	if exp < minExp || exp > maxExp {
+		return 0, false
+	}
+	return pack32(neg, coeff, exp), true
+}
@@ -0,0 +1,407 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestParseDec32(t *testing.T) {
+	testCases := []struct {
+		s   string
+		ref Dec32
+		err error
+	}{
+		{"0", mustEncode(t, 0, 0), nil},
+		{"123.45", mustEncode(t, 12345, -2), nil},
+		{"+123.45", mustEncode(t, 12345, -2), nil},
+		{"-0.00", pack32(true, 0, -2), nil},
+		{"1.20", mustEncode(t, 120, -2), nil},
+		{"1.", mustEncode(t, 1, 0), nil},
+		{".5", mustEncode(t, 5, -1), nil},
+		{"00012", mustEncode(t, 12, 0), nil},
+		{"1E3", mustEncode(t, 1, 3), nil},
+		{"1.5e-3", mustEncode(t, 15, -4), nil},
+		{"-9.999999E+96", mustEncode(t, -9999999, 90), nil},
+		{"1E+96", mustEncode(t, 1000000, 90), nil},
+		{"1E+97", inf32, strconv.ErrRange},
+		{"1E-101", mustEncode(t, 1, -101), nil},
+		// Rounds to seven digits, ties to even.
+		{"12345675", mustEncode(t, 1234568, 1), nil},
+		{"12345665", mustEncode(t, 1234566, 1), nil},
+		{"1.0000000", mustEncode(t, 1000000, -6), nil},
+		// Too small values round to the minimum exponent.
+		{"1E-102", mustEncode(t, 0, -101), nil},
+		{"1.5E-101", mustEncode(t, 2, -101), nil},
+		{"1E-1000000000000", mustEncode(t, 0, -101), nil},
+		{"0E-200", mustEncode(t, 0, -101), nil},
+		{"0E+200", mustEncode(t, 0, 90), nil},
+		{"inf", inf32, nil},
+		{"-Infinity", inf32 | signMask, nil},
+		{"NaN", nan32, nil},
+		{"-sNaN", Dec32(snanMask) | signMask, nil},
+		{"1E+200", inf32, strconv.ErrRange},
+		{"-1E+1000000000000", inf32 | signMask, strconv.ErrRange},
+		{"", nan32, strconv.ErrSyntax},
+		{"-", nan32, strconv.ErrSyntax},
+		{".", nan32, strconv.ErrSyntax},
+		{"1e", nan32, strconv.ErrSyntax},
+		{"1e+", nan32, strconv.ErrSyntax},
+		{"e5", nan32, strconv.ErrSyntax},
+		{"1.2.3", nan32, strconv.ErrSyntax},
+		{"12a", nan32, strconv.ErrSyntax},
+		{" 1", nan32, strconv.ErrSyntax},
+		{"Infinit", nan32, strconv.ErrSyntax},
+	}
+	for i, testCase := range testCases {
+		d, err := ParseDec32(testCase.s)
+		if !errors.Is(err, testCase.err) || (err == nil) != (testCase.err == nil) {
+			t.Errorf("testCase #%d: %q: expect error %v, got %v", i, testCase.s, testCase.err, err)
+		}
+		if d != testCase.ref {
+			t.Errorf("testCase #%d: %q: expect %v (%x), got %v (%x)", i, testCase.s, testCase.ref, uint32(testCase.ref), d, uint32(d))
+		}
+	}
+}
+
+func TestParseStringRoundTrip(t *testing.T) {
+	for _, s := range []string{"0", "-0", "123.45", "0.00", "1.23E+5", "1.23E-8", "0E+2", "9.999999E+96", "1E-101", "Infinity", "-Infinity", "NaN", "sNaN"} {
+		if got := MustParseDec32(s).String(); got != s {
+			t.Errorf("expect %q, got %q", s, got)
+		}
+	}
+}
+
+func TestParserNormalize(t *testing.T) {
+	testCases := []struct {
+		s   string
+		ref Dec32
+	}{
+		{"1.20", mustEncode(t, 12, -1)},
+		{"1200", mustEncode(t, 12, 2)},
+		{"-0.0500", mustEncode(t, -5, -2)},
+		{"123.45", mustEncode(t, 12345, -2)},
+		{"0.000", mustEncode(t, 0, 0)},
+		{"-0E+7", pack32(true, 0, 0)},
+		// Trailing zeros are kept where the exponent is at its maximum.
+		{"1E+96", mustEncode(t, 1000000, 90)},
+		{"1.000000E+92", mustEncode(t, 100, 90)},
+		{"12345678", mustEncode(t, 1234568, 1)},
+		{"NaN", nan32},
+	}
+	p := Parser{Normalize: true}
+	for i, testCase := range testCases {
+		d, err := p.ParseDec32(testCase.s)
+		if err != nil || d != testCase.ref {
+			t.Errorf("testCase #%d: %q: expect %v, got %v %v", i, testCase.s, testCase.ref, d, err)
+		}
+	}
+	var preserve Parser
+	if d, _ := preserve.ParseDec32("1.20"); d != mustEncode(t, 120, -2) {
+		t.Errorf("unexpected %v", d)
+	}
+}
+
+func TestParserUnderscores(t *testing.T) {
+	testCases := []struct {
+		s   string
+		ref Dec32
+		ok  bool
+	}{
+		{"1_000_000", mustEncode(t, 1000000, 0), true},
+		{"1_000.25", mustEncode(t, 100025, -2), true},
+		{"+1_0", mustEncode(t, 10, 0), true},
+		{"-0.000_001", mustEncode(t, -1, -6), true},
+		{"1.5e1_0", mustEncode(t, 15, 9), true},
+		{"12", mustEncode(t, 12, 0), true},
+		{"_1", 0, false},
+		{"1_", 0, false},
+		{"1__0", 0, false},
+		{"1_.5", 0, false},
+		{"1._5", 0, false},
+		{"1_e5", 0, false},
+		{"-_1", 0, false},
+	}
+	p := Parser{Underscores: true}
+	for i, testCase := range testCases {
+		d, err := p.ParseDec32(testCase.s)
+		if !testCase.ok {
+			if !errors.Is(err, strconv.ErrSyntax) {
+				t.Errorf("testCase #%d: %q: expect syntax error, got %v %v", i, testCase.s, d, err)
+			}
+		} else if err != nil || d != testCase.ref {
+			t.Errorf("testCase #%d: %q: expect %v, got %v %v", i, testCase.s, testCase.ref, d, err)
+		}
+		dst := make([]Dec32, 1)
+		n, serr := p.ParseSlice(dst, [][]byte{[]byte(testCase.s)})
+		if testCase.ok != (serr == nil) || testCase.ok && (n != 1 || dst[0] != testCase.ref) {
+			t.Errorf("testCase #%d: ParseSlice(%q): expect %v %v, got %v %v", i, testCase.s, testCase.ref, testCase.ok, dst[0], serr)
+		}
+	}
+	if _, err := ParseDec32("1_000"); err == nil {
+		t.Errorf("expect underscores rejected by default")
+	}
+}
+
+func TestParserSeparators(t *testing.T) {
+	european := Parser{DecimalSeparator: ',', GroupSeparator: '.'}
+	french := Parser{DecimalSeparator: ',', GroupSeparator: '\u00a0'}
+	english := Parser{GroupSeparator: ','}
+	testCases := []struct {
+		p   Parser
+		s   string
+		ref Dec32
+		ok  bool
+	}{
+		{european, "1.234,56", mustEncode(t, 123456, -2), true},
+		{european, "1.234", mustEncode(t, 1234, 0), true},
+		{european, "-1.234.567", mustEncode(t, -1234567, 0), true},
+		{european, "0,05", mustEncode(t, 5, -2), true},
+		{european, "1234,5E+3", mustEncode(t, 12345, 2), true},
+		{european, ",5", mustEncode(t, 5, -1), true},
+		{european, "Infinity", inf32, true},
+		{european, "1,234.56", 0, false},
+		{european, "1.234,5.6", 0, false},
+		{european, "1..234", 0, false},
+		{european, ".234", 0, false},
+		{european, "1234.", 0, false},
+		{french, "1\u00a0234,56", mustEncode(t, 123456, -2), true},
+		{french, "1.5", 0, false},
+		{english, "1,234.56", mustEncode(t, 123456, -2), true},
+		{english, "1,00,000", mustEncode(t, 100000, 0), true},
+		{english, "1.5,0", 0, false},
+		{english, "1E1,0", 0, false},
+		{Parser{DecimalSeparator: ',', Underscores: true}, "1_000,5", mustEncode(t, 10005, -1), true},
+		{Parser{DecimalSeparator: ','}, "1.5", 0, false},
+	}
+	for i, testCase := range testCases {
+		d, err := testCase.p.ParseDec32(testCase.s)
+		if !testCase.ok {
+			if !errors.Is(err, strconv.ErrSyntax) {
+				t.Errorf("testCase #%d: %q: expect syntax error, got %v %v", i, testCase.s, d, err)
+			}
+		} else if err != nil || d != testCase.ref {
+			t.Errorf("testCase #%d: %q: expect %v, got %v %v", i, testCase.s, testCase.ref, d, err)
+		}
+		// ParseSlice agrees with ParseDec32.
+		dst := make([]Dec32, 1)
+		n, serr := testCase.p.ParseSlice(dst, [][]byte{[]byte(testCase.s)})
+		if testCase.ok != (serr == nil) || testCase.ok && (n != 1 || dst[0] != testCase.ref) {
+			t.Errorf("testCase #%d: ParseSlice(%q): expect %v %v, got %v %v", i, testCase.s, testCase.ref, testCase.ok, dst[0], serr)
+		}
+	}
+}
+
+func TestParserSeparatorCollision(t *testing.T) {
+	testCases := []Parser{
+		{GroupSeparator: '.'},
+		{DecimalSeparator: ',', GroupSeparator: ','},
+		{DecimalSeparator: '.', GroupSeparator: '.', Underscores: true},
+	}
+	for i, p := range testCases {
+		for _, s := range []string{"1.5", "15", "NaN"} {
+			if d, err := p.ParseDec32(s); !errors.Is(err, errSeparators) {
+				t.Errorf("testCase #%d: %q: expect errSeparators, got %v %v", i, s, d, err)
+			}
+			if _, err := p.ParseSlice(make([]Dec32, 1), [][]byte{[]byte(s)}); !errors.Is(err, errSeparators) {
+				t.Errorf("testCase #%d: ParseSlice(%q): expect errSeparators, got %v", i, s, err)
+			}
+		}
+	}
+}
+
+func TestMustParseDec32(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic")
+		}
+	}()
+	MustParseDec32("bogus")
+}
+
+func TestParseSlice(t *testing.T) {
+	inputs := []string{
+		"0", "-0.00", "123.45", "+1.20", "1.", ".5", "00012", "1E3", "1.5e-3",
+		"9999999", "12345678", "0.0000001234567", "1E-101", "1E-102", "9.999999E+96",
+		"0E+95", "1e0000001", "Infinity", "-nan", "sNaN",
+	}
+	fields := make([][]byte, len(inputs))
+	for i, s := range inputs {
+		fields[i] = []byte(s)
+	}
+	dst := make([]Dec32, len(fields))
+	for _, p := range []Parser{{}, {Normalize: true}} {
+		if n, err := p.ParseSlice(dst, fields); n != len(fields) || err != nil {
+			t.Fatalf("expect %d values, got %d %v", len(fields), n, err)
+		}
+		for i, s := range inputs {
+			if ref, _ := p.ParseDec32(s); dst[i] != ref {
+				t.Errorf("%q: expect %v, got %v", s, ref, dst[i])
+			}
+		}
+	}
+
+	testCases := []struct {
+		fields []string
+		n      int
+		err    error
+	}{
+		{[]string{"1", "2", "x"}, 2, nil},
+		{[]string{"1", "1..2", "x"}, 1, strconv.ErrSyntax},
+		{[]string{"1", "2", "1E+97"}, 2, strconv.ErrRange},
+	}
+	for i, testCase := range testCases {
+		fields := make([][]byte, len(testCase.fields))
+		for j, s := range testCase.fields {
+			fields[j] = []byte(s)
+		}
+		size := len(fields)
+		if testCase.err == nil {
+			size = testCase.n
+		}
+		n, err := ParseSlice(make([]Dec32, size), fields)
+		if n != testCase.n || !errors.Is(err, testCase.err) {
+			t.Errorf("testCase #%d: expect %d %v, got %d %v", i, testCase.n, testCase.err, n, err)
+		}
+	}
+}
+
+func TestParseSlice64(t *testing.T) {
+	inputs := []string{
+		"0", "-0.00", "123.45", "+1.20", "1.", ".5", "00012", "1E3", "1.5e-3",
+		"9999999", "12345678", "1234567.890123456", "12345678901234567", "1E-398",
+		"1E-399", "1000000E+90", "0E+400", "Infinity", "-nan", "sNaN",
+	}
+	fields := make([][]byte, len(inputs))
+	for i, s := range inputs {
+		fields[i] = []byte(s)
+	}
+	dst := make([]Dec64, len(fields))
+	for _, p := range []Parser{{}, {Normalize: true}, {Underscores: true}} {
+		if n, err := p.ParseSlice64(dst, fields); n != len(fields) || err != nil {
+			t.Fatalf("expect %d values, got %d %v", len(fields), n, err)
+		}
+		for i, s := range inputs {
+			if ref, _ := p.ParseDec64(s); dst[i] != ref {
+				t.Errorf("%q: expect %v, got %v", s, ref, dst[i])
+			}
+		}
+	}
+
+	testCases := []struct {
+		fields []string
+		n      int
+		err    error
+	}{
+		{[]string{"1", "2", "x"}, 2, nil},
+		{[]string{"1", "1..2", "x"}, 1, strconv.ErrSyntax},
+		{[]string{"1", "2", "1E+385"}, 2, strconv.ErrRange},
+	}
+	for i, testCase := range testCases {
+		fields := make([][]byte, len(testCase.fields))
+		for j, s := range testCase.fields {
+			fields[j] = []byte(s)
+		}
+		size := len(fields)
+		if testCase.err == nil {
+			size = testCase.n
+		}
+		n, err := ParseSlice64(make([]Dec64, size), fields)
+		if n != testCase.n || !errors.Is(err, testCase.err) {
+			t.Errorf("testCase #%d: expect %d %v, got %d %v", i, testCase.n, testCase.err, n, err)
+		}
+	}
+	p := Parser{GroupSeparator: ','}
+	if _, err := p.ParseSlice64(make([]Dec64, 1), [][]byte{[]byte("1,234.5")}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if allocs := testing.AllocsPerRun(10, func() { ParseSlice64(dst[:3], fields[:3]) }); allocs != 0 {
+		t.Errorf("expect no allocations, got %v", allocs)
+	}
+}
+
+func BenchmarkParseSlice(b *testing.B) {
+	fields := make([][]byte, 1000)
+	for i := range fields {
+		fields[i] = []byte(strconv.Itoa(i*7919%100000) + "." + strconv.Itoa(i%100))
+	}
+	dst := make([]Dec32, len(fields))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ParseSlice(dst, fields)
+	}
+}
+
+func TestParseDec32Exact(t *testing.T) {
+	testCases := []struct {
+		s     string
+		ref   Dec32
+		exact bool
+		err   error
+	}{
+		{"123.45", mustEncode(t, 12345, -2), true, nil},
+		{"1.0000000", mustEncode(t, 1000000, -6), true, nil},
+		{"1.00000001", mustEncode(t, 1000000, -6), false, nil},
+		{"12345675", mustEncode(t, 1234568, 1), false, nil},
+		{"12345670", mustEncode(t, 1234567, 1), true, nil},
+		{"1E-101", mustEncode(t, 1, -101), true, nil},
+		{"1E-102", mustEncode(t, 0, -101), false, nil},
+		{"0E-200", mustEncode(t, 0, -101), true, nil},
+		{"1E+96", mustEncode(t, 1000000, 90), true, nil},
+		{"1E+97", inf32, false, strconv.ErrRange},
+		{"-Infinity", inf32 | signMask, true, nil},
+		{"NaN", nan32, true, nil},
+		{"1.2.3", nan32, false, strconv.ErrSyntax},
+	}
+	for i, testCase := range testCases {
+		d, exact, err := ParseDec32Exact(testCase.s)
+		if d != testCase.ref || exact != testCase.exact || !errors.Is(err, testCase.err) || (err == nil) != (testCase.err == nil) {
+			t.Errorf("testCase #%d: %q: expect %v %v %v, got %v %v %v", i, testCase.s, testCase.ref, testCase.exact, testCase.err, d, exact, err)
+		}
+	}
+	p := Parser{Normalize: true, GroupSeparator: ','}
+	if d, exact, err := p.ParseDec32Exact("1,234,567.80"); d != mustEncode(t, 1234568, 0) || exact || err != nil {
+		t.Errorf("unexpected %v %v %v", d, exact, err)
+	}
+	var e *strconv.NumError
+	if _, _, err := ParseDec32Exact("x"); !errors.As(err, &e) || e.Func != "ParseDec32Exact" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestParseDec64Exact(t *testing.T) {
+	testCases := []struct {
+		s     string
+		ref   Dec64
+		exact bool
+		err   error
+	}{
+		{"1234567.890123456", MustEncodeDec64(1234567890123456, -9), true, nil},
+		{"12345678901234567", MustEncodeDec64(1234567890123457, 1), false, nil},
+		{"12345678901234560", MustEncodeDec64(1234567890123456, 1), true, nil},
+		{"1E-398", MustEncodeDec64(1, -398), true, nil},
+		{"1E-399", MustEncodeDec64(0, -398), false, nil},
+		{"1E+384", MustEncodeDec64(1000000000000000, 369), true, nil},
+		{"1E+385", inf64, false, strconv.ErrRange},
+		{"-sNaN", Dec64(snanMask64 | signMask64), true, nil},
+		{"1.2.3", nan64, false, strconv.ErrSyntax},
+	}
+	for i, testCase := range testCases {
+		d, exact, err := ParseDec64Exact(testCase.s)
+		if d != testCase.ref || exact != testCase.exact || !errors.Is(err, testCase.err) || (err == nil) != (testCase.err == nil) {
+			t.Errorf("testCase #%d: %q: expect %v %v %v, got %v %v %v", i, testCase.s, testCase.ref, testCase.exact, testCase.err, d, exact, err)
+		}
+	}
+	p := Parser{Normalize: true, DecimalSeparator: ',', GroupSeparator: '.'}
+	if d, err := p.ParseDec64("1.234.567,8900"); d != MustEncodeDec64(123456789, -2) || err != nil {
+		t.Errorf("unexpected %v %v", d, err)
+	}
+	var e *strconv.NumError
+	if _, err := ParseDec64("x"); !errors.As(err, &e) || e.Func != "ParseDec64" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
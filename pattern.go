@@ -0,0 +1,268 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"strconv"
+	"strings"
+)
+
+// FormatPattern returns the decimal value formatted with a number pattern in
+// the style of Excel and ICU, such as "#,##0.00" or "0.000E+00", rounding
+// ties to even. It returns false if the pattern is invalid.
+//
+// A pattern has a number part between an optional prefix and suffix. In the
+// integer digits of the number part, '0' is a digit that is always written
+// and '#' one written only if significant; a ',' asks for grouping separators
+// every as many digits as follow it, and a second ',' sets the size of the
+// groups before the last, as in the Indian "#,##,##0". After a '.', the '0's give the minimum
+// and all the digits the maximum number of fraction digits. An exponent
+// part, 'E' with an optional '+' and '0's giving its minimum digits, selects
+// scientific notation with as many integer digits as the '0's before the
+// point. A '%' in the prefix or suffix multiplies the value by 100. Text in
+// single quotes is written literally, and two single quotes write one.
+//
+// A second pattern after a ';' gives the prefix and suffix of negative
+// values, such as "#,##0.00;(#,##0.00)"; its number part is ignored.
+// Without one, negative values are written with a minus sign before the
+// prefix. Infinities are written "Infinity" and NaNs "NaN", with the
+// prefix and suffix.
+//
+//	1234.5   "#,##0.00"   1,234.50
+//	0.07256  "0.0%"       7.3%
+//	12345    "0.000E+00"  1.234E+04
+func (d Dec32) FormatPattern(pattern string) (string, bool) {
+	pos, neg, ok := parsePattern(pattern)
+	if !ok {
+		return "", false
+	}
+	np := pos
+	if d.Sign() < 0 && !d.IsNaN() {
+		np = neg
+	}
+	buf := append([]byte(nil), np.prefix...)
+	switch {
+	case d.IsInf():
+		buf = append(buf, "Infinity"...)
+	case d.IsNaN():
+		buf = append(buf, "NaN"...)
+	default:
+		x := newBigDec32(d)
+		if pos.percent {
+			x.exp += 2
+		}
+		if pos.exp {
+			buf = pos.appendScientific(buf, x)
+		} else {
+			buf = pos.appendFixed(buf, x)
+		}
+	}
+	return string(append(buf, np.suffix...)), true
+}
+
+// numberPattern is a parsed subpattern of FormatPattern.
+type numberPattern struct {
+	prefix, suffix   string
+	percent          bool
+	minInt           int
+	minFrac, maxFrac int
+	group            int  // digits in the last group, or 0
+	group2           int  // digits in the other groups
+	exp              bool // scientific notation
+	expPlus          bool // whether positive exponents have a '+'
+	minExp           int
+}
+
+// parsePattern parses a FormatPattern pattern into the subpatterns for
+// positive and negative values.
+func parsePattern(pattern string) (pos, neg numberPattern, ok bool) {
+	pos, rest, ok := parseSubpattern(pattern)
+	if !ok {
+		return pos, neg, false
+	}
+	if rest == "" {
+		neg = pos
+		neg.prefix = "-" + pos.prefix
+		return pos, neg, true
+	}
+	if rest[0] != ';' {
+		return pos, neg, false
+	}
+	neg, rest, ok = parseSubpattern(rest[1:])
+	return pos, neg, ok && rest == ""
+}
+
+// parseSubpattern parses a subpattern at the start of s, returning the text
+// that follows it.
+func parseSubpattern(s string) (p numberPattern, rest string, ok bool) {
+	p.prefix, s, ok = parseAffix(s, &p.percent)
+	if !ok {
+		return p, s, false
+	}
+	intDigits, groupStart, prevGroupStart, sawPoint := 0, -1, -1, false
+	i := 0
+numberPart:
+	for ; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '#' && !sawPoint:
+			if p.minInt > 0 {
+				return p, s, false
+			}
+			intDigits++
+		case c == '0' && !sawPoint:
+			p.minInt++
+			intDigits++
+		case c == ',' && !sawPoint:
+			prevGroupStart, groupStart = groupStart, intDigits
+		case c == '.' && !sawPoint:
+			sawPoint = true
+		case c == '0' && sawPoint:
+			if p.maxFrac > p.minFrac {
+				return p, s, false
+			}
+			p.minFrac++
+			p.maxFrac++
+		case c == '#' && sawPoint:
+			p.maxFrac++
+		default:
+			break numberPart
+		}
+	}
+	if intDigits == 0 && p.maxFrac == 0 {
+		return p, s, false
+	}
+	if groupStart >= 0 {
+		if p.group = intDigits - groupStart; p.group == 0 {
+			return p, s, false
+		}
+		p.group2 = p.group
+		if prevGroupStart >= 0 {
+			if p.group2 = groupStart - prevGroupStart; p.group2 == 0 {
+				return p, s, false
+			}
+		}
+	}
+	if i < len(s) && s[i] == 'E' {
+		p.exp, p.group = true, 0
+		i++
+		if i < len(s) && s[i] == '+' {
+			p.expPlus = true
+			i++
+		}
+		for ; i < len(s) && s[i] == '0'; i++ {
+			p.minExp++
+		}
+		if p.minExp == 0 {
+			return p, s, false
+		}
+	}
+	p.suffix, rest, ok = parseAffix(s[i:], &p.percent)
+	return p, rest, ok
+}
+
+// parseAffix parses the literal text at the start of s, up to the number part
+// of a pattern or a ';', and sets percent if the text contains an unquoted
+// '%'.
+func parseAffix(s string, percent *bool) (affix, rest string, ok bool) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '#', '0', ',', '.', ';':
+			return b.String(), s[i:], true
+		case '\'':
+			j := strings.IndexByte(s[i+1:], '\'')
+			if j < 0 {
+				return "", s, false
+			}
+			if j == 0 {
+				b.WriteByte('\'')
+			} else {
+				b.WriteString(s[i+1 : i+1+j])
+			}
+			i += j + 1
+		case '%':
+			*percent = true
+			b.WriteByte(c)
+		default:
+			if '1' <= c && c <= '9' {
+				return "", s, false
+			}
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), "", true
+}
+
+// appendFixed appends the magnitude of x in plain notation.
+func (p *numberPattern) appendFixed(buf []byte, x *bigDec) []byte {
+	x.rescale(-p.maxFrac)
+	c := x.coeff.String()
+	if len(c) <= p.maxFrac {
+		c = strings.Repeat("0", p.maxFrac-len(c)+1) + c
+	}
+	ipart, frac := strings.TrimLeft(c[:len(c)-p.maxFrac], "0"), c[len(c)-p.maxFrac:]
+	frac = trimFraction(frac, p.minFrac)
+	if len(ipart) < p.minInt {
+		ipart = strings.Repeat("0", p.minInt-len(ipart)) + ipart
+	}
+	if ipart == "" && frac == "" {
+		ipart = "0"
+	}
+	for i := 0; i < len(ipart); i++ {
+		if n := len(ipart) - i; i > 0 && p.group > 0 && n >= p.group && (n-p.group)%p.group2 == 0 {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, ipart[i])
+	}
+	if frac != "" {
+		buf = append(buf, '.')
+		buf = append(buf, frac...)
+	}
+	return buf
+}
+
+// appendScientific appends the magnitude of x in scientific notation.
+func (p *numberPattern) appendScientific(buf []byte, x *bigDec) []byte {
+	minInt := max(p.minInt, 1)
+	sig := minInt + p.maxFrac
+	exp := 0
+	if !x.isZero() {
+		x.roundDigits(sig, ToNearestEven)
+		exp = x.exp + bigDigits(&x.coeff) - minInt
+	}
+	c := x.coeff.String()
+	if x.isZero() {
+		c = ""
+	}
+	c += strings.Repeat("0", sig-len(c))
+	buf = append(buf, c[:minInt]...)
+	if frac := trimFraction(c[minInt:], p.minFrac); frac != "" {
+		buf = append(buf, '.')
+		buf = append(buf, frac...)
+	}
+	buf = append(buf, 'E')
+	switch {
+	case exp < 0:
+		buf = append(buf, '-')
+		exp = -exp
+	case p.expPlus:
+		buf = append(buf, '+')
+	}
+	e := strconv.Itoa(exp)
+	for i := len(e); i < p.minExp; i++ {
+		buf = append(buf, '0')
+	}
+	return append(buf, e...)
+}
+
+// trimFraction removes trailing zeros from the fraction digits frac, keeping
+// at least keep digits.
+func trimFraction(frac string, keep int) string {
+	n := len(frac)
+	for n > keep && frac[n-1] == '0' {
+		n--
+	}
+	return frac[:n]
+}
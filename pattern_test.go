@@ -0,0 +1,53 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import "testing"
+
+func TestFormatPattern(t *testing.T) {
+	testCases := []struct {
+		d       Dec32
+		pattern string
+		ref     string
+	}{
+		{mustEncode(t, 12345, -1), "#,##0.00", "1,234.50"},
+		{mustEncode(t, -12345, -1), "#,##0.00", "-1,234.50"},
+		{mustEncode(t, -12345, -1), "#,##0.00;(#,##0.00)", "(1,234.50)"},
+		{mustEncode(t, 1234567, 0), "#,##0", "1,234,567"},
+		{mustEncode(t, 1234567, 0), "#,##,##0", "12,34,567"},
+		{mustEncode(t, 1234567, -2), "0", "12346"},
+		{mustEncode(t, 125, -2), "0.0", "1.2"},
+		{mustEncode(t, 135, -2), "0.0", "1.4"},
+		{mustEncode(t, 5, -1), "#.##", ".5"},
+		{mustEncode(t, 0, 0), "#.##", "0"},
+		{mustEncode(t, 5, -1), "000.0#", "000.5"},
+		{mustEncode(t, 12345, -4), "0.0#", "1.23"},
+		{mustEncode(t, 12, 0), "0.0#", "12.0"},
+		{mustEncode(t, 7256, -5), "0.0%", "7.3%"},
+		{mustEncode(t, 12345, 0), "0.000E+00", "1.234E+04"},
+		{mustEncode(t, 12355, 0), "0.000E+00", "1.236E+04"},
+		{mustEncode(t, 12, -5), "0.###E0", "1.2E-4"},
+		{mustEncode(t, 1, 90), "0.0E0", "1.0E90"},
+		{mustEncode(t, 0, 5), "0.00E+00", "0.00E+00"},
+		{mustEncode(t, 15, 0), "00.0E0", "15.0E0"},
+		{mustEncode(t, 999, -1), "$#,##0.00' USD'", "$99.90 USD"},
+		{mustEncode(t, -999, -1), "$#,##0.00", "-$99.90"},
+		{mustEncode(t, 5, 0), "'#'0", "#5"},
+		{mustEncode(t, 5, 0), "0'' ", "5' "},
+		{inf32 | signMask, "#,##0.00;(#)", "(Infinity)"},
+		{nan32, "#,##0.00;(#)", "NaN"},
+	}
+	for i, testCase := range testCases {
+		s, ok := testCase.d.FormatPattern(testCase.pattern)
+		if !ok || s != testCase.ref {
+			t.Errorf("testCase #%d: %v %q: expect %q, got %q %v", i, testCase.d, testCase.pattern, testCase.ref, s, ok)
+		}
+	}
+	for _, pattern := range []string{"", "abc", "0#", "0.#0", "#,", "0.0E", "1.00", "'0", "0;0;0", "0.0,0"} {
+		if s, ok := mustEncode(t, 1, 0).FormatPattern(pattern); ok {
+			t.Errorf("pattern %q: expect invalid, got %q", pattern, s)
+		}
+	}
+}
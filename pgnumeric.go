@@ -0,0 +1,191 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+)
+
+// PostgreSQL NUMERIC binary format sign words.
+const (
+	pgNumericPos    = 0x0000
+	pgNumericNeg    = 0x4000
+	pgNumericNaN    = 0xc000
+	pgNumericPosInf = 0xd000
+	pgNumericNegInf = 0xf000
+)
+
+var (
+	errPGNumericFormat  = errors.New("decimal: malformed PostgreSQL numeric")
+	errPGNumericRange   = errors.New("decimal: PostgreSQL numeric out of decimal32 range")
+	errPGNumericRange64 = errors.New("decimal: PostgreSQL numeric out of decimal64 range")
+)
+
+// AppendPGNumeric appends the PostgreSQL NUMERIC binary wire format of d to
+// buf and returns the extended buffer. The display scale is taken from the
+// exponent, so 1.50 is sent with scale 2. Infinities use the encoding
+// introduced in PostgreSQL 14. Negative zero is sent as zero.
+func (d Dec32) AppendPGNumeric(buf []byte) []byte {
+	switch {
+	case d.IsNaN():
+		return appendPGNumericHeader(buf, 0, 0, pgNumericNaN, 0)
+	case d.IsInf() && d.Sign() < 0:
+		return appendPGNumericHeader(buf, 0, 0, pgNumericNegInf, 0)
+	case d.IsInf():
+		return appendPGNumericHeader(buf, 0, 0, pgNumericPosInf, 0)
+	}
+	return appendPGNumeric(buf, newBigDec32(d))
+}
+
+// AppendPGNumeric is like Dec32.AppendPGNumeric, for decimal64 values.
+func (d Dec64) AppendPGNumeric(buf []byte) []byte {
+	switch {
+	case d.IsNaN():
+		return appendPGNumericHeader(buf, 0, 0, pgNumericNaN, 0)
+	case d.IsInf() && d.Sign() < 0:
+		return appendPGNumericHeader(buf, 0, 0, pgNumericNegInf, 0)
+	case d.IsInf():
+		return appendPGNumericHeader(buf, 0, 0, pgNumericPosInf, 0)
+	}
+	return appendPGNumeric(buf, newBigDec64(d))
+}
+
+// appendPGNumeric appends the NUMERIC binary wire format of the finite x to
+// buf.
+func appendPGNumeric(buf []byte, x *bigDec) []byte {
+	dscale := 0
+	if x.exp < 0 {
+		dscale = -x.exp
+	}
+	if x.isZero() {
+		return appendPGNumericHeader(buf, 0, 0, pgNumericPos, dscale)
+	}
+	sign := pgNumericPos
+	if x.neg {
+		sign = pgNumericNeg
+	}
+
+	// Align the fraction to whole base-10000 digits.
+	fracGroups := (dscale + 3) / 4
+	var n big.Int
+	n.Mul(&x.coeff, bigPow10(x.exp+4*fracGroups))
+	var groups []uint16
+	base := big.NewInt(10000)
+	var r big.Int
+	for n.Sign() != 0 {
+		n.QuoRem(&n, base, &r)
+		groups = append(groups, uint16(r.Uint64()))
+	}
+	weight := len(groups) - 1 - fracGroups
+	// Trailing zero groups are implied by the weight.
+	lo := 0
+	for groups[lo] == 0 {
+		lo++
+	}
+	groups = groups[lo:]
+	buf = appendPGNumericHeader(buf, len(groups), weight, sign, dscale)
+	for i := len(groups) - 1; i >= 0; i-- {
+		buf = binary.BigEndian.AppendUint16(buf, groups[i])
+	}
+	return buf
+}
+
+func appendPGNumericHeader(buf []byte, ndigits, weight, sign, dscale int) []byte {
+	buf = binary.BigEndian.AppendUint16(buf, uint16(ndigits))
+	buf = binary.BigEndian.AppendUint16(buf, uint16(int16(weight)))
+	buf = binary.BigEndian.AppendUint16(buf, uint16(sign))
+	return binary.BigEndian.AppendUint16(buf, uint16(dscale))
+}
+
+// DecodePGNumeric decodes a value in the PostgreSQL NUMERIC binary wire
+// format, rounding it to the nearest decimal32, ties to even. The exponent of
+// the result is the negated display scale where the precision allows, so a
+// NUMERIC(10,2) value keeps two fraction digits. It returns an error if b is
+// malformed or the value is too large in magnitude for decimal32.
+func DecodePGNumeric(b []byte) (Dec32, error) {
+	x, sign, err := decodePGNumeric(b)
+	switch {
+	case err != nil:
+		return nan32, err
+	case sign == pgNumericNaN:
+		return nan32, nil
+	case sign == pgNumericPosInf:
+		return inf32, nil
+	case sign == pgNumericNegInf:
+		return inf32 | signMask, nil
+	}
+	d, _ := x.dec32()
+	if d.IsInf() {
+		return d, errPGNumericRange
+	}
+	return d, nil
+}
+
+// DecodePGNumeric64 is like DecodePGNumeric, but rounds the value to the
+// nearest decimal64, which holds NUMERIC values of up to 16 significant
+// digits exactly.
+func DecodePGNumeric64(b []byte) (Dec64, error) {
+	x, sign, err := decodePGNumeric(b)
+	switch {
+	case err != nil:
+		return nan64, err
+	case sign == pgNumericNaN:
+		return nan64, nil
+	case sign == pgNumericPosInf:
+		return inf64, nil
+	case sign == pgNumericNegInf:
+		return inf64 | signMask64, nil
+	}
+	d, _ := x.dec64()
+	if d.IsInf() {
+		return d, errPGNumericRange64
+	}
+	return d, nil
+}
+
+// decodePGNumeric decodes the NUMERIC binary wire format b, returning its
+// sign word and, unless that is of a special value, its exact value with the
+// exponent of the display scale.
+func decodePGNumeric(b []byte) (*bigDec, uint16, error) {
+	if len(b) < 8 {
+		return nil, 0, errPGNumericFormat
+	}
+	ndigits := int(binary.BigEndian.Uint16(b))
+	weight := int(int16(binary.BigEndian.Uint16(b[2:])))
+	sign := binary.BigEndian.Uint16(b[4:])
+	dscale := int(binary.BigEndian.Uint16(b[6:]))
+	if len(b) != 8+2*ndigits || dscale > 0x3fff {
+		return nil, 0, errPGNumericFormat
+	}
+	switch sign {
+	case pgNumericNaN, pgNumericPosInf, pgNumericNegInf:
+		return nil, sign, nil
+	case pgNumericPos, pgNumericNeg:
+	default:
+		return nil, 0, errPGNumericFormat
+	}
+
+	x := &bigDec{neg: sign == pgNumericNeg}
+	group := new(big.Int)
+	for i := 0; i < ndigits; i++ {
+		g := binary.BigEndian.Uint16(b[8+2*i:])
+		if g >= 10000 {
+			return nil, 0, errPGNumericFormat
+		}
+		x.coeff.Mul(&x.coeff, big.NewInt(10000))
+		x.coeff.Add(&x.coeff, group.SetUint64(uint64(g)))
+	}
+	x.exp = 4 * (weight - ndigits + 1)
+	if x.exp < -dscale {
+		// Digits beyond the display scale are not significant.
+		x.shr(-dscale - x.exp)
+	} else if x.exp > -dscale {
+		x.coeff.Mul(&x.coeff, bigPow10(x.exp+dscale))
+		x.exp = -dscale
+	}
+	return x, sign, nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPGNumeric(t *testing.T) {
+	testCases := []struct {
+		d   Dec32
+		ref []byte
+	}{
+		// 123.45: digits 123, 4500 with weight 0 and scale 2.
+		{MustParseDec32("123.45"), []byte{0, 2, 0, 0, 0, 0, 0, 2, 0, 123, 0x11, 0x94}},
+		{MustParseDec32("-0.0001"), []byte{0, 1, 0xff, 0xff, 0x40, 0, 0, 4, 0, 1}},
+		{MustParseDec32("1.50"), []byte{0, 2, 0, 0, 0, 0, 0, 2, 0, 1, 0x13, 0x88}},
+		{MustParseDec32("10000"), []byte{0, 1, 0, 1, 0, 0, 0, 0, 0, 1}},
+		{MustParseDec32("1.2E+5"), []byte{0, 1, 0, 1, 0, 0, 0, 0, 0, 12}},
+		{MustParseDec32("0"), []byte{0, 0, 0, 0, 0, 0, 0, 0}},
+		{MustParseDec32("0.000"), []byte{0, 0, 0, 0, 0, 0, 0, 3}},
+		{MustParseDec32("NaN"), []byte{0, 0, 0, 0, 0xc0, 0, 0, 0}},
+		{MustParseDec32("Infinity"), []byte{0, 0, 0, 0, 0xd0, 0, 0, 0}},
+		{MustParseDec32("-Infinity"), []byte{0, 0, 0, 0, 0xf0, 0, 0, 0}},
+	}
+	for i, testCase := range testCases {
+		b := testCase.d.AppendPGNumeric(nil)
+		if !bytes.Equal(b, testCase.ref) {
+			t.Errorf("testCase #%d: %v: expect %x, got %x", i, testCase.d, testCase.ref, b)
+		}
+		d, err := DecodePGNumeric(b)
+		if err != nil {
+			t.Errorf("testCase #%d: %v", i, err)
+		} else if d.IsNaN() != testCase.d.IsNaN() || (!d.IsNaN() && cmp32(d, testCase.d) != 0) {
+			t.Errorf("testCase #%d: expect %v, got %v", i, testCase.d, d)
+		}
+	}
+
+	// Values with a non-positive exponent round trip exactly.
+	for _, s := range []string{"9999999", "1E-101", "-1234567E-50", "0.000001", "123456.7"} {
+		d := MustParseDec32(s)
+		if got, err := DecodePGNumeric(d.AppendPGNumeric(nil)); err != nil || got != d {
+			t.Errorf("%s: round trip failed: %v %v", s, got, err)
+		}
+	}
+}
+
+func TestDecodePGNumeric(t *testing.T) {
+	// 12345678.90 rounds to seven digits.
+	b := []byte{0, 3, 0, 1, 0, 0, 0, 2, 0x04, 0xd2, 0x16, 0x2e, 0x23, 0x28}
+	if d, err := DecodePGNumeric(b); err != nil || d != MustParseDec32("1.234568E+7") {
+		t.Errorf("unexpected %v %v", d, err)
+	}
+	// 10^100 is out of range.
+	if _, err := DecodePGNumeric([]byte{0, 1, 0, 25, 0, 0, 0, 0, 0, 1}); err != errPGNumericRange {
+		t.Errorf("expected range error, got %v", err)
+	}
+	for i, b := range [][]byte{
+		nil,
+		{0, 1, 0, 0, 0, 0, 0, 0},
+		{0, 1, 0, 0, 0, 0, 0, 0, 0x27, 0x10},
+		{0, 0, 0, 0, 0x80, 0, 0, 0},
+	} {
+		if _, err := DecodePGNumeric(b); err != errPGNumericFormat {
+			t.Errorf("testCase #%d: expected format error, got %v", i, err)
+		}
+	}
+}
+
+func TestPGNumeric64(t *testing.T) {
+	// 12345678.90 keeps all its digits.
+	b := []byte{0, 3, 0, 1, 0, 0, 0, 2, 0x04, 0xd2, 0x16, 0x2e, 0x23, 0x28}
+	if d, err := DecodePGNumeric64(b); err != nil || d != MustEncodeDec64(1234567890, -2) {
+		t.Errorf("unexpected %v %v", d, err)
+	}
+	if got := MustEncodeDec64(1234567890, -2).AppendPGNumeric(nil); !bytes.Equal(got, b) {
+		t.Errorf("expect %x, got %x", b, got)
+	}
+	// 10^400 is out of range.
+	if _, err := DecodePGNumeric64([]byte{0, 1, 0, 100, 0, 0, 0, 0, 0, 1}); err != errPGNumericRange64 {
+		t.Errorf("expected range error, got %v", err)
+	}
+	if _, err := DecodePGNumeric64([]byte{0, 1, 0, 0, 0, 0, 0, 0}); err != errPGNumericFormat {
+		t.Errorf("expected format error, got %v", err)
+	}
+
+	// Values with a non-positive exponent round trip exactly.
+	for _, s := range []string{"1234567.890123456", "-9999999999999999", "1E-398", "0.000", "123456789012345.6", "NaN", "Infinity", "-Infinity"} {
+		d, err := ParseDec64(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, err := DecodePGNumeric64(d.AppendPGNumeric(nil)); err != nil || got.String() != d.String() {
+			t.Errorf("%s: round trip failed: %v %v", s, got, err)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import "math/big"
+
+// Quantize returns d rounded with the given mode to the exponent of q, the
+// IEEE 754 quantize operation, so that quantizing 1.2345 to 0.01 gives 1.23
+// and 2 to 0.01 gives 2.00. The value of q does not matter, only its
+// exponent, its quantum. It reports whether the result is exact, without
+// discarding nonzero digits.
+//
+// The result is NaN, and reported inexact, if the quantized value needs more
+// than seven digits, if either operand is NaN, or if just one of them is
+// infinite; quantizing an infinity to an infinity gives the infinity d.
+func (d Dec32) Quantize(q Dec32, mode RoundingMode) (Dec32, bool) {
+	c := Context{Mode: mode}
+	r := c.Quantize(d, q)
+	return r, c.Flags&Inexact == 0 && !r.IsNaN()
+}
+
+// Rescale is like Quantize with the exponent given as exp, such as -2 for
+// cents, rather than as that of a decimal. The result is NaN if exp is
+// outside the decimal32 exponent range, -101 to 90.
+func (d Dec32) Rescale(exp int, mode RoundingMode) (Dec32, bool) {
+	c := Context{Mode: mode}
+	r := c.Rescale(d, exp)
+	return r, c.Flags&Inexact == 0 && !r.IsNaN()
+}
+
+// SameQuantum reports whether x and y have the same exponent, or are both
+// infinities or both NaNs, as the IEEE 754 sameQuantum predicate does: 1.20
+// and 3.45 have the same quantum, and 1.2 and 1.20 do not.
+func (x Dec32) SameQuantum(y Dec32) bool {
+	switch {
+	case x.IsNaN() || y.IsNaN():
+		return x.IsNaN() && y.IsNaN()
+	case x.IsInf() || y.IsInf():
+		return x.IsInf() && y.IsInf()
+	}
+	_, _, xe := x.unpack()
+	_, _, ye := y.unpack()
+	return xe == ye
+}
+
+// DivQuantize returns the quotient x/y rounded once, with the given mode, to
+// scale fraction digits, that is to the exponent -scale, so that dividing
+// 10 by 3 to scale 2 gives 3.33. Dividing and then rounding the quotient to
+// fewer digits can round twice and give a different result.
+//
+// As when quantizing, the result is NaN if the rounded quotient needs more
+// than seven digits, if -scale is outside the decimal32 exponent range, or if
+// the quotient is infinite, including division of a nonzero value by zero.
+// Dividing by an infinity gives a zero with the requested scale.
+func (x Dec32) DivQuantize(y Dec32, scale int, mode RoundingMode) Dec32 {
+	exp := -scale
+	switch {
+	case x.IsNaN() || y.IsNaN() || exp < minExp || exp > maxExp:
+		return nan32
+	case x.IsInf() || y.Zero():
+		return nan32
+	case y.IsInf():
+		return pack32(x.Sign() != y.Sign(), 0, exp)
+	}
+	a, b := newBigDec32(x), newBigDec32(y)
+	neg := a.neg != b.neg
+	num, den := &a.coeff, &b.coeff
+	if s := a.exp - b.exp - exp; s >= 0 {
+		num.Mul(num, bigPow10(s))
+	} else {
+		den.Mul(den, bigPow10(-s))
+	}
+	var q, r big.Int
+	q.QuoRem(num, den, &r)
+	if r.Sign() != 0 && mode.roundUp(neg, &q, r.Lsh(&r, 1).Cmp(den)) {
+		q.Add(&q, big.NewInt(1))
+	}
+	if bigDigits(&q) > 7 {
+		return nan32
+	}
+	return pack32(neg, uint32(q.Uint64()), exp)
+}
+
+// RoundToIncrement returns d rounded with the given mode to a multiple of the
+// increment tick, such as a tick size of 0.05, 0.25 or 0.03125 for 1/32: with
+// a tick of 0.05, 1.23 rounds to the nearest multiple 1.25. The exact
+// quotient d/tick decides the rounding, so that ToNearestEven breaks ties to
+// an even multiple of the tick. The result has the exponent of tick where
+// its coefficient fits in seven digits, and the sign of tick is ignored.
+//
+// The result is NaN if tick is zero, infinite or NaN, if d is NaN, or if the
+// multiple cannot be represented exactly. Infinities are returned unchanged.
+func (d Dec32) RoundToIncrement(tick Dec32, mode RoundingMode) Dec32 {
+	switch {
+	case d.IsNaN() || tick.IsNaN() || tick.IsInf() || tick.Zero():
+		return nan32
+	case d.IsInf():
+		return d
+	}
+	a, b := newBigDec32(d), newBigDec32(tick)
+	// Align both coefficients to the smaller exponent.
+	exp := min(a.exp, b.exp)
+	num := new(big.Int).Mul(&a.coeff, bigPow10(a.exp-exp))
+	den := new(big.Int).Mul(&b.coeff, bigPow10(b.exp-exp))
+	var q, r big.Int
+	q.QuoRem(num, den, &r)
+	if r.Sign() != 0 && mode.roundUp(a.neg, &q, r.Lsh(&r, 1).Cmp(den)) {
+		q.Add(&q, big.NewInt(1))
+	}
+	x := &bigDec{neg: a.neg, exp: b.exp}
+	x.coeff.Mul(&q, &b.coeff)
+	if bigDigits(&x.coeff) > 7 {
+		x.reduce(maxExp)
+	}
+	m, exact := x.dec32()
+	if !exact {
+		return nan32
+	}
+	return m
+}
+
+// RoundBid returns d rounded down to a multiple of tick, toward negative
+// infinity, as a buy price is rounded so as not to bid more than intended.
+func (d Dec32) RoundBid(tick Dec32) Dec32 {
+	return d.RoundToIncrement(tick, ToNegativeInf)
+}
+
+// RoundAsk returns d rounded up to a multiple of tick, toward positive
+// infinity, as a sell price is rounded so as not to ask less than intended.
+func (d Dec32) RoundAsk(tick Dec32) Dec32 {
+	return d.RoundToIncrement(tick, ToPositiveInf)
+}
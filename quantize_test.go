@@ -0,0 +1,170 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import "testing"
+
+func TestDivQuantize(t *testing.T) {
+	testCases := []struct {
+		x, y  Dec32
+		scale int
+		mode  RoundingMode
+		ref   Dec32
+	}{
+		{mustEncode(t, 10, 0), mustEncode(t, 3, 0), 2, ToNearestEven, mustEncode(t, 333, -2)},
+		{mustEncode(t, 20, 0), mustEncode(t, 3, 0), 2, ToNearestEven, mustEncode(t, 667, -2)},
+		{mustEncode(t, 20, 0), mustEncode(t, 3, 0), 2, ToZero, mustEncode(t, 666, -2)},
+		{mustEncode(t, -10, 0), mustEncode(t, 3, 0), 2, ToNegativeInf, mustEncode(t, -334, -2)},
+		{mustEncode(t, -10, 0), mustEncode(t, 3, 0), 2, ToPositiveInf, mustEncode(t, -333, -2)},
+		// Ties are decided by the exact quotient.
+		{mustEncode(t, 1, 0), mustEncode(t, 8, 0), 2, ToNearestEven, mustEncode(t, 12, -2)},
+		{mustEncode(t, 1, 0), mustEncode(t, 8, 0), 2, ToNearestAway, mustEncode(t, 13, -2)},
+		{mustEncode(t, 3, 0), mustEncode(t, 8, 0), 2, ToNearestEven, mustEncode(t, 38, -2)},
+		// A quotient of 0.1249999844 rounds to 0.12, though rounding it
+		// first to seven digits gives 0.1250000.
+		{mustEncode(t, 1, 0), mustEncode(t, 8000001, -6), 2, ToNearestAway, mustEncode(t, 12, -2)},
+		{mustEncode(t, 12345, 0), mustEncode(t, 1, -2), -3, ToNearestEven, mustEncode(t, 1234, 3)},
+		{mustEncode(t, 5, 0), mustEncode(t, 1, -6), 0, ToNearestEven, mustEncode(t, 5000000, 0)},
+		{mustEncode(t, 5, 0), mustEncode(t, 1, -6), 1, ToNearestEven, nan32},
+		{mustEncode(t, 1, 0), mustEncode(t, 3, 0), 102, ToNearestEven, nan32},
+		{mustEncode(t, 1, 0), mustEncode(t, 0, 0), 2, ToNearestEven, nan32},
+		{mustEncode(t, 0, 0), mustEncode(t, 0, 0), 2, ToNearestEven, nan32},
+		{mustEncode(t, 0, 0), mustEncode(t, -7, 0), 2, ToNearestEven, mustEncode(t, 0, -2) | signMask},
+		{mustEncode(t, -1, 0), inf32, 3, ToNearestEven, mustEncode(t, 0, -3) | signMask},
+		{inf32, mustEncode(t, 1, 0), 2, ToNearestEven, nan32},
+		{nan32, mustEncode(t, 1, 0), 2, ToNearestEven, nan32},
+	}
+	for i, testCase := range testCases {
+		if r := testCase.x.DivQuantize(testCase.y, testCase.scale, testCase.mode); r != testCase.ref {
+			t.Errorf("testCase #%d: expect %v, got %v", i, testCase.ref, r)
+		}
+	}
+}
+
+func TestRoundToIncrement(t *testing.T) {
+	testCases := []struct {
+		d, tick Dec32
+		mode    RoundingMode
+		ref     Dec32
+	}{
+		{mustEncode(t, 123, -2), mustEncode(t, 5, -2), ToNearestEven, mustEncode(t, 125, -2)},
+		{mustEncode(t, 122, -2), mustEncode(t, 5, -2), ToNearestEven, mustEncode(t, 120, -2)},
+		{mustEncode(t, 10, 0), mustEncode(t, 25, -2), ToNearestEven, mustEncode(t, 1000, -2)},
+		{mustEncode(t, 1013, -2), mustEncode(t, 25, -2), ToNearestEven, mustEncode(t, 1025, -2)},
+		// Ties go to an even multiple of the tick.
+		{mustEncode(t, 1125, -3), mustEncode(t, 25, -2), ToNearestEven, mustEncode(t, 100, -2)},
+		{mustEncode(t, 1375, -3), mustEncode(t, 25, -2), ToNearestEven, mustEncode(t, 150, -2)},
+		{mustEncode(t, 1125, -3), mustEncode(t, 25, -2), ToNearestAway, mustEncode(t, 125, -2)},
+		// A tick of 1/32.
+		{mustEncode(t, 9951, -2), mustEncode(t, 3125, -5), ToNearestEven, mustEncode(t, 9950000, -5)},
+		{mustEncode(t, 9954, -2), mustEncode(t, 3125, -5), ToNearestEven, mustEncode(t, 9953125, -5)},
+		{mustEncode(t, -123, -2), mustEncode(t, 5, -2), ToNegativeInf, mustEncode(t, -125, -2)},
+		{mustEncode(t, -123, -2), mustEncode(t, -5, -2), ToZero, mustEncode(t, -120, -2)},
+		{mustEncode(t, -1, -2), mustEncode(t, 5, -2), ToNearestEven, pack32(true, 0, -2)},
+		{mustEncode(t, 1234, 0), mustEncode(t, 5, 2), ToNearestEven, mustEncode(t, 10, 2)},
+		// Multiples too long for the tick's exponent keep fewer zeros.
+		{mustEncode(t, 9999999, 0), mustEncode(t, 1, -2), ToNearestEven, mustEncode(t, 9999999, 0)},
+		{mustEncode(t, 9999999, 0), mustEncode(t, 7, -2), ToNearestEven, nan32},
+		{mustEncode(t, 1, 0), mustEncode(t, 0, 0), ToNearestEven, nan32},
+		{mustEncode(t, 1, 0), inf32, ToNearestEven, nan32},
+		{mustEncode(t, 1, 0), nan32, ToNearestEven, nan32},
+		{nan32, mustEncode(t, 5, -2), ToNearestEven, nan32},
+		{inf32 | signMask, mustEncode(t, 5, -2), ToNearestEven, inf32 | signMask},
+	}
+	for i, testCase := range testCases {
+		if r := testCase.d.RoundToIncrement(testCase.tick, testCase.mode); r != testCase.ref {
+			t.Errorf("testCase #%d: expect %v, got %v", i, testCase.ref, r)
+		}
+	}
+
+	price, tick := mustEncode(t, 10012, -3), mustEncode(t, 5, -2)
+	if bid := price.RoundBid(tick); bid != mustEncode(t, 1000, -2) {
+		t.Errorf("expect bid 10.00, got %v", bid)
+	}
+	if ask := price.RoundAsk(tick); ask != mustEncode(t, 1005, -2) {
+		t.Errorf("expect ask 10.05, got %v", ask)
+	}
+}
+
+func TestQuantize(t *testing.T) {
+	cent := mustEncode(t, 1, -2)
+	testCases := []struct {
+		d, q  Dec32
+		mode  RoundingMode
+		ref   Dec32
+		exact bool
+	}{
+		{mustEncode(t, 12345, -4), cent, ToNearestEven, mustEncode(t, 123, -2), false},
+		{mustEncode(t, 2, 0), cent, ToNearestEven, mustEncode(t, 200, -2), true},
+		{mustEncode(t, -125, -3), cent, ToNearestEven, mustEncode(t, -12, -2), false},
+		{mustEncode(t, -125, -3), cent, ToNearestAway, mustEncode(t, -13, -2), false},
+		{mustEncode(t, -125, -3), cent, ToNegativeInf, mustEncode(t, -13, -2), false},
+		// Only the exponent of q matters.
+		{mustEncode(t, 12345, -4), mustEncode(t, -9999, -2), ToZero, mustEncode(t, 123, -2), false},
+		{mustEncode(t, 150, 0), mustEncode(t, 1, 2), ToNearestEven, mustEncode(t, 2, 2), false},
+		{mustEncode(t, 9999999, 0), mustEncode(t, 1, 1), ToNearestEven, mustEncode(t, 1000000, 1), false},
+		{mustEncode(t, 0, -2), mustEncode(t, 1, 5), ToNearestEven, mustEncode(t, 0, 5), true},
+		{mustEncode(t, -1, -3), mustEncode(t, 1, -1), ToNearestEven, pack32(true, 0, -1), false},
+		// The quantized value must fit in seven digits.
+		{mustEncode(t, 1234567, 0), mustEncode(t, 1, -1), ToNearestEven, nan32, false},
+		{mustEncode(t, 1, 90), cent, ToNearestEven, nan32, false},
+		{inf32, inf32 | signMask, ToNearestEven, inf32, true},
+		{inf32, cent, ToNearestEven, nan32, false},
+		{cent, inf32, ToNearestEven, nan32, false},
+		{nan32, cent, ToNearestEven, nan32, false},
+	}
+	for i, testCase := range testCases {
+		r, exact := testCase.d.Quantize(testCase.q, testCase.mode)
+		if r != testCase.ref || exact != testCase.exact {
+			t.Errorf("testCase #%d: quantize %v to %v: expect %v %v, got %v %v", i, testCase.d, testCase.q, testCase.ref, testCase.exact, r, exact)
+		}
+		if !r.IsNaN() && !r.SameQuantum(testCase.q) {
+			t.Errorf("testCase #%d: expect %v to have the quantum of %v", i, r, testCase.q)
+		}
+	}
+}
+
+func TestRescale(t *testing.T) {
+	if r, exact := mustEncode(t, 12345, -4).Rescale(-2, ToNearestEven); r != mustEncode(t, 123, -2) || exact {
+		t.Errorf("unexpected %v %v", r, exact)
+	}
+	if r, exact := mustEncode(t, 5, 0).Rescale(-3, ToNearestEven); r != mustEncode(t, 5000, -3) || !exact {
+		t.Errorf("unexpected %v %v", r, exact)
+	}
+	for _, exp := range []int{minExp - 1, maxExp + 1} {
+		if r, _ := mustEncode(t, 0, 0).Rescale(exp, ToNearestEven); !r.IsNaN() {
+			t.Errorf("exponent %d: expect NaN, got %v", exp, r)
+		}
+	}
+	c := Context{Precision: 3}
+	if r := c.Rescale(mustEncode(t, 12345, -4), -3); r != nan32 || c.Flags != Invalid {
+		t.Errorf("expect NaN and Invalid at precision 3, got %v %v", r, c.Flags)
+	}
+	c.Flags = 0
+	if r := c.Quantize(mustEncode(t, 12345, -4), mustEncode(t, 1, -2)); r != mustEncode(t, 123, -2) || c.Flags != Inexact {
+		t.Errorf("expect 1.23 and Inexact, got %v %v", r, c.Flags)
+	}
+}
+
+func TestSameQuantum(t *testing.T) {
+	testCases := []struct {
+		x, y Dec32
+		ref  bool
+	}{
+		{mustEncode(t, 120, -2), mustEncode(t, 345, -2), true},
+		{mustEncode(t, 12, -1), mustEncode(t, 120, -2), false},
+		{mustEncode(t, 0, 0), pack32(true, 0, 0), true},
+		{inf32, inf32 | signMask, true},
+		{nan32, Dec32(snanMask), true},
+		{inf32, nan32, false},
+		{mustEncode(t, 0, 0), inf32, false},
+		{mustEncode(t, 0, 0), nan32, false},
+	}
+	for i, testCase := range testCases {
+		if r := testCase.x.SameQuantum(testCase.y); r != testCase.ref {
+			t.Errorf("testCase #%d: %v, %v: expect %v, got %v", i, testCase.x, testCase.y, testCase.ref, r)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"math"
+	"math/big"
+)
+
+// Rat returns the exact value of d as a new big.Rat, or nil if d is infinite
+// or NaN, which a big.Rat cannot hold. The sign of a negative zero is lost.
+func (d Dec32) Rat() *big.Rat {
+	if d.IsInf() || d.IsNaN() {
+		return nil
+	}
+	return d.rat()
+}
+
+// FromBigRat converts r to the nearest decimal32 with the given rounding
+// mode, reporting whether the conversion is exact, so that 1/4 converts
+// exactly to 0.25 and 1/3 to 0.3333333 inexactly. An exact result has the
+// exponent closest to 0, so 3/1 is 3; values too large in magnitude overflow
+// as Context rounding does.
+func FromBigRat(r *big.Rat, mode RoundingMode) (Dec32, bool) {
+	c := Context{Mode: mode}
+	d := c.FromBigRat(r)
+	return d, c.Flags&Inexact == 0
+}
+
+// BigFloat returns the value of d as a new big.Float with precision prec,
+// rounded to nearest, ties to even, or nil if d is NaN, which a big.Float
+// cannot hold. Infinities and zeros keep their sign. If prec is 0, the
+// precision is at least 64 and enough to hold the numerator and denominator
+// of the exact value, as for big.Float.SetRat.
+func (d Dec32) BigFloat(prec uint) *big.Float {
+	z := new(big.Float).SetPrec(prec)
+	switch {
+	case d.IsNaN():
+		return nil
+	case d.IsInf():
+		return z.SetInf(d.Sign() < 0)
+	case d.Zero():
+		return z.SetFloat64(math.Copysign(0, float64(d.Sign())))
+	}
+	return z.SetRat(d.rat())
+}
@@ -0,0 +1,88 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestRat(t *testing.T) {
+	testCases := []struct {
+		d   Dec32
+		ref string
+	}{
+		{mustEncode(t, 125, -2), "5/4"},
+		{mustEncode(t, -3, 4), "-30000/1"},
+		{SmallestSubnormalDec32, "1/100000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000"},
+		{pack32(true, 0, 0), "0/1"},
+	}
+	for i, testCase := range testCases {
+		if r := testCase.d.Rat(); r == nil || r.String() != testCase.ref {
+			t.Errorf("testCase #%d: expect %s, got %v", i, testCase.ref, r)
+		}
+	}
+	if r := inf32.Rat(); r != nil {
+		t.Errorf("expect nil for Inf, got %v", r)
+	}
+}
+
+func TestFromBigRat(t *testing.T) {
+	testCases := []struct {
+		r     string
+		mode  RoundingMode
+		ref   Dec32
+		exact bool
+	}{
+		{"1/4", ToNearestEven, mustEncode(t, 25, -2), true},
+		{"3", ToNearestEven, mustEncode(t, 3, 0), true},
+		{"-30000", ToNearestEven, mustEncode(t, -30000, 0), true},
+		{"1/3", ToNearestEven, mustEncode(t, 3333333, -7), false},
+		{"2/3", ToZero, mustEncode(t, 6666666, -7), false},
+		{"-2/3", ToNegativeInf, mustEncode(t, -6666667, -7), false},
+		{"0", ToNearestEven, mustEncode(t, 0, 0), true},
+		{"1e97", ToNearestEven, inf32, false},
+		{"1e97", ToZero, MaxDec32, false},
+		{"1/3" + strings.Repeat("0", 101), ToNearestEven, mustEncode(t, 0, -101), false},
+	}
+	for i, testCase := range testCases {
+		r, ok := new(big.Rat).SetString(testCase.r)
+		if !ok {
+			t.Fatalf("testCase #%d: bad rational %q", i, testCase.r)
+		}
+		if d, exact := FromBigRat(r, testCase.mode); d != testCase.ref || exact != testCase.exact {
+			t.Errorf("testCase #%d: %s: expect %v %v, got %v %v", i, testCase.r, testCase.ref, testCase.exact, d, exact)
+		}
+	}
+}
+
+func TestBigFloat(t *testing.T) {
+	testCases := []struct {
+		d    Dec32
+		prec uint
+		ref  float64
+	}{
+		{mustEncode(t, 125, -2), 53, 1.25},
+		{mustEncode(t, 1, -1), 53, 0.1},
+		{mustEncode(t, 1, -1), 8, 0.10009765625},
+		{mustEncode(t, -9999999, 90), 0, -9.999999e96},
+		{pack32(true, 0, -3), 0, math.Copysign(0, -1)},
+		{inf32 | signMask, 0, math.Inf(-1)},
+	}
+	for i, testCase := range testCases {
+		f := testCase.d.BigFloat(testCase.prec)
+		if testCase.prec != 0 && f.Prec() != testCase.prec {
+			t.Errorf("testCase #%d: expect precision %d, got %d", i, testCase.prec, f.Prec())
+		}
+		if v, _ := f.Float64(); v != testCase.ref || math.Signbit(v) != math.Signbit(testCase.ref) {
+			t.Errorf("testCase #%d: expect %g, got %g", i, testCase.ref, v)
+		}
+	}
+	if f := nan32.BigFloat(53); f != nil {
+		t.Errorf("expect nil for NaN, got %v", f)
+	}
+}
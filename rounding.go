@@ -0,0 +1,120 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"math/big"
+	"strconv"
+)
+
+// A RoundingMode selects how a result that cannot be represented exactly is
+// rounded to one with fewer digits. The first five modes are the
+// rounding-direction attributes of IEEE 754-2008, named as in math/big; the
+// rest are the additional modes of the decNumber library.
+type RoundingMode uint8
+
+const (
+	// ToNearestEven rounds to the nearest value, ties to the one with an
+	// even last digit. This is the default in IEEE 754-2008, and the mode
+	// of all operations that do not take one.
+	ToNearestEven RoundingMode = iota
+	// ToNearestAway rounds to the nearest value, ties away from zero, as
+	// in the commercial round-half-up convention. It is ROUND_HALF_UP in
+	// decNumber.
+	ToNearestAway
+	// ToZero rounds toward zero, truncating the discarded digits.
+	ToZero
+	// ToPositiveInf rounds toward positive infinity.
+	ToPositiveInf
+	// ToNegativeInf rounds toward negative infinity.
+	ToNegativeInf
+	// ToNearestTowardZero rounds to the nearest value, ties toward zero.
+	// It is ROUND_HALF_DOWN in decNumber.
+	ToNearestTowardZero
+	// AwayFromZero rounds away from zero, so that any nonzero discarded
+	// digit increments the magnitude. It is ROUND_UP in decNumber.
+	AwayFromZero
+	// ZeroFiveUp rounds toward zero, unless that would leave a last digit
+	// of 0 or 5, in which case it rounds away from zero. It is ROUND_05UP
+	// in decNumber, used to round in several steps without the error of
+	// double rounding.
+	ZeroFiveUp
+)
+
+var roundingModeNames = [...]string{
+	ToNearestEven: "ToNearestEven",
+	ToNearestAway: "ToNearestAway",
+	ToZero:        "ToZero",
+	ToPositiveInf: "ToPositiveInf",
+	ToNegativeInf: "ToNegativeInf",
+
+	ToNearestTowardZero: "ToNearestTowardZero",
+	AwayFromZero:        "AwayFromZero",
+	ZeroFiveUp:          "ZeroFiveUp",
+}
+
+func (mode RoundingMode) String() string {
+	if int(mode) < len(roundingModeNames) {
+		return roundingModeNames[mode]
+	}
+	return "RoundingMode(" + strconv.Itoa(int(mode)) + ")"
+}
+
+// roundUp reports whether a coefficient c of a value negative if neg is
+// incremented in magnitude when the nonzero discarded digits compare with one
+// half of a unit in the last place as half does: -1 for less than half, 0 for
+// exactly half, +1 for more.
+func (mode RoundingMode) roundUp(neg bool, c *big.Int, half int) bool {
+	switch mode {
+	case ToNearestEven:
+		return half > 0 || (half == 0 && c.Bit(0) == 1)
+	case ToNearestAway:
+		return half >= 0
+	case ToPositiveInf:
+		return !neg
+	case ToNegativeInf:
+		return neg
+	case ToNearestTowardZero:
+		return half > 0
+	case AwayFromZero:
+		return true
+	case ZeroFiveUp:
+		var r big.Int
+		last := r.Rem(c, bigTen).Int64()
+		return last == 0 || last == 5
+	}
+	return false
+}
+
+// overflowsToInf reports whether a result too large to represent, negative
+// if neg, rounds to an infinity, as IEEE 754 specifies, rather than to the
+// largest finite value, as modes that round towards zero in its direction do.
+func (mode RoundingMode) overflowsToInf(neg bool) bool {
+	switch {
+	case mode == ToZero || mode == ZeroFiveUp,
+		mode == ToPositiveInf && neg,
+		mode == ToNegativeInf && !neg:
+		return false
+	}
+	return true
+}
+
+// RoundSignificant returns d rounded to n significant digits with the given
+// rounding mode, such as 1.23E+3 for 1234.5 with n = 3. Values with at most n
+// digits are returned unchanged, and values of n below 1 are treated as 1.
+// Rounding up 9.999999E+96 overflows to infinity. Infinities are returned
+// unchanged, and NaNs as a quiet NaN.
+func (d Dec32) RoundSignificant(n int, mode RoundingMode) Dec32 {
+	switch {
+	case d.IsInf():
+		return d
+	case d.IsNaN():
+		return nan32
+	}
+	x := newBigDec32(d)
+	x.roundDigits(max(n, 1), mode)
+	r, _ := x.dec32()
+	return r
+}
@@ -0,0 +1,88 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import "testing"
+
+func TestRoundingModeString(t *testing.T) {
+	if s := ToNegativeInf.String(); s != "ToNegativeInf" {
+		t.Errorf("unexpected %q", s)
+	}
+	if s := RoundingMode(99).String(); s != "RoundingMode(99)" {
+		t.Errorf("unexpected %q", s)
+	}
+}
+
+func TestRoundSignificant(t *testing.T) {
+	type modes [5]Dec32 // indexed by RoundingMode
+	testCases := []struct {
+		d   Dec32
+		n   int
+		ref modes
+	}{
+		{mustEncode(t, 12345, -1), 3, modes{
+			mustEncode(t, 123, 1), mustEncode(t, 123, 1), mustEncode(t, 123, 1), mustEncode(t, 124, 1), mustEncode(t, 123, 1)}},
+		{mustEncode(t, 1235, 0), 3, modes{
+			mustEncode(t, 124, 1), mustEncode(t, 124, 1), mustEncode(t, 123, 1), mustEncode(t, 124, 1), mustEncode(t, 123, 1)}},
+		{mustEncode(t, 1225, 0), 3, modes{
+			mustEncode(t, 122, 1), mustEncode(t, 123, 1), mustEncode(t, 122, 1), mustEncode(t, 123, 1), mustEncode(t, 122, 1)}},
+		{mustEncode(t, -1225, 0), 3, modes{
+			mustEncode(t, -122, 1), mustEncode(t, -123, 1), mustEncode(t, -122, 1), mustEncode(t, -122, 1), mustEncode(t, -123, 1)}},
+		{mustEncode(t, -1226, 0), 3, modes{
+			mustEncode(t, -123, 1), mustEncode(t, -123, 1), mustEncode(t, -122, 1), mustEncode(t, -122, 1), mustEncode(t, -123, 1)}},
+		// Carries into a new digit.
+		{mustEncode(t, 9996, -2), 3, modes{
+			mustEncode(t, 100, 0), mustEncode(t, 100, 0), mustEncode(t, 999, -1), mustEncode(t, 100, 0), mustEncode(t, 999, -1)}},
+		{mustEncode(t, 9999999, 90), 1, modes{
+			inf32, inf32, mustEncode(t, 9000000, 90), inf32, mustEncode(t, 9000000, 90)}},
+		// Values with no more than n digits are unchanged.
+		{mustEncode(t, 120, -2), 3, modes{
+			mustEncode(t, 120, -2), mustEncode(t, 120, -2), mustEncode(t, 120, -2), mustEncode(t, 120, -2), mustEncode(t, 120, -2)}},
+		{mustEncode(t, 15, 0), 0, modes{
+			mustEncode(t, 2, 1), mustEncode(t, 2, 1), mustEncode(t, 1, 1), mustEncode(t, 2, 1), mustEncode(t, 1, 1)}},
+		{inf32 | signMask, 3, modes{inf32 | signMask, inf32 | signMask, inf32 | signMask, inf32 | signMask, inf32 | signMask}},
+		{Dec32(snanMask), 3, modes{nan32, nan32, nan32, nan32, nan32}},
+	}
+	for i, testCase := range testCases {
+		for mode, ref := range testCase.ref {
+			if r := testCase.d.RoundSignificant(testCase.n, RoundingMode(mode)); r != ref {
+				t.Errorf("testCase #%d: %v: expect %v, got %v", i, RoundingMode(mode), ref, r)
+			}
+		}
+	}
+}
+
+func TestExtendedRoundingModes(t *testing.T) {
+	modes := []RoundingMode{ZeroFiveUp, ToNearestTowardZero, AwayFromZero}
+	testCases := []struct {
+		d   Dec32
+		ref [3]int32 // indexed as modes
+	}{
+		{mustEncode(t, 3, -1), [3]int32{1, 0, 1}},
+		{mustEncode(t, 13, -1), [3]int32{1, 1, 2}},
+		{mustEncode(t, 53, -1), [3]int32{6, 5, 6}},
+		{mustEncode(t, 43, -1), [3]int32{4, 4, 5}},
+		{mustEncode(t, -53, -1), [3]int32{-6, -5, -6}},
+		{mustEncode(t, 50, -1), [3]int32{5, 5, 5}},
+		{mustEncode(t, 25, -1), [3]int32{2, 2, 3}},
+		{mustEncode(t, -25, -1), [3]int32{-2, -2, -3}},
+		{mustEncode(t, 26, -1), [3]int32{2, 3, 3}},
+	}
+	one := mustEncode(t, 1, 0)
+	for i, testCase := range testCases {
+		for j, mode := range modes {
+			ref := mustEncode(t, testCase.ref[j], 0)
+			if r := testCase.d.DivQuantize(one, 0, mode); r != ref {
+				t.Errorf("testCase #%d: %v: expect %v, got %v", i, mode, ref, r)
+			}
+		}
+	}
+	if r := mustEncode(t, 1235, 0).RoundSignificant(2, ZeroFiveUp); r != mustEncode(t, 12, 2) {
+		t.Errorf("unexpected %v", r)
+	}
+	if r := mustEncode(t, 1051, 0).RoundSignificant(2, ZeroFiveUp); r != mustEncode(t, 11, 2) {
+		t.Errorf("unexpected %v", r)
+	}
+}
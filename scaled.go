@@ -0,0 +1,59 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+// FromScaledInt64 converts the scaled integer v * 10^-scale to a decimal,
+// the (value, scale) representation of many APIs: Google's money micros are
+// scale 6, so (1500000, 6) is 1.500000. The exponent of the result is
+// -scale where the value fits, keeping the scale. It returns false if the
+// value cannot be represented exactly in decimal32, in which case the result
+// is rounded to the nearest decimal32, ties to even.
+func FromScaledInt64(v int64, scale int32) (Dec32, bool) {
+	x := newBigDecInt64(v)
+	x.exp = -int(scale)
+	return x.dec32()
+}
+
+// FromInt64 converts v to a decimal with exponent 0, reporting whether the
+// conversion is exact, as it is for integers of at most seven digits. Larger
+// integers are rounded to the nearest decimal32, ties to even, so that
+// 123456789 becomes 1.234568E+8.
+func FromInt64(v int64) (Dec32, bool) {
+	return FromScaledInt64(v, 0)
+}
+
+// Int64 returns the value of d as an int64, reporting false, with a result of
+// 0, if d is not finite, not an integer, or out of the int64 range. Integers
+// with a positive exponent or trailing fraction zeros, such as 1.5E+3 and
+// 12.00, convert exactly.
+func (d Dec32) Int64() (int64, bool) {
+	return d.ToScaledInt64(0)
+}
+
+// ToScaledInt64 returns the integer v such that d is v * 10^-scale, so that
+// 1.5 is 1500000 with scale 6. It returns false if d is not finite, has more
+// than scale fraction digits, or v is out of the int64 range; use
+// RoundScaledInt64 to round to the scale instead.
+func (d Dec32) ToScaledInt64(scale int32) (int64, bool) {
+	if d.IsInf() || d.IsNaN() {
+		return 0, false
+	}
+	return newBigDec32(d).scaledInt64(int(scale))
+}
+
+// RoundScaledInt64 is like ToScaledInt64 but rounds d to scale fraction
+// digits with the given mode when it has more, so that 0.125 with scale 2
+// gives 12 ties to even, or 13 rounding ToNearestAway. It returns false only
+// if d is not finite or the result is out of the int64 range.
+func (d Dec32) RoundScaledInt64(scale int32, mode RoundingMode) (int64, bool) {
+	if d.IsInf() || d.IsNaN() {
+		return 0, false
+	}
+	x := newBigDec32(d)
+	if x.exp < -int(scale) {
+		x.rescaleMode(-int(scale), mode)
+	}
+	return x.scaledInt64(int(scale))
+}
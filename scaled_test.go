@@ -0,0 +1,127 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFromScaledInt64(t *testing.T) {
+	testCases := []struct {
+		v     int64
+		scale int32
+		ref   Dec32
+		exact bool
+	}{
+		{1500000, 6, mustEncode(t, 1500000, -6), true},
+		{-12345, 2, mustEncode(t, -12345, -2), true},
+		{0, 2, mustEncode(t, 0, -2), true},
+		{7, -3, mustEncode(t, 7, 3), true},
+		{12345678, 2, mustEncode(t, 1234568, -1), false},
+		{math.MinInt64, 0, mustEncode(t, -9223372, 12), false},
+		{1, -97, inf32, false},
+		{1, 102, mustEncode(t, 0, -101), false},
+	}
+	for i, testCase := range testCases {
+		d, exact := FromScaledInt64(testCase.v, testCase.scale)
+		if d != testCase.ref || exact != testCase.exact {
+			t.Errorf("testCase #%d: expect %v %v, got %v %v", i, testCase.ref, testCase.exact, d, exact)
+		}
+	}
+}
+
+func TestToScaledInt64(t *testing.T) {
+	testCases := []struct {
+		d     Dec32
+		scale int32
+		v     int64
+		ok    bool
+	}{
+		{mustEncode(t, 15, -1), 6, 1500000, true},
+		{mustEncode(t, -12345, -2), 2, -12345, true},
+		{mustEncode(t, 12, 3), 0, 12000, true},
+		{mustEncode(t, 12, 3), -3, 12, true},
+		{mustEncode(t, 125, -3), 2, 0, false},
+		{mustEncode(t, 1200, -3), 2, 120, true},
+		{mustEncode(t, 1, 90), 0, 0, false},
+		{inf32, 2, 0, false},
+		{nan32, 2, 0, false},
+	}
+	for i, testCase := range testCases {
+		v, ok := testCase.d.ToScaledInt64(testCase.scale)
+		if v != testCase.v || ok != testCase.ok {
+			t.Errorf("testCase #%d: expect %d %v, got %d %v", i, testCase.v, testCase.ok, v, ok)
+		}
+	}
+}
+
+func TestRoundScaledInt64(t *testing.T) {
+	testCases := []struct {
+		d     Dec32
+		scale int32
+		mode  RoundingMode
+		v     int64
+		ok    bool
+	}{
+		{mustEncode(t, 125, -3), 2, ToNearestEven, 12, true},
+		{mustEncode(t, 125, -3), 2, ToNearestAway, 13, true},
+		{mustEncode(t, -125, -3), 2, ToNegativeInf, -13, true},
+		{mustEncode(t, -125, -3), 2, ToZero, -12, true},
+		{mustEncode(t, 15, -1), 6, ToZero, 1500000, true},
+		{mustEncode(t, 1234567, 0), -3, ToNearestEven, 1235, true},
+		{mustEncode(t, 1, -101), 2, ToPositiveInf, 1, true},
+		{mustEncode(t, 1, 90), 0, ToNearestEven, 0, false},
+		{inf32, 2, ToNearestEven, 0, false},
+	}
+	for i, testCase := range testCases {
+		v, ok := testCase.d.RoundScaledInt64(testCase.scale, testCase.mode)
+		if v != testCase.v || ok != testCase.ok {
+			t.Errorf("testCase #%d: expect %d %v, got %d %v", i, testCase.v, testCase.ok, v, ok)
+		}
+	}
+}
+
+func TestInt64(t *testing.T) {
+	testCases := []struct {
+		d   Dec32
+		ref int64
+		ok  bool
+	}{
+		{mustEncode(t, -1234567, 0), -1234567, true},
+		{mustEncode(t, 15, 2), 1500, true},
+		{mustEncode(t, 1200, -2), 12, true},
+		{mustEncode(t, 125, -2), 0, false},
+		{mustEncode(t, 9223372, 12), 9223372000000000000, true},
+		{mustEncode(t, 9223373, 12), 0, false},
+		{pack32(true, 0, 5), 0, true},
+		{inf32, 0, false},
+		{nan32, 0, false},
+	}
+	for i, testCase := range testCases {
+		if v, ok := testCase.d.Int64(); v != testCase.ref || ok != testCase.ok {
+			t.Errorf("testCase #%d: %v: expect %d %v, got %d %v", i, testCase.d, testCase.ref, testCase.ok, v, ok)
+		}
+	}
+}
+
+func TestFromInt64(t *testing.T) {
+	testCases := []struct {
+		v     int64
+		ref   Dec32
+		exact bool
+	}{
+		{-42, mustEncode(t, -42, 0), true},
+		{9999999, mustEncode(t, 9999999, 0), true},
+		{123456789, mustEncode(t, 1234568, 2), false},
+		{12345000, mustEncode(t, 1234500, 1), true},
+		{math.MinInt64, mustEncode(t, -9223372, 12), false},
+	}
+	for i, testCase := range testCases {
+		if d, exact := FromInt64(testCase.v); d != testCase.ref || exact != testCase.exact {
+			t.Errorf("testCase #%d: %d: expect %v %v, got %v %v", i, testCase.v, testCase.ref, testCase.exact, d, exact)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+// siSymbols holds the SI prefix symbols from quecto (10^-30) to quetta
+// (10^30), in steps of three powers of ten.
+var siSymbols = []string{
+	"q", "r", "y", "z", "a", "f", "p", "n", "µ", "m",
+	"",
+	"k", "M", "G", "T", "P", "E", "Z", "Y", "R", "Q",
+}
+
+// siBase is the power of ten of the first symbol in siSymbols.
+const siBase = -30
+
+// FormatSI returns the decimal value in compact form with an SI prefix
+// symbol, chosen from the adjusted exponent so that between one and three
+// digits precede the decimal point: 125E+2 is written "12.5k" and 0.0000032 is
+// written "3.2µ" (with the micro sign, U+00B5). If prec is positive, the value
+// is first rounded to prec significant digits, ties to even; otherwise all of
+// its digits are kept, including trailing zeros, so 1.20E+4 is "12.0k".
+//
+// Zeros are written "0", and values beyond the range of the prefixes, as well
+// as special values, are written as by String.
+func (d Dec32) FormatSI(prec int) string {
+	if d.IsInf() || d.IsNaN() {
+		return d.String()
+	}
+	x := newBigDec32(d)
+	if x.isZero() {
+		if x.neg {
+			return "-0"
+		}
+		return "0"
+	}
+	if prec > 0 {
+		x.roundDigits(prec, ToNearestEven)
+	}
+	adjusted := x.exp + bigDigits(&x.coeff) - 1
+	group := adjusted / 3
+	if adjusted < 0 && adjusted%3 != 0 {
+		group--
+	}
+	i := group - siBase/3
+	if i < 0 || i >= len(siSymbols) {
+		r, _ := x.dec32()
+		return r.String()
+	}
+	var buf []byte
+	if x.neg {
+		buf = append(buf, '-')
+	}
+	buf = appendPlainDigits(buf, []byte(x.coeff.String()), x.exp-3*group)
+	return string(append(buf, siSymbols[i]...))
+}
@@ -0,0 +1,43 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import "testing"
+
+func TestFormatSI(t *testing.T) {
+	testCases := []struct {
+		d    Dec32
+		prec int
+		ref  string
+	}{
+		{mustEncode(t, 12500, 0), 0, "12.500k"},
+		{mustEncode(t, 125, 2), 0, "12.5k"},
+		{mustEncode(t, 32, -7), 0, "3.2µ"},
+		{mustEncode(t, 120, 2), 0, "12.0k"},
+		{mustEncode(t, 999, 0), 0, "999"},
+		{mustEncode(t, 15, -1), 0, "1.5"},
+		{mustEncode(t, 15, -4), 0, "1.5m"},
+		{mustEncode(t, 1, 3), 0, "1k"},
+		{mustEncode(t, 1, 2), 0, "100"},
+		{mustEncode(t, -4567, 5), 0, "-456.7M"},
+		{mustEncode(t, 1234567, 0), 3, "1.23M"},
+		{mustEncode(t, 1235, 0), 3, "1.24k"},
+		// Rounding can move the value to the next prefix.
+		{mustEncode(t, 9996, -1), 3, "1.00k"},
+		{mustEncode(t, 1, 30), 0, "1Q"},
+		{mustEncode(t, 1, 33), 0, "1E+33"},
+		{mustEncode(t, 1, -30), 0, "1q"},
+		{mustEncode(t, 1, -31), 0, "1E-31"},
+		{mustEncode(t, 0, 5), 0, "0"},
+		{mustEncode(t, 0, 0) | signMask, 0, "-0"},
+		{inf32 | signMask, 0, "-Infinity"},
+		{nan32, 2, "NaN"},
+	}
+	for i, testCase := range testCases {
+		if s := testCase.d.FormatSI(testCase.prec); s != testCase.ref {
+			t.Errorf("testCase #%d: expect %q, got %q", i, testCase.ref, s)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"cmp"
+	"encoding/binary"
+	"math"
+)
+
+// DecodeSlice decodes packed 4-byte decimal32 interchange encodings from src
+// into dst, using the given byte order, and returns the number of values
+// decoded, which is the minimum of len(dst) and len(src)/4. Trailing bytes
+// of src that do not form a whole value are ignored.
+func DecodeSlice(dst []Dec32, src []byte, order binary.ByteOrder) int {
+	n := len(src) / 4
+	if len(dst) < n {
+		n = len(dst)
+	}
+	dst, src = dst[:n], src[:4*n]
+	switch order {
+	case binary.BigEndian:
+		for i := range dst {
+			b := src[4*i : 4*i+4 : 4*i+4]
+			dst[i] = Dec32(uint32(b[3]) | uint32(b[2])<<8 | uint32(b[1])<<16 | uint32(b[0])<<24)
+		}
+	case binary.LittleEndian:
+		for i := range dst {
+			b := src[4*i : 4*i+4 : 4*i+4]
+			dst[i] = Dec32(uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16 | uint32(b[3])<<24)
+		}
+	default:
+		for i := range dst {
+			dst[i] = Dec32(order.Uint32(src[4*i:]))
+		}
+	}
+	return n
+}
+
+// EncodeSlice encodes the values of src into dst as packed 4-byte decimal32
+// interchange encodings, using the given byte order, and returns the number
+// of values encoded, which is the minimum of len(src) and len(dst)/4.
+func EncodeSlice(dst []byte, src []Dec32, order binary.ByteOrder) int {
+	n := len(dst) / 4
+	if len(src) < n {
+		n = len(src)
+	}
+	dst, src = dst[:4*n], src[:n]
+	switch order {
+	case binary.BigEndian:
+		for i, d := range src {
+			b := dst[4*i : 4*i+4 : 4*i+4]
+			b[0], b[1], b[2], b[3] = byte(d>>24), byte(d>>16), byte(d>>8), byte(d)
+		}
+	case binary.LittleEndian:
+		for i, d := range src {
+			b := dst[4*i : 4*i+4 : 4*i+4]
+			b[0], b[1], b[2], b[3] = byte(d), byte(d>>8), byte(d>>16), byte(d>>24)
+		}
+	default:
+		for i, d := range src {
+			order.PutUint32(dst[4*i:], uint32(d))
+		}
+	}
+	return n
+}
+
+// AlignSlice rewrites the finite values of xs in place to share a single
+// exponent, which it returns, so that a column of values can be aggregated
+// as scaled integers. The exponent is the smallest of the nonzero values'
+// exponents for which every value still fits in seven digits; values with
+// more fraction digits than that are rounded to it, ties to even, and the
+// indices of those that lost nonzero digits are returned in inexact. Zeros
+// take the common exponent, and infinities and NaNs are left unchanged. If
+// xs holds no finite nonzero value, the exponent is the smallest of the
+// zeros' exponents, or 0 if there are none.
+func AlignSlice(xs []Dec32) (exp int, inexact []int) {
+	exp = math.MaxInt
+	maxAdjusted, zeroExp := math.MinInt, math.MaxInt
+	for _, d := range xs {
+		if d.IsInf() || d.IsNaN() {
+			continue
+		}
+		_, coeff, e := d.unpack()
+		if coeff == 0 {
+			zeroExp = min(zeroExp, e)
+			continue
+		}
+		exp = min(exp, e)
+		maxAdjusted = max(maxAdjusted, e+numDigits(uint64(coeff))-1)
+	}
+	switch {
+	case exp != math.MaxInt:
+		exp = max(exp, maxAdjusted-6)
+	case zeroExp != math.MaxInt:
+		exp = zeroExp
+	default:
+		return 0, nil
+	}
+	for i, d := range xs {
+		if d.IsInf() || d.IsNaN() {
+			continue
+		}
+		x := newBigDec32(d)
+		if x.rescale(exp) {
+			inexact = append(inexact, i)
+		}
+		xs[i] = pack32(x.neg, uint32(x.coeff.Uint64()), exp)
+	}
+	return exp, inexact
+}
+
+// Components holds the coefficient and exponent of a decimal32 value, as
+// returned by Decode.
+type Components struct {
+	Coeff int32
+	Exp   int8
+}
+
+// DecodeComponents decodes the values of src into their coefficients and
+// exponents in dst, as Decode does, and returns the number of values
+// decoded, which is the minimum of len(dst) and len(src), and whether all of
+// them could be decoded. Infinities and NaNs decode to a zero coefficient
+// and exponent.
+func DecodeComponents(dst []Components, src []Dec32) (n int, ok bool) {
+	n = min(len(dst), len(src))
+	dst, src = dst[:n], src[:n]
+	ok = true
+	for i, d := range src {
+		u := uint32(d)
+		coeff, bexp := u&smallCoeffMask, (u&smallExpMask)>>smallExpOffset
+		if u&largeMask == largeMask {
+			coeff, bexp = coeffMaxBits|u&largeCoeffMask, (u&largeExpMask)>>largeExpOffset
+		}
+		// Negate without a branch: s is 0 for positive values and -1 for
+		// negative ones.
+		s := int32(u) >> signOffset
+		c := Components{(int32(coeff) ^ s) - s, int8(int32(bexp) - expBias)}
+		if u&uint32(inf32) == uint32(inf32) {
+			c, ok = Components{}, false
+		}
+		dst[i] = c
+	}
+	return n, ok
+}
+
+// AddSlice stores in dst the sums of the corresponding values of x and y,
+// rounded as for Add, and returns the number of sums, which is the minimum
+// of the lengths of dst, x and y.
+func AddSlice(dst, x, y []Dec32) int {
+	var c Context
+	return c.AddSlice(dst, x, y)
+}
+
+// AddSlice stores in dst the sums of the corresponding values of x and y
+// rounded with c, as for Add, and returns the number of sums. Sums of values
+// with the same exponent whose coefficients fit in seven digits, such as
+// prices quoted to the same tick, take a fast path that adds the encodings'
+// coefficients directly.
+func (c *Context) AddSlice(dst, x, y []Dec32) int {
+	n := min(len(dst), len(x), len(y))
+	dst, x, y = dst[:n], x[:n], y[:n]
+	fast := c.precision() == 7
+	for i := range dst {
+		u, v := uint32(x[i]), uint32(y[i])
+		// The small form holds only finite values, and values with the
+		// same exponent field have the same exponent.
+		if fast && u&largeMask != largeMask && v&largeMask != largeMask && (u^v)&smallExpMask == 0 {
+			s, t := int32(u)>>signOffset, int32(v)>>signOffset
+			sum := (int32(u&smallCoeffMask) ^ s) - s + (int32(v&smallCoeffMask) ^ t) - t
+			// A zero sum takes its sign from the rounding mode.
+			if sum != 0 && sum >= -maxCoeff && sum <= maxCoeff {
+				// Sums from 2^23 need the large form, so pack them.
+				exp := int((u&smallExpMask)>>smallExpOffset) - expBias
+				dst[i] = pack32(sum < 0, uint32(max(sum, -sum)), exp)
+				continue
+			}
+		}
+		dst[i] = c.Add(x[i], y[i])
+	}
+	return n
+}
+
+// CmpSlice stores in dst the comparisons of the corresponding values of x
+// and y, as Cmp returns them, and returns the number of comparisons, which
+// is the minimum of the lengths of dst, x and y.
+func CmpSlice(dst []int, x, y []Dec32) int {
+	n := min(len(dst), len(x), len(y))
+	dst, x, y = dst[:n], x[:n], y[:n]
+	for i := range dst {
+		u, v := uint32(x[i]), uint32(y[i])
+		if u&largeMask != largeMask && v&largeMask != largeMask && (u^v)&smallExpMask == 0 {
+			s, t := int32(u)>>signOffset, int32(v)>>signOffset
+			a, b := (int32(u&smallCoeffMask)^s)-s, (int32(v&smallCoeffMask)^t)-t
+			dst[i] = cmp.Compare(a, b)
+			continue
+		}
+		dst[i] = x[i].Cmp(y[i])
+	}
+	return n
+}
@@ -0,0 +1,228 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"testing"
+)
+
+// swapOrder is a binary.ByteOrder that is not one of the standard orders,
+// to exercise the generic path.
+type swapOrder struct{ binary.ByteOrder }
+
+func TestSliceEncDec(t *testing.T) {
+	values := []Dec32{mustEncode(t, 9999999, 0), mustEncode(t, -125, -2), inf32}
+	testCases := []struct {
+		order binary.ByteOrder
+		ref   []byte
+	}{
+		{binary.BigEndian, []byte{0x6c, 0xb8, 0x96, 0x7f, 0xb1, 0x80, 0x00, 0x7d, 0x78, 0x00, 0x00, 0x00}},
+		{binary.LittleEndian, []byte{0x7f, 0x96, 0xb8, 0x6c, 0x7d, 0x00, 0x80, 0xb1, 0x00, 0x00, 0x00, 0x78}},
+		{swapOrder{binary.BigEndian}, []byte{0x6c, 0xb8, 0x96, 0x7f, 0xb1, 0x80, 0x00, 0x7d, 0x78, 0x00, 0x00, 0x00}},
+	}
+	for i, testCase := range testCases {
+		buf := make([]byte, 14)
+		if n := EncodeSlice(buf, values, testCase.order); n != 3 {
+			t.Errorf("testCase #%d: expect 3 encoded, got %d", i, n)
+		}
+		if !bytes.Equal(buf[:12], testCase.ref) {
+			t.Errorf("testCase #%d: expect %x, got %x", i, testCase.ref, buf[:12])
+		}
+		dst := make([]Dec32, 4)
+		if n := DecodeSlice(dst, buf, testCase.order); n != 3 {
+			t.Errorf("testCase #%d: expect 3 decoded, got %d", i, n)
+		}
+		for j := range values {
+			if dst[j] != values[j] {
+				t.Errorf("testCase #%d: value %d: expect %x, got %x", i, j, uint32(values[j]), uint32(dst[j]))
+			}
+		}
+	}
+
+	// Short destinations limit the count.
+	if n := DecodeSlice(make([]Dec32, 1), testCases[0].ref, binary.BigEndian); n != 1 {
+		t.Errorf("expect 1 decoded, got %d", n)
+	}
+	if n := EncodeSlice(make([]byte, 7), values, binary.BigEndian); n != 1 {
+		t.Errorf("expect 1 encoded, got %d", n)
+	}
+}
+
+func TestAlignSlice(t *testing.T) {
+	testCases := []struct {
+		xs      []Dec32
+		exp     int
+		ref     []Dec32
+		inexact []int
+	}{
+		{
+			[]Dec32{mustEncode(t, 15, -1), mustEncode(t, 125, -2), mustEncode(t, 3, 0)},
+			-2,
+			[]Dec32{mustEncode(t, 150, -2), mustEncode(t, 125, -2), mustEncode(t, 300, -2)},
+			nil,
+		},
+		{
+			// 1234567 leaves room for a single fraction digit.
+			[]Dec32{mustEncode(t, 1234567, 0), mustEncode(t, 125, -3), mustEncode(t, 5, -2), mustEncode(t, 2, -1)},
+			0,
+			[]Dec32{mustEncode(t, 1234567, 0), mustEncode(t, 0, 0), mustEncode(t, 0, 0), mustEncode(t, 0, 0)},
+			[]int{1, 2, 3},
+		},
+		{
+			[]Dec32{mustEncode(t, 123456, 0), mustEncode(t, 125, -2), mustEncode(t, -135, -2)},
+			-1,
+			[]Dec32{mustEncode(t, 1234560, -1), mustEncode(t, 12, -1), mustEncode(t, -14, -1)},
+			[]int{1, 2},
+		},
+		{
+			[]Dec32{mustEncode(t, 0, -5), mustEncode(t, 7, 3), inf32, nan32},
+			3,
+			[]Dec32{mustEncode(t, 0, 3), mustEncode(t, 7, 3), inf32, nan32},
+			nil,
+		},
+		{
+			[]Dec32{mustEncode(t, 0, 2), mustEncode(t, 0, -2) | signMask},
+			-2,
+			[]Dec32{mustEncode(t, 0, -2), mustEncode(t, 0, -2) | signMask},
+			nil,
+		},
+		{[]Dec32{nan32}, 0, []Dec32{nan32}, nil},
+	}
+	for i, testCase := range testCases {
+		exp, inexact := AlignSlice(testCase.xs)
+		if exp != testCase.exp {
+			t.Errorf("testCase #%d: expect exponent %d, got %d", i, testCase.exp, exp)
+		}
+		for j := range testCase.ref {
+			if testCase.xs[j] != testCase.ref[j] {
+				t.Errorf("testCase #%d: element %d: expect %v, got %v", i, j, testCase.ref[j], testCase.xs[j])
+			}
+		}
+		if fmt.Sprint(inexact) != fmt.Sprint(testCase.inexact) {
+			t.Errorf("testCase #%d: expect inexact %v, got %v", i, testCase.inexact, inexact)
+		}
+	}
+}
+
+func BenchmarkDecodeSlice(b *testing.B) {
+	src := make([]byte, 4096)
+	dst := make([]Dec32, len(src)/4)
+	b.SetBytes(int64(len(src)))
+	for i := 0; i < b.N; i++ {
+		DecodeSlice(dst, src, binary.BigEndian)
+	}
+}
+
+// sliceValues returns values for the slice operations: values sharing an
+// exponent, in both encoding forms, with other values, zeros and specials.
+func sliceValues(t *testing.T) []Dec32 {
+	snan, _ := SignalingNaN(3)
+	values := []Dec32{
+		mustEncode(t, 12345, -2), mustEncode(t, -12340, -2), mustEncode(t, 9999999, -2),
+		mustEncode(t, -9000000, -2), mustEncode(t, 5, 0), mustEncode(t, -5, 0),
+		mustEncode(t, 0, -2), mustEncode(t, 0, -2) | signMask, mustEncode(t, 1, -101),
+		mustEncode(t, -999999, -101), MaxDec32, MaxDec32 | signMask,
+		inf32, inf32 | signMask, nan32 | 7, snan,
+		// Sums of these need the large form: 2^23, 8400000 and 9999998.
+		mustEncode(t, 4194304, -2), mustEncode(t, 4200000, -2), mustEncode(t, 4999999, -2),
+	}
+	if !debugStrict {
+		// The decdebug build panics on non-canonical operands.
+		values = append(values, Dec32(0x6cbfffff))
+	}
+	return values
+}
+
+func TestDecodeComponents(t *testing.T) {
+	src := sliceValues(t)
+	dst := make([]Components, len(src)+1)
+	n, ok := DecodeComponents(dst, src)
+	if n != len(src) || ok {
+		t.Errorf("expect %d, false, got %d, %v", len(src), n, ok)
+	}
+	for i, d := range src {
+		coeff, exp, _ := d.Decode()
+		if ref := (Components{coeff, exp}); dst[i] != ref {
+			t.Errorf("testCase #%d: %v: expect %v, got %v", i, d, ref, dst[i])
+		}
+	}
+	if n, ok := DecodeComponents(dst, src[:6]); n != 6 || !ok {
+		t.Errorf("expect 6, true, got %d, %v", n, ok)
+	}
+}
+
+func TestAddSlice(t *testing.T) {
+	values := sliceValues(t)
+	var x, y []Dec32
+	for _, u := range values {
+		for _, v := range values {
+			x, y = append(x, u), append(y, v)
+		}
+	}
+	dst := make([]Dec32, len(x))
+	for _, mode := range []RoundingMode{ToNearestEven, ToNegativeInf, ToZero} {
+		for _, prec := range []int{0, 7, 3} {
+			c, ref := Context{Precision: prec, Mode: mode}, Context{Precision: prec, Mode: mode}
+			if n := c.AddSlice(dst, x, y); n != len(x) {
+				t.Errorf("expect %d sums, got %d", len(x), n)
+			}
+			for i := range x {
+				if r := ref.Add(x[i], y[i]); dst[i] != r {
+					t.Errorf("mode %v, precision %d: %v+%v: expect %v, got %v", mode, prec, x[i], y[i], r, dst[i])
+				}
+			}
+			if c.Flags != ref.Flags {
+				t.Errorf("mode %v, precision %d: expect flags %v, got %v", mode, prec, ref.Flags, c.Flags)
+			}
+		}
+	}
+	for _, s := range [][3]Dec32{
+		{mustEncode(t, 4194304, 0), mustEncode(t, 4194304, 0), mustEncode(t, 8388608, 0)},
+		{mustEncode(t, 4200000, -2), mustEncode(t, 4200000, -2), mustEncode(t, 8400000, -2)},
+		{mustEncode(t, -4999999, 3), mustEncode(t, -4999999, 3), mustEncode(t, -9999998, 3)},
+		{mustEncode(t, 4194303, 0), mustEncode(t, 4194304, 0), mustEncode(t, 8388607, 0)},
+	} {
+		if AddSlice(dst, s[:1], s[1:2]); dst[0] != s[2] {
+			t.Errorf("%v+%v: expect %v, got %v", s[0], s[1], s[2], dst[0])
+		}
+	}
+	if n := AddSlice(dst, x[:3], y); n != 3 || dst[0] != x[0].Add(y[0]) {
+		t.Errorf("expect 3 sums, got %d", n)
+	}
+}
+
+func TestCmpSlice(t *testing.T) {
+	values := sliceValues(t)
+	var x, y []Dec32
+	for _, u := range values {
+		for _, v := range values {
+			x, y = append(x, u), append(y, v)
+		}
+	}
+	dst := make([]int, len(x)+2)
+	if n := CmpSlice(dst, x, y); n != len(x) {
+		t.Errorf("expect %d comparisons, got %d", len(x), n)
+	}
+	for i := range x {
+		if r := x[i].Cmp(y[i]); dst[i] != r {
+			t.Errorf("Cmp(%v, %v): expect %d, got %d", x[i], y[i], r, dst[i])
+		}
+	}
+}
+
+func BenchmarkAddSlice(b *testing.B) {
+	x, y := make([]Dec32, 1024), make([]Dec32, 1024)
+	for i := range x {
+		x[i] = MustEncodeDec32(int32(100000+i), -4)
+		y[i] = MustEncodeDec32(int32(25-i), -4)
+	}
+	dst := make([]Dec32, len(x))
+	for i := 0; i < b.N; i++ {
+		AddSlice(dst, x, y)
+	}
+}
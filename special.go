@@ -0,0 +1,37 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+// Limits of the decimal32 value set.
+const (
+	// MaxDec32 is the largest finite decimal32 value, 9.999999E+96.
+	MaxDec32 Dec32 = 0x77f8967f
+	// MinPositiveDec32 is the smallest positive normal decimal32 value,
+	// 1E-95, encoded with a seven-digit coefficient as 1.000000E-95.
+	MinPositiveDec32 Dec32 = 0x000f4240
+	// SmallestSubnormalDec32 is the smallest positive decimal32 value,
+	// 1E-101.
+	SmallestSubnormalDec32 Dec32 = 0x00000001
+)
+
+// Inf returns positive infinity if sign >= 0, negative infinity if sign < 0.
+func Inf(sign int) Dec32 {
+	return inf32 | signOf(sign < 0)
+}
+
+// NaN returns the default quiet NaN, positive and without payload.
+func NaN() Dec32 {
+	return nan32
+}
+
+// Zero returns a zero with the given exponent, positive if sign >= 0 and
+// negative if sign < 0, such as 0.00 for exponent -2. It panics if exp is
+// outside the decimal32 exponent range, -101 to 90.
+func Zero(sign int, exp int8) Dec32 {
+	if exp < minExp || exp > maxExp {
+		panic("decimal: exponent out of range in Zero")
+	}
+	return pack32(sign < 0, 0, int(exp))
+}
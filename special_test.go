@@ -0,0 +1,68 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import "testing"
+
+func TestLimits(t *testing.T) {
+	testCases := []struct {
+		d   Dec32
+		ref Dec32
+		s   string
+	}{
+		{MaxDec32, pack32(false, maxCoeff, maxExp), "9.999999E+96"},
+		{MinPositiveDec32, pack32(false, 1000000, minExp), "1.000000E-95"},
+		{SmallestSubnormalDec32, pack32(false, 1, minExp), "1E-101"},
+	}
+	for i, testCase := range testCases {
+		if testCase.d != testCase.ref || testCase.d.String() != testCase.s {
+			t.Errorf("testCase #%d: expect %x %s, got %x %v", i, uint32(testCase.ref), testCase.s, uint32(testCase.d), testCase.d)
+		}
+	}
+	if r := MaxDec32.Add(mustEncode(t, 1, 90)); !r.IsInf() {
+		t.Errorf("expect MaxDec32 + 1E+90 to overflow, got %v", r)
+	}
+	if r := SmallestSubnormalDec32.Mul(mustEncode(t, 5, -1)); !r.Zero() {
+		t.Errorf("expect half the smallest subnormal to round to zero, got %v", r)
+	}
+}
+
+func TestSpecialConstructors(t *testing.T) {
+	if d := Inf(1); !d.IsInf() || d.Sign() < 0 {
+		t.Errorf("unexpected Inf(1) %v", d)
+	}
+	if d := Inf(0); d != inf32 {
+		t.Errorf("unexpected Inf(0) %v", d)
+	}
+	if d := Inf(-1); d != inf32|signMask {
+		t.Errorf("unexpected Inf(-1) %v", d)
+	}
+	if d := NaN(); !d.IsNaN() || d.IsSignalingNaN() || d.NaNPayload() != 0 {
+		t.Errorf("unexpected NaN() %x", uint32(d))
+	}
+	testCases := []struct {
+		sign int
+		exp  int8
+		s    string
+	}{
+		{1, 0, "0"},
+		{-1, 0, "-0"},
+		{0, -2, "0.00"},
+		{-5, 3, "-0E+3"},
+		{1, minExp, "0E-101"},
+		{1, maxExp, "0E+90"},
+	}
+	for i, testCase := range testCases {
+		if d := Zero(testCase.sign, testCase.exp); !d.Zero() || d.String() != testCase.s {
+			t.Errorf("testCase #%d: expect %s, got %v", i, testCase.s, d)
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic")
+		}
+	}()
+	Zero(1, maxExp+1)
+}
@@ -0,0 +1,59 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+)
+
+var (
+	_ driver.Valuer = Dec32(0)
+	_ sql.Scanner   = (*Dec32)(nil)
+)
+
+var (
+	errSQLNull  = errors.New("decimal: cannot scan NULL into Dec32")
+	errSQLRange = errors.New("decimal: SQL value out of decimal32 range")
+)
+
+// Value implements the driver.Valuer interface, returning d in plain
+// notation, as by PlainString, which NUMERIC and DECIMAL columns accept
+// without loss. Infinities and NaNs are returned as by String, "Infinity",
+// "-Infinity" and "NaN", which only some databases can store.
+func (d Dec32) Value() (driver.Value, error) {
+	return d.PlainString(), nil
+}
+
+// Scan implements the sql.Scanner interface. It accepts the forms in which
+// drivers return NUMERIC and DECIMAL columns: a numeric string as []byte or
+// string, parsed as ParseDec32 does, an int64, or a float64, converted as
+// FromFloat64 does; each is rounded to the nearest decimal32, ties to even.
+// A value too large in magnitude to represent is an error, as is NULL, which
+// a *Dec32 or sql.Null[Dec32] can receive instead. On error d is unchanged.
+func (d *Dec32) Scan(src any) error {
+	var v Dec32
+	switch src := src.(type) {
+	case []byte:
+		return d.UnmarshalText(src)
+	case string:
+		return d.UnmarshalText([]byte(src))
+	case int64:
+		v, _ = FromScaledInt64(src, 0)
+	case float64:
+		if v, _ = FromFloat64(src); v.IsInf() && !math.IsInf(src, 0) {
+			return errSQLRange
+		}
+	case nil:
+		return errSQLNull
+	default:
+		return fmt.Errorf("decimal: cannot scan %T into Dec32", src)
+	}
+	*d = v
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"database/sql"
+	"math"
+	"testing"
+)
+
+func TestValue(t *testing.T) {
+	testCases := []struct {
+		d   Dec32
+		ref string
+	}{
+		{mustEncode(t, 12345, -2), "123.45"},
+		{mustEncode(t, -123, 3), "-123000"},
+		{mustEncode(t, 1, -8), "0.00000001"},
+		{mustEncode(t, 0, -2), "0.00"},
+		{inf32 | signMask, "-Infinity"},
+		{nan32, "NaN"},
+	}
+	for i, testCase := range testCases {
+		v, err := testCase.d.Value()
+		if s, ok := v.(string); err != nil || !ok || s != testCase.ref {
+			t.Errorf("testCase #%d: expect %q, got %#v %v", i, testCase.ref, v, err)
+		}
+	}
+}
+
+func TestScan(t *testing.T) {
+	testCases := []struct {
+		src any
+		ref Dec32
+	}{
+		{[]byte("123.45"), mustEncode(t, 12345, -2)},
+		{"-0.00", mustEncode(t, 0, -2) | signMask},
+		{"1.23456789", mustEncode(t, 1234568, -6)},
+		{"NaN", nan32},
+		{int64(-42), mustEncode(t, -42, 0)},
+		{int64(math.MaxInt64), mustEncode(t, 9223372, 12)},
+		{float64(0.1), mustEncode(t, 1, -1)},
+		{float64(-2.5e-3), mustEncode(t, -25, -4)},
+		{math.Inf(1), inf32},
+	}
+	for i, testCase := range testCases {
+		var d Dec32
+		if err := d.Scan(testCase.src); err != nil || d != testCase.ref {
+			t.Errorf("testCase #%d: expect %v, got %v %v", i, testCase.ref, d, err)
+		}
+	}
+}
+
+func TestScanError(t *testing.T) {
+	for i, src := range []any{nil, "1E+97", []byte("12x"), float64(1e100), true} {
+		d := mustEncode(t, 7, 0)
+		if err := d.Scan(src); err == nil || d != mustEncode(t, 7, 0) {
+			t.Errorf("testCase #%d: expect error and unchanged 7, got %v %v", i, d, err)
+		}
+	}
+}
+
+func TestScanNull(t *testing.T) {
+	var n sql.Null[Dec32]
+	if err := n.Scan(nil); err != nil || n.Valid {
+		t.Errorf("expect invalid, got %v %v", n, err)
+	}
+	if err := n.Scan("1.5"); err != nil || !n.Valid || n.V != mustEncode(t, 15, -1) {
+		t.Errorf("expect 1.5, got %v %v", n, err)
+	}
+}
@@ -0,0 +1,321 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import "math/big"
+
+// Median returns the median of the values in xs. For an even number of values
+// the median is the mean of the two middle values, computed exactly and then
+// rounded to the nearest decimal32, ties to even. Median returns NaN if xs is
+// empty or contains a NaN. xs is not modified.
+func Median(xs []Dec32) Dec32 {
+	return Percentile(xs, pack32(false, 50, 0))
+}
+
+// Percentile returns the p-th percentile of the values in xs, for p between 0
+// and 100. The result is interpolated linearly between the two closest ranks,
+// where the value of rank h = (len(xs)-1) * p/100 is
+//
+//	xs[floor(h)] + (h - floor(h)) * (xs[floor(h)+1] - xs[floor(h)])
+//
+// in sorted order, as in Excel's PERCENTILE.INC. The interpolation is
+// computed exactly and rounded once to the nearest decimal32, ties to even;
+// when h is an integer, the element at that rank is returned unchanged.
+//
+// Percentile returns NaN if xs is empty, contains a NaN, or p is out of
+// range. Interpolating towards an infinity yields that infinity, and
+// interpolating between -Inf and +Inf yields NaN. xs is not modified.
+func Percentile(xs []Dec32, p Dec32) Dec32 {
+	if len(xs) == 0 || p.IsNaN() || p.IsInf() ||
+		(p.cmpSign() < 0) || cmp32(p, pack32(false, 100, 0)) > 0 {
+		return nan32
+	}
+	for _, x := range xs {
+		if x.IsNaN() {
+			return nan32
+		}
+	}
+
+	// h = (n-1) * p / 100, split into integer rank k and fraction f.
+	h := newBigDec32(p)
+	h.neg = false
+	h.coeff.Mul(&h.coeff, big.NewInt(int64(len(xs)-1)))
+	h.exp -= 2
+	k, f := h.modf()
+
+	sorted := make([]Dec32, len(xs))
+	copy(sorted, xs)
+	selectNth(sorted, k)
+	a := sorted[k]
+	if f.isZero() {
+		return a
+	}
+	b := sorted[k+1]
+	for _, x := range sorted[k+2:] {
+		if cmp32(x, b) < 0 {
+			b = x
+		}
+	}
+
+	switch {
+	case a.IsInf() && b.IsInf() && a.Sign() != b.Sign():
+		return nan32
+	case a.IsInf() && a.Sign() < 0:
+		return a
+	case b.IsInf():
+		return b
+	}
+	x, y := newBigDec32(a), newBigDec32(b)
+	r := new(bigDec).sub(y, x)
+	r.mul(r, f)
+	r.add(r, x)
+	ideal := x.exp
+	if y.exp < ideal {
+		ideal = y.exp
+	}
+	r.reduce(ideal)
+	d, _ := r.dec32()
+	return d
+}
+
+// modf splits the non-negative x into its integer part, which must fit in an
+// int, and its exact fractional part.
+func (x *bigDec) modf() (int, *bigDec) {
+	f := &bigDec{exp: x.exp}
+	if x.exp >= 0 {
+		var i big.Int
+		i.Mul(&x.coeff, bigPow10(x.exp))
+		return int(i.Int64()), f
+	}
+	var i big.Int
+	i.QuoRem(&x.coeff, bigPow10(-x.exp), &f.coeff)
+	return int(i.Int64()), f
+}
+
+// selectNth partially orders xs, none of which may be NaN, so that xs[k] holds
+// the value it would have if xs were sorted, with no greater values before it
+// and no lesser values after it.
+func selectNth(xs []Dec32, k int) {
+	lo, hi := 0, len(xs)-1
+	for lo < hi {
+		// Median-of-three pivot guards against sorted inputs.
+		mid := lo + (hi-lo)/2
+		if cmp32(xs[mid], xs[lo]) < 0 {
+			xs[mid], xs[lo] = xs[lo], xs[mid]
+		}
+		if cmp32(xs[hi], xs[lo]) < 0 {
+			xs[hi], xs[lo] = xs[lo], xs[hi]
+		}
+		if cmp32(xs[hi], xs[mid]) < 0 {
+			xs[hi], xs[mid] = xs[mid], xs[hi]
+		}
+		pivot := xs[mid]
+		i, j := lo, hi
+		for i <= j {
+			for cmp32(xs[i], pivot) < 0 {
+				i++
+			}
+			for cmp32(xs[j], pivot) > 0 {
+				j--
+			}
+			if i <= j {
+				xs[i], xs[j] = xs[j], xs[i]
+				i++
+				j--
+			}
+		}
+		switch {
+		case k <= j:
+			hi = j
+		case k >= i:
+			lo = i
+		default:
+			return
+		}
+	}
+}
+
+// Stats accumulates summary statistics of a stream of values, one at a time,
+// without holding them. Rather than updating a running mean and sum of
+// squared deviations as Welford's method does in floating point, it keeps the
+// sum and the sum of squares exactly, which the decimal32 exponent range
+// bounds to a few hundred digits however many values are added. Every result
+// is therefore computed exactly and rounded once to the nearest decimal32,
+// ties to even, with no cancellation error in the variance. The zero value
+// holds no values.
+type Stats struct {
+	n, finite      int // values added, and those finite
+	sum, sumSq     bigDec
+	min, max       Dec32
+	ranked         bool // whether min and max are set
+	posInf, negInf bool
+	nan            bool
+}
+
+// Add adds d to the values of s.
+func (s *Stats) Add(d Dec32) {
+	s.n++
+	switch {
+	case d.IsNaN():
+		s.nan = true
+		return
+	case !s.ranked:
+		s.min, s.max, s.ranked = d, d, true
+	case cmp32(d, s.min) < 0:
+		s.min = d
+	case cmp32(d, s.max) > 0:
+		s.max = d
+	}
+	if d.IsInf() {
+		if d.Sign() < 0 {
+			s.negInf = true
+		} else {
+			s.posInf = true
+		}
+		return
+	}
+	x := newBigDec32(d)
+	if s.finite++; s.finite == 1 {
+		s.sum.setSigned(x.signed(), x.exp, x.neg)
+		s.sumSq.mul(x, x)
+		return
+	}
+	s.sum.add(&s.sum, x)
+	s.sumSq.add(&s.sumSq, x.mul(x, x))
+}
+
+// Count returns the number of values added to s, including NaNs.
+func (s *Stats) Count() int {
+	return s.n
+}
+
+// Sum returns the sum of the values of s, with the smallest of their
+// exponents where exact, or zero if s holds no values. It is NaN if a value
+// is NaN or infinities of both signs were added, and otherwise an infinity
+// if one was added.
+func (s *Stats) Sum() Dec32 {
+	if special, ok := s.special(); ok {
+		return special
+	}
+	if s.n == 0 {
+		return pack32(false, 0, 0)
+	}
+	d, _ := s.sum.dec32()
+	return d
+}
+
+// Mean returns the arithmetic mean of the values of s, with the exponent of
+// their sum where exact, so that the mean of 1.20 and 1.3 is 1.25 and that of
+// 1.0 and 3.0 is 2.0. It is NaN if s holds no values, and otherwise special
+// as for Sum.
+func (s *Stats) Mean() Dec32 {
+	if special, ok := s.special(); ok {
+		return special
+	}
+	if s.n == 0 {
+		return nan32
+	}
+	d, _ := new(bigDec).quo(&s.sum, newBigDecInt64(int64(s.n)), 7).dec32()
+	return d
+}
+
+// Variance returns the sample variance of the values of s, the sum of their
+// squared deviations from the mean divided by Count()-1. It is NaN if s holds
+// fewer than two values, or a NaN or an infinity.
+func (s *Stats) Variance() Dec32 {
+	return s.variance(s.n - 1)
+}
+
+// PopulationVariance returns the population variance of the values of s, the
+// sum of their squared deviations from the mean divided by Count(). It is NaN
+// if s holds no values, or a NaN or an infinity.
+func (s *Stats) PopulationVariance() Dec32 {
+	return s.variance(s.n)
+}
+
+// variance returns the sum of squared deviations of the values of s divided
+// by the positive count div.
+func (s *Stats) variance(div int) Dec32 {
+	if s.nan || s.posInf || s.negInf || div <= 0 {
+		return nan32
+	}
+	// The squared deviations sum to (n*sumSq - sum^2) / n.
+	n := newBigDecInt64(int64(s.n))
+	m2 := new(bigDec).mul(n, &s.sumSq)
+	m2.sub(m2, new(bigDec).mul(&s.sum, &s.sum))
+	m2.neg = false
+	n.coeff.Mul(&n.coeff, big.NewInt(int64(div)))
+	d, _ := new(bigDec).quo(m2, n, 7).dec32()
+	return d
+}
+
+// Min returns the least of the values of s, compared by value, or NaN if s
+// holds no values or a NaN. Of values that compare equal, the first added is
+// returned.
+func (s *Stats) Min() Dec32 {
+	if s.nan || s.n == 0 {
+		return nan32
+	}
+	return s.min
+}
+
+// Max returns the greatest of the values of s, as for Min.
+func (s *Stats) Max() Dec32 {
+	if s.nan || s.n == 0 {
+		return nan32
+	}
+	return s.max
+}
+
+// special returns the sum of s if it is NaN or infinite.
+func (s *Stats) special() (Dec32, bool) {
+	switch {
+	case s.nan || s.posInf && s.negInf:
+		return nan32, true
+	case s.posInf:
+		return inf32, true
+	case s.negInf:
+		return inf32 | signMask, true
+	}
+	return 0, false
+}
+
+// WeightedMean returns the mean of values weighted by the corresponding
+// weights, sum(values[i]*weights[i]) / sum(weights), as a volume-weighted
+// average price is of prices weighted by traded quantities. The numerator
+// and denominator are summed exactly and the quotient rounded once to the
+// nearest decimal32, ties to even, so that no intermediate rounding biases
+// the result; an exact mean has the exponent closest to that of the
+// numerator less that of the denominator. Weights may be negative.
+//
+// WeightedMean returns NaN if values and weights differ in length or are
+// empty, if the weights sum to zero, or if any value or weight is NaN or
+// infinite.
+func WeightedMean(values, weights []Dec32) Dec32 {
+	if len(values) != len(weights) || len(values) == 0 {
+		return nan32
+	}
+	var num, den, p bigDec
+	for i, v := range values {
+		w := weights[i]
+		if v.IsNaN() || v.IsInf() || w.IsNaN() || w.IsInf() {
+			return nan32
+		}
+		x, y := newBigDec32(v), newBigDec32(w)
+		p.mul(x, y)
+		if i == 0 {
+			num.setSigned(p.signed(), p.exp, p.neg)
+			den.setSigned(y.signed(), y.exp, y.neg)
+			continue
+		}
+		num.add(&num, &p)
+		den.add(&den, y)
+	}
+	if den.isZero() {
+		return nan32
+	}
+	d, _ := new(bigDec).quo(&num, &den, 7).dec32()
+	return d
+}
@@ -0,0 +1,213 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestMedian(t *testing.T) {
+	inf, negInf := inf32, inf32|signMask
+	testCases := []struct {
+		xs  []Dec32
+		ref Dec32
+	}{
+		{[]Dec32{mustEncode(t, 3, 0), mustEncode(t, 1, 0), mustEncode(t, 2, 0)}, mustEncode(t, 2, 0)},
+		{[]Dec32{mustEncode(t, 4, 0), mustEncode(t, 1, 0), mustEncode(t, 3, 0), mustEncode(t, 2, 0)}, mustEncode(t, 25, -1)},
+		// The exact mean keeps the smaller exponent of the middle values.
+		{[]Dec32{mustEncode(t, 100, -2), mustEncode(t, 3, 0)}, mustEncode(t, 200, -2)},
+		// Rounds half to even.
+		{[]Dec32{mustEncode(t, 1234567, 0), mustEncode(t, 1234568, 0)}, mustEncode(t, 1234568, 0)},
+		{[]Dec32{mustEncode(t, 1234565, 0), mustEncode(t, 1234566, 0)}, mustEncode(t, 1234566, 0)},
+		{[]Dec32{mustEncode(t, -5, 0), mustEncode(t, 1, 0)}, mustEncode(t, -2, 0)},
+		{[]Dec32{mustEncode(t, 7, 0)}, mustEncode(t, 7, 0)},
+		{[]Dec32{negInf, mustEncode(t, 1, 0)}, negInf},
+		{[]Dec32{inf, mustEncode(t, 1, 0)}, inf},
+		{[]Dec32{inf, negInf}, nan32},
+		{[]Dec32{inf, inf}, inf},
+		{[]Dec32{mustEncode(t, 1, 0), nan32, mustEncode(t, 2, 0)}, nan32},
+		{nil, nan32},
+	}
+	for i, testCase := range testCases {
+		orig := append([]Dec32(nil), testCase.xs...)
+		if m := Median(testCase.xs); m != testCase.ref {
+			t.Errorf("testCase #%d: expect median %x, got %x", i, uint32(testCase.ref), uint32(m))
+		}
+		for j := range orig {
+			if orig[j] != testCase.xs[j] {
+				t.Errorf("testCase #%d: input modified", i)
+			}
+		}
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	var xs []Dec32
+	for i := 10; i >= 1; i-- {
+		xs = append(xs, mustEncode(t, int32(i), 0))
+	}
+	testCases := []struct {
+		p   Dec32
+		ref Dec32
+	}{
+		{mustEncode(t, 0, 0), mustEncode(t, 1, 0)},
+		{mustEncode(t, 100, 0), mustEncode(t, 10, 0)},
+		{mustEncode(t, 90, 0), mustEncode(t, 91, -1)},
+		{mustEncode(t, 25, 0), mustEncode(t, 325, -2)},
+		{mustEncode(t, 999, -1), mustEncode(t, 9991, -3)},
+		{mustEncode(t, -1, 0), nan32},
+		{mustEncode(t, 1001, -1), nan32},
+		{nan32, nan32},
+	}
+	for i, testCase := range testCases {
+		if r := Percentile(xs, testCase.p); r != testCase.ref {
+			t.Errorf("testCase #%d: expect percentile %x, got %x", i, uint32(testCase.ref), uint32(r))
+		}
+	}
+}
+
+func TestSelectNth(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for n := 1; n < 50; n++ {
+		xs := make([]Dec32, n)
+		for i := range xs {
+			xs[i] = mustEncode(t, int32(r.Intn(20)-10), int8(r.Intn(3)-1))
+		}
+		sorted := append([]Dec32(nil), xs...)
+		sort.Slice(sorted, func(i, j int) bool { return cmp32(sorted[i], sorted[j]) < 0 })
+		for k := 0; k < n; k++ {
+			work := append([]Dec32(nil), xs...)
+			selectNth(work, k)
+			if cmp32(work[k], sorted[k]) != 0 {
+				t.Fatalf("n=%d k=%d: expect %x, got %x", n, k, uint32(sorted[k]), uint32(work[k]))
+			}
+		}
+	}
+}
+
+func TestStats(t *testing.T) {
+	inf, negInf := inf32, inf32|signMask
+	testCases := []struct {
+		xs                        []Dec32
+		sum, mean, variance, pvar Dec32
+		min, max                  Dec32
+	}{
+		{
+			[]Dec32{mustEncode(t, 120, -2), mustEncode(t, 13, -1)},
+			mustEncode(t, 250, -2), mustEncode(t, 125, -2), mustEncode(t, 50, -4), mustEncode(t, 25, -4),
+			mustEncode(t, 120, -2), mustEncode(t, 13, -1),
+		},
+		{
+			[]Dec32{mustEncode(t, 2, 0), mustEncode(t, 4, 0), mustEncode(t, 4, 0), mustEncode(t, 4, 0),
+				mustEncode(t, 5, 0), mustEncode(t, 5, 0), mustEncode(t, 7, 0), mustEncode(t, 9, 0)},
+			mustEncode(t, 40, 0), mustEncode(t, 5, 0), mustEncode(t, 4571429, -6), mustEncode(t, 4, 0),
+			mustEncode(t, 2, 0), mustEncode(t, 9, 0),
+		},
+		// The exact sums leave no cancellation in the variance of values
+		// with a large common offset.
+		{
+			[]Dec32{mustEncode(t, 1000001, 0), mustEncode(t, 1000002, 0), mustEncode(t, 1000003, 0)},
+			mustEncode(t, 3000006, 0), mustEncode(t, 1000002, 0), mustEncode(t, 1, 0), mustEncode(t, 6666667, -7),
+			mustEncode(t, 1000001, 0), mustEncode(t, 1000003, 0),
+		},
+		{
+			[]Dec32{mustEncode(t, -3, 0), mustEncode(t, 1, 0)},
+			mustEncode(t, -2, 0), mustEncode(t, -1, 0), mustEncode(t, 8, 0), mustEncode(t, 4, 0),
+			mustEncode(t, -3, 0), mustEncode(t, 1, 0),
+		},
+		{
+			[]Dec32{mustEncode(t, 1, 5)},
+			mustEncode(t, 1, 5), mustEncode(t, 1, 5), nan32, mustEncode(t, 0, 10),
+			mustEncode(t, 1, 5), mustEncode(t, 1, 5),
+		},
+		{
+			[]Dec32{mustEncode(t, 9999999, 90), mustEncode(t, 9999999, 90)},
+			inf, mustEncode(t, 9999999, 90), mustEncode(t, 0, 90), mustEncode(t, 0, 90),
+			mustEncode(t, 9999999, 90), mustEncode(t, 9999999, 90),
+		},
+		{
+			[]Dec32{mustEncode(t, 1, 0), inf},
+			inf, inf, nan32, nan32, mustEncode(t, 1, 0), inf,
+		},
+		{
+			[]Dec32{negInf, mustEncode(t, 1, 0), inf},
+			nan32, nan32, nan32, nan32, negInf, inf,
+		},
+		{
+			[]Dec32{nan32, mustEncode(t, 1, 0)},
+			nan32, nan32, nan32, nan32, nan32, nan32,
+		},
+		{nil, mustEncode(t, 0, 0), nan32, nan32, nan32, nan32, nan32},
+	}
+	for i, testCase := range testCases {
+		var s Stats
+		for _, x := range testCase.xs {
+			s.Add(x)
+		}
+		if s.Count() != len(testCase.xs) {
+			t.Errorf("testCase #%d: expect count %d, got %d", i, len(testCase.xs), s.Count())
+		}
+		got := []Dec32{s.Sum(), s.Mean(), s.Variance(), s.PopulationVariance(), s.Min(), s.Max()}
+		ref := []Dec32{testCase.sum, testCase.mean, testCase.variance, testCase.pvar, testCase.min, testCase.max}
+		for j := range got {
+			if got[j] != ref[j] {
+				t.Errorf("testCase #%d: statistic %d: expect %v, got %v", i, j, ref[j], got[j])
+			}
+		}
+	}
+}
+
+func TestWeightedMean(t *testing.T) {
+	testCases := []struct {
+		values, weights []Dec32
+		ref             Dec32
+	}{
+		// A volume-weighted average price.
+		{
+			[]Dec32{mustEncode(t, 1050, -2), mustEncode(t, 1060, -2)},
+			[]Dec32{mustEncode(t, 100, 0), mustEncode(t, 300, 0)},
+			mustEncode(t, 10575, -3),
+		},
+		{
+			[]Dec32{mustEncode(t, 1, 0), mustEncode(t, 2, 0)},
+			[]Dec32{mustEncode(t, 1, 0), mustEncode(t, 2, 0)},
+			mustEncode(t, 1666667, -6),
+		},
+		// Blended rates keep the exponent where the mean is exact.
+		{
+			[]Dec32{mustEncode(t, 500, -4), mustEncode(t, 300, -4)},
+			[]Dec32{mustEncode(t, 1, 0), mustEncode(t, 1, 0)},
+			mustEncode(t, 400, -4),
+		},
+		// No intermediate rounding: each product has more than seven
+		// digits.
+		{
+			[]Dec32{mustEncode(t, 1234567, -2), mustEncode(t, 7654321, -2)},
+			[]Dec32{mustEncode(t, 3333333, 0), mustEncode(t, 1, 0)},
+			mustEncode(t, 1234569, -2),
+		},
+		{
+			[]Dec32{mustEncode(t, -4, 0), mustEncode(t, 2, 0)},
+			[]Dec32{mustEncode(t, -1, 0), mustEncode(t, 3, 0)},
+			mustEncode(t, 5, 0),
+		},
+		{
+			[]Dec32{mustEncode(t, 1, 0), mustEncode(t, 2, 0)},
+			[]Dec32{mustEncode(t, 1, 0), mustEncode(t, -1, 0)},
+			nan32,
+		},
+		{[]Dec32{mustEncode(t, 1, 0)}, []Dec32{inf32}, nan32},
+		{[]Dec32{nan32}, []Dec32{mustEncode(t, 1, 0)}, nan32},
+		{[]Dec32{mustEncode(t, 1, 0)}, nil, nan32},
+		{nil, nil, nan32},
+	}
+	for i, testCase := range testCases {
+		if m := WeightedMean(testCase.values, testCase.weights); m != testCase.ref {
+			t.Errorf("testCase #%d: expect %v, got %v", i, testCase.ref, m)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+// A Prefix is a metric unit prefix, represented by its power of ten.
+type Prefix int
+
+// Metric prefixes.
+const (
+	Pico  Prefix = -12
+	Nano  Prefix = -9
+	Micro Prefix = -6
+	Milli Prefix = -3
+	Centi Prefix = -2
+	Deci  Prefix = -1
+	Unit  Prefix = 0
+	Kilo  Prefix = 3
+	Mega  Prefix = 6
+	Giga  Prefix = 9
+	Tera  Prefix = 12
+)
+
+// ConvertPrefix converts a quantity d, measured in units with the prefix
+// from, to units with the prefix to, by shifting its exponent rather than by
+// multiplying. For example, 1500 in Milli units is 1.5 in Unit units and
+// 0.0015 in Kilo units. It returns false if the result is not exact: when it
+// overflows to infinity, or is so small that digits are rounded away.
+func (d Dec32) ConvertPrefix(from, to Prefix) (Dec32, bool) {
+	return d.scaleb(int(from) - int(to))
+}
+
+// Milli returns d thousandths as a number of whole units, shifting the
+// exponent down by three, and whether the result is exact.
+func (d Dec32) Milli() (Dec32, bool) { return d.ConvertPrefix(Milli, Unit) }
+
+// Micro returns d millionths as a number of whole units, shifting the
+// exponent down by six, and whether the result is exact.
+func (d Dec32) Micro() (Dec32, bool) { return d.ConvertPrefix(Micro, Unit) }
+
+// Kilo returns d thousands as a number of whole units, shifting the exponent
+// up by three, and whether the result is exact.
+func (d Dec32) Kilo() (Dec32, bool) { return d.ConvertPrefix(Kilo, Unit) }
+
+// Mega returns d millions as a number of whole units, shifting the exponent
+// up by six, and whether the result is exact.
+func (d Dec32) Mega() (Dec32, bool) { return d.ConvertPrefix(Mega, Unit) }
+
+// scaleb returns d * 10^n and whether the result is exact. The coefficient is
+// kept where the exponent allows. Otherwise trailing zeros are added or
+// removed to bring the exponent into range, rounding half to even if nonzero
+// digits must go, and overflowing to infinity if too many zeros are needed.
+func (d Dec32) scaleb(n int) (Dec32, bool) {
+	if d.IsInf() || d.IsNaN() {
+		return d, true
+	}
+	x := newBigDec32(d)
+	x.exp += n
+	return x.dec32()
+}
@@ -0,0 +1,55 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"testing"
+)
+
+func TestConvertPrefix(t *testing.T) {
+	testCases := []struct {
+		d        string
+		from, to Prefix
+		ref      string
+		exact    bool
+	}{
+		{"1500", Milli, Unit, "1.500", true},
+		{"1500", Milli, Kilo, "0.001500", true},
+		{"2.5", Mega, Kilo, "2.5E+3", true},
+		{"-7", Unit, Micro, "-7E+6", true},
+		{"12", Centi, Milli, "1.2E+2", true},
+		// Clamped by adding trailing zeros to the coefficient.
+		{"1E+90", Kilo, Unit, "1.000E+93", true},
+		{"1E+96", Kilo, Unit, "Infinity", false},
+		// Subnormal results lose digits.
+		{"1.234567E-97", Unit, Kilo, "1.2E-100", false},
+		{"1.200000E-97", Unit, Kilo, "1.2E-100", true},
+		{"Infinity", Unit, Kilo, "Infinity", true},
+		{"NaN", Unit, Kilo, "NaN", true},
+	}
+	for i, testCase := range testCases {
+		d, exact := MustParseDec32(testCase.d).ConvertPrefix(testCase.from, testCase.to)
+		if d.String() != testCase.ref || exact != testCase.exact {
+			t.Errorf("testCase #%d: expect %s %v, got %v %v", i, testCase.ref, testCase.exact, d, exact)
+		}
+	}
+}
+
+func TestPrefixHelpers(t *testing.T) {
+	d := MustParseDec32("1.25")
+	for _, testCase := range []struct {
+		f   func() (Dec32, bool)
+		ref string
+	}{
+		{d.Milli, "0.00125"},
+		{d.Micro, "0.00000125"},
+		{d.Kilo, "1.25E+3"},
+		{d.Mega, "1.25E+6"},
+	} {
+		if r, ok := testCase.f(); !ok || r.String() != testCase.ref {
+			t.Errorf("expect %s, got %v %v", testCase.ref, r, ok)
+		}
+	}
+}
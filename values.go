@@ -0,0 +1,62 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import "iter"
+
+// Values returns an iterator over every numeric value representable in
+// decimal32 from lo to hi inclusive, in increasing order, each one unit in
+// the last place from the one before. Every value is yielded once, as the
+// member of its cohort with a seven-digit coefficient, or with the minimum
+// exponent for values too small for seven digits: from 9.999998 the values
+// are 9.999999, 10.00000, 10.00001 and so on. Zero is yielded once, as the
+// positive zero with the minimum exponent. An infinite bound leaves the range
+// unbounded at that end, and a NaN bound makes it empty.
+func Values(lo, hi Dec32) iter.Seq[Dec32] {
+	return func(yield func(Dec32) bool) {
+		if lo.IsNaN() || hi.IsNaN() || cmp32(lo, hi) > 0 {
+			return
+		}
+		var neg bool
+		var coeff uint32
+		var exp int
+		switch {
+		case lo.IsInf() && lo.Sign() > 0:
+			return
+		case lo.IsInf():
+			neg, coeff, exp = true, maxCoeff, maxExp
+		default:
+			neg, coeff, exp = lo.unpack()
+			for coeff != 0 && coeff < maxCoeff/10+1 && exp > minExp {
+				coeff *= 10
+				exp--
+			}
+			if coeff == 0 {
+				neg, exp = false, minExp
+			}
+		}
+		for {
+			d := pack32(neg, coeff, exp)
+			if cmp32(d, hi) > 0 || !yield(d) {
+				return
+			}
+			switch {
+			case neg && coeff == 1 && exp == minExp:
+				neg, coeff = false, 0
+			case neg:
+				coeff--
+				if coeff < maxCoeff/10+1 && exp > minExp {
+					coeff, exp = maxCoeff, exp-1
+				}
+			case coeff < maxCoeff:
+				coeff++
+			case exp == maxExp:
+				return
+			default:
+				coeff, exp = maxCoeff/10+1, exp+1
+			}
+		}
+	}
+}
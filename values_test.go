@@ -0,0 +1,51 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValues(t *testing.T) {
+	negInf := inf32 | signMask
+	testCases := []struct {
+		lo, hi Dec32
+		ref    string
+	}{
+		{mustEncode(t, 9999998, -6), mustEncode(t, 1000001, -5), "9.999998 9.999999 10.00000 10.00001"},
+		{mustEncode(t, 1, 1), mustEncode(t, 1000001, -5), "10.00000 10.00001"},
+		{mustEncode(t, 5, -99), mustEncode(t, 501, -101), "5.00E-99 5.01E-99"},
+		{mustEncode(t, -1000001, -6), mustEncode(t, -9999998, -7), "-1.000001 -1.000000 -0.9999999 -0.9999998"},
+		{mustEncode(t, -2, -101), mustEncode(t, 2, -101), "-2E-101 -1E-101 0E-101 1E-101 2E-101"},
+		{mustEncode(t, 0, 5) | signMask, mustEncode(t, 1, -101), "0E-101 1E-101"},
+		{mustEncode(t, 9999998, 90), inf32, "9.999998E+96 9.999999E+96"},
+		{negInf, mustEncode(t, -9999998, 90), "-9.999999E+96 -9.999998E+96"},
+		{mustEncode(t, 1, 0), mustEncode(t, 10, -1), "1.000000"},
+		{mustEncode(t, 2, 0), mustEncode(t, 1, 0), ""},
+		{nan32, inf32, ""},
+		{inf32, inf32, ""},
+	}
+	for i, testCase := range testCases {
+		var got []string
+		for d := range Values(testCase.lo, testCase.hi) {
+			got = append(got, d.String())
+		}
+		if s := strings.Join(got, " "); s != testCase.ref {
+			t.Errorf("testCase #%d: expect %q, got %q", i, testCase.ref, s)
+		}
+	}
+
+	// Stopping early.
+	n := 0
+	for range Values(mustEncode(t, 0, 0), inf32) {
+		if n++; n == 3 {
+			break
+		}
+	}
+	if n != 3 {
+		t.Errorf("unexpected count %d", n)
+	}
+}
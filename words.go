@@ -0,0 +1,106 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"math/big"
+	"strings"
+)
+
+var (
+	englishOnes = [...]string{
+		"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine",
+		"ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen",
+		"seventeen", "eighteen", "nineteen",
+	}
+	englishTens = [...]string{
+		"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety",
+	}
+	// englishScales names the powers of a thousand, in the short scale.
+	englishScales = [...]string{
+		"", "thousand", "million", "billion", "trillion", "quadrillion",
+		"quintillion", "sextillion", "septillion", "octillion", "nonillion",
+		"decillion",
+	}
+)
+
+// Words returns the decimal value spelled out in words in the language lang,
+// as written on checks and in legal documents: 1234.56 is written "one
+// thousand two hundred thirty-four and 56/100". The fraction digits of d are
+// written as a fraction over the matching power of ten, so 12.50 ends in
+// "and 50/100" and 12.5 in "and 5/10"; integers have no fraction. Negative
+// values, including negative zero, begin with "minus".
+//
+// The only language supported is English, "en" or a tag starting "en-",
+// with the short scale. Words returns false for other languages, for values
+// that are not finite and for integer parts of a thousand decillion (10^36)
+// or more.
+func (d Dec32) Words(lang string) (string, bool) {
+	if lang != "en" && !strings.HasPrefix(lang, "en-") || d.IsInf() || d.IsNaN() {
+		return "", false
+	}
+	x := newBigDec32(d)
+	var ipart, frac big.Int
+	ipart.Set(&x.coeff)
+	if x.exp > 0 {
+		ipart.Mul(&ipart, bigPow10(x.exp))
+	} else if x.exp < 0 {
+		ipart.QuoRem(&ipart, bigPow10(-x.exp), &frac)
+	}
+	if bigDigits(&ipart) > 3*len(englishScales) {
+		return "", false
+	}
+
+	var words []string
+	if x.neg {
+		words = append(words, "minus")
+	}
+	if ipart.Sign() == 0 {
+		words = append(words, englishOnes[0])
+	}
+	// Split the integer part into groups of three digits, most significant
+	// first.
+	var groups []int
+	thousand := big.NewInt(1000)
+	var g big.Int
+	for ipart.Sign() != 0 {
+		ipart.QuoRem(&ipart, thousand, &g)
+		groups = append(groups, int(g.Int64()))
+	}
+	for i := len(groups) - 1; i >= 0; i-- {
+		if groups[i] == 0 {
+			continue
+		}
+		words = appendEnglishHundreds(words, groups[i])
+		if i > 0 {
+			words = append(words, englishScales[i])
+		}
+	}
+
+	if x.exp < 0 {
+		digits := frac.String()
+		digits = strings.Repeat("0", -x.exp-len(digits)) + digits
+		words = append(words, "and", digits+"/1"+strings.Repeat("0", -x.exp))
+	}
+	return strings.Join(words, " "), true
+}
+
+// appendEnglishHundreds appends the words for n, between 1 and 999.
+func appendEnglishHundreds(words []string, n int) []string {
+	if n >= 100 {
+		words = append(words, englishOnes[n/100], "hundred")
+		n %= 100
+	}
+	switch {
+	case n == 0:
+	case n < 20:
+		words = append(words, englishOnes[n])
+	case n%10 == 0:
+		words = append(words, englishTens[n/10])
+	default:
+		words = append(words, englishTens[n/10]+"-"+englishOnes[n%10])
+	}
+	return words
+}
@@ -0,0 +1,41 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import "testing"
+
+func TestWords(t *testing.T) {
+	testCases := []struct {
+		d    Dec32
+		lang string
+		ref  string
+		ok   bool
+	}{
+		{mustEncode(t, 123456, -2), "en", "one thousand two hundred thirty-four and 56/100", true},
+		{mustEncode(t, 1250, -2), "en-US", "twelve and 50/100", true},
+		{mustEncode(t, 125, -1), "en", "twelve and 5/10", true},
+		{mustEncode(t, 1200, -2), "en", "twelve and 00/100", true},
+		{mustEncode(t, 5, -2), "en", "zero and 05/100", true},
+		{mustEncode(t, 0, 0), "en", "zero", true},
+		{mustEncode(t, 0, 0) | signMask, "en", "minus zero", true},
+		{mustEncode(t, -40, 0), "en", "minus forty", true},
+		{mustEncode(t, 1000001, 0), "en", "one million one", true},
+		{mustEncode(t, 100, 0), "en", "one hundred", true},
+		{mustEncode(t, 919, 0), "en", "nine hundred nineteen", true},
+		{mustEncode(t, 12, 9), "en", "twelve billion", true},
+		{mustEncode(t, 1001, 3), "en", "one million one thousand", true},
+		{mustEncode(t, 999, 33), "en", "nine hundred ninety-nine decillion", true},
+		{mustEncode(t, 1, 36), "en", "", false},
+		{mustEncode(t, 1, 0), "fr", "", false},
+		{inf32, "en", "", false},
+		{nan32, "en", "", false},
+	}
+	for i, testCase := range testCases {
+		s, ok := testCase.d.Words(testCase.lang)
+		if s != testCase.ref || ok != testCase.ok {
+			t.Errorf("testCase #%d: %v: expect %q %v, got %q %v", i, testCase.d, testCase.ref, testCase.ok, s, ok)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+// workForm distinguishes finite, infinite and NaN DecWork values.
+type workForm uint8
+
+const (
+	workFinite workForm = iota
+	workInf
+	workNaN
+)
+
+// DecWork is a mutable decimal32 workspace holding an unpacked sign,
+// coefficient and exponent. Operations on DecWork values round their results
+// to the decimal32 value set exactly as the equivalent Dec32 operations would,
+// ties to even, but leave them unpacked, so a long chain of computation only
+// pays for decoding and encoding at its ends.
+//
+// Like big.Int, operations set the receiver to the result and return it, and
+// the receiver may be one of the operands:
+//
+//	var sum decimal.DecWork
+//	for _, x := range xs {
+//		sum.Add(&sum, decimal.NewDecWork(x))
+//	}
+//	total := sum.Dec32()
+//
+// The zero value of DecWork is zero with exponent 0. Operations work on
+// fixed-width integers, at most seven coefficient digits between operations
+// and fourteen within a product, so they do not allocate.
+type DecWork struct {
+	coeff   uint64
+	exp     int
+	neg     bool
+	form    workForm
+	inexact bool
+}
+
+// NewDecWork allocates and returns a new DecWork set to d.
+func NewDecWork(d Dec32) *DecWork {
+	return new(DecWork).SetDec32(d)
+}
+
+// SetDec32 sets z to d and returns z. Non-canonical coefficients are read as
+// zero, and NaN payloads are discarded.
+func (z *DecWork) SetDec32(d Dec32) *DecWork {
+	z.inexact = false
+	z.neg = d.Sign() < 0
+	switch {
+	case d.IsInf():
+		z.form = workInf
+	case d.IsNaN():
+		z.form = workNaN
+	default:
+		_, coeff, exp := d.unpack()
+		z.form = workFinite
+		z.coeff, z.exp = uint64(coeff), exp
+	}
+	return z
+}
+
+// Set sets z to x and returns z.
+func (z *DecWork) Set(x *DecWork) *DecWork {
+	*z = *x
+	return z
+}
+
+// Dec32 returns the value of x as a Dec32.
+func (x *DecWork) Dec32() Dec32 {
+	switch x.form {
+	case workInf:
+		return inf32 | signOf(x.neg)
+	case workNaN:
+		return nan32 | signOf(x.neg)
+	}
+	return pack32(x.neg, uint32(x.coeff), x.exp)
+}
+
+// String returns the value of x in the same form as Dec32.String.
+func (x *DecWork) String() string {
+	return x.Dec32().String()
+}
+
+// Inexact reports whether rounding discarded any nonzero digits in the
+// operation that last set x.
+func (x *DecWork) Inexact() bool {
+	return x.inexact
+}
+
+// Add sets z to the rounded sum x+y and returns z.
+func (z *DecWork) Add(x, y *DecWork) *DecWork {
+	return z.add(x, y, y.neg)
+}
+
+// Sub sets z to the rounded difference x-y and returns z.
+func (z *DecWork) Sub(x, y *DecWork) *DecWork {
+	return z.add(x, y, !y.neg)
+}
+
+// addShift is the largest exponent difference at which add aligns the
+// coefficients exactly. Beyond it the operand with the smaller exponent lies
+// wholly below the seven digits kept, and only its leading digits and a
+// sticky digit are needed.
+const addShift = 10
+
+// add sets z to x+y, taking the sign of y to be yneg.
+func (z *DecWork) add(x, y *DecWork, yneg bool) *DecWork {
+	switch {
+	case x.form == workNaN || y.form == workNaN:
+		return z.setNaN()
+	case x.form == workInf && y.form == workInf:
+		if x.neg != yneg {
+			return z.setNaN()
+		}
+		return z.setInf(yneg)
+	case x.form == workInf:
+		return z.setInf(x.neg)
+	case y.form == workInf:
+		return z.setInf(yneg)
+	}
+	// Let a be the operand with the larger exponent.
+	a, aexp, aneg := x.coeff, x.exp, x.neg
+	b, exp, bneg := y.coeff, y.exp, yneg
+	if aexp < exp {
+		a, aexp, aneg, b, exp, bneg = b, exp, bneg, a, aexp, aneg
+	}
+	if a != 0 {
+		if d := aexp - exp; d > addShift {
+			// Keep the digits of b above 10^-addShift of a's exponent,
+			// and a sticky digit if any below it are nonzero.
+			var r uint64
+			if n := d - addShift; n < len(pow10Uint64) {
+				b, r = b/pow10Uint64[n], b%pow10Uint64[n]
+			} else {
+				b, r = 0, b
+			}
+			exp = aexp - addShift
+			if r != 0 {
+				b, exp = b*10+1, exp-1
+			}
+		}
+		a *= pow10Uint64[aexp-exp]
+	}
+	var c uint64
+	neg := aneg
+	switch {
+	case aneg == bneg:
+		c = a + b
+	case a >= b:
+		c = a - b
+	default:
+		c, neg = b-a, bneg
+	}
+	if c == 0 {
+		// An exact zero sum is positive unless both operands are negative.
+		neg = aneg && bneg
+	}
+	z.coeff, z.exp, z.neg = c, exp, neg
+	return z.round()
+}
+
+// Mul sets z to the rounded product x*y and returns z.
+func (z *DecWork) Mul(x, y *DecWork) *DecWork {
+	neg := x.neg != y.neg
+	switch {
+	case x.form == workNaN || y.form == workNaN:
+		return z.setNaN()
+	case x.form == workInf || y.form == workInf:
+		if (x.form == workFinite && x.coeff == 0) || (y.form == workFinite && y.coeff == 0) {
+			return z.setNaN()
+		}
+		return z.setInf(neg)
+	}
+	z.coeff, z.exp, z.neg = x.coeff*y.coeff, x.exp+y.exp, neg
+	return z.round()
+}
+
+// Neg sets z to x with its sign reversed and returns z.
+func (z *DecWork) Neg(x *DecWork) *DecWork {
+	z.Set(x)
+	z.neg = !z.neg
+	z.inexact = false
+	return z
+}
+
+// Abs sets z to the absolute value of x and returns z.
+func (z *DecWork) Abs(x *DecWork) *DecWork {
+	z.Set(x)
+	z.neg = false
+	z.inexact = false
+	return z
+}
+
+// Sign returns -1, 0 or +1 according to the sign of x. Zeros of either sign
+// and NaNs return 0.
+func (x *DecWork) Sign() int {
+	switch {
+	case x.form == workNaN || (x.form == workFinite && x.coeff == 0):
+		return 0
+	case x.neg:
+		return -1
+	}
+	return 1
+}
+
+// round rounds the finite value of z, whose coefficient may have up to 19
+// digits, to the decimal32 value set, ties to even, as bigDec.round32 does.
+func (z *DecWork) round() *DecWork {
+	z.form, z.inexact = workFinite, false
+	if z.coeff == 0 {
+		z.exp = min(max(z.exp, minExp), maxExp)
+		return z
+	}
+	n := numDigits(z.coeff)
+	drop := max(n-7, 0)
+	if z.exp+drop < minExp {
+		drop = minExp - z.exp
+	}
+	if drop > 0 {
+		var q, r, m uint64
+		if drop < len(pow10Uint64) {
+			m = pow10Uint64[drop]
+			q, r = z.coeff/m, z.coeff%m
+		} else {
+			// The coefficient is below half of 10^drop.
+			r = z.coeff
+		}
+		z.exp += drop
+		z.inexact = r != 0
+		if m != 0 && (r > m-r || r == m-r && q&1 == 1) {
+			if q++; q > maxCoeff {
+				// Rounding carried into an eighth digit, a zero.
+				q, z.exp = q/10, z.exp+1
+			}
+		}
+		z.coeff = q
+		if q == 0 {
+			z.exp = minExp
+			return z
+		}
+	}
+	if z.exp > maxExp {
+		pad := z.exp - maxExp
+		if numDigits(z.coeff)+pad > 7 {
+			z.setInf(z.neg)
+			z.inexact = true
+			return z
+		}
+		z.coeff *= pow10Uint64[pad]
+		z.exp = maxExp
+	}
+	return z
+}
+
+func (z *DecWork) setInf(neg bool) *DecWork {
+	z.form, z.neg, z.inexact = workInf, neg, false
+	return z
+}
+
+func (z *DecWork) setNaN() *DecWork {
+	z.form, z.neg, z.inexact = workNaN, false, false
+	return z
+}
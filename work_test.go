@@ -0,0 +1,190 @@
+// Copyright 2014 Casey Marshall. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"testing"
+)
+
+func TestDecWorkChain(t *testing.T) {
+	// Summing 0.1 ten times is exact in decimal.
+	var sum DecWork
+	tenth := NewDecWork(mustEncode(t, 1, -1))
+	for i := 0; i < 10; i++ {
+		sum.Add(&sum, tenth)
+	}
+	if d := sum.Dec32(); d != mustEncode(t, 10, -1) {
+		t.Errorf("expect 1.0, got %v", d)
+	}
+
+	// Compound 1.05 over 10 periods, rounding each step to seven digits.
+	rate := NewDecWork(mustEncode(t, 105, -2))
+	acc := NewDecWork(mustEncode(t, 1000, 0))
+	for i := 0; i < 10; i++ {
+		acc.Mul(acc, rate)
+	}
+	if d := acc.Dec32(); d != mustEncode(t, 1628894, -3) {
+		t.Errorf("expect 1628.894, got %v", d)
+	}
+	if !acc.Inexact() {
+		t.Errorf("expected last step to be inexact")
+	}
+}
+
+func TestDecWorkOps(t *testing.T) {
+	inf, negInf := inf32, inf32|signMask
+	one, negTwo := mustEncode(t, 1, 0), mustEncode(t, -2, 0)
+	max := mustEncode(t, 9999999, 90)
+	testCases := []struct {
+		op   string
+		x, y Dec32
+		ref  Dec32
+	}{
+		{"+", one, negTwo, mustEncode(t, -1, 0)},
+		{"-", one, negTwo, mustEncode(t, 3, 0)},
+		{"*", one, negTwo, negTwo},
+		{"+", one, mustEncode(t, -1, 0), mustEncode(t, 0, 0)},
+		{"+", pack32(true, 0, 0), pack32(true, 0, 0), pack32(true, 0, 0)},
+		{"+", mustEncode(t, 1234567, 0), mustEncode(t, 5, -1), mustEncode(t, 1234568, 0)},
+		{"+", mustEncode(t, 1234567, 0), mustEncode(t, 1, -20), mustEncode(t, 1234567, 0)},
+		{"*", mustEncode(t, 1, -60), mustEncode(t, 1, -60), mustEncode(t, 0, -101)},
+		{"*", max, mustEncode(t, 2, 0), inf},
+		{"*", mustEncode(t, 1, 90), mustEncode(t, 1, 2), mustEncode(t, 100, 90)},
+		{"+", inf, one, inf},
+		{"-", one, inf, negInf},
+		{"+", inf, negInf, nan32},
+		{"-", inf, inf, nan32},
+		{"*", inf, negTwo, negInf},
+		{"*", inf, mustEncode(t, 0, 0), nan32},
+		{"+", nan32, one, nan32},
+		{"*", one, nan32, nan32},
+	}
+	for i, testCase := range testCases {
+		x, y := NewDecWork(testCase.x), NewDecWork(testCase.y)
+		var z DecWork
+		switch testCase.op {
+		case "+":
+			z.Add(x, y)
+		case "-":
+			z.Sub(x, y)
+		case "*":
+			z.Mul(x, y)
+		}
+		if d := z.Dec32(); d != testCase.ref {
+			t.Errorf("testCase #%d: %v %s %v: expect %v, got %v", i, testCase.x, testCase.op, testCase.y, testCase.ref, d)
+		}
+	}
+}
+
+func TestDecWorkUnary(t *testing.T) {
+	x := NewDecWork(mustEncode(t, -125, -2))
+	if d := new(DecWork).Abs(x).Dec32(); d != mustEncode(t, 125, -2) {
+		t.Errorf("unexpected abs %v", d)
+	}
+	if d := x.Neg(x).Dec32(); d != mustEncode(t, 125, -2) {
+		t.Errorf("unexpected neg %v", d)
+	}
+	if x.Sign() != 1 || new(DecWork).Sign() != 0 || NewDecWork(nan32).Sign() != 0 {
+		t.Errorf("unexpected sign")
+	}
+	if s := NewDecWork(inf32 | signMask).String(); s != "-Infinity" {
+		t.Errorf("unexpected string %q", s)
+	}
+}
+
+func TestDecWorkNegZero(t *testing.T) {
+	negZero := mustEncode(t, 0, 0) | signMask
+	posZero := mustEncode(t, 0, 0)
+	one, negOne := mustEncode(t, 1, 0), mustEncode(t, -1, 0)
+	tiny := mustEncode(t, -1, -101)
+	tenth := mustEncode(t, 1, -1)
+	testCases := []struct {
+		op   string
+		x, y Dec32
+		neg  bool
+	}{
+		{"+", negZero, negZero, true},
+		{"+", negZero, posZero, false},
+		{"+", posZero, negZero, false},
+		{"-", negZero, posZero, true},
+		{"-", one, one, false},
+		{"+", negOne, one, false},
+		{"*", negOne, posZero, true},
+		{"*", negZero, negZero, false},
+		// Underflow to zero keeps the sign of the exact result.
+		{"*", tiny, tenth, true},
+	}
+	for i, testCase := range testCases {
+		x, y := NewDecWork(testCase.x), NewDecWork(testCase.y)
+		var z DecWork
+		switch testCase.op {
+		case "+":
+			z.Add(x, y)
+		case "-":
+			z.Sub(x, y)
+		case "*":
+			z.Mul(x, y)
+		}
+		d := z.Dec32()
+		if !d.Zero() || d.IsNegZero() != testCase.neg {
+			t.Errorf("testCase #%d: %v %s %v: expect zero with negative %v, got %v", i, testCase.x, testCase.op, testCase.y, testCase.neg, d)
+		}
+	}
+}
+
+func TestDecWorkMatchesContext(t *testing.T) {
+	var values []Dec32
+	for _, coeff := range []uint32{0, 1, 5, 15, 1234567, 5000000, 9999999} {
+		for _, exp := range []int{minExp, minExp + 5, -20, -7, -1, 0, 3, 12, maxExp - 6, maxExp} {
+			values = append(values, pack32(false, coeff, exp), pack32(true, coeff, exp))
+		}
+	}
+	ops := []struct {
+		name string
+		work func(z, x, y *DecWork) *DecWork
+		ctx  func(c *Context, x, y Dec32) Dec32
+	}{
+		{"add", (*DecWork).Add, (*Context).Add},
+		{"sub", (*DecWork).Sub, (*Context).Sub},
+		{"mul", (*DecWork).Mul, (*Context).Mul},
+	}
+	for _, x := range values {
+		for _, y := range values {
+			for _, op := range ops {
+				z := op.work(new(DecWork), NewDecWork(x), NewDecWork(y))
+				var c Context
+				ref := op.ctx(&c, x, y)
+				if d := z.Dec32(); d != ref || z.Inexact() != (c.Flags&Inexact != 0) {
+					t.Errorf("%s(%v, %v): expect %v inexact %v, got %v inexact %v", op.name, x, y,
+						ref, c.Flags&Inexact != 0, d, z.Inexact())
+				}
+			}
+		}
+	}
+}
+
+func TestDecWorkAllocs(t *testing.T) {
+	x, y := NewDecWork(mustEncode(t, 1234567, -3)), NewDecWork(mustEncode(t, 105, -2))
+	var z DecWork
+	allocs := testing.AllocsPerRun(100, func() {
+		z.Add(x, y)
+		z.Mul(&z, y)
+		z.Sub(&z, x)
+	})
+	if allocs != 0 {
+		t.Errorf("expect no allocations, got %v", allocs)
+	}
+}
+
+func BenchmarkDecWork(b *testing.B) {
+	x, y := NewDecWork(MustEncodeDec32(1234567, -3)), NewDecWork(MustEncodeDec32(105, -2))
+	b.ReportAllocs()
+	var z DecWork
+	for i := 0; i < b.N; i++ {
+		z.Add(x, y)
+		z.Mul(&z, y)
+		z.Sub(&z, x)
+	}
+}